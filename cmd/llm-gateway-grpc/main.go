@@ -2,20 +2,34 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/poly-workshop/go-webmods/app"
 	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/admission"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/audit"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/config"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/debuglog"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/generationstore"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/health"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/azureopenai"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/dashscope"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/keypool"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/openrouter"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/providerhttp"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/metrics"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/postgresrepo"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/secrets"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/server/grpcserver"
 )
 
@@ -35,46 +49,299 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	transportOpts := providerhttp.TransportOptions{
+		ProxyURL:            cfg.LLM.HTTP.ProxyURL,
+		DialTimeout:         cfg.LLM.HTTP.DialTimeout,
+		TLSHandshakeTimeout: cfg.LLM.HTTP.TLSHandshakeTimeout,
+		MaxIdleConnsPerHost: cfg.LLM.HTTP.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.LLM.HTTP.IdleConnTimeout,
+	}
+	var sharedTransport http.RoundTripper
+	transport := func() http.RoundTripper {
+		if sharedTransport != nil {
+			return sharedTransport
+		}
+		t, err := providerhttp.NewTransport(transportOpts)
+		if err != nil {
+			slog.Error("create upstream transport failed", "error", err)
+			os.Exit(1)
+		}
+		if cfg.LLM.HTTP.ShareTransport {
+			sharedTransport = t
+		}
+		return t
+	}
+
+	wireLog, closeWireLog, err := wireLogger(cfg)
+	if err != nil {
+		slog.Error("open upstream wire log failed", "error", err)
+		os.Exit(1)
+	}
+	defer closeWireLog()
+	providerTransport := func(provider string) http.RoundTripper {
+		w := cfg.LLM.WireLog
+		if !w.Enabled || (len(w.Providers) > 0 && !slices.Contains(w.Providers, provider)) {
+			return transport()
+		}
+		return providerhttp.NewWireLogTransport(transport(), providerhttp.WireLogOptions{
+			Provider:      provider,
+			Logger:        wireLog,
+			RedactContent: w.RedactContent,
+			MaxBody:       w.MaxBodyBytes,
+		})
+	}
+
+	// newProvider builds a provider from its configured settings with the given
+	// base URL and keys, so tenant pools only differ in credentials.
+	newProvider := func(name, baseURL string, keys []string, weighted []config.WeightedAPIKey) llmgateway.Provider {
+		switch name {
+		case "dashscope":
+			ds := cfg.LLM.Providers.DashScope
+			return dashscope.NewProvider(
+				baseURL,
+				keys,
+				ds.Timeout,
+				dashscope.WithUserAgent(cfg.LLM.UserAgent),
+				dashscope.WithHeaders(ds.Headers),
+				dashscope.WithStreamIdleTimeout(ds.StreamIdleTimeout),
+				dashscope.WithTransport(providerTransport("dashscope")),
+				dashscope.WithMaxResponseBytes(cfg.LLM.MaxResponseBytes),
+				dashscope.WithWeightedKeys(weightedKeys(weighted)),
+			)
+		case "openrouter":
+			or := cfg.LLM.Providers.OpenRouter
+			return openrouter.NewProvider(
+				baseURL,
+				keys,
+				or.Timeout,
+				openrouter.WithUserAgent(cfg.LLM.UserAgent),
+				openrouter.WithHeaders(or.Headers),
+				openrouter.WithStreamIdleTimeout(or.StreamIdleTimeout),
+				openrouter.WithTransport(providerTransport("openrouter")),
+				openrouter.WithMaxResponseBytes(cfg.LLM.MaxResponseBytes),
+				openrouter.WithWeightedKeys(weightedKeys(weighted)),
+			)
+		default:
+			az := cfg.LLM.Providers.Azure
+			return azureopenai.NewProvider(
+				baseURL,
+				az.APIVersion,
+				keys,
+				az.Timeout,
+				azureopenai.WithUserAgent(cfg.LLM.UserAgent),
+				azureopenai.WithHeaders(az.Headers),
+				azureopenai.WithStreamIdleTimeout(az.StreamIdleTimeout),
+				azureopenai.WithTransport(providerTransport("azure")),
+				azureopenai.WithMaxResponseBytes(cfg.LLM.MaxResponseBytes),
+				azureopenai.WithWeightedKeys(weightedKeys(weighted)),
+			)
+		}
+	}
+
+	ds, or := cfg.LLM.Providers.DashScope, cfg.LLM.Providers.OpenRouter
+	keys, err := providerKeys(ctx, cfg)
+	if err != nil {
+		slog.Error("resolve provider api keys failed", "error", err)
+		os.Exit(1)
+	}
 	providers := map[string]llmgateway.Provider{
-		"dashscope": dashscope.NewProvider(
-			cfg.LLM.Providers.DashScope.BaseURL,
-			cfg.LLM.Providers.DashScope.APIKey,
-			cfg.LLM.Providers.DashScope.Timeout,
-		),
-		"openrouter": openrouter.NewProvider(
-			cfg.LLM.Providers.OpenRouter.BaseURL,
-			cfg.LLM.Providers.OpenRouter.APIKey,
-			cfg.LLM.Providers.OpenRouter.Timeout,
-		),
+		"dashscope":  newProvider("dashscope", ds.BaseURL, keys["dashscope"], ds.WeightedKeys),
+		"openrouter": newProvider("openrouter", or.BaseURL, keys["openrouter"], or.WeightedKeys),
+	}
+	if az := cfg.LLM.Providers.Azure; az.BaseURL != "" {
+		providers["azure"] = newProvider("azure", az.BaseURL, keys["azure"], az.WeightedKeys)
+	}
+	defaultBaseURLs := map[string]string{
+		"dashscope":  ds.BaseURL,
+		"openrouter": or.BaseURL,
+		"azure":      cfg.LLM.Providers.Azure.BaseURL,
+	}
+	providerPools := make(map[string]map[string]llmgateway.Provider, len(cfg.LLM.TenantPools))
+	tenantPools := make([]grpcserver.TenantPool, 0, len(cfg.LLM.TenantPools))
+	for _, tp := range cfg.LLM.TenantPools {
+		pool := make(map[string]llmgateway.Provider, len(tp.Providers))
+		for name, creds := range tp.Providers {
+			baseURL := creds.BaseURL
+			if baseURL == "" {
+				baseURL = defaultBaseURLs[name]
+			}
+			pool[name] = newProvider(name, baseURL, keys[tenantKey(tp.Name, name)], nil)
+		}
+		providerPools[tp.Name] = pool
+		tenantPools = append(tenantPools, grpcserver.TenantPool{Name: tp.Name, Subjects: tp.Subjects})
 	}
 
 	models := make([]llmgateway.ModelSpec, 0, len(cfg.LLM.Models))
+	modelLimits := make(map[string]llmgateway.ModelRateLimit)
 	for _, m := range cfg.LLM.Models {
+		if m.RequestsPerMinute > 0 {
+			modelLimits[m.ID] = llmgateway.ModelRateLimit{RequestsPerMinute: m.RequestsPerMinute, Burst: m.RateLimitBurst, MaxWait: m.RateLimitMaxWait}
+		}
 		models = append(models, llmgateway.ModelSpec{
 			ID:            m.ID,
 			Name:          m.Name,
 			Provider:      m.Provider,
 			Capabilities:  m.Capabilities,
 			UpstreamModel: m.UpstreamModel,
+
+			DefaultTemperature: m.DefaultTemperature,
+			SystemPrompt:       m.SystemPrompt,
+			SystemPromptAlways: m.SystemPromptAlways,
+			StripTags:          m.StripTags,
+			KeepRawContent:     m.KeepRawContent,
+			ContextWindow:      m.ContextWindow,
+			MaxOutputTokens:    m.MaxOutputTokens,
+			Pricing:            modelPricing(m.InputPricePerMillion, m.OutputPricePerMillion),
 		})
 	}
 
-	// TODO: Implement a concrete GenerationRepository (e.g., in-memory or database).
-	// For now, pass nil to skip generation record storage.
-	appSvc := llmgateway.NewService(providers, models, nil)
-
 	serviceTokens := make([]auth.ServiceToken, 0, len(cfg.Auth.ServiceTokens))
+	modelAccess := make(map[string]llmgateway.ModelAccess)
+	var auditSubjects []string
 	for _, t := range cfg.Auth.ServiceTokens {
-		serviceTokens = append(serviceTokens, auth.ServiceToken{Name: t.Name, Token: t.Token})
+		serviceTokens = append(serviceTokens, auth.ServiceToken{Name: t.Name, Token: t.Token, Scopes: t.Scopes, Priority: t.Priority})
+		if t.Audit && t.Token != "" {
+			auditSubjects = append(auditSubjects, auth.SubjectForServiceToken(t.Name))
+		}
+		if len(t.AllowedModels) > 0 || len(t.DeniedModels) > 0 {
+			modelAccess[auth.SubjectForServiceToken(t.Name)] = llmgateway.ModelAccess{Allowed: t.AllowedModels, Denied: t.DeniedModels}
+		}
+	}
+
+	metricsRec := metrics.New(
+		metrics.WithLatencyBuckets(cfg.Metrics.LatencyBuckets),
+		metrics.WithLatencySLO(cfg.Metrics.SLO.P99Threshold, cfg.Metrics.SLO.Window),
+	)
+
+	appOpts := []llmgateway.Option{
+		llmgateway.WithMaxTemperature(cfg.LLM.Limits.MaxTemperature),
+		llmgateway.WithUpstreamConcurrency(cfg.LLM.Limits.UpstreamConcurrency),
+		llmgateway.WithEmbeddingsBatchSize(cfg.LLM.Limits.EmbeddingsBatchSize),
+		llmgateway.WithEmbeddingsAutoChunk(cfg.LLM.Limits.EmbeddingsAutoChunkChars),
+		llmgateway.WithEmbeddingsInputLimits(cfg.LLM.Limits.MaxEmbeddingsInputs, cfg.LLM.Limits.MaxEmbeddingsInputBytes),
+		llmgateway.WithContentPartLimits(cfg.LLM.Limits.MaxContentPartsPerMessage, cfg.LLM.Limits.MaxContentPartsPerRequest),
+		llmgateway.WithContextWindowCheck(cfg.LLM.Limits.CheckContextWindow),
+		llmgateway.WithMetrics(metricsRec),
+		llmgateway.WithEmptyResponsePolicy(llmgateway.EmptyResponsePolicy(cfg.LLM.EmptyResponse)),
+		llmgateway.WithMetadataAllowlist(cfg.LLM.MetadataAllowlist),
+		llmgateway.WithImageURLPolicy(llmgateway.ImageURLPolicy{
+			DataURIOnly:  cfg.LLM.Images.DataURIOnly,
+			AllowedHosts: cfg.LLM.Images.AllowedHosts,
+		}),
+		llmgateway.WithModelAccess(modelAccess),
+		llmgateway.WithProviderAliases(cfg.LLM.ProviderAliases),
+		llmgateway.WithProviderPools(providerPools),
+		llmgateway.WithModelRateLimits(modelLimits),
+		llmgateway.WithOutputFilter(outputFilter(cfg)),
+		llmgateway.WithGatewayGenerationIDs(cfg.LLM.GatewayGenerationIDs),
+		llmgateway.WithChoiceDedup(cfg.LLM.DedupChoices),
+		llmgateway.WithRetryPolicy(llmgateway.RetryPolicy{
+			MaxAttempts: cfg.LLM.Retry.MaxAttempts,
+			BaseBackoff: cfg.LLM.Retry.BaseBackoff,
+			MaxBackoff:  cfg.LLM.Retry.MaxBackoff,
+
+			RetryConnectionReset: cfg.LLM.Retry.RetryConnectionReset,
+		}),
+		llmgateway.WithRequestTimeout(cfg.LLM.Retry.RequestTimeout),
+		llmgateway.WithUpstreamTimeout(cfg.LLM.Retry.UpstreamTimeout),
+		llmgateway.WithEmbeddingsCache(cfg.LLM.EmbeddingsCache.Size, cfg.LLM.EmbeddingsCache.TTL),
+		llmgateway.WithSlowUpstreamThreshold(cfg.LLM.SlowRequestThreshold, slowRequestThresholds(cfg)),
+	}
+	var discoverFrom []string
+	if cfg.LLM.Providers.DashScope.DiscoverModels {
+		discoverFrom = append(discoverFrom, "dashscope")
+	}
+	if cfg.LLM.Providers.OpenRouter.DiscoverModels {
+		discoverFrom = append(discoverFrom, "openrouter")
+	}
+	if cfg.LLM.DefaultUserFromSubject {
+		appOpts = append(appOpts, llmgateway.WithDefaultUserFromSubject(cfg.LLM.DefaultUserSalt))
+	}
+	if len(discoverFrom) > 0 {
+		appOpts = append(appOpts, llmgateway.WithModelDiscovery(discoverFrom...))
+	}
+	if len(auditSubjects) > 0 {
+		auditSink, err := audit.NewFileSink(cfg.Audit.Path, cfg.Audit.Buffer)
+		if err != nil {
+			slog.Error("create audit sink failed", "error", err)
+			os.Exit(1)
+		}
+		defer auditSink.Close()
+		slog.Warn("AUDIT TRAIL ENABLED: full prompts and responses are persisted to disk", "path", cfg.Audit.Path, "subjects", auditSubjects)
+		appOpts = append(appOpts, llmgateway.WithAudit(auditSink, auditSubjects))
+	}
+
+	var generations llmgateway.GenerationRepository = generationstore.NewMemory(cfg.Generations.MemoryCapacity)
+	if cfg.Generations.Store == "postgres" {
+		repo, err := postgresrepo.Open(ctx, postgresrepo.Options{
+			DSN:             cfg.Generations.Postgres.DSN,
+			MaxOpenConns:    cfg.Generations.Postgres.MaxOpenConns,
+			MaxIdleConns:    cfg.Generations.Postgres.MaxIdleConns,
+			ConnMaxLifetime: cfg.Generations.Postgres.ConnMaxLifetime,
+		})
+		if err != nil {
+			slog.Error("open generation store failed", "error", err)
+			os.Exit(1)
+		}
+		defer repo.Close()
+		generations = repo
 	}
-	authMgr := auth.NewManager(serviceTokens, cfg.Auth.TempTTL)
 
-	grpcSrv, err := grpcserver.New(cfg.GRPC.Listen, appSvc, authMgr)
+	appSvc := llmgateway.NewService(providers, models, generations, appOpts...)
+
+	if len(discoverFrom) > 0 {
+		go refreshModels(ctx, appSvc, cfg.LLM.ModelRefreshInterval)
+	}
+	if cfg.LLM.Warmup.Enabled {
+		warmupProviders(ctx, appSvc, cfg.LLM.Warmup.Timeout)
+	}
+
+	authMgr := auth.NewManager(serviceTokens, cfg.Auth.TempTTL, cfg.Auth.ClockSkew,
+		auth.WithIssueRateLimit(*cfg.Auth.IssuePerMinute, cfg.Auth.IssueBurst),
+		auth.WithFailureMetrics(metricsRec))
+
+	limiter := admission.NewLimiter(cfg.GRPC.MaxConcurrentRequests,
+		admission.WithQueue(cfg.GRPC.AdmissionQueueSize, cfg.GRPC.AdmissionQueueTimeout),
+		admission.WithPriority(grpcserver.AdmissionPriority(authMgr)),
+	)
+	metricsRec.ObserveGatewayInFlight(limiter.InFlight)
+
+	methodSwitch, err := grpcserver.NewMethodSwitch(cfg.GRPC.DisabledMethods)
+	if err != nil {
+		slog.Error("invalid grpc.disabled_methods", "error", err)
+		os.Exit(1)
+	}
+
+	grpcSrv, err := grpcserver.New(cfg.GRPC.Listen, appSvc, authMgr,
+		grpcserver.WithAdmission(limiter),
+		grpcserver.WithDebugSampling(debuglog.NewSampler(cfg.Log.DebugSampleRate)),
+		grpcserver.WithShutdownTimeout(cfg.GRPC.ShutdownTimeout),
+		grpcserver.WithGzip(cfg.GRPC.Gzip),
+		grpcserver.WithMethodSwitch(methodSwitch),
+		grpcserver.WithUpstreamOverrideHosts(cfg.GRPC.UpstreamOverrideHosts),
+		grpcserver.WithStreamsPerSubject(cfg.GRPC.MaxStreamsPerSubject),
+		grpcserver.WithTenantPools(tenantPools),
+		grpcserver.WithUsageTrailers(cfg.GRPC.UsageTrailers),
+		grpcserver.WithMaxUpstreamTimeout(cfg.GRPC.MaxUpstreamTimeout),
+		grpcserver.WithUsageCallbackBatching(cfg.UsageCallback.BatchSize, cfg.UsageCallback.BatchInterval),
+		grpcserver.WithKeepalive(grpcserver.Keepalive{
+			MaxConnectionIdle:     cfg.GRPC.Keepalive.MaxConnectionIdle,
+			MaxConnectionAge:      cfg.GRPC.Keepalive.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.GRPC.Keepalive.MaxConnectionAgeGrace,
+			Time:                  cfg.GRPC.Keepalive.Time,
+			Timeout:               cfg.GRPC.Keepalive.Timeout,
+			MinPingInterval:       cfg.GRPC.Keepalive.MinPingInterval,
+			PermitWithoutStream:   cfg.GRPC.Keepalive.PermitWithoutStream,
+		}),
+	)
 	if err != nil {
 		slog.Error("create grpc server failed", "error", err)
 		os.Exit(1)
 	}
 
+	go maintenanceOnSignal(ctx, appSvc, cfg.GRPC.MaintenanceRetryAfter)
+	go reloadOnSignal(ctx, methodSwitch, providers, providerPools)
+
 	healthSrv := &http.Server{
 		Addr: cfg.Health.Listen,
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -82,7 +349,11 @@ func main() {
 			case "/livez":
 				health.Livez(w, r)
 			case "/readyz":
-				health.Readyz(nil)(w, r)
+				health.Readyz(health.MaintenanceReadyChecker(appSvc))(w, r)
+			case "/healthz/detail":
+				health.Detail(appSvc)(w, r)
+			case "/metrics":
+				metricsRec.Handler().ServeHTTP(w, r)
 			default:
 				http.NotFound(w, r)
 			}
@@ -101,7 +372,7 @@ func main() {
 
 	select {
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.GRPC.ShutdownTimeout)
 		defer cancel()
 		_ = healthSrv.Shutdown(shutdownCtx)
 		_ = grpcSrv.Stop(shutdownCtx)
@@ -112,3 +383,263 @@ func main() {
 		}
 	}
 }
+
+// warmupProviders primes provider connections before the server accepts
+// traffic. Failures are only logged: a cold upstream is slower, not broken.
+func warmupProviders(ctx context.Context, appSvc *llmgateway.Service, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for _, r := range appSvc.Warmup(ctx) {
+		if r.Err != nil {
+			slog.Warn("provider warmup failed", "provider", r.Provider, "duration", r.Duration, "error", r.Err)
+			continue
+		}
+		slog.Info("provider warmed up", "provider", r.Provider, "duration", r.Duration)
+	}
+}
+
+// refreshModels merges upstream model lists into the catalog now and then every interval.
+func refreshModels(ctx context.Context, appSvc *llmgateway.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := appSvc.RefreshModels(ctx); err != nil {
+			slog.Warn("refresh upstream models failed", "error", err)
+		} else {
+			slog.Info("refreshed upstream models", "models", appSvc.ModelCount())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// maintenanceOnSignal turns maintenance mode on at SIGUSR1 and off at SIGUSR2,
+// so operators can drain model traffic without restarting the pod.
+func maintenanceOnSignal(ctx context.Context, appSvc *llmgateway.Service, retryAfter time.Duration) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(sigCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigCh:
+			on := sig == syscall.SIGUSR1
+			appSvc.SetMaintenance(on, retryAfter)
+			slog.Warn("maintenance mode changed", "enabled", on, "signal", sig.String())
+		}
+	}
+}
+
+// reloadOnSignal re-reads the config file (and environment) at SIGHUP and
+// applies the settings that can change at runtime: grpc.disabled_methods and
+// provider API keys. A config that fails to load, a secret that fails to
+// resolve or an unknown method name is logged and leaves all running settings
+// unchanged.
+func reloadOnSignal(ctx context.Context, methodSwitch *grpcserver.MethodSwitch,
+	providers map[string]llmgateway.Provider, providerPools map[string]map[string]llmgateway.Provider,
+) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			if err := app.Config().ReadInConfig(); err != nil {
+				slog.Error("reload config failed", "error", err)
+				continue
+			}
+			cfg, err := config.LoadGRPC()
+			if err != nil {
+				slog.Error("reload config failed", "error", err)
+				continue
+			}
+			keys, err := providerKeys(ctx, cfg)
+			if err != nil {
+				slog.Error("reload provider api keys failed", "error", err)
+				continue
+			}
+			// SetDisabled is the last step that can fail, so keys are only
+			// swapped once the whole config has been accepted.
+			if err := methodSwitch.SetDisabled(cfg.GRPC.DisabledMethods); err != nil {
+				slog.Error("reload grpc.disabled_methods failed", "error", err)
+				continue
+			}
+			reloadProviderKeys(cfg, keys, providers, providerPools)
+			slog.Warn("config reloaded", "disabled_methods", cfg.GRPC.DisabledMethods)
+		}
+	}
+}
+
+// keySetter is implemented by providers whose API keys can be swapped live.
+type keySetter interface {
+	SetKeys(apiKeys []string, weighted []keypool.Key)
+}
+
+// reloadProviderKeys swaps keys, as resolved again by providerKeys, into the
+// running providers so a leaked key can be rotated without a restart; a
+// rotated secret is picked up too. A provider whose reloaded config has no
+// key keeps its running keys rather than failing every request. Requests in
+// flight finish with the key they started with. Tenant pools and providers
+// added since startup need a restart.
+func reloadProviderKeys(cfg config.GRPCAppConfig, keys map[string][]string, providers map[string]llmgateway.Provider, providerPools map[string]map[string]llmgateway.Provider) {
+	setKeys := func(name string, provider llmgateway.Provider, plain []string, weighted []keypool.Key) {
+		s, ok := provider.(keySetter)
+		if !ok {
+			return
+		}
+		if !hasKey(plain, weighted) {
+			slog.Error("reload provider api keys skipped: no api key configured, keeping the running keys", "provider", name)
+			return
+		}
+		s.SetKeys(plain, weighted)
+	}
+	p := cfg.LLM.Providers
+	for name, weighted := range map[string][]config.WeightedAPIKey{
+		"dashscope":  p.DashScope.WeightedKeys,
+		"openrouter": p.OpenRouter.WeightedKeys,
+		"azure":      p.Azure.WeightedKeys,
+	} {
+		setKeys(name, providers[name], keys[name], weightedKeys(weighted))
+	}
+	for _, tp := range cfg.LLM.TenantPools {
+		for name := range tp.Providers {
+			key := tenantKey(tp.Name, name)
+			setKeys(key, providerPools[tp.Name][name], keys[key], nil)
+		}
+	}
+	slog.Warn("provider api keys reloaded")
+}
+
+// hasKey reports whether plain or weighted holds a non-empty key.
+func hasKey(plain []string, weighted []keypool.Key) bool {
+	return slices.ContainsFunc(plain, func(k string) bool { return k != "" }) ||
+		slices.ContainsFunc(weighted, func(k keypool.Key) bool { return k.Value != "" })
+}
+
+// providerKeys returns each provider's plain API keys: the configured ones
+// followed by the values of its api_key_secrets. Tenant pool providers are
+// keyed by tenantKey.
+func providerKeys(ctx context.Context, cfg config.GRPCAppConfig) (map[string][]string, error) {
+	var store secrets.Provider
+	if cfg.Secrets.Provider == "file" {
+		store = secrets.NewFile(cfg.Secrets.Dir)
+	}
+	withSecrets := func(keys, names []string) ([]string, error) {
+		if len(names) == 0 {
+			return keys, nil
+		}
+		resolved, err := secrets.Resolve(ctx, store, names)
+		if err != nil {
+			return nil, err
+		}
+		return append(slices.Clone(keys), resolved...), nil
+	}
+
+	p := cfg.LLM.Providers
+	out := make(map[string][]string)
+	for name, src := range map[string]struct {
+		keys    []string
+		secrets []string
+	}{
+		"dashscope":  {append([]string{p.DashScope.APIKey}, p.DashScope.APIKeys...), p.DashScope.APIKeySecrets},
+		"openrouter": {append([]string{p.OpenRouter.APIKey}, p.OpenRouter.APIKeys...), p.OpenRouter.APIKeySecrets},
+		"azure":      {append([]string{p.Azure.APIKey}, p.Azure.APIKeys...), p.Azure.APIKeySecrets},
+	} {
+		keys, err := withSecrets(src.keys, src.secrets)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		out[name] = keys
+	}
+	for _, tp := range cfg.LLM.TenantPools {
+		for name, creds := range tp.Providers {
+			keys, err := withSecrets(creds.APIKeys, creds.APIKeySecrets)
+			if err != nil {
+				return nil, fmt.Errorf("tenant pool %s %s: %w", tp.Name, name, err)
+			}
+			out[tenantKey(tp.Name, name)] = keys
+		}
+	}
+	return out, nil
+}
+
+// tenantKey is providerKeys' key for a tenant pool's provider.
+func tenantKey(pool, provider string) string {
+	return pool + "/" + provider
+}
+
+// outputFilter compiles the configured output filter; patterns were validated
+// when the config was loaded.
+func outputFilter(cfg config.GRPCAppConfig) llmgateway.OutputFilter {
+	f := cfg.LLM.OutputFilter
+	patterns := make([]*regexp.Regexp, 0, len(f.Patterns))
+	for _, p := range f.Patterns {
+		patterns = append(patterns, regexp.MustCompile(p))
+	}
+	return llmgateway.OutputFilter{
+		Patterns:       patterns,
+		Action:         llmgateway.OutputFilterAction(f.Action),
+		Replacement:    f.Replacement,
+		StreamHoldback: f.StreamHoldback,
+	}
+}
+
+// wireLogger returns the logger for upstream wire logs and a func closing its
+// file. It warns on every start with wire logging on, as the logs can hold
+// user content and are meant for non-production debugging only.
+func wireLogger(cfg config.GRPCAppConfig) (*slog.Logger, func(), error) {
+	w := cfg.LLM.WireLog
+	if !w.Enabled {
+		return nil, func() {}, nil
+	}
+	slog.Warn("UPSTREAM WIRE LOGGING IS ENABLED: raw provider requests and responses are being captured; do not run this in production",
+		"path", w.Path, "providers", w.Providers, "redact_content", w.RedactContent)
+	if w.Path == "" {
+		return slog.Default(), func() {}, nil
+	}
+	f, err := os.OpenFile(w.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger := slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return logger, func() { _ = f.Close() }, nil
+}
+
+// modelPricing returns nil when neither price is configured.
+func modelPricing(input, output float64) *llm.ModelPricing {
+	if input == 0 && output == 0 {
+		return nil
+	}
+	return &llm.ModelPricing{InputPerMillion: input, OutputPerMillion: output}
+}
+
+// weightedKeys converts configured weighted keys to key pool entries.
+func weightedKeys(keys []config.WeightedAPIKey) []keypool.Key {
+	out := make([]keypool.Key, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, keypool.Key{Value: k.Key, Weight: k.Weight, DailyQuota: k.DailyQuota})
+	}
+	return out
+}
+
+// slowRequestThresholds collects the per-provider slow request overrides.
+func slowRequestThresholds(cfg config.GRPCAppConfig) map[string]time.Duration {
+	out := make(map[string]time.Duration)
+	p := cfg.LLM.Providers
+	for name, d := range map[string]*time.Duration{
+		"dashscope":  p.DashScope.SlowRequestThreshold,
+		"openrouter": p.OpenRouter.SlowRequestThreshold,
+		"azure":      p.Azure.SlowRequestThreshold,
+	} {
+		if d != nil {
+			out[name] = *d
+		}
+	}
+	return out
+}
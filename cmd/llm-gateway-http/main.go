@@ -25,7 +25,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	srv, err := httpgateway.New(cfg.HTTP.Listen, cfg.GRPC.Target, cfg.GRPC.Insecure)
+	srv, err := httpgateway.New(cfg.HTTP.Listen, cfg.GRPC.Target, cfg.GRPC.Insecure,
+		httpgateway.WithShutdownTimeout(cfg.HTTP.ShutdownTimeout),
+		httpgateway.WithJSONFieldNames(cfg.HTTP.JSONFieldNames),
+		httpgateway.WithModelsCacheTTL(cfg.HTTP.ModelsCacheTTL),
+		httpgateway.WithRequestValidation(cfg.HTTP.ValidateRequests),
+		httpgateway.WithTrailerHeaders(cfg.HTTP.TrailerHeaders),
+	)
 	if err != nil {
 		slog.Error("create http gateway failed", "error", err)
 		os.Exit(1)
@@ -0,0 +1,302 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: llmgateway/v1/capabilities.proto
+
+package llmgatewayv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetCapabilitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	mi := &file_llmgateway_v1_capabilities_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_capabilities_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_capabilities_proto_rawDescGZIP(), []int{0}
+}
+
+type GetCapabilitiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Gateway build version, e.g. "v1.2.3" ("dev" for local builds).
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// LLMGatewayService method names this gateway accepts, sorted; methods
+	// disabled by configuration are omitted.
+	Operations []string `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Whether streamed chat completions are enabled and at least one model can stream.
+	Streaming bool `protobuf:"varint,3,opt,name=streaming,proto3" json:"streaming,omitempty"`
+	// Whether at least one model is tagged "tools".
+	ToolCalling bool `protobuf:"varint,4,opt,name=tool_calling,json=toolCalling,proto3" json:"tool_calling,omitempty"`
+	// Whether at least one model is tagged "vision".
+	Vision bool `protobuf:"varint,5,opt,name=vision,proto3" json:"vision,omitempty"`
+	// Per-model capabilities, sorted by id.
+	Models        []*ModelCapabilities `protobuf:"bytes,6,rep,name=models,proto3" json:"models,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesResponse) Reset() {
+	*x = GetCapabilitiesResponse{}
+	mi := &file_llmgateway_v1_capabilities_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *GetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_capabilities_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_capabilities_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetCapabilitiesResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetCapabilitiesResponse) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *GetCapabilitiesResponse) GetStreaming() bool {
+	if x != nil {
+		return x.Streaming
+	}
+	return false
+}
+
+func (x *GetCapabilitiesResponse) GetToolCalling() bool {
+	if x != nil {
+		return x.ToolCalling
+	}
+	return false
+}
+
+func (x *GetCapabilitiesResponse) GetVision() bool {
+	if x != nil {
+		return x.Vision
+	}
+	return false
+}
+
+func (x *GetCapabilitiesResponse) GetModels() []*ModelCapabilities {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type ModelCapabilities struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Configured capability tags, e.g. "chat", "vision", "tools".
+	Capabilities []string `protobuf:"bytes,2,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// Whether the model's provider supports streamed chat completions.
+	Streaming bool `protobuf:"varint,3,opt,name=streaming,proto3" json:"streaming,omitempty"`
+	// Whether the model's provider serves legacy text completions (/v1/completions).
+	TextCompletions bool `protobuf:"varint,4,opt,name=text_completions,json=textCompletions,proto3" json:"text_completions,omitempty"`
+	// Whether the model's provider can count prompt tokens upstream.
+	TokenCounting bool `protobuf:"varint,5,opt,name=token_counting,json=tokenCounting,proto3" json:"token_counting,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelCapabilities) Reset() {
+	*x = ModelCapabilities{}
+	mi := &file_llmgateway_v1_capabilities_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelCapabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelCapabilities) ProtoMessage() {}
+
+func (x *ModelCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_capabilities_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelCapabilities.ProtoReflect.Descriptor instead.
+func (*ModelCapabilities) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_capabilities_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ModelCapabilities) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ModelCapabilities) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *ModelCapabilities) GetStreaming() bool {
+	if x != nil {
+		return x.Streaming
+	}
+	return false
+}
+
+func (x *ModelCapabilities) GetTextCompletions() bool {
+	if x != nil {
+		return x.TextCompletions
+	}
+	return false
+}
+
+func (x *ModelCapabilities) GetTokenCounting() bool {
+	if x != nil {
+		return x.TokenCounting
+	}
+	return false
+}
+
+var File_llmgateway_v1_capabilities_proto protoreflect.FileDescriptor
+
+const file_llmgateway_v1_capabilities_proto_rawDesc = "" +
+	"\n" +
+	" llmgateway/v1/capabilities.proto\x12\rllmgateway.v1\"\x18\n" +
+	"\x16GetCapabilitiesRequest\"\xe6\x01\n" +
+	"\x17GetCapabilitiesResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\tR\n" +
+	"operations\x12\x1c\n" +
+	"\tstreaming\x18\x03 \x01(\bR\tstreaming\x12!\n" +
+	"\ftool_calling\x18\x04 \x01(\bR\vtoolCalling\x12\x16\n" +
+	"\x06vision\x18\x05 \x01(\bR\x06vision\x128\n" +
+	"\x06models\x18\x06 \x03(\v2 .llmgateway.v1.ModelCapabilitiesR\x06models\"\xb7\x01\n" +
+	"\x11ModelCapabilities\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\fcapabilities\x18\x02 \x03(\tR\fcapabilities\x12\x1c\n" +
+	"\tstreaming\x18\x03 \x01(\bR\tstreaming\x12)\n" +
+	"\x10text_completions\x18\x04 \x01(\bR\x0ftextCompletions\x12%\n" +
+	"\x0etoken_counting\x18\x05 \x01(\bR\rtokenCountingBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
+
+var (
+	file_llmgateway_v1_capabilities_proto_rawDescOnce sync.Once
+	file_llmgateway_v1_capabilities_proto_rawDescData []byte
+)
+
+func file_llmgateway_v1_capabilities_proto_rawDescGZIP() []byte {
+	file_llmgateway_v1_capabilities_proto_rawDescOnce.Do(func() {
+		file_llmgateway_v1_capabilities_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_llmgateway_v1_capabilities_proto_rawDesc), len(file_llmgateway_v1_capabilities_proto_rawDesc)))
+	})
+	return file_llmgateway_v1_capabilities_proto_rawDescData
+}
+
+var file_llmgateway_v1_capabilities_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_llmgateway_v1_capabilities_proto_goTypes = []any{
+	(*GetCapabilitiesRequest)(nil),  // 0: llmgateway.v1.GetCapabilitiesRequest
+	(*GetCapabilitiesResponse)(nil), // 1: llmgateway.v1.GetCapabilitiesResponse
+	(*ModelCapabilities)(nil),       // 2: llmgateway.v1.ModelCapabilities
+}
+var file_llmgateway_v1_capabilities_proto_depIdxs = []int32{
+	2, // 0: llmgateway.v1.GetCapabilitiesResponse.models:type_name -> llmgateway.v1.ModelCapabilities
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_llmgateway_v1_capabilities_proto_init() }
+func file_llmgateway_v1_capabilities_proto_init() {
+	if File_llmgateway_v1_capabilities_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_capabilities_proto_rawDesc), len(file_llmgateway_v1_capabilities_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_llmgateway_v1_capabilities_proto_goTypes,
+		DependencyIndexes: file_llmgateway_v1_capabilities_proto_depIdxs,
+		MessageInfos:      file_llmgateway_v1_capabilities_proto_msgTypes,
+	}.Build()
+	File_llmgateway_v1_capabilities_proto = out.File
+	file_llmgateway_v1_capabilities_proto_goTypes = nil
+	file_llmgateway_v1_capabilities_proto_depIdxs = nil
+}
@@ -86,7 +86,9 @@ type ContentPart struct {
 	// Text content (when type = "text").
 	Text string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
 	// Image URL content (when type = "image_url").
-	ImageUrl      *ImageURL `protobuf:"bytes,3,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	ImageUrl *ImageURL `protobuf:"bytes,3,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	// Optional prompt caching breakpoint.
+	CacheControl  *CacheControl `protobuf:"bytes,4,opt,name=cache_control,json=cacheControl,proto3" json:"cache_control,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -142,6 +144,60 @@ func (x *ContentPart) GetImageUrl() *ImageURL {
 	return nil
 }
 
+func (x *ContentPart) GetCacheControl() *CacheControl {
+	if x != nil {
+		return x.CacheControl
+	}
+	return nil
+}
+
+// CacheControl marks a prompt prefix as cacheable (Anthropic prompt caching).
+// Forwarded by providers that support it (OpenRouter), ignored by others.
+type CacheControl struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only "ephemeral" is supported.
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheControl) Reset() {
+	*x = CacheControl{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheControl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheControl) ProtoMessage() {}
+
+func (x *CacheControl) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheControl.ProtoReflect.Descriptor instead.
+func (*CacheControl) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CacheControl) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
 // One chat message (OpenAI-style).
 // Supports both simple text content and multimodal content (text + images).
 type ChatMessage struct {
@@ -150,15 +206,21 @@ type ChatMessage struct {
 	Role string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
 	// Content can be either a string (for text-only) or an array of ContentPart (for vision).
 	// In JSON: "content": "hello" or "content": [{"type": "text", "text": "hello"}, {"type": "image_url", ...}]
-	Content       *structpb.Value `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	Name          string          `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Parts may carry "cache_control": {"type": "ephemeral"} (see CacheControl).
+	Content *structpb.Value `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Name    string          `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Marks the whole message as a prompt caching breakpoint.
+	CacheControl *CacheControl `protobuf:"bytes,4,opt,name=cache_control,json=cacheControl,proto3" json:"cache_control,omitempty"`
+	// Audio output of an assistant response; set when the request's
+	// modalities include "audio". content keeps the text, if any.
+	Audio         *AudioContent `protobuf:"bytes,5,opt,name=audio,proto3" json:"audio,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ChatMessage) Reset() {
 	*x = ChatMessage{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[2]
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -170,7 +232,7 @@ func (x *ChatMessage) String() string {
 func (*ChatMessage) ProtoMessage() {}
 
 func (x *ChatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[2]
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -183,7 +245,7 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
 func (*ChatMessage) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{2}
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ChatMessage) GetRole() string {
@@ -207,18 +269,121 @@ func (x *ChatMessage) GetName() string {
 	return ""
 }
 
+func (x *ChatMessage) GetCacheControl() *CacheControl {
+	if x != nil {
+		return x.CacheControl
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetAudio() *AudioContent {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+// Audio produced by the model.
+type AudioContent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Base64-encoded audio bytes.
+	Data string `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// e.g. "wav", "mp3"; the format requested in AudioOutputOptions.
+	Format     string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Transcript string `protobuf:"bytes,4,opt,name=transcript,proto3" json:"transcript,omitempty"`
+	// unix seconds; 0 if unknown
+	ExpiresAt     int64 `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AudioContent) Reset() {
+	*x = AudioContent{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioContent) ProtoMessage() {}
+
+func (x *AudioContent) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioContent.ProtoReflect.Descriptor instead.
+func (*AudioContent) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AudioContent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AudioContent) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+func (x *AudioContent) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *AudioContent) GetTranscript() string {
+	if x != nil {
+		return x.Transcript
+	}
+	return ""
+}
+
+func (x *AudioContent) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
 type TokenUsage struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	PromptTokens     uint32                 `protobuf:"varint,1,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
 	CompletionTokens uint32                 `protobuf:"varint,2,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
 	TotalTokens      uint32                 `protobuf:"varint,3,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Optional breakdowns below are 0 when the provider doesn't report them.
+	// Parts of prompt_tokens read from / written to the provider's prompt cache.
+	CacheReadTokens  uint32 `protobuf:"varint,4,opt,name=cache_read_tokens,json=cacheReadTokens,proto3" json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens uint32 `protobuf:"varint,5,opt,name=cache_write_tokens,json=cacheWriteTokens,proto3" json:"cache_write_tokens,omitempty"`
+	// Part of completion_tokens spent on hidden reasoning.
+	ReasoningTokens uint32 `protobuf:"varint,6,opt,name=reasoning_tokens,json=reasoningTokens,proto3" json:"reasoning_tokens,omitempty"`
+	// Audio parts of prompt_tokens and completion_tokens.
+	PromptAudioTokens     uint32 `protobuf:"varint,7,opt,name=prompt_audio_tokens,json=promptAudioTokens,proto3" json:"prompt_audio_tokens,omitempty"`
+	CompletionAudioTokens uint32 `protobuf:"varint,8,opt,name=completion_audio_tokens,json=completionAudioTokens,proto3" json:"completion_audio_tokens,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *TokenUsage) Reset() {
 	*x = TokenUsage{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[3]
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -230,7 +395,106 @@ func (x *TokenUsage) String() string {
 func (*TokenUsage) ProtoMessage() {}
 
 func (x *TokenUsage) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[3]
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenUsage.ProtoReflect.Descriptor instead.
+func (*TokenUsage) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TokenUsage) GetPromptTokens() uint32 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetCompletionTokens() uint32 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetTotalTokens() uint32 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetCacheReadTokens() uint32 {
+	if x != nil {
+		return x.CacheReadTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetCacheWriteTokens() uint32 {
+	if x != nil {
+		return x.CacheWriteTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetReasoningTokens() uint32 {
+	if x != nil {
+		return x.ReasoningTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetPromptAudioTokens() uint32 {
+	if x != nil {
+		return x.PromptAudioTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetCompletionAudioTokens() uint32 {
+	if x != nil {
+		return x.CompletionAudioTokens
+	}
+	return 0
+}
+
+type ChatCompletionChoice struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Index   uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Message *ChatMessage           `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// e.g. "stop", "length", "tool_calls".
+	FinishReason string `protobuf:"bytes,3,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+	// Message content before the gateway stripped reasoning tag blocks (e.g.
+	// <think>); set only when the model is configured to keep it.
+	RawContent    string `protobuf:"bytes,4,opt,name=raw_content,json=rawContent,proto3" json:"raw_content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatCompletionChoice) Reset() {
+	*x = ChatCompletionChoice{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatCompletionChoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatCompletionChoice) ProtoMessage() {}
+
+func (x *ChatCompletionChoice) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -241,57 +505,636 @@ func (x *TokenUsage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TokenUsage.ProtoReflect.Descriptor instead.
-func (*TokenUsage) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ChatCompletionChoice.ProtoReflect.Descriptor instead.
+func (*ChatCompletionChoice) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChatCompletionChoice) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ChatCompletionChoice) GetMessage() *ChatMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ChatCompletionChoice) GetFinishReason() string {
+	if x != nil {
+		return x.FinishReason
+	}
+	return ""
+}
+
+func (x *ChatCompletionChoice) GetRawContent() string {
+	if x != nil {
+		return x.RawContent
+	}
+	return ""
+}
+
+type CreateChatCompletionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Routed model id, e.g. "openai/gpt-4.1-mini".
+	Model    string         `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Messages []*ChatMessage `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	// Optional tuning knobs (minimal subset).
+	// Unset uses the model's configured default temperature, if any, else the
+	// provider's default; an explicit 0 is sent as 0.
+	Temperature *float64 `protobuf:"fixed64,3,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`
+	// Unset leaves the limit to the provider; an explicit 0 is rejected.
+	MaxTokens *uint32 `protobuf:"varint,4,opt,name=max_tokens,json=maxTokens,proto3,oneof" json:"max_tokens,omitempty"`
+	// Optional user identifier for analytics/rate-limit.
+	User string `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	// Up to 4 sequences where the upstream stops generating.
+	Stop []string `protobuf:"bytes,14,rep,name=stop,proto3" json:"stop,omitempty"`
+	// Number of choices to generate, 1 to 128. Unset leaves it to the provider (1).
+	N *uint32 `protobuf:"varint,15,opt,name=n,proto3,oneof" json:"n,omitempty"`
+	// Optional output modalities, e.g. ["text"] or ["text", "audio"].
+	Modalities []string `protobuf:"bytes,6,rep,name=modalities,proto3" json:"modalities,omitempty"`
+	// Audio output options; required when modalities includes "audio".
+	Audio *AudioOutputOptions `protobuf:"bytes,7,opt,name=audio,proto3" json:"audio,omitempty"`
+	// OpenRouter upstream routing preferences; ignored by other providers.
+	Provider *ProviderPreferences `protobuf:"bytes,8,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Optional OpenAI-style service tier: "auto", "default" or "flex".
+	// Forwarded to providers that support it; "flex" requests also yield to
+	// other traffic when the gateway's upstream slots are contended.
+	ServiceTier string `protobuf:"bytes,9,opt,name=service_tier,json=serviceTier,proto3" json:"service_tier,omitempty"`
+	// Optional logical conversation this request belongs to, for audit and usage
+	// grouping (the gateway stays stateless). Defaults to the x-conversation-id header.
+	ConversationId string `protobuf:"bytes,10,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	// Optional extra response sections, for debugging. "prompt" echoes the
+	// normalized prompt sent upstream (see PromptEcho); it is only honored for
+	// callers with the admin scope and silently ignored otherwise.
+	// Non-streaming only.
+	Include []string `protobuf:"bytes,11,rep,name=include,proto3" json:"include,omitempty"`
+	// Optional tracking metadata forwarded to providers that accept it (e.g.
+	// OpenRouter "metadata"). Keys outside the server's allowlist are dropped.
+	Metadata map[string]string `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Stream the model's reasoning (e.g. QwQ, DeepSeek-R1) as delta.reasoning,
+	// separately from content. Reasoning is dropped unless set. Streaming only.
+	IncludeReasoning bool `protobuf:"varint,13,opt,name=include_reasoning,json=includeReasoning,proto3" json:"include_reasoning,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateChatCompletionRequest) Reset() {
+	*x = CreateChatCompletionRequest{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatCompletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatCompletionRequest) ProtoMessage() {}
+
+func (x *CreateChatCompletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatCompletionRequest.ProtoReflect.Descriptor instead.
+func (*CreateChatCompletionRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateChatCompletionRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateChatCompletionRequest) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionRequest) GetTemperature() float64 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *CreateChatCompletionRequest) GetMaxTokens() uint32 {
+	if x != nil && x.MaxTokens != nil {
+		return *x.MaxTokens
+	}
+	return 0
+}
+
+func (x *CreateChatCompletionRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateChatCompletionRequest) GetStop() []string {
+	if x != nil {
+		return x.Stop
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionRequest) GetN() uint32 {
+	if x != nil && x.N != nil {
+		return *x.N
+	}
+	return 0
+}
+
+func (x *CreateChatCompletionRequest) GetModalities() []string {
+	if x != nil {
+		return x.Modalities
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionRequest) GetAudio() *AudioOutputOptions {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionRequest) GetProvider() *ProviderPreferences {
+	if x != nil {
+		return x.Provider
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionRequest) GetServiceTier() string {
+	if x != nil {
+		return x.ServiceTier
+	}
+	return ""
+}
+
+func (x *CreateChatCompletionRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *CreateChatCompletionRequest) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionRequest) GetIncludeReasoning() bool {
+	if x != nil {
+		return x.IncludeReasoning
+	}
+	return false
+}
+
+// OpenRouter provider routing preferences (https://openrouter.ai/docs/features/provider-routing).
+type ProviderPreferences struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Upstream providers to try first, in order, e.g. ["anthropic", "openai"].
+	Order []string `protobuf:"bytes,1,rep,name=order,proto3" json:"order,omitempty"`
+	// Whether providers outside order may be used. Unset means OpenRouter's default (true).
+	AllowFallbacks *bool `protobuf:"varint,2,opt,name=allow_fallbacks,json=allowFallbacks,proto3,oneof" json:"allow_fallbacks,omitempty"`
+	// Only route to providers that support every parameter in the request.
+	RequireParameters bool `protobuf:"varint,3,opt,name=require_parameters,json=requireParameters,proto3" json:"require_parameters,omitempty"`
+	// "allow" or "deny". Unset means OpenRouter's default.
+	DataCollection string `protobuf:"bytes,4,opt,name=data_collection,json=dataCollection,proto3" json:"data_collection,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProviderPreferences) Reset() {
+	*x = ProviderPreferences{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderPreferences) ProtoMessage() {}
+
+func (x *ProviderPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderPreferences.ProtoReflect.Descriptor instead.
+func (*ProviderPreferences) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProviderPreferences) GetOrder() []string {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *ProviderPreferences) GetAllowFallbacks() bool {
+	if x != nil && x.AllowFallbacks != nil {
+		return *x.AllowFallbacks
+	}
+	return false
+}
+
+func (x *ProviderPreferences) GetRequireParameters() bool {
+	if x != nil {
+		return x.RequireParameters
+	}
+	return false
+}
+
+func (x *ProviderPreferences) GetDataCollection() string {
+	if x != nil {
+		return x.DataCollection
+	}
+	return ""
+}
+
+type AudioOutputOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g. "alloy".
+	Voice string `protobuf:"bytes,1,opt,name=voice,proto3" json:"voice,omitempty"`
+	// e.g. "wav", "mp3".
+	Format        string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AudioOutputOptions) Reset() {
+	*x = AudioOutputOptions{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioOutputOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioOutputOptions) ProtoMessage() {}
+
+func (x *AudioOutputOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioOutputOptions.ProtoReflect.Descriptor instead.
+func (*AudioOutputOptions) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AudioOutputOptions) GetVoice() string {
+	if x != nil {
+		return x.Voice
+	}
+	return ""
+}
+
+func (x *AudioOutputOptions) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type CreateChatCompletionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// unix seconds
+	Created int64                   `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Model   string                  `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Choices []*ChatCompletionChoice `protobuf:"bytes,4,rep,name=choices,proto3" json:"choices,omitempty"`
+	Usage   *TokenUsage             `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Set only when requested with include: ["prompt"] by an admin caller.
+	Prompt        *PromptEcho `protobuf:"bytes,6,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatCompletionResponse) Reset() {
+	*x = CreateChatCompletionResponse{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatCompletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatCompletionResponse) ProtoMessage() {}
+
+func (x *CreateChatCompletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatCompletionResponse.ProtoReflect.Descriptor instead.
+func (*CreateChatCompletionResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateChatCompletionResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateChatCompletionResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *CreateChatCompletionResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateChatCompletionResponse) GetChoices() []*ChatCompletionChoice {
+	if x != nil {
+		return x.Choices
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionResponse) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionResponse) GetPrompt() *PromptEcho {
+	if x != nil {
+		return x.Prompt
+	}
+	return nil
+}
+
+// The prompt as the gateway sent it upstream, after routing and normalization.
+type PromptEcho struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Upstream model name.
+	Model         string         `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Messages      []*ChatMessage `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromptEcho) Reset() {
+	*x = PromptEcho{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromptEcho) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromptEcho) ProtoMessage() {}
+
+func (x *PromptEcho) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromptEcho.ProtoReflect.Descriptor instead.
+func (*PromptEcho) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PromptEcho) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *PromptEcho) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type CreateChatCompletionStreamRequest struct {
+	state   protoimpl.MessageState       `protogen:"open.v1"`
+	Request *CreateChatCompletionRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// Also send each choice's fully assembled tool calls on its final chunk
+	// (the one with finish_reason), in addition to the streamed fragments.
+	AssembleToolCalls bool `protobuf:"varint,2,opt,name=assemble_tool_calls,json=assembleToolCalls,proto3" json:"assemble_tool_calls,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateChatCompletionStreamRequest) Reset() {
+	*x = CreateChatCompletionStreamRequest{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatCompletionStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatCompletionStreamRequest) ProtoMessage() {}
+
+func (x *CreateChatCompletionStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatCompletionStreamRequest.ProtoReflect.Descriptor instead.
+func (*CreateChatCompletionStreamRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateChatCompletionStreamRequest) GetRequest() *CreateChatCompletionRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *CreateChatCompletionStreamRequest) GetAssembleToolCalls() bool {
+	if x != nil {
+		return x.AssembleToolCalls
+	}
+	return false
+}
+
+// Streaming chunk shape (minimal).
+type CreateChatCompletionStreamResponse struct {
+	state         protoimpl.MessageState              `protogen:"open.v1"`
+	Id            string                              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Created       int64                               `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Model         string                              `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Choices       []*CreateChatCompletionStreamChoice `protobuf:"bytes,4,rep,name=choices,proto3" json:"choices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatCompletionStreamResponse) Reset() {
+	*x = CreateChatCompletionStreamResponse{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatCompletionStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatCompletionStreamResponse) ProtoMessage() {}
+
+func (x *CreateChatCompletionStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatCompletionStreamResponse.ProtoReflect.Descriptor instead.
+func (*CreateChatCompletionStreamResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateChatCompletionStreamResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-func (x *TokenUsage) GetPromptTokens() uint32 {
+func (x *CreateChatCompletionStreamResponse) GetCreated() int64 {
 	if x != nil {
-		return x.PromptTokens
+		return x.Created
 	}
 	return 0
 }
 
-func (x *TokenUsage) GetCompletionTokens() uint32 {
+func (x *CreateChatCompletionStreamResponse) GetModel() string {
 	if x != nil {
-		return x.CompletionTokens
+		return x.Model
 	}
-	return 0
+	return ""
 }
 
-func (x *TokenUsage) GetTotalTokens() uint32 {
+func (x *CreateChatCompletionStreamResponse) GetChoices() []*CreateChatCompletionStreamChoice {
 	if x != nil {
-		return x.TotalTokens
+		return x.Choices
 	}
-	return 0
+	return nil
 }
 
-type ChatCompletionChoice struct {
-	state   protoimpl.MessageState `protogen:"open.v1"`
-	Index   uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Message *ChatMessage           `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	// e.g. "stop", "length", "tool_calls".
-	FinishReason  string `protobuf:"bytes,3,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+type CreateChatCompletionStreamChoice struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Index        uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Delta        *ChatCompletionDelta   `protobuf:"bytes,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	FinishReason string                 `protobuf:"bytes,3,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+	// Complete tool calls of this choice; only set on its final chunk when
+	// assemble_tool_calls was requested.
+	ToolCalls     []*ToolCall `protobuf:"bytes,4,rep,name=tool_calls,json=toolCalls,proto3" json:"tool_calls,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatCompletionChoice) Reset() {
-	*x = ChatCompletionChoice{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[4]
+func (x *CreateChatCompletionStreamChoice) Reset() {
+	*x = CreateChatCompletionStreamChoice{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatCompletionChoice) String() string {
+func (x *CreateChatCompletionStreamChoice) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatCompletionChoice) ProtoMessage() {}
+func (*CreateChatCompletionStreamChoice) ProtoMessage() {}
 
-func (x *ChatCompletionChoice) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[4]
+func (x *CreateChatCompletionStreamChoice) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -302,61 +1145,68 @@ func (x *ChatCompletionChoice) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatCompletionChoice.ProtoReflect.Descriptor instead.
-func (*ChatCompletionChoice) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use CreateChatCompletionStreamChoice.ProtoReflect.Descriptor instead.
+func (*CreateChatCompletionStreamChoice) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ChatCompletionChoice) GetIndex() uint32 {
+func (x *CreateChatCompletionStreamChoice) GetIndex() uint32 {
 	if x != nil {
 		return x.Index
 	}
 	return 0
 }
 
-func (x *ChatCompletionChoice) GetMessage() *ChatMessage {
+func (x *CreateChatCompletionStreamChoice) GetDelta() *ChatCompletionDelta {
 	if x != nil {
-		return x.Message
+		return x.Delta
 	}
 	return nil
 }
 
-func (x *ChatCompletionChoice) GetFinishReason() string {
+func (x *CreateChatCompletionStreamChoice) GetFinishReason() string {
 	if x != nil {
 		return x.FinishReason
 	}
 	return ""
 }
 
-type CreateChatCompletionRequest struct {
+func (x *CreateChatCompletionStreamChoice) GetToolCalls() []*ToolCall {
+	if x != nil {
+		return x.ToolCalls
+	}
+	return nil
+}
+
+type ChatCompletionDelta struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Routed model id, e.g. "openai/gpt-4.1-mini".
-	Model    string         `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
-	Messages []*ChatMessage `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
-	// Optional tuning knobs (minimal subset).
-	Temperature float64 `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
-	MaxTokens   uint32  `protobuf:"varint,4,opt,name=max_tokens,json=maxTokens,proto3" json:"max_tokens,omitempty"`
-	// Optional user identifier for analytics/rate-limit.
-	User          string `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	// In practice you may only stream content deltas; role may appear in the first chunk.
+	Role    string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Content string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Tool call fragments, keyed by index: id, type and function.name arrive
+	// once; function.arguments is split across deltas and must be concatenated.
+	ToolCalls []*ToolCall `protobuf:"bytes,3,rep,name=tool_calls,json=toolCalls,proto3" json:"tool_calls,omitempty"`
+	// Reasoning text of reasoning models; only sent with include_reasoning.
+	Reasoning     string `protobuf:"bytes,4,opt,name=reasoning,proto3" json:"reasoning,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatCompletionRequest) Reset() {
-	*x = CreateChatCompletionRequest{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[5]
+func (x *ChatCompletionDelta) Reset() {
+	*x = ChatCompletionDelta{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatCompletionRequest) String() string {
+func (x *ChatCompletionDelta) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatCompletionRequest) ProtoMessage() {}
+func (*ChatCompletionDelta) ProtoMessage() {}
 
-func (x *CreateChatCompletionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[5]
+func (x *ChatCompletionDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -367,73 +1217,66 @@ func (x *CreateChatCompletionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatCompletionRequest.ProtoReflect.Descriptor instead.
-func (*CreateChatCompletionRequest) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ChatCompletionDelta.ProtoReflect.Descriptor instead.
+func (*ChatCompletionDelta) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *CreateChatCompletionRequest) GetModel() string {
+func (x *ChatCompletionDelta) GetRole() string {
 	if x != nil {
-		return x.Model
+		return x.Role
 	}
 	return ""
 }
 
-func (x *CreateChatCompletionRequest) GetMessages() []*ChatMessage {
-	if x != nil {
-		return x.Messages
-	}
-	return nil
-}
-
-func (x *CreateChatCompletionRequest) GetTemperature() float64 {
+func (x *ChatCompletionDelta) GetContent() string {
 	if x != nil {
-		return x.Temperature
+		return x.Content
 	}
-	return 0
+	return ""
 }
 
-func (x *CreateChatCompletionRequest) GetMaxTokens() uint32 {
+func (x *ChatCompletionDelta) GetToolCalls() []*ToolCall {
 	if x != nil {
-		return x.MaxTokens
+		return x.ToolCalls
 	}
-	return 0
+	return nil
 }
 
-func (x *CreateChatCompletionRequest) GetUser() string {
+func (x *ChatCompletionDelta) GetReasoning() string {
 	if x != nil {
-		return x.User
+		return x.Reasoning
 	}
 	return ""
 }
 
-type CreateChatCompletionResponse struct {
+// A function call requested by the model (OpenAI-style).
+type ToolCall struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// unix seconds
-	Created       int64                   `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
-	Model         string                  `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
-	Choices       []*ChatCompletionChoice `protobuf:"bytes,4,rep,name=choices,proto3" json:"choices,omitempty"`
-	Usage         *TokenUsage             `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	Index uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Id    string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// e.g. "function".
+	Type          string            `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Function      *ToolCallFunction `protobuf:"bytes,4,opt,name=function,proto3" json:"function,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatCompletionResponse) Reset() {
-	*x = CreateChatCompletionResponse{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[6]
+func (x *ToolCall) Reset() {
+	*x = ToolCall{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatCompletionResponse) String() string {
+func (x *ToolCall) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatCompletionResponse) ProtoMessage() {}
+func (*ToolCall) ProtoMessage() {}
 
-func (x *CreateChatCompletionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[6]
+func (x *ToolCall) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,68 +1287,63 @@ func (x *CreateChatCompletionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatCompletionResponse.ProtoReflect.Descriptor instead.
-func (*CreateChatCompletionResponse) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *CreateChatCompletionResponse) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+// Deprecated: Use ToolCall.ProtoReflect.Descriptor instead.
+func (*ToolCall) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *CreateChatCompletionResponse) GetCreated() int64 {
+func (x *ToolCall) GetIndex() uint32 {
 	if x != nil {
-		return x.Created
+		return x.Index
 	}
 	return 0
 }
 
-func (x *CreateChatCompletionResponse) GetModel() string {
+func (x *ToolCall) GetId() string {
 	if x != nil {
-		return x.Model
+		return x.Id
 	}
 	return ""
 }
 
-func (x *CreateChatCompletionResponse) GetChoices() []*ChatCompletionChoice {
+func (x *ToolCall) GetType() string {
 	if x != nil {
-		return x.Choices
+		return x.Type
 	}
-	return nil
+	return ""
 }
 
-func (x *CreateChatCompletionResponse) GetUsage() *TokenUsage {
+func (x *ToolCall) GetFunction() *ToolCallFunction {
 	if x != nil {
-		return x.Usage
+		return x.Function
 	}
 	return nil
 }
 
-type CreateChatCompletionStreamRequest struct {
-	state         protoimpl.MessageState       `protogen:"open.v1"`
-	Request       *CreateChatCompletionRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+type ToolCallFunction struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// JSON-encoded arguments.
+	Arguments     string `protobuf:"bytes,2,opt,name=arguments,proto3" json:"arguments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatCompletionStreamRequest) Reset() {
-	*x = CreateChatCompletionStreamRequest{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[7]
+func (x *ToolCallFunction) Reset() {
+	*x = ToolCallFunction{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatCompletionStreamRequest) String() string {
+func (x *ToolCallFunction) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatCompletionStreamRequest) ProtoMessage() {}
+func (*ToolCallFunction) ProtoMessage() {}
 
-func (x *CreateChatCompletionStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[7]
+func (x *ToolCallFunction) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,44 +1354,56 @@ func (x *CreateChatCompletionStreamRequest) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatCompletionStreamRequest.ProtoReflect.Descriptor instead.
-func (*CreateChatCompletionStreamRequest) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ToolCallFunction.ProtoReflect.Descriptor instead.
+func (*ToolCallFunction) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *CreateChatCompletionStreamRequest) GetRequest() *CreateChatCompletionRequest {
+func (x *ToolCallFunction) GetName() string {
 	if x != nil {
-		return x.Request
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-// Streaming chunk shape (minimal).
-type CreateChatCompletionStreamResponse struct {
-	state         protoimpl.MessageState              `protogen:"open.v1"`
-	Id            string                              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Created       int64                               `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
-	Model         string                              `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
-	Choices       []*CreateChatCompletionStreamChoice `protobuf:"bytes,4,rep,name=choices,proto3" json:"choices,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ToolCallFunction) GetArguments() string {
+	if x != nil {
+		return x.Arguments
+	}
+	return ""
 }
 
-func (x *CreateChatCompletionStreamResponse) Reset() {
-	*x = CreateChatCompletionStreamResponse{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[8]
+type CompareCompletionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Models to compare, run concurrently. Catalog models must declare the
+	// "chat" capability.
+	Models []string `protobuf:"bytes,1,rep,name=models,proto3" json:"models,omitempty"`
+	// The prompt sent to every model.
+	Messages    []*ChatMessage `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	Temperature *float64       `protobuf:"fixed64,3,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`
+	MaxTokens   *uint32        `protobuf:"varint,4,opt,name=max_tokens,json=maxTokens,proto3,oneof" json:"max_tokens,omitempty"`
+	User        string         `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	// Defaults to the x-conversation-id header.
+	ConversationId string `protobuf:"bytes,6,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CompareCompletionsRequest) Reset() {
+	*x = CompareCompletionsRequest{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatCompletionStreamResponse) String() string {
+func (x *CompareCompletionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatCompletionStreamResponse) ProtoMessage() {}
+func (*CompareCompletionsRequest) ProtoMessage() {}
 
-func (x *CreateChatCompletionStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[8]
+func (x *CompareCompletionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -564,63 +1414,76 @@ func (x *CreateChatCompletionStreamResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatCompletionStreamResponse.ProtoReflect.Descriptor instead.
-func (*CreateChatCompletionStreamResponse) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use CompareCompletionsRequest.ProtoReflect.Descriptor instead.
+func (*CompareCompletionsRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *CreateChatCompletionStreamResponse) GetId() string {
+func (x *CompareCompletionsRequest) GetModels() []string {
 	if x != nil {
-		return x.Id
+		return x.Models
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateChatCompletionStreamResponse) GetCreated() int64 {
+func (x *CompareCompletionsRequest) GetMessages() []*ChatMessage {
 	if x != nil {
-		return x.Created
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *CompareCompletionsRequest) GetTemperature() float64 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
 	}
 	return 0
 }
 
-func (x *CreateChatCompletionStreamResponse) GetModel() string {
+func (x *CompareCompletionsRequest) GetMaxTokens() uint32 {
+	if x != nil && x.MaxTokens != nil {
+		return *x.MaxTokens
+	}
+	return 0
+}
+
+func (x *CompareCompletionsRequest) GetUser() string {
 	if x != nil {
-		return x.Model
+		return x.User
 	}
 	return ""
 }
 
-func (x *CreateChatCompletionStreamResponse) GetChoices() []*CreateChatCompletionStreamChoice {
+func (x *CompareCompletionsRequest) GetConversationId() string {
 	if x != nil {
-		return x.Choices
+		return x.ConversationId
 	}
-	return nil
+	return ""
 }
 
-type CreateChatCompletionStreamChoice struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Delta         *ChatCompletionDelta   `protobuf:"bytes,2,opt,name=delta,proto3" json:"delta,omitempty"`
-	FinishReason  string                 `protobuf:"bytes,3,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+type CompareCompletionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per requested model, in request order.
+	Results       []*CompareCompletionsResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatCompletionStreamChoice) Reset() {
-	*x = CreateChatCompletionStreamChoice{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[9]
+func (x *CompareCompletionsResponse) Reset() {
+	*x = CompareCompletionsResponse{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatCompletionStreamChoice) String() string {
+func (x *CompareCompletionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatCompletionStreamChoice) ProtoMessage() {}
+func (*CompareCompletionsResponse) ProtoMessage() {}
 
-func (x *CreateChatCompletionStreamChoice) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[9]
+func (x *CompareCompletionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -631,56 +1494,43 @@ func (x *CreateChatCompletionStreamChoice) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatCompletionStreamChoice.ProtoReflect.Descriptor instead.
-func (*CreateChatCompletionStreamChoice) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *CreateChatCompletionStreamChoice) GetIndex() uint32 {
-	if x != nil {
-		return x.Index
-	}
-	return 0
+// Deprecated: Use CompareCompletionsResponse.ProtoReflect.Descriptor instead.
+func (*CompareCompletionsResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *CreateChatCompletionStreamChoice) GetDelta() *ChatCompletionDelta {
+func (x *CompareCompletionsResponse) GetResults() []*CompareCompletionsResult {
 	if x != nil {
-		return x.Delta
+		return x.Results
 	}
 	return nil
 }
 
-func (x *CreateChatCompletionStreamChoice) GetFinishReason() string {
-	if x != nil {
-		return x.FinishReason
-	}
-	return ""
-}
-
-type ChatCompletionDelta struct {
+type CompareCompletionsResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// In practice you may only stream content deltas; role may appear in the first chunk.
-	Role          string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
-	Content       string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// The requested model id this result belongs to.
+	Model string `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	// The model's response, including its own usage.
+	Response      *CreateChatCompletionResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatCompletionDelta) Reset() {
-	*x = ChatCompletionDelta{}
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[10]
+func (x *CompareCompletionsResult) Reset() {
+	*x = CompareCompletionsResult{}
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatCompletionDelta) String() string {
+func (x *CompareCompletionsResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatCompletionDelta) ProtoMessage() {}
+func (*CompareCompletionsResult) ProtoMessage() {}
 
-func (x *ChatCompletionDelta) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_chat_proto_msgTypes[10]
+func (x *CompareCompletionsResult) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_chat_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -691,23 +1541,23 @@ func (x *ChatCompletionDelta) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatCompletionDelta.ProtoReflect.Descriptor instead.
-func (*ChatCompletionDelta) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use CompareCompletionsResult.ProtoReflect.Descriptor instead.
+func (*CompareCompletionsResult) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_chat_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *ChatCompletionDelta) GetRole() string {
+func (x *CompareCompletionsResult) GetModel() string {
 	if x != nil {
-		return x.Role
+		return x.Model
 	}
 	return ""
 }
 
-func (x *ChatCompletionDelta) GetContent() string {
+func (x *CompareCompletionsResult) GetResponse() *CreateChatCompletionResponse {
 	if x != nil {
-		return x.Content
+		return x.Response
 	}
-	return ""
+	return nil
 }
 
 var File_llmgateway_v1_chat_proto protoreflect.FileDescriptor
@@ -717,51 +1567,134 @@ const file_llmgateway_v1_chat_proto_rawDesc = "" +
 	"\x18llmgateway/v1/chat.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\x1a\x1cgoogle/protobuf/struct.proto\"9\n" +
 	"\bImageURL\x12\x15\n" +
 	"\x03url\x18\x01 \x01(\tB\x03\xe0A\x02R\x03url\x12\x16\n" +
-	"\x06detail\x18\x02 \x01(\tR\x06detail\"p\n" +
+	"\x06detail\x18\x02 \x01(\tR\x06detail\"\xb2\x01\n" +
 	"\vContentPart\x12\x17\n" +
 	"\x04type\x18\x01 \x01(\tB\x03\xe0A\x02R\x04type\x12\x12\n" +
 	"\x04text\x18\x02 \x01(\tR\x04text\x124\n" +
-	"\timage_url\x18\x03 \x01(\v2\x17.llmgateway.v1.ImageURLR\bimageUrl\"l\n" +
+	"\timage_url\x18\x03 \x01(\v2\x17.llmgateway.v1.ImageURLR\bimageUrl\x12@\n" +
+	"\rcache_control\x18\x04 \x01(\v2\x1b.llmgateway.v1.CacheControlR\fcacheControl\"'\n" +
+	"\fCacheControl\x12\x17\n" +
+	"\x04type\x18\x01 \x01(\tB\x03\xe0A\x02R\x04type\"\xe6\x01\n" +
 	"\vChatMessage\x12\x17\n" +
 	"\x04role\x18\x01 \x01(\tB\x03\xe0A\x02R\x04role\x120\n" +
 	"\acontent\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\acontent\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\"\x81\x01\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12@\n" +
+	"\rcache_control\x18\x04 \x01(\v2\x1b.llmgateway.v1.CacheControlR\fcacheControl\x126\n" +
+	"\x05audio\x18\x05 \x01(\v2\x1b.llmgateway.v1.AudioContentB\x03\xe0A\x03R\x05audio\"\x89\x01\n" +
+	"\fAudioContent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\tR\x04data\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12\x1e\n" +
+	"\n" +
+	"transcript\x18\x04 \x01(\tR\n" +
+	"transcript\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\"\xee\x02\n" +
 	"\n" +
 	"TokenUsage\x12#\n" +
 	"\rprompt_tokens\x18\x01 \x01(\rR\fpromptTokens\x12+\n" +
 	"\x11completion_tokens\x18\x02 \x01(\rR\x10completionTokens\x12!\n" +
-	"\ftotal_tokens\x18\x03 \x01(\rR\vtotalTokens\"\x87\x01\n" +
+	"\ftotal_tokens\x18\x03 \x01(\rR\vtotalTokens\x12*\n" +
+	"\x11cache_read_tokens\x18\x04 \x01(\rR\x0fcacheReadTokens\x12,\n" +
+	"\x12cache_write_tokens\x18\x05 \x01(\rR\x10cacheWriteTokens\x12)\n" +
+	"\x10reasoning_tokens\x18\x06 \x01(\rR\x0freasoningTokens\x12.\n" +
+	"\x13prompt_audio_tokens\x18\a \x01(\rR\x11promptAudioTokens\x126\n" +
+	"\x17completion_audio_tokens\x18\b \x01(\rR\x15completionAudioTokens\"\xa8\x01\n" +
 	"\x14ChatCompletionChoice\x12\x14\n" +
 	"\x05index\x18\x01 \x01(\rR\x05index\x124\n" +
 	"\amessage\x18\x02 \x01(\v2\x1a.llmgateway.v1.ChatMessageR\amessage\x12#\n" +
-	"\rfinish_reason\x18\x03 \x01(\tR\ffinishReason\"\xca\x01\n" +
+	"\rfinish_reason\x18\x03 \x01(\tR\ffinishReason\x12\x1f\n" +
+	"\vraw_content\x18\x04 \x01(\tR\n" +
+	"rawContent\"\xdf\x05\n" +
 	"\x1bCreateChatCompletionRequest\x12\x19\n" +
 	"\x05model\x18\x01 \x01(\tB\x03\xe0A\x02R\x05model\x12;\n" +
-	"\bmessages\x18\x02 \x03(\v2\x1a.llmgateway.v1.ChatMessageB\x03\xe0A\x02R\bmessages\x12 \n" +
-	"\vtemperature\x18\x03 \x01(\x01R\vtemperature\x12\x1d\n" +
+	"\bmessages\x18\x02 \x03(\v2\x1a.llmgateway.v1.ChatMessageB\x03\xe0A\x02R\bmessages\x12%\n" +
+	"\vtemperature\x18\x03 \x01(\x01H\x00R\vtemperature\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"max_tokens\x18\x04 \x01(\rH\x01R\tmaxTokens\x88\x01\x01\x12\x12\n" +
+	"\x04user\x18\x05 \x01(\tR\x04user\x12\x12\n" +
+	"\x04stop\x18\x0e \x03(\tR\x04stop\x12\x11\n" +
+	"\x01n\x18\x0f \x01(\rH\x02R\x01n\x88\x01\x01\x12\x1e\n" +
 	"\n" +
-	"max_tokens\x18\x04 \x01(\rR\tmaxTokens\x12\x12\n" +
-	"\x04user\x18\x05 \x01(\tR\x04user\"\xce\x01\n" +
+	"modalities\x18\x06 \x03(\tR\n" +
+	"modalities\x127\n" +
+	"\x05audio\x18\a \x01(\v2!.llmgateway.v1.AudioOutputOptionsR\x05audio\x12>\n" +
+	"\bprovider\x18\b \x01(\v2\".llmgateway.v1.ProviderPreferencesR\bprovider\x12!\n" +
+	"\fservice_tier\x18\t \x01(\tR\vserviceTier\x12'\n" +
+	"\x0fconversation_id\x18\n" +
+	" \x01(\tR\x0econversationId\x12\x18\n" +
+	"\ainclude\x18\v \x03(\tR\ainclude\x12T\n" +
+	"\bmetadata\x18\f \x03(\v28.llmgateway.v1.CreateChatCompletionRequest.MetadataEntryR\bmetadata\x12+\n" +
+	"\x11include_reasoning\x18\r \x01(\bR\x10includeReasoning\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0e\n" +
+	"\f_temperatureB\r\n" +
+	"\v_max_tokensB\x04\n" +
+	"\x02_n\"\xc5\x01\n" +
+	"\x13ProviderPreferences\x12\x14\n" +
+	"\x05order\x18\x01 \x03(\tR\x05order\x12,\n" +
+	"\x0fallow_fallbacks\x18\x02 \x01(\bH\x00R\x0eallowFallbacks\x88\x01\x01\x12-\n" +
+	"\x12require_parameters\x18\x03 \x01(\bR\x11requireParameters\x12'\n" +
+	"\x0fdata_collection\x18\x04 \x01(\tR\x0edataCollectionB\x12\n" +
+	"\x10_allow_fallbacks\"B\n" +
+	"\x12AudioOutputOptions\x12\x14\n" +
+	"\x05voice\x18\x01 \x01(\tR\x05voice\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"\x81\x02\n" +
 	"\x1cCreateChatCompletionResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
 	"\acreated\x18\x02 \x01(\x03R\acreated\x12\x14\n" +
 	"\x05model\x18\x03 \x01(\tR\x05model\x12=\n" +
 	"\achoices\x18\x04 \x03(\v2#.llmgateway.v1.ChatCompletionChoiceR\achoices\x12/\n" +
-	"\x05usage\x18\x05 \x01(\v2\x19.llmgateway.v1.TokenUsageR\x05usage\"n\n" +
+	"\x05usage\x18\x05 \x01(\v2\x19.llmgateway.v1.TokenUsageR\x05usage\x121\n" +
+	"\x06prompt\x18\x06 \x01(\v2\x19.llmgateway.v1.PromptEchoR\x06prompt\"Z\n" +
+	"\n" +
+	"PromptEcho\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x126\n" +
+	"\bmessages\x18\x02 \x03(\v2\x1a.llmgateway.v1.ChatMessageR\bmessages\"\x9e\x01\n" +
 	"!CreateChatCompletionStreamRequest\x12I\n" +
-	"\arequest\x18\x01 \x01(\v2*.llmgateway.v1.CreateChatCompletionRequestB\x03\xe0A\x02R\arequest\"\xaf\x01\n" +
+	"\arequest\x18\x01 \x01(\v2*.llmgateway.v1.CreateChatCompletionRequestB\x03\xe0A\x02R\arequest\x12.\n" +
+	"\x13assemble_tool_calls\x18\x02 \x01(\bR\x11assembleToolCalls\"\xaf\x01\n" +
 	"\"CreateChatCompletionStreamResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
 	"\acreated\x18\x02 \x01(\x03R\acreated\x12\x14\n" +
 	"\x05model\x18\x03 \x01(\tR\x05model\x12I\n" +
-	"\achoices\x18\x04 \x03(\v2/.llmgateway.v1.CreateChatCompletionStreamChoiceR\achoices\"\x97\x01\n" +
+	"\achoices\x18\x04 \x03(\v2/.llmgateway.v1.CreateChatCompletionStreamChoiceR\achoices\"\xcf\x01\n" +
 	" CreateChatCompletionStreamChoice\x12\x14\n" +
 	"\x05index\x18\x01 \x01(\rR\x05index\x128\n" +
 	"\x05delta\x18\x02 \x01(\v2\".llmgateway.v1.ChatCompletionDeltaR\x05delta\x12#\n" +
-	"\rfinish_reason\x18\x03 \x01(\tR\ffinishReason\"C\n" +
+	"\rfinish_reason\x18\x03 \x01(\tR\ffinishReason\x126\n" +
+	"\n" +
+	"tool_calls\x18\x04 \x03(\v2\x17.llmgateway.v1.ToolCallR\ttoolCalls\"\x99\x01\n" +
 	"\x13ChatCompletionDelta\x12\x12\n" +
 	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\tR\acontentBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
+	"\acontent\x18\x02 \x01(\tR\acontent\x126\n" +
+	"\n" +
+	"tool_calls\x18\x03 \x03(\v2\x17.llmgateway.v1.ToolCallR\ttoolCalls\x12\x1c\n" +
+	"\treasoning\x18\x04 \x01(\tR\treasoning\"\x81\x01\n" +
+	"\bToolCall\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12;\n" +
+	"\bfunction\x18\x04 \x01(\v2\x1f.llmgateway.v1.ToolCallFunctionR\bfunction\"D\n" +
+	"\x10ToolCallFunction\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\targuments\x18\x02 \x01(\tR\targuments\"\x9c\x02\n" +
+	"\x19CompareCompletionsRequest\x12\x1b\n" +
+	"\x06models\x18\x01 \x03(\tB\x03\xe0A\x02R\x06models\x12;\n" +
+	"\bmessages\x18\x02 \x03(\v2\x1a.llmgateway.v1.ChatMessageB\x03\xe0A\x02R\bmessages\x12%\n" +
+	"\vtemperature\x18\x03 \x01(\x01H\x00R\vtemperature\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"max_tokens\x18\x04 \x01(\rH\x01R\tmaxTokens\x88\x01\x01\x12\x12\n" +
+	"\x04user\x18\x05 \x01(\tR\x04user\x12'\n" +
+	"\x0fconversation_id\x18\x06 \x01(\tR\x0econversationIdB\x0e\n" +
+	"\f_temperatureB\r\n" +
+	"\v_max_tokens\"_\n" +
+	"\x1aCompareCompletionsResponse\x12A\n" +
+	"\aresults\x18\x01 \x03(\v2'.llmgateway.v1.CompareCompletionsResultR\aresults\"y\n" +
+	"\x18CompareCompletionsResult\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12G\n" +
+	"\bresponse\x18\x02 \x01(\v2+.llmgateway.v1.CreateChatCompletionResponseR\bresponseBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
 
 var (
 	file_llmgateway_v1_chat_proto_rawDescOnce sync.Once
@@ -775,36 +1708,61 @@ func file_llmgateway_v1_chat_proto_rawDescGZIP() []byte {
 	return file_llmgateway_v1_chat_proto_rawDescData
 }
 
-var file_llmgateway_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_llmgateway_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_llmgateway_v1_chat_proto_goTypes = []any{
 	(*ImageURL)(nil),                           // 0: llmgateway.v1.ImageURL
 	(*ContentPart)(nil),                        // 1: llmgateway.v1.ContentPart
-	(*ChatMessage)(nil),                        // 2: llmgateway.v1.ChatMessage
-	(*TokenUsage)(nil),                         // 3: llmgateway.v1.TokenUsage
-	(*ChatCompletionChoice)(nil),               // 4: llmgateway.v1.ChatCompletionChoice
-	(*CreateChatCompletionRequest)(nil),        // 5: llmgateway.v1.CreateChatCompletionRequest
-	(*CreateChatCompletionResponse)(nil),       // 6: llmgateway.v1.CreateChatCompletionResponse
-	(*CreateChatCompletionStreamRequest)(nil),  // 7: llmgateway.v1.CreateChatCompletionStreamRequest
-	(*CreateChatCompletionStreamResponse)(nil), // 8: llmgateway.v1.CreateChatCompletionStreamResponse
-	(*CreateChatCompletionStreamChoice)(nil),   // 9: llmgateway.v1.CreateChatCompletionStreamChoice
-	(*ChatCompletionDelta)(nil),                // 10: llmgateway.v1.ChatCompletionDelta
-	(*structpb.Value)(nil),                     // 11: google.protobuf.Value
+	(*CacheControl)(nil),                       // 2: llmgateway.v1.CacheControl
+	(*ChatMessage)(nil),                        // 3: llmgateway.v1.ChatMessage
+	(*AudioContent)(nil),                       // 4: llmgateway.v1.AudioContent
+	(*TokenUsage)(nil),                         // 5: llmgateway.v1.TokenUsage
+	(*ChatCompletionChoice)(nil),               // 6: llmgateway.v1.ChatCompletionChoice
+	(*CreateChatCompletionRequest)(nil),        // 7: llmgateway.v1.CreateChatCompletionRequest
+	(*ProviderPreferences)(nil),                // 8: llmgateway.v1.ProviderPreferences
+	(*AudioOutputOptions)(nil),                 // 9: llmgateway.v1.AudioOutputOptions
+	(*CreateChatCompletionResponse)(nil),       // 10: llmgateway.v1.CreateChatCompletionResponse
+	(*PromptEcho)(nil),                         // 11: llmgateway.v1.PromptEcho
+	(*CreateChatCompletionStreamRequest)(nil),  // 12: llmgateway.v1.CreateChatCompletionStreamRequest
+	(*CreateChatCompletionStreamResponse)(nil), // 13: llmgateway.v1.CreateChatCompletionStreamResponse
+	(*CreateChatCompletionStreamChoice)(nil),   // 14: llmgateway.v1.CreateChatCompletionStreamChoice
+	(*ChatCompletionDelta)(nil),                // 15: llmgateway.v1.ChatCompletionDelta
+	(*ToolCall)(nil),                           // 16: llmgateway.v1.ToolCall
+	(*ToolCallFunction)(nil),                   // 17: llmgateway.v1.ToolCallFunction
+	(*CompareCompletionsRequest)(nil),          // 18: llmgateway.v1.CompareCompletionsRequest
+	(*CompareCompletionsResponse)(nil),         // 19: llmgateway.v1.CompareCompletionsResponse
+	(*CompareCompletionsResult)(nil),           // 20: llmgateway.v1.CompareCompletionsResult
+	nil,                                        // 21: llmgateway.v1.CreateChatCompletionRequest.MetadataEntry
+	(*structpb.Value)(nil),                     // 22: google.protobuf.Value
 }
 var file_llmgateway_v1_chat_proto_depIdxs = []int32{
 	0,  // 0: llmgateway.v1.ContentPart.image_url:type_name -> llmgateway.v1.ImageURL
-	11, // 1: llmgateway.v1.ChatMessage.content:type_name -> google.protobuf.Value
-	2,  // 2: llmgateway.v1.ChatCompletionChoice.message:type_name -> llmgateway.v1.ChatMessage
-	2,  // 3: llmgateway.v1.CreateChatCompletionRequest.messages:type_name -> llmgateway.v1.ChatMessage
-	4,  // 4: llmgateway.v1.CreateChatCompletionResponse.choices:type_name -> llmgateway.v1.ChatCompletionChoice
-	3,  // 5: llmgateway.v1.CreateChatCompletionResponse.usage:type_name -> llmgateway.v1.TokenUsage
-	5,  // 6: llmgateway.v1.CreateChatCompletionStreamRequest.request:type_name -> llmgateway.v1.CreateChatCompletionRequest
-	9,  // 7: llmgateway.v1.CreateChatCompletionStreamResponse.choices:type_name -> llmgateway.v1.CreateChatCompletionStreamChoice
-	10, // 8: llmgateway.v1.CreateChatCompletionStreamChoice.delta:type_name -> llmgateway.v1.ChatCompletionDelta
-	9,  // [9:9] is the sub-list for method output_type
-	9,  // [9:9] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	2,  // 1: llmgateway.v1.ContentPart.cache_control:type_name -> llmgateway.v1.CacheControl
+	22, // 2: llmgateway.v1.ChatMessage.content:type_name -> google.protobuf.Value
+	2,  // 3: llmgateway.v1.ChatMessage.cache_control:type_name -> llmgateway.v1.CacheControl
+	4,  // 4: llmgateway.v1.ChatMessage.audio:type_name -> llmgateway.v1.AudioContent
+	3,  // 5: llmgateway.v1.ChatCompletionChoice.message:type_name -> llmgateway.v1.ChatMessage
+	3,  // 6: llmgateway.v1.CreateChatCompletionRequest.messages:type_name -> llmgateway.v1.ChatMessage
+	9,  // 7: llmgateway.v1.CreateChatCompletionRequest.audio:type_name -> llmgateway.v1.AudioOutputOptions
+	8,  // 8: llmgateway.v1.CreateChatCompletionRequest.provider:type_name -> llmgateway.v1.ProviderPreferences
+	21, // 9: llmgateway.v1.CreateChatCompletionRequest.metadata:type_name -> llmgateway.v1.CreateChatCompletionRequest.MetadataEntry
+	6,  // 10: llmgateway.v1.CreateChatCompletionResponse.choices:type_name -> llmgateway.v1.ChatCompletionChoice
+	5,  // 11: llmgateway.v1.CreateChatCompletionResponse.usage:type_name -> llmgateway.v1.TokenUsage
+	11, // 12: llmgateway.v1.CreateChatCompletionResponse.prompt:type_name -> llmgateway.v1.PromptEcho
+	3,  // 13: llmgateway.v1.PromptEcho.messages:type_name -> llmgateway.v1.ChatMessage
+	7,  // 14: llmgateway.v1.CreateChatCompletionStreamRequest.request:type_name -> llmgateway.v1.CreateChatCompletionRequest
+	14, // 15: llmgateway.v1.CreateChatCompletionStreamResponse.choices:type_name -> llmgateway.v1.CreateChatCompletionStreamChoice
+	15, // 16: llmgateway.v1.CreateChatCompletionStreamChoice.delta:type_name -> llmgateway.v1.ChatCompletionDelta
+	16, // 17: llmgateway.v1.CreateChatCompletionStreamChoice.tool_calls:type_name -> llmgateway.v1.ToolCall
+	16, // 18: llmgateway.v1.ChatCompletionDelta.tool_calls:type_name -> llmgateway.v1.ToolCall
+	17, // 19: llmgateway.v1.ToolCall.function:type_name -> llmgateway.v1.ToolCallFunction
+	3,  // 20: llmgateway.v1.CompareCompletionsRequest.messages:type_name -> llmgateway.v1.ChatMessage
+	20, // 21: llmgateway.v1.CompareCompletionsResponse.results:type_name -> llmgateway.v1.CompareCompletionsResult
+	10, // 22: llmgateway.v1.CompareCompletionsResult.response:type_name -> llmgateway.v1.CreateChatCompletionResponse
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_llmgateway_v1_chat_proto_init() }
@@ -812,13 +1770,16 @@ func file_llmgateway_v1_chat_proto_init() {
 	if File_llmgateway_v1_chat_proto != nil {
 		return
 	}
+	file_llmgateway_v1_chat_proto_msgTypes[7].OneofWrappers = []any{}
+	file_llmgateway_v1_chat_proto_msgTypes[8].OneofWrappers = []any{}
+	file_llmgateway_v1_chat_proto_msgTypes[18].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_chat_proto_rawDesc), len(file_llmgateway_v1_chat_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,353 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: llmgateway/v1/completions.proto
+
+package llmgatewayv1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Legacy OpenAI text completion (/v1/completions). New clients should use
+// chat completions; this exists for older tooling that sends a bare prompt.
+type CreateCompletionRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Model  string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Prompt string                 `protobuf:"bytes,2,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	// Unset leaves the limit to the provider; an explicit 0 is rejected.
+	MaxTokens *uint32 `protobuf:"varint,3,opt,name=max_tokens,json=maxTokens,proto3,oneof" json:"max_tokens,omitempty"`
+	// Unset uses the provider default; an explicit 0 is sent upstream.
+	Temperature *float64 `protobuf:"fixed64,4,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`
+	// Up to 4 sequences where the upstream stops generating.
+	Stop []string `protobuf:"bytes,5,rep,name=stop,proto3" json:"stop,omitempty"`
+	// Optional user identifier.
+	User string `protobuf:"bytes,6,opt,name=user,proto3" json:"user,omitempty"`
+	// Optional caller-chosen id grouping related requests; see
+	// CreateChatCompletionRequest.conversation_id.
+	ConversationId string `protobuf:"bytes,7,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	// Number of choices to generate, 1 to 128. Unset leaves it to the provider (1).
+	N             *uint32 `protobuf:"varint,8,opt,name=n,proto3,oneof" json:"n,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCompletionRequest) Reset() {
+	*x = CreateCompletionRequest{}
+	mi := &file_llmgateway_v1_completions_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCompletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCompletionRequest) ProtoMessage() {}
+
+func (x *CreateCompletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_completions_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCompletionRequest.ProtoReflect.Descriptor instead.
+func (*CreateCompletionRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_completions_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateCompletionRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateCompletionRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *CreateCompletionRequest) GetMaxTokens() uint32 {
+	if x != nil && x.MaxTokens != nil {
+		return *x.MaxTokens
+	}
+	return 0
+}
+
+func (x *CreateCompletionRequest) GetTemperature() float64 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *CreateCompletionRequest) GetStop() []string {
+	if x != nil {
+		return x.Stop
+	}
+	return nil
+}
+
+func (x *CreateCompletionRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateCompletionRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *CreateCompletionRequest) GetN() uint32 {
+	if x != nil && x.N != nil {
+		return *x.N
+	}
+	return 0
+}
+
+type CompletionChoice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	FinishReason  string                 `protobuf:"bytes,3,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompletionChoice) Reset() {
+	*x = CompletionChoice{}
+	mi := &file_llmgateway_v1_completions_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompletionChoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompletionChoice) ProtoMessage() {}
+
+func (x *CompletionChoice) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_completions_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompletionChoice.ProtoReflect.Descriptor instead.
+func (*CompletionChoice) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_completions_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CompletionChoice) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CompletionChoice) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CompletionChoice) GetFinishReason() string {
+	if x != nil {
+		return x.FinishReason
+	}
+	return ""
+}
+
+type CreateCompletionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// unix seconds
+	Created       int64               `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Model         string              `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Choices       []*CompletionChoice `protobuf:"bytes,4,rep,name=choices,proto3" json:"choices,omitempty"`
+	Usage         *TokenUsage         `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCompletionResponse) Reset() {
+	*x = CreateCompletionResponse{}
+	mi := &file_llmgateway_v1_completions_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCompletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCompletionResponse) ProtoMessage() {}
+
+func (x *CreateCompletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_completions_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCompletionResponse.ProtoReflect.Descriptor instead.
+func (*CreateCompletionResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_completions_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateCompletionResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateCompletionResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *CreateCompletionResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateCompletionResponse) GetChoices() []*CompletionChoice {
+	if x != nil {
+		return x.Choices
+	}
+	return nil
+}
+
+func (x *CreateCompletionResponse) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+var File_llmgateway_v1_completions_proto protoreflect.FileDescriptor
+
+const file_llmgateway_v1_completions_proto_rawDesc = "" +
+	"\n" +
+	"\x1fllmgateway/v1/completions.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\x1a\x18llmgateway/v1/chat.proto\"\xa5\x02\n" +
+	"\x17CreateCompletionRequest\x12\x19\n" +
+	"\x05model\x18\x01 \x01(\tB\x03\xe0A\x02R\x05model\x12\x1b\n" +
+	"\x06prompt\x18\x02 \x01(\tB\x03\xe0A\x02R\x06prompt\x12\"\n" +
+	"\n" +
+	"max_tokens\x18\x03 \x01(\rH\x00R\tmaxTokens\x88\x01\x01\x12%\n" +
+	"\vtemperature\x18\x04 \x01(\x01H\x01R\vtemperature\x88\x01\x01\x12\x12\n" +
+	"\x04stop\x18\x05 \x03(\tR\x04stop\x12\x12\n" +
+	"\x04user\x18\x06 \x01(\tR\x04user\x12'\n" +
+	"\x0fconversation_id\x18\a \x01(\tR\x0econversationId\x12\x11\n" +
+	"\x01n\x18\b \x01(\rH\x02R\x01n\x88\x01\x01B\r\n" +
+	"\v_max_tokensB\x0e\n" +
+	"\f_temperatureB\x04\n" +
+	"\x02_n\"a\n" +
+	"\x10CompletionChoice\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12#\n" +
+	"\rfinish_reason\x18\x03 \x01(\tR\ffinishReason\"\xc6\x01\n" +
+	"\x18CreateCompletionResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x03R\acreated\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x129\n" +
+	"\achoices\x18\x04 \x03(\v2\x1f.llmgateway.v1.CompletionChoiceR\achoices\x12/\n" +
+	"\x05usage\x18\x05 \x01(\v2\x19.llmgateway.v1.TokenUsageR\x05usageBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
+
+var (
+	file_llmgateway_v1_completions_proto_rawDescOnce sync.Once
+	file_llmgateway_v1_completions_proto_rawDescData []byte
+)
+
+func file_llmgateway_v1_completions_proto_rawDescGZIP() []byte {
+	file_llmgateway_v1_completions_proto_rawDescOnce.Do(func() {
+		file_llmgateway_v1_completions_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_llmgateway_v1_completions_proto_rawDesc), len(file_llmgateway_v1_completions_proto_rawDesc)))
+	})
+	return file_llmgateway_v1_completions_proto_rawDescData
+}
+
+var file_llmgateway_v1_completions_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_llmgateway_v1_completions_proto_goTypes = []any{
+	(*CreateCompletionRequest)(nil),  // 0: llmgateway.v1.CreateCompletionRequest
+	(*CompletionChoice)(nil),         // 1: llmgateway.v1.CompletionChoice
+	(*CreateCompletionResponse)(nil), // 2: llmgateway.v1.CreateCompletionResponse
+	(*TokenUsage)(nil),               // 3: llmgateway.v1.TokenUsage
+}
+var file_llmgateway_v1_completions_proto_depIdxs = []int32{
+	1, // 0: llmgateway.v1.CreateCompletionResponse.choices:type_name -> llmgateway.v1.CompletionChoice
+	3, // 1: llmgateway.v1.CreateCompletionResponse.usage:type_name -> llmgateway.v1.TokenUsage
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_llmgateway_v1_completions_proto_init() }
+func file_llmgateway_v1_completions_proto_init() {
+	if File_llmgateway_v1_completions_proto != nil {
+		return
+	}
+	file_llmgateway_v1_chat_proto_init()
+	file_llmgateway_v1_completions_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_completions_proto_rawDesc), len(file_llmgateway_v1_completions_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_llmgateway_v1_completions_proto_goTypes,
+		DependencyIndexes: file_llmgateway_v1_completions_proto_depIdxs,
+		MessageInfos:      file_llmgateway_v1_completions_proto_msgTypes,
+	}.Build()
+	File_llmgateway_v1_completions_proto = out.File
+	file_llmgateway_v1_completions_proto_goTypes = nil
+	file_llmgateway_v1_completions_proto_depIdxs = nil
+}
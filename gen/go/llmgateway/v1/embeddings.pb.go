@@ -26,9 +26,14 @@ type CreateEmbeddingsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	Model string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
 	// Minimal: accept a list of input strings.
+	// Over HTTP, a single string ("input": "hello") is also accepted, as in the OpenAI API.
 	Input []string `protobuf:"bytes,2,rep,name=input,proto3" json:"input,omitempty"`
 	// Optional user identifier.
-	User          string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	User string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	// Split inputs that are too long into chunks, embed each chunk and return
+	// one mean-pooled vector per input. Requires server-side
+	// llm.limits.embeddings_auto_chunk_chars; not supported for streams.
+	AutoChunk     bool `protobuf:"varint,4,opt,name=auto_chunk,json=autoChunk,proto3" json:"auto_chunk,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -84,10 +89,19 @@ func (x *CreateEmbeddingsRequest) GetUser() string {
 	return ""
 }
 
+func (x *CreateEmbeddingsRequest) GetAutoChunk() bool {
+	if x != nil {
+		return x.AutoChunk
+	}
+	return false
+}
+
 type Embedding struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Embedding     []float32              `protobuf:"fixed32,2,rep,packed,name=embedding,proto3" json:"embedding,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Index     uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Embedding []float32              `protobuf:"fixed32,2,rep,packed,name=embedding,proto3" json:"embedding,omitempty"`
+	// Number of chunks pooled into this vector; set only with auto_chunk.
+	ChunkCount    uint32 `protobuf:"varint,3,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -136,6 +150,13 @@ func (x *Embedding) GetEmbedding() []float32 {
 	return nil
 }
 
+func (x *Embedding) GetChunkCount() uint32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
 // Token usage for embeddings (input only, no completion tokens).
 type EmbeddingsUsage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -192,10 +213,12 @@ func (x *EmbeddingsUsage) GetTotalTokens() uint32 {
 type CreateEmbeddingsResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Unique identifier for this embeddings request (used for GetGeneration).
-	Id            string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Model         string           `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
-	Data          []*Embedding     `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
-	Usage         *EmbeddingsUsage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	Id    string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Model string           `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Data  []*Embedding     `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	Usage *EmbeddingsUsage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Unix timestamp (seconds) when the gateway received the request.
+	Created       int64 `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -258,26 +281,320 @@ func (x *CreateEmbeddingsResponse) GetUsage() *EmbeddingsUsage {
 	return nil
 }
 
+func (x *CreateEmbeddingsResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+// Embeds the same input with several models in one call, e.g. to compare
+// embedding models side by side.
+type CreateEmbeddingsGroupRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Models to embed with, run concurrently. Catalog models must declare the
+	// "embeddings" capability.
+	Models []string `protobuf:"bytes,1,rep,name=models,proto3" json:"models,omitempty"`
+	Input  []string `protobuf:"bytes,2,rep,name=input,proto3" json:"input,omitempty"`
+	// Optional user identifier.
+	User          string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEmbeddingsGroupRequest) Reset() {
+	*x = CreateEmbeddingsGroupRequest{}
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEmbeddingsGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEmbeddingsGroupRequest) ProtoMessage() {}
+
+func (x *CreateEmbeddingsGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEmbeddingsGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateEmbeddingsGroupRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_embeddings_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateEmbeddingsGroupRequest) GetModels() []string {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+func (x *CreateEmbeddingsGroupRequest) GetInput() []string {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *CreateEmbeddingsGroupRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type CreateEmbeddingsGroupResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per requested model, keyed by model id.
+	Results       map[string]*CreateEmbeddingsResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEmbeddingsGroupResponse) Reset() {
+	*x = CreateEmbeddingsGroupResponse{}
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEmbeddingsGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEmbeddingsGroupResponse) ProtoMessage() {}
+
+func (x *CreateEmbeddingsGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEmbeddingsGroupResponse.ProtoReflect.Descriptor instead.
+func (*CreateEmbeddingsGroupResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_embeddings_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateEmbeddingsGroupResponse) GetResults() map[string]*CreateEmbeddingsResponse {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type CreateEmbeddingsStreamRequest struct {
+	state   protoimpl.MessageState   `protogen:"open.v1"`
+	Request *CreateEmbeddingsRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// Inputs per upstream call and stream message. 0 uses the server default.
+	BatchSize     uint32 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEmbeddingsStreamRequest) Reset() {
+	*x = CreateEmbeddingsStreamRequest{}
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEmbeddingsStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEmbeddingsStreamRequest) ProtoMessage() {}
+
+func (x *CreateEmbeddingsStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEmbeddingsStreamRequest.ProtoReflect.Descriptor instead.
+func (*CreateEmbeddingsStreamRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_embeddings_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateEmbeddingsStreamRequest) GetRequest() *CreateEmbeddingsRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *CreateEmbeddingsStreamRequest) GetBatchSize() uint32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// One completed batch.
+type CreateEmbeddingsStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Generation id of this batch (used for GetGeneration).
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Model string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// Embedding indices refer to positions in the request's input.
+	Data []*Embedding `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	// Usage of this batch only.
+	Usage *EmbeddingsUsage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Progress: inputs embedded so far (including this batch) out of total.
+	Completed uint32 `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`
+	Total     uint32 `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	// Unix timestamp (seconds) when the gateway received the request.
+	Created       int64 `protobuf:"varint,7,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEmbeddingsStreamResponse) Reset() {
+	*x = CreateEmbeddingsStreamResponse{}
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEmbeddingsStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEmbeddingsStreamResponse) ProtoMessage() {}
+
+func (x *CreateEmbeddingsStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_embeddings_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEmbeddingsStreamResponse.ProtoReflect.Descriptor instead.
+func (*CreateEmbeddingsStreamResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_embeddings_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateEmbeddingsStreamResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateEmbeddingsStreamResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateEmbeddingsStreamResponse) GetData() []*Embedding {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *CreateEmbeddingsStreamResponse) GetUsage() *EmbeddingsUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *CreateEmbeddingsStreamResponse) GetCompleted() uint32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *CreateEmbeddingsStreamResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *CreateEmbeddingsStreamResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
 var File_llmgateway_v1_embeddings_proto protoreflect.FileDescriptor
 
 const file_llmgateway_v1_embeddings_proto_rawDesc = "" +
 	"\n" +
-	"\x1ellmgateway/v1/embeddings.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\"c\n" +
+	"\x1ellmgateway/v1/embeddings.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\"\x82\x01\n" +
 	"\x17CreateEmbeddingsRequest\x12\x19\n" +
 	"\x05model\x18\x01 \x01(\tB\x03\xe0A\x02R\x05model\x12\x19\n" +
 	"\x05input\x18\x02 \x03(\tB\x03\xe0A\x02R\x05input\x12\x12\n" +
-	"\x04user\x18\x03 \x01(\tR\x04user\"?\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x1d\n" +
+	"\n" +
+	"auto_chunk\x18\x04 \x01(\bR\tautoChunk\"`\n" +
 	"\tEmbedding\x12\x14\n" +
 	"\x05index\x18\x01 \x01(\rR\x05index\x12\x1c\n" +
-	"\tembedding\x18\x02 \x03(\x02R\tembedding\"Y\n" +
+	"\tembedding\x18\x02 \x03(\x02R\tembedding\x12\x1f\n" +
+	"\vchunk_count\x18\x03 \x01(\rR\n" +
+	"chunkCount\"Y\n" +
 	"\x0fEmbeddingsUsage\x12#\n" +
 	"\rprompt_tokens\x18\x01 \x01(\rR\fpromptTokens\x12!\n" +
-	"\ftotal_tokens\x18\x02 \x01(\rR\vtotalTokens\"\xa4\x01\n" +
+	"\ftotal_tokens\x18\x02 \x01(\rR\vtotalTokens\"\xbe\x01\n" +
 	"\x18CreateEmbeddingsResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05model\x18\x02 \x01(\tR\x05model\x12,\n" +
 	"\x04data\x18\x03 \x03(\v2\x18.llmgateway.v1.EmbeddingR\x04data\x124\n" +
-	"\x05usage\x18\x04 \x01(\v2\x1e.llmgateway.v1.EmbeddingsUsageR\x05usageBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
+	"\x05usage\x18\x04 \x01(\v2\x1e.llmgateway.v1.EmbeddingsUsageR\x05usage\x12\x18\n" +
+	"\acreated\x18\x05 \x01(\x03R\acreated\"j\n" +
+	"\x1cCreateEmbeddingsGroupRequest\x12\x1b\n" +
+	"\x06models\x18\x01 \x03(\tB\x03\xe0A\x02R\x06models\x12\x19\n" +
+	"\x05input\x18\x02 \x03(\tB\x03\xe0A\x02R\x05input\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\"\xd9\x01\n" +
+	"\x1dCreateEmbeddingsGroupResponse\x12S\n" +
+	"\aresults\x18\x01 \x03(\v29.llmgateway.v1.CreateEmbeddingsGroupResponse.ResultsEntryR\aresults\x1ac\n" +
+	"\fResultsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12=\n" +
+	"\x05value\x18\x02 \x01(\v2'.llmgateway.v1.CreateEmbeddingsResponseR\x05value:\x028\x01\"\x85\x01\n" +
+	"\x1dCreateEmbeddingsStreamRequest\x12E\n" +
+	"\arequest\x18\x01 \x01(\v2&.llmgateway.v1.CreateEmbeddingsRequestB\x03\xe0A\x02R\arequest\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\rR\tbatchSize\"\xf8\x01\n" +
+	"\x1eCreateEmbeddingsStreamResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12,\n" +
+	"\x04data\x18\x03 \x03(\v2\x18.llmgateway.v1.EmbeddingR\x04data\x124\n" +
+	"\x05usage\x18\x04 \x01(\v2\x1e.llmgateway.v1.EmbeddingsUsageR\x05usage\x12\x1c\n" +
+	"\tcompleted\x18\x05 \x01(\rR\tcompleted\x12\x14\n" +
+	"\x05total\x18\x06 \x01(\rR\x05total\x12\x18\n" +
+	"\acreated\x18\a \x01(\x03R\acreatedBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
 
 var (
 	file_llmgateway_v1_embeddings_proto_rawDescOnce sync.Once
@@ -291,21 +608,31 @@ func file_llmgateway_v1_embeddings_proto_rawDescGZIP() []byte {
 	return file_llmgateway_v1_embeddings_proto_rawDescData
 }
 
-var file_llmgateway_v1_embeddings_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_llmgateway_v1_embeddings_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_llmgateway_v1_embeddings_proto_goTypes = []any{
-	(*CreateEmbeddingsRequest)(nil),  // 0: llmgateway.v1.CreateEmbeddingsRequest
-	(*Embedding)(nil),                // 1: llmgateway.v1.Embedding
-	(*EmbeddingsUsage)(nil),          // 2: llmgateway.v1.EmbeddingsUsage
-	(*CreateEmbeddingsResponse)(nil), // 3: llmgateway.v1.CreateEmbeddingsResponse
+	(*CreateEmbeddingsRequest)(nil),        // 0: llmgateway.v1.CreateEmbeddingsRequest
+	(*Embedding)(nil),                      // 1: llmgateway.v1.Embedding
+	(*EmbeddingsUsage)(nil),                // 2: llmgateway.v1.EmbeddingsUsage
+	(*CreateEmbeddingsResponse)(nil),       // 3: llmgateway.v1.CreateEmbeddingsResponse
+	(*CreateEmbeddingsGroupRequest)(nil),   // 4: llmgateway.v1.CreateEmbeddingsGroupRequest
+	(*CreateEmbeddingsGroupResponse)(nil),  // 5: llmgateway.v1.CreateEmbeddingsGroupResponse
+	(*CreateEmbeddingsStreamRequest)(nil),  // 6: llmgateway.v1.CreateEmbeddingsStreamRequest
+	(*CreateEmbeddingsStreamResponse)(nil), // 7: llmgateway.v1.CreateEmbeddingsStreamResponse
+	nil,                                    // 8: llmgateway.v1.CreateEmbeddingsGroupResponse.ResultsEntry
 }
 var file_llmgateway_v1_embeddings_proto_depIdxs = []int32{
 	1, // 0: llmgateway.v1.CreateEmbeddingsResponse.data:type_name -> llmgateway.v1.Embedding
 	2, // 1: llmgateway.v1.CreateEmbeddingsResponse.usage:type_name -> llmgateway.v1.EmbeddingsUsage
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	8, // 2: llmgateway.v1.CreateEmbeddingsGroupResponse.results:type_name -> llmgateway.v1.CreateEmbeddingsGroupResponse.ResultsEntry
+	0, // 3: llmgateway.v1.CreateEmbeddingsStreamRequest.request:type_name -> llmgateway.v1.CreateEmbeddingsRequest
+	1, // 4: llmgateway.v1.CreateEmbeddingsStreamResponse.data:type_name -> llmgateway.v1.Embedding
+	2, // 5: llmgateway.v1.CreateEmbeddingsStreamResponse.usage:type_name -> llmgateway.v1.EmbeddingsUsage
+	3, // 6: llmgateway.v1.CreateEmbeddingsGroupResponse.ResultsEntry.value:type_name -> llmgateway.v1.CreateEmbeddingsResponse
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_llmgateway_v1_embeddings_proto_init() }
@@ -319,7 +646,7 @@ func file_llmgateway_v1_embeddings_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_embeddings_proto_rawDesc), len(file_llmgateway_v1_embeddings_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -332,11 +332,217 @@ func (x *GetUsageCallbackResponse) GetUrls() []string {
 	return nil
 }
 
+type SetMaintenanceModeRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Retry hint returned to rejected callers; 0 omits it.
+	RetryAfterSeconds uint32 `protobuf:"varint,2,opt,name=retry_after_seconds,json=retryAfterSeconds,proto3" json:"retry_after_seconds,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceModeRequest) Reset() {
+	*x = SetMaintenanceModeRequest{}
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceModeRequest) ProtoMessage() {}
+
+func (x *SetMaintenanceModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceModeRequest.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceModeRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_gateway_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetMaintenanceModeRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SetMaintenanceModeRequest) GetRetryAfterSeconds() uint32 {
+	if x != nil {
+		return x.RetryAfterSeconds
+	}
+	return 0
+}
+
+type SetMaintenanceModeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceModeResponse) Reset() {
+	*x = SetMaintenanceModeResponse{}
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceModeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceModeResponse) ProtoMessage() {}
+
+func (x *SetMaintenanceModeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceModeResponse.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceModeResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_gateway_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetMaintenanceModeResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type PurgeCacheRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Evict every entry; other fields are ignored.
+	All bool `protobuf:"varint,1,opt,name=all,proto3" json:"all,omitempty"`
+	// Evict entries of this routed model, e.g. "openrouter/openai/text-embedding-3-small".
+	Model string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// Evict entries whose key ("<routed model>:<hash>") starts with this prefix,
+	// e.g. "openrouter/" for a whole provider.
+	KeyPrefix     string `protobuf:"bytes,3,opt,name=key_prefix,json=keyPrefix,proto3" json:"key_prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeCacheRequest) Reset() {
+	*x = PurgeCacheRequest{}
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeCacheRequest) ProtoMessage() {}
+
+func (x *PurgeCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeCacheRequest.ProtoReflect.Descriptor instead.
+func (*PurgeCacheRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_gateway_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PurgeCacheRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *PurgeCacheRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *PurgeCacheRequest) GetKeyPrefix() string {
+	if x != nil {
+		return x.KeyPrefix
+	}
+	return ""
+}
+
+type PurgeCacheResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of entries evicted.
+	Purged        uint32 `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeCacheResponse) Reset() {
+	*x = PurgeCacheResponse{}
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeCacheResponse) ProtoMessage() {}
+
+func (x *PurgeCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_gateway_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeCacheResponse.ProtoReflect.Descriptor instead.
+func (*PurgeCacheResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_gateway_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PurgeCacheResponse) GetPurged() uint32 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
 var File_llmgateway_v1_gateway_proto protoreflect.FileDescriptor
 
 const file_llmgateway_v1_gateway_proto_rawDesc = "" +
 	"\n" +
-	"\x1bllmgateway/v1/gateway.proto\x12\rllmgateway.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x18llmgateway/v1/chat.proto\x1a\x1ellmgateway/v1/embeddings.proto\x1a\x1ellmgateway/v1/generation.proto\x1a\x1allmgateway/v1/models.proto\"\"\n" +
+	"\x1bllmgateway/v1/gateway.proto\x12\rllmgateway.v1\x1a\x1cgoogle/api/annotations.proto\x1a llmgateway/v1/capabilities.proto\x1a\x18llmgateway/v1/chat.proto\x1a\x1fllmgateway/v1/completions.proto\x1a\x1ellmgateway/v1/embeddings.proto\x1a\x1ellmgateway/v1/generation.proto\x1a\x1allmgateway/v1/models.proto\x1a\x1dllmgateway/v1/responses.proto\"\"\n" +
 	" IssueTemporaryCredentialsRequest\"\x8e\x01\n" +
 	"\x14TemporaryCredentials\x12\"\n" +
 	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\x12*\n" +
@@ -350,19 +556,41 @@ const file_llmgateway_v1_gateway_proto_rawDesc = "" +
 	"\x04urls\x18\x01 \x03(\tR\x04urls\"\x19\n" +
 	"\x17GetUsageCallbackRequest\".\n" +
 	"\x18GetUsageCallbackResponse\x12\x12\n" +
-	"\x04urls\x18\x01 \x03(\tR\x04urls2\xd7\t\n" +
+	"\x04urls\x18\x01 \x03(\tR\x04urls\"e\n" +
+	"\x19SetMaintenanceModeRequest\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12.\n" +
+	"\x13retry_after_seconds\x18\x02 \x01(\rR\x11retryAfterSeconds\"6\n" +
+	"\x1aSetMaintenanceModeResponse\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\"Z\n" +
+	"\x11PurgeCacheRequest\x12\x10\n" +
+	"\x03all\x18\x01 \x01(\bR\x03all\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x1d\n" +
+	"\n" +
+	"key_prefix\x18\x03 \x01(\tR\tkeyPrefix\",\n" +
+	"\x12PurgeCacheResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\rR\x06purged2\xdd\x13\n" +
 	"\x11LLMGatewayService\x12\xa9\x01\n" +
 	"\x19IssueTemporaryCredentials\x12/.llmgateway.v1.IssueTemporaryCredentialsRequest\x1a0.llmgateway.v1.IssueTemporaryCredentialsResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/auth/temporary-credentials\x12\x87\x01\n" +
 	"\x10SetUsageCallback\x12&.llmgateway.v1.SetUsageCallbackRequest\x1a'.llmgateway.v1.SetUsageCallbackResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\x1a\x17/v1/auth/usage-callback\x12\x84\x01\n" +
-	"\x10GetUsageCallback\x12&.llmgateway.v1.GetUsageCallbackRequest\x1a'.llmgateway.v1.GetUsageCallbackResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/auth/usage-callback\x12e\n" +
+	"\x10GetUsageCallback\x12&.llmgateway.v1.GetUsageCallbackRequest\x1a'.llmgateway.v1.GetUsageCallbackResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/auth/usage-callback\x12\x8b\x01\n" +
+	"\x12SetMaintenanceMode\x12(.llmgateway.v1.SetMaintenanceModeRequest\x1a).llmgateway.v1.SetMaintenanceModeResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\x1a\x15/v1/admin/maintenance\x12s\n" +
+	"\n" +
+	"PurgeCache\x12 .llmgateway.v1.PurgeCacheRequest\x1a!.llmgateway.v1.PurgeCacheResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/admin/cache:purge\x12e\n" +
 	"\n" +
 	"ListModels\x12 .llmgateway.v1.ListModelsRequest\x1a!.llmgateway.v1.ListModelsResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
 	"/v1/models\x12d\n" +
-	"\bGetModel\x12\x1e.llmgateway.v1.GetModelRequest\x1a\x1f.llmgateway.v1.GetModelResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/models/{id}\x12\x90\x01\n" +
+	"\bGetModel\x12\x1e.llmgateway.v1.GetModelRequest\x1a\x1f.llmgateway.v1.GetModelResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/models/{id}\x12z\n" +
+	"\x0fGetCapabilities\x12%.llmgateway.v1.GetCapabilitiesRequest\x1a&.llmgateway.v1.GetCapabilitiesResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/capabilities\x12\x90\x01\n" +
 	"\x14CreateChatCompletion\x12*.llmgateway.v1.CreateChatCompletionRequest\x1a+.llmgateway.v1.CreateChatCompletionResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/chat/completions\x12\xab\x01\n" +
-	"\x1aCreateChatCompletionStream\x120.llmgateway.v1.CreateChatCompletionStreamRequest\x1a1.llmgateway.v1.CreateChatCompletionStreamResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/chat/completions:stream0\x01\x12~\n" +
-	"\x10CreateEmbeddings\x12&.llmgateway.v1.CreateEmbeddingsRequest\x1a'.llmgateway.v1.CreateEmbeddingsResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/embeddings\x12w\n" +
-	"\rGetGeneration\x12#.llmgateway.v1.GetGenerationRequest\x1a$.llmgateway.v1.GetGenerationResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/generation/{id}BHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
+	"\x1aCreateChatCompletionStream\x120.llmgateway.v1.CreateChatCompletionStreamRequest\x1a1.llmgateway.v1.CreateChatCompletionStreamResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/chat/completions:stream0\x01\x12\x92\x01\n" +
+	"\x12CompareCompletions\x12(.llmgateway.v1.CompareCompletionsRequest\x1a).llmgateway.v1.CompareCompletionsResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/chat/completions:compare\x12\x7f\n" +
+	"\x10CreateCompletion\x12&.llmgateway.v1.CreateCompletionRequest\x1a'.llmgateway.v1.CreateCompletionResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/completions\x12w\n" +
+	"\x0eCreateResponse\x12$.llmgateway.v1.CreateResponseRequest\x1a%.llmgateway.v1.CreateResponseResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/responses\x12~\n" +
+	"\x10CreateEmbeddings\x12&.llmgateway.v1.CreateEmbeddingsRequest\x1a'.llmgateway.v1.CreateEmbeddingsResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/embeddings\x12\x93\x01\n" +
+	"\x15CreateEmbeddingsGroup\x12+.llmgateway.v1.CreateEmbeddingsGroupRequest\x1a,.llmgateway.v1.CreateEmbeddingsGroupResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/embeddings:group\x12\x99\x01\n" +
+	"\x16CreateEmbeddingsStream\x12,.llmgateway.v1.CreateEmbeddingsStreamRequest\x1a-.llmgateway.v1.CreateEmbeddingsStreamResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/embeddings:stream0\x01\x12w\n" +
+	"\rGetGeneration\x12#.llmgateway.v1.GetGenerationRequest\x1a$.llmgateway.v1.GetGenerationResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/generation/{id}\x12\xc3\x01\n" +
+	"\x1dListGenerationsByConversation\x123.llmgateway.v1.ListGenerationsByConversationRequest\x1a4.llmgateway.v1.ListGenerationsByConversationResponse\"7\x82\xd3\xe4\x93\x021\x12//v1/conversations/{conversation_id}/generationsBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
 
 var (
 	file_llmgateway_v1_gateway_proto_rawDescOnce sync.Once
@@ -376,50 +604,86 @@ func file_llmgateway_v1_gateway_proto_rawDescGZIP() []byte {
 	return file_llmgateway_v1_gateway_proto_rawDescData
 }
 
-var file_llmgateway_v1_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_llmgateway_v1_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_llmgateway_v1_gateway_proto_goTypes = []any{
-	(*IssueTemporaryCredentialsRequest)(nil),   // 0: llmgateway.v1.IssueTemporaryCredentialsRequest
-	(*TemporaryCredentials)(nil),               // 1: llmgateway.v1.TemporaryCredentials
-	(*IssueTemporaryCredentialsResponse)(nil),  // 2: llmgateway.v1.IssueTemporaryCredentialsResponse
-	(*SetUsageCallbackRequest)(nil),            // 3: llmgateway.v1.SetUsageCallbackRequest
-	(*SetUsageCallbackResponse)(nil),           // 4: llmgateway.v1.SetUsageCallbackResponse
-	(*GetUsageCallbackRequest)(nil),            // 5: llmgateway.v1.GetUsageCallbackRequest
-	(*GetUsageCallbackResponse)(nil),           // 6: llmgateway.v1.GetUsageCallbackResponse
-	(*ListModelsRequest)(nil),                  // 7: llmgateway.v1.ListModelsRequest
-	(*GetModelRequest)(nil),                    // 8: llmgateway.v1.GetModelRequest
-	(*CreateChatCompletionRequest)(nil),        // 9: llmgateway.v1.CreateChatCompletionRequest
-	(*CreateChatCompletionStreamRequest)(nil),  // 10: llmgateway.v1.CreateChatCompletionStreamRequest
-	(*CreateEmbeddingsRequest)(nil),            // 11: llmgateway.v1.CreateEmbeddingsRequest
-	(*GetGenerationRequest)(nil),               // 12: llmgateway.v1.GetGenerationRequest
-	(*ListModelsResponse)(nil),                 // 13: llmgateway.v1.ListModelsResponse
-	(*GetModelResponse)(nil),                   // 14: llmgateway.v1.GetModelResponse
-	(*CreateChatCompletionResponse)(nil),       // 15: llmgateway.v1.CreateChatCompletionResponse
-	(*CreateChatCompletionStreamResponse)(nil), // 16: llmgateway.v1.CreateChatCompletionStreamResponse
-	(*CreateEmbeddingsResponse)(nil),           // 17: llmgateway.v1.CreateEmbeddingsResponse
-	(*GetGenerationResponse)(nil),              // 18: llmgateway.v1.GetGenerationResponse
+	(*IssueTemporaryCredentialsRequest)(nil),      // 0: llmgateway.v1.IssueTemporaryCredentialsRequest
+	(*TemporaryCredentials)(nil),                  // 1: llmgateway.v1.TemporaryCredentials
+	(*IssueTemporaryCredentialsResponse)(nil),     // 2: llmgateway.v1.IssueTemporaryCredentialsResponse
+	(*SetUsageCallbackRequest)(nil),               // 3: llmgateway.v1.SetUsageCallbackRequest
+	(*SetUsageCallbackResponse)(nil),              // 4: llmgateway.v1.SetUsageCallbackResponse
+	(*GetUsageCallbackRequest)(nil),               // 5: llmgateway.v1.GetUsageCallbackRequest
+	(*GetUsageCallbackResponse)(nil),              // 6: llmgateway.v1.GetUsageCallbackResponse
+	(*SetMaintenanceModeRequest)(nil),             // 7: llmgateway.v1.SetMaintenanceModeRequest
+	(*SetMaintenanceModeResponse)(nil),            // 8: llmgateway.v1.SetMaintenanceModeResponse
+	(*PurgeCacheRequest)(nil),                     // 9: llmgateway.v1.PurgeCacheRequest
+	(*PurgeCacheResponse)(nil),                    // 10: llmgateway.v1.PurgeCacheResponse
+	(*ListModelsRequest)(nil),                     // 11: llmgateway.v1.ListModelsRequest
+	(*GetModelRequest)(nil),                       // 12: llmgateway.v1.GetModelRequest
+	(*GetCapabilitiesRequest)(nil),                // 13: llmgateway.v1.GetCapabilitiesRequest
+	(*CreateChatCompletionRequest)(nil),           // 14: llmgateway.v1.CreateChatCompletionRequest
+	(*CreateChatCompletionStreamRequest)(nil),     // 15: llmgateway.v1.CreateChatCompletionStreamRequest
+	(*CompareCompletionsRequest)(nil),             // 16: llmgateway.v1.CompareCompletionsRequest
+	(*CreateCompletionRequest)(nil),               // 17: llmgateway.v1.CreateCompletionRequest
+	(*CreateResponseRequest)(nil),                 // 18: llmgateway.v1.CreateResponseRequest
+	(*CreateEmbeddingsRequest)(nil),               // 19: llmgateway.v1.CreateEmbeddingsRequest
+	(*CreateEmbeddingsGroupRequest)(nil),          // 20: llmgateway.v1.CreateEmbeddingsGroupRequest
+	(*CreateEmbeddingsStreamRequest)(nil),         // 21: llmgateway.v1.CreateEmbeddingsStreamRequest
+	(*GetGenerationRequest)(nil),                  // 22: llmgateway.v1.GetGenerationRequest
+	(*ListGenerationsByConversationRequest)(nil),  // 23: llmgateway.v1.ListGenerationsByConversationRequest
+	(*ListModelsResponse)(nil),                    // 24: llmgateway.v1.ListModelsResponse
+	(*GetModelResponse)(nil),                      // 25: llmgateway.v1.GetModelResponse
+	(*GetCapabilitiesResponse)(nil),               // 26: llmgateway.v1.GetCapabilitiesResponse
+	(*CreateChatCompletionResponse)(nil),          // 27: llmgateway.v1.CreateChatCompletionResponse
+	(*CreateChatCompletionStreamResponse)(nil),    // 28: llmgateway.v1.CreateChatCompletionStreamResponse
+	(*CompareCompletionsResponse)(nil),            // 29: llmgateway.v1.CompareCompletionsResponse
+	(*CreateCompletionResponse)(nil),              // 30: llmgateway.v1.CreateCompletionResponse
+	(*CreateResponseResponse)(nil),                // 31: llmgateway.v1.CreateResponseResponse
+	(*CreateEmbeddingsResponse)(nil),              // 32: llmgateway.v1.CreateEmbeddingsResponse
+	(*CreateEmbeddingsGroupResponse)(nil),         // 33: llmgateway.v1.CreateEmbeddingsGroupResponse
+	(*CreateEmbeddingsStreamResponse)(nil),        // 34: llmgateway.v1.CreateEmbeddingsStreamResponse
+	(*GetGenerationResponse)(nil),                 // 35: llmgateway.v1.GetGenerationResponse
+	(*ListGenerationsByConversationResponse)(nil), // 36: llmgateway.v1.ListGenerationsByConversationResponse
 }
 var file_llmgateway_v1_gateway_proto_depIdxs = []int32{
 	1,  // 0: llmgateway.v1.IssueTemporaryCredentialsResponse.credentials:type_name -> llmgateway.v1.TemporaryCredentials
 	0,  // 1: llmgateway.v1.LLMGatewayService.IssueTemporaryCredentials:input_type -> llmgateway.v1.IssueTemporaryCredentialsRequest
 	3,  // 2: llmgateway.v1.LLMGatewayService.SetUsageCallback:input_type -> llmgateway.v1.SetUsageCallbackRequest
 	5,  // 3: llmgateway.v1.LLMGatewayService.GetUsageCallback:input_type -> llmgateway.v1.GetUsageCallbackRequest
-	7,  // 4: llmgateway.v1.LLMGatewayService.ListModels:input_type -> llmgateway.v1.ListModelsRequest
-	8,  // 5: llmgateway.v1.LLMGatewayService.GetModel:input_type -> llmgateway.v1.GetModelRequest
-	9,  // 6: llmgateway.v1.LLMGatewayService.CreateChatCompletion:input_type -> llmgateway.v1.CreateChatCompletionRequest
-	10, // 7: llmgateway.v1.LLMGatewayService.CreateChatCompletionStream:input_type -> llmgateway.v1.CreateChatCompletionStreamRequest
-	11, // 8: llmgateway.v1.LLMGatewayService.CreateEmbeddings:input_type -> llmgateway.v1.CreateEmbeddingsRequest
-	12, // 9: llmgateway.v1.LLMGatewayService.GetGeneration:input_type -> llmgateway.v1.GetGenerationRequest
-	2,  // 10: llmgateway.v1.LLMGatewayService.IssueTemporaryCredentials:output_type -> llmgateway.v1.IssueTemporaryCredentialsResponse
-	4,  // 11: llmgateway.v1.LLMGatewayService.SetUsageCallback:output_type -> llmgateway.v1.SetUsageCallbackResponse
-	6,  // 12: llmgateway.v1.LLMGatewayService.GetUsageCallback:output_type -> llmgateway.v1.GetUsageCallbackResponse
-	13, // 13: llmgateway.v1.LLMGatewayService.ListModels:output_type -> llmgateway.v1.ListModelsResponse
-	14, // 14: llmgateway.v1.LLMGatewayService.GetModel:output_type -> llmgateway.v1.GetModelResponse
-	15, // 15: llmgateway.v1.LLMGatewayService.CreateChatCompletion:output_type -> llmgateway.v1.CreateChatCompletionResponse
-	16, // 16: llmgateway.v1.LLMGatewayService.CreateChatCompletionStream:output_type -> llmgateway.v1.CreateChatCompletionStreamResponse
-	17, // 17: llmgateway.v1.LLMGatewayService.CreateEmbeddings:output_type -> llmgateway.v1.CreateEmbeddingsResponse
-	18, // 18: llmgateway.v1.LLMGatewayService.GetGeneration:output_type -> llmgateway.v1.GetGenerationResponse
-	10, // [10:19] is the sub-list for method output_type
-	1,  // [1:10] is the sub-list for method input_type
+	7,  // 4: llmgateway.v1.LLMGatewayService.SetMaintenanceMode:input_type -> llmgateway.v1.SetMaintenanceModeRequest
+	9,  // 5: llmgateway.v1.LLMGatewayService.PurgeCache:input_type -> llmgateway.v1.PurgeCacheRequest
+	11, // 6: llmgateway.v1.LLMGatewayService.ListModels:input_type -> llmgateway.v1.ListModelsRequest
+	12, // 7: llmgateway.v1.LLMGatewayService.GetModel:input_type -> llmgateway.v1.GetModelRequest
+	13, // 8: llmgateway.v1.LLMGatewayService.GetCapabilities:input_type -> llmgateway.v1.GetCapabilitiesRequest
+	14, // 9: llmgateway.v1.LLMGatewayService.CreateChatCompletion:input_type -> llmgateway.v1.CreateChatCompletionRequest
+	15, // 10: llmgateway.v1.LLMGatewayService.CreateChatCompletionStream:input_type -> llmgateway.v1.CreateChatCompletionStreamRequest
+	16, // 11: llmgateway.v1.LLMGatewayService.CompareCompletions:input_type -> llmgateway.v1.CompareCompletionsRequest
+	17, // 12: llmgateway.v1.LLMGatewayService.CreateCompletion:input_type -> llmgateway.v1.CreateCompletionRequest
+	18, // 13: llmgateway.v1.LLMGatewayService.CreateResponse:input_type -> llmgateway.v1.CreateResponseRequest
+	19, // 14: llmgateway.v1.LLMGatewayService.CreateEmbeddings:input_type -> llmgateway.v1.CreateEmbeddingsRequest
+	20, // 15: llmgateway.v1.LLMGatewayService.CreateEmbeddingsGroup:input_type -> llmgateway.v1.CreateEmbeddingsGroupRequest
+	21, // 16: llmgateway.v1.LLMGatewayService.CreateEmbeddingsStream:input_type -> llmgateway.v1.CreateEmbeddingsStreamRequest
+	22, // 17: llmgateway.v1.LLMGatewayService.GetGeneration:input_type -> llmgateway.v1.GetGenerationRequest
+	23, // 18: llmgateway.v1.LLMGatewayService.ListGenerationsByConversation:input_type -> llmgateway.v1.ListGenerationsByConversationRequest
+	2,  // 19: llmgateway.v1.LLMGatewayService.IssueTemporaryCredentials:output_type -> llmgateway.v1.IssueTemporaryCredentialsResponse
+	4,  // 20: llmgateway.v1.LLMGatewayService.SetUsageCallback:output_type -> llmgateway.v1.SetUsageCallbackResponse
+	6,  // 21: llmgateway.v1.LLMGatewayService.GetUsageCallback:output_type -> llmgateway.v1.GetUsageCallbackResponse
+	8,  // 22: llmgateway.v1.LLMGatewayService.SetMaintenanceMode:output_type -> llmgateway.v1.SetMaintenanceModeResponse
+	10, // 23: llmgateway.v1.LLMGatewayService.PurgeCache:output_type -> llmgateway.v1.PurgeCacheResponse
+	24, // 24: llmgateway.v1.LLMGatewayService.ListModels:output_type -> llmgateway.v1.ListModelsResponse
+	25, // 25: llmgateway.v1.LLMGatewayService.GetModel:output_type -> llmgateway.v1.GetModelResponse
+	26, // 26: llmgateway.v1.LLMGatewayService.GetCapabilities:output_type -> llmgateway.v1.GetCapabilitiesResponse
+	27, // 27: llmgateway.v1.LLMGatewayService.CreateChatCompletion:output_type -> llmgateway.v1.CreateChatCompletionResponse
+	28, // 28: llmgateway.v1.LLMGatewayService.CreateChatCompletionStream:output_type -> llmgateway.v1.CreateChatCompletionStreamResponse
+	29, // 29: llmgateway.v1.LLMGatewayService.CompareCompletions:output_type -> llmgateway.v1.CompareCompletionsResponse
+	30, // 30: llmgateway.v1.LLMGatewayService.CreateCompletion:output_type -> llmgateway.v1.CreateCompletionResponse
+	31, // 31: llmgateway.v1.LLMGatewayService.CreateResponse:output_type -> llmgateway.v1.CreateResponseResponse
+	32, // 32: llmgateway.v1.LLMGatewayService.CreateEmbeddings:output_type -> llmgateway.v1.CreateEmbeddingsResponse
+	33, // 33: llmgateway.v1.LLMGatewayService.CreateEmbeddingsGroup:output_type -> llmgateway.v1.CreateEmbeddingsGroupResponse
+	34, // 34: llmgateway.v1.LLMGatewayService.CreateEmbeddingsStream:output_type -> llmgateway.v1.CreateEmbeddingsStreamResponse
+	35, // 35: llmgateway.v1.LLMGatewayService.GetGeneration:output_type -> llmgateway.v1.GetGenerationResponse
+	36, // 36: llmgateway.v1.LLMGatewayService.ListGenerationsByConversation:output_type -> llmgateway.v1.ListGenerationsByConversationResponse
+	19, // [19:37] is the sub-list for method output_type
+	1,  // [1:19] is the sub-list for method input_type
 	1,  // [1:1] is the sub-list for extension type_name
 	1,  // [1:1] is the sub-list for extension extendee
 	0,  // [0:1] is the sub-list for field type_name
@@ -430,17 +694,20 @@ func file_llmgateway_v1_gateway_proto_init() {
 	if File_llmgateway_v1_gateway_proto != nil {
 		return
 	}
+	file_llmgateway_v1_capabilities_proto_init()
 	file_llmgateway_v1_chat_proto_init()
+	file_llmgateway_v1_completions_proto_init()
 	file_llmgateway_v1_embeddings_proto_init()
 	file_llmgateway_v1_generation_proto_init()
 	file_llmgateway_v1_models_proto_init()
+	file_llmgateway_v1_responses_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_gateway_proto_rawDesc), len(file_llmgateway_v1_gateway_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
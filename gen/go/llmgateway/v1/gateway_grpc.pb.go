@@ -19,15 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LLMGatewayService_IssueTemporaryCredentials_FullMethodName  = "/llmgateway.v1.LLMGatewayService/IssueTemporaryCredentials"
-	LLMGatewayService_SetUsageCallback_FullMethodName           = "/llmgateway.v1.LLMGatewayService/SetUsageCallback"
-	LLMGatewayService_GetUsageCallback_FullMethodName           = "/llmgateway.v1.LLMGatewayService/GetUsageCallback"
-	LLMGatewayService_ListModels_FullMethodName                 = "/llmgateway.v1.LLMGatewayService/ListModels"
-	LLMGatewayService_GetModel_FullMethodName                   = "/llmgateway.v1.LLMGatewayService/GetModel"
-	LLMGatewayService_CreateChatCompletion_FullMethodName       = "/llmgateway.v1.LLMGatewayService/CreateChatCompletion"
-	LLMGatewayService_CreateChatCompletionStream_FullMethodName = "/llmgateway.v1.LLMGatewayService/CreateChatCompletionStream"
-	LLMGatewayService_CreateEmbeddings_FullMethodName           = "/llmgateway.v1.LLMGatewayService/CreateEmbeddings"
-	LLMGatewayService_GetGeneration_FullMethodName              = "/llmgateway.v1.LLMGatewayService/GetGeneration"
+	LLMGatewayService_IssueTemporaryCredentials_FullMethodName     = "/llmgateway.v1.LLMGatewayService/IssueTemporaryCredentials"
+	LLMGatewayService_SetUsageCallback_FullMethodName              = "/llmgateway.v1.LLMGatewayService/SetUsageCallback"
+	LLMGatewayService_GetUsageCallback_FullMethodName              = "/llmgateway.v1.LLMGatewayService/GetUsageCallback"
+	LLMGatewayService_SetMaintenanceMode_FullMethodName            = "/llmgateway.v1.LLMGatewayService/SetMaintenanceMode"
+	LLMGatewayService_PurgeCache_FullMethodName                    = "/llmgateway.v1.LLMGatewayService/PurgeCache"
+	LLMGatewayService_ListModels_FullMethodName                    = "/llmgateway.v1.LLMGatewayService/ListModels"
+	LLMGatewayService_GetModel_FullMethodName                      = "/llmgateway.v1.LLMGatewayService/GetModel"
+	LLMGatewayService_GetCapabilities_FullMethodName               = "/llmgateway.v1.LLMGatewayService/GetCapabilities"
+	LLMGatewayService_CreateChatCompletion_FullMethodName          = "/llmgateway.v1.LLMGatewayService/CreateChatCompletion"
+	LLMGatewayService_CreateChatCompletionStream_FullMethodName    = "/llmgateway.v1.LLMGatewayService/CreateChatCompletionStream"
+	LLMGatewayService_CompareCompletions_FullMethodName            = "/llmgateway.v1.LLMGatewayService/CompareCompletions"
+	LLMGatewayService_CreateCompletion_FullMethodName              = "/llmgateway.v1.LLMGatewayService/CreateCompletion"
+	LLMGatewayService_CreateResponse_FullMethodName                = "/llmgateway.v1.LLMGatewayService/CreateResponse"
+	LLMGatewayService_CreateEmbeddings_FullMethodName              = "/llmgateway.v1.LLMGatewayService/CreateEmbeddings"
+	LLMGatewayService_CreateEmbeddingsGroup_FullMethodName         = "/llmgateway.v1.LLMGatewayService/CreateEmbeddingsGroup"
+	LLMGatewayService_CreateEmbeddingsStream_FullMethodName        = "/llmgateway.v1.LLMGatewayService/CreateEmbeddingsStream"
+	LLMGatewayService_GetGeneration_FullMethodName                 = "/llmgateway.v1.LLMGatewayService/GetGeneration"
+	LLMGatewayService_ListGenerationsByConversation_FullMethodName = "/llmgateway.v1.LLMGatewayService/ListGenerationsByConversation"
 )
 
 // LLMGatewayServiceClient is the client API for LLMGatewayService service.
@@ -41,17 +50,43 @@ type LLMGatewayServiceClient interface {
 	SetUsageCallback(ctx context.Context, in *SetUsageCallbackRequest, opts ...grpc.CallOption) (*SetUsageCallbackResponse, error)
 	// Get current service's trusted usage callback allowlist (ServiceToken only).
 	GetUsageCallback(ctx context.Context, in *GetUsageCallbackRequest, opts ...grpc.CallOption) (*GetUsageCallbackResponse, error)
+	// Admin: drain model calls for upstream maintenance (requires the "admin" scope).
+	// While enabled, chat, completion and embeddings calls fail with UNAVAILABLE
+	// and a RetryInfo hint; liveness stays OK and readiness reports draining.
+	SetMaintenanceMode(ctx context.Context, in *SetMaintenanceModeRequest, opts ...grpc.CallOption) (*SetMaintenanceModeResponse, error)
+	// Admin: evict gateway response cache entries (requires the "admin" scope),
+	// e.g. after a bad upstream response was cached. Set all, or model and/or
+	// key_prefix to select entries.
+	PurgeCache(ctx context.Context, in *PurgeCacheRequest, opts ...grpc.CallOption) (*PurgeCacheResponse, error)
 	// Models
 	ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error)
 	GetModel(ctx context.Context, in *GetModelRequest, opts ...grpc.CallOption) (*GetModelResponse, error)
+	// Server-wide feature flags and per-model capabilities, so clients can check
+	// support for streaming, tools, vision, etc. before sending.
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
 	// Chat Completions (OpenAI-style)
 	CreateChatCompletion(ctx context.Context, in *CreateChatCompletionRequest, opts ...grpc.CallOption) (*CreateChatCompletionResponse, error)
 	// Server-streaming chat completion. Mapped to a distinct HTTP endpoint to avoid conflicts.
 	CreateChatCompletionStream(ctx context.Context, in *CreateChatCompletionStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateChatCompletionStreamResponse], error)
+	// Sends one prompt to several chat models concurrently and returns their
+	// responses side by side, e.g. for A/B evaluation.
+	CompareCompletions(ctx context.Context, in *CompareCompletionsRequest, opts ...grpc.CallOption) (*CompareCompletionsResponse, error)
+	// Legacy text completion (OpenAI /v1/completions) for older tooling.
+	CreateCompletion(ctx context.Context, in *CreateCompletionRequest, opts ...grpc.CallOption) (*CreateCompletionResponse, error)
+	// OpenAI Responses API shape (input/instructions), served by the chat
+	// completion flow. Non-streaming only.
+	CreateResponse(ctx context.Context, in *CreateResponseRequest, opts ...grpc.CallOption) (*CreateResponseResponse, error)
 	// Embeddings (OpenAI-style)
 	CreateEmbeddings(ctx context.Context, in *CreateEmbeddingsRequest, opts ...grpc.CallOption) (*CreateEmbeddingsResponse, error)
+	// Embeddings from several models for the same input, keyed by model.
+	CreateEmbeddingsGroup(ctx context.Context, in *CreateEmbeddingsGroupRequest, opts ...grpc.CallOption) (*CreateEmbeddingsGroupResponse, error)
+	// Server-streaming embeddings for large batches: one message per completed
+	// batch, so clients can report progress and process results incrementally.
+	CreateEmbeddingsStream(ctx context.Context, in *CreateEmbeddingsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateEmbeddingsStreamResponse], error)
 	// Generation (query usage for a completed request)
 	GetGeneration(ctx context.Context, in *GetGenerationRequest, opts ...grpc.CallOption) (*GetGenerationResponse, error)
+	// Lists the caller's generations tagged with a conversation id.
+	ListGenerationsByConversation(ctx context.Context, in *ListGenerationsByConversationRequest, opts ...grpc.CallOption) (*ListGenerationsByConversationResponse, error)
 }
 
 type lLMGatewayServiceClient struct {
@@ -92,6 +127,26 @@ func (c *lLMGatewayServiceClient) GetUsageCallback(ctx context.Context, in *GetU
 	return out, nil
 }
 
+func (c *lLMGatewayServiceClient) SetMaintenanceMode(ctx context.Context, in *SetMaintenanceModeRequest, opts ...grpc.CallOption) (*SetMaintenanceModeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetMaintenanceModeResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_SetMaintenanceMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMGatewayServiceClient) PurgeCache(ctx context.Context, in *PurgeCacheRequest, opts ...grpc.CallOption) (*PurgeCacheResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeCacheResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_PurgeCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lLMGatewayServiceClient) ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListModelsResponse)
@@ -112,6 +167,16 @@ func (c *lLMGatewayServiceClient) GetModel(ctx context.Context, in *GetModelRequ
 	return out, nil
 }
 
+func (c *lLMGatewayServiceClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lLMGatewayServiceClient) CreateChatCompletion(ctx context.Context, in *CreateChatCompletionRequest, opts ...grpc.CallOption) (*CreateChatCompletionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateChatCompletionResponse)
@@ -141,6 +206,36 @@ func (c *lLMGatewayServiceClient) CreateChatCompletionStream(ctx context.Context
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LLMGatewayService_CreateChatCompletionStreamClient = grpc.ServerStreamingClient[CreateChatCompletionStreamResponse]
 
+func (c *lLMGatewayServiceClient) CompareCompletions(ctx context.Context, in *CompareCompletionsRequest, opts ...grpc.CallOption) (*CompareCompletionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareCompletionsResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_CompareCompletions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMGatewayServiceClient) CreateCompletion(ctx context.Context, in *CreateCompletionRequest, opts ...grpc.CallOption) (*CreateCompletionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateCompletionResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_CreateCompletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMGatewayServiceClient) CreateResponse(ctx context.Context, in *CreateResponseRequest, opts ...grpc.CallOption) (*CreateResponseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateResponseResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_CreateResponse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lLMGatewayServiceClient) CreateEmbeddings(ctx context.Context, in *CreateEmbeddingsRequest, opts ...grpc.CallOption) (*CreateEmbeddingsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateEmbeddingsResponse)
@@ -151,6 +246,35 @@ func (c *lLMGatewayServiceClient) CreateEmbeddings(ctx context.Context, in *Crea
 	return out, nil
 }
 
+func (c *lLMGatewayServiceClient) CreateEmbeddingsGroup(ctx context.Context, in *CreateEmbeddingsGroupRequest, opts ...grpc.CallOption) (*CreateEmbeddingsGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateEmbeddingsGroupResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_CreateEmbeddingsGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMGatewayServiceClient) CreateEmbeddingsStream(ctx context.Context, in *CreateEmbeddingsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateEmbeddingsStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LLMGatewayService_ServiceDesc.Streams[1], LLMGatewayService_CreateEmbeddingsStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreateEmbeddingsStreamRequest, CreateEmbeddingsStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMGatewayService_CreateEmbeddingsStreamClient = grpc.ServerStreamingClient[CreateEmbeddingsStreamResponse]
+
 func (c *lLMGatewayServiceClient) GetGeneration(ctx context.Context, in *GetGenerationRequest, opts ...grpc.CallOption) (*GetGenerationResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetGenerationResponse)
@@ -161,6 +285,16 @@ func (c *lLMGatewayServiceClient) GetGeneration(ctx context.Context, in *GetGene
 	return out, nil
 }
 
+func (c *lLMGatewayServiceClient) ListGenerationsByConversation(ctx context.Context, in *ListGenerationsByConversationRequest, opts ...grpc.CallOption) (*ListGenerationsByConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGenerationsByConversationResponse)
+	err := c.cc.Invoke(ctx, LLMGatewayService_ListGenerationsByConversation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LLMGatewayServiceServer is the server API for LLMGatewayService service.
 // All implementations must embed UnimplementedLLMGatewayServiceServer
 // for forward compatibility.
@@ -172,17 +306,43 @@ type LLMGatewayServiceServer interface {
 	SetUsageCallback(context.Context, *SetUsageCallbackRequest) (*SetUsageCallbackResponse, error)
 	// Get current service's trusted usage callback allowlist (ServiceToken only).
 	GetUsageCallback(context.Context, *GetUsageCallbackRequest) (*GetUsageCallbackResponse, error)
+	// Admin: drain model calls for upstream maintenance (requires the "admin" scope).
+	// While enabled, chat, completion and embeddings calls fail with UNAVAILABLE
+	// and a RetryInfo hint; liveness stays OK and readiness reports draining.
+	SetMaintenanceMode(context.Context, *SetMaintenanceModeRequest) (*SetMaintenanceModeResponse, error)
+	// Admin: evict gateway response cache entries (requires the "admin" scope),
+	// e.g. after a bad upstream response was cached. Set all, or model and/or
+	// key_prefix to select entries.
+	PurgeCache(context.Context, *PurgeCacheRequest) (*PurgeCacheResponse, error)
 	// Models
 	ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error)
 	GetModel(context.Context, *GetModelRequest) (*GetModelResponse, error)
+	// Server-wide feature flags and per-model capabilities, so clients can check
+	// support for streaming, tools, vision, etc. before sending.
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
 	// Chat Completions (OpenAI-style)
 	CreateChatCompletion(context.Context, *CreateChatCompletionRequest) (*CreateChatCompletionResponse, error)
 	// Server-streaming chat completion. Mapped to a distinct HTTP endpoint to avoid conflicts.
 	CreateChatCompletionStream(*CreateChatCompletionStreamRequest, grpc.ServerStreamingServer[CreateChatCompletionStreamResponse]) error
+	// Sends one prompt to several chat models concurrently and returns their
+	// responses side by side, e.g. for A/B evaluation.
+	CompareCompletions(context.Context, *CompareCompletionsRequest) (*CompareCompletionsResponse, error)
+	// Legacy text completion (OpenAI /v1/completions) for older tooling.
+	CreateCompletion(context.Context, *CreateCompletionRequest) (*CreateCompletionResponse, error)
+	// OpenAI Responses API shape (input/instructions), served by the chat
+	// completion flow. Non-streaming only.
+	CreateResponse(context.Context, *CreateResponseRequest) (*CreateResponseResponse, error)
 	// Embeddings (OpenAI-style)
 	CreateEmbeddings(context.Context, *CreateEmbeddingsRequest) (*CreateEmbeddingsResponse, error)
+	// Embeddings from several models for the same input, keyed by model.
+	CreateEmbeddingsGroup(context.Context, *CreateEmbeddingsGroupRequest) (*CreateEmbeddingsGroupResponse, error)
+	// Server-streaming embeddings for large batches: one message per completed
+	// batch, so clients can report progress and process results incrementally.
+	CreateEmbeddingsStream(*CreateEmbeddingsStreamRequest, grpc.ServerStreamingServer[CreateEmbeddingsStreamResponse]) error
 	// Generation (query usage for a completed request)
 	GetGeneration(context.Context, *GetGenerationRequest) (*GetGenerationResponse, error)
+	// Lists the caller's generations tagged with a conversation id.
+	ListGenerationsByConversation(context.Context, *ListGenerationsByConversationRequest) (*ListGenerationsByConversationResponse, error)
 	mustEmbedUnimplementedLLMGatewayServiceServer()
 }
 
@@ -202,24 +362,51 @@ func (UnimplementedLLMGatewayServiceServer) SetUsageCallback(context.Context, *S
 func (UnimplementedLLMGatewayServiceServer) GetUsageCallback(context.Context, *GetUsageCallbackRequest) (*GetUsageCallbackResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUsageCallback not implemented")
 }
+func (UnimplementedLLMGatewayServiceServer) SetMaintenanceMode(context.Context, *SetMaintenanceModeRequest) (*SetMaintenanceModeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenanceMode not implemented")
+}
+func (UnimplementedLLMGatewayServiceServer) PurgeCache(context.Context, *PurgeCacheRequest) (*PurgeCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeCache not implemented")
+}
 func (UnimplementedLLMGatewayServiceServer) ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
 }
 func (UnimplementedLLMGatewayServiceServer) GetModel(context.Context, *GetModelRequest) (*GetModelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetModel not implemented")
 }
+func (UnimplementedLLMGatewayServiceServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
 func (UnimplementedLLMGatewayServiceServer) CreateChatCompletion(context.Context, *CreateChatCompletionRequest) (*CreateChatCompletionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateChatCompletion not implemented")
 }
 func (UnimplementedLLMGatewayServiceServer) CreateChatCompletionStream(*CreateChatCompletionStreamRequest, grpc.ServerStreamingServer[CreateChatCompletionStreamResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method CreateChatCompletionStream not implemented")
 }
+func (UnimplementedLLMGatewayServiceServer) CompareCompletions(context.Context, *CompareCompletionsRequest) (*CompareCompletionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareCompletions not implemented")
+}
+func (UnimplementedLLMGatewayServiceServer) CreateCompletion(context.Context, *CreateCompletionRequest) (*CreateCompletionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCompletion not implemented")
+}
+func (UnimplementedLLMGatewayServiceServer) CreateResponse(context.Context, *CreateResponseRequest) (*CreateResponseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateResponse not implemented")
+}
 func (UnimplementedLLMGatewayServiceServer) CreateEmbeddings(context.Context, *CreateEmbeddingsRequest) (*CreateEmbeddingsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateEmbeddings not implemented")
 }
+func (UnimplementedLLMGatewayServiceServer) CreateEmbeddingsGroup(context.Context, *CreateEmbeddingsGroupRequest) (*CreateEmbeddingsGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmbeddingsGroup not implemented")
+}
+func (UnimplementedLLMGatewayServiceServer) CreateEmbeddingsStream(*CreateEmbeddingsStreamRequest, grpc.ServerStreamingServer[CreateEmbeddingsStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method CreateEmbeddingsStream not implemented")
+}
 func (UnimplementedLLMGatewayServiceServer) GetGeneration(context.Context, *GetGenerationRequest) (*GetGenerationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetGeneration not implemented")
 }
+func (UnimplementedLLMGatewayServiceServer) ListGenerationsByConversation(context.Context, *ListGenerationsByConversationRequest) (*ListGenerationsByConversationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGenerationsByConversation not implemented")
+}
 func (UnimplementedLLMGatewayServiceServer) mustEmbedUnimplementedLLMGatewayServiceServer() {}
 func (UnimplementedLLMGatewayServiceServer) testEmbeddedByValue()                           {}
 
@@ -295,6 +482,42 @@ func _LLMGatewayService_GetUsageCallback_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMGatewayService_SetMaintenanceMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).SetMaintenanceMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_SetMaintenanceMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).SetMaintenanceMode(ctx, req.(*SetMaintenanceModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMGatewayService_PurgeCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).PurgeCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_PurgeCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).PurgeCache(ctx, req.(*PurgeCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LLMGatewayService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListModelsRequest)
 	if err := dec(in); err != nil {
@@ -331,6 +554,24 @@ func _LLMGatewayService_GetModel_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMGatewayService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LLMGatewayService_CreateChatCompletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateChatCompletionRequest)
 	if err := dec(in); err != nil {
@@ -360,6 +601,60 @@ func _LLMGatewayService_CreateChatCompletionStream_Handler(srv interface{}, stre
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LLMGatewayService_CreateChatCompletionStreamServer = grpc.ServerStreamingServer[CreateChatCompletionStreamResponse]
 
+func _LLMGatewayService_CompareCompletions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareCompletionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).CompareCompletions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_CompareCompletions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).CompareCompletions(ctx, req.(*CompareCompletionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMGatewayService_CreateCompletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCompletionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).CreateCompletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_CreateCompletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).CreateCompletion(ctx, req.(*CreateCompletionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMGatewayService_CreateResponse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateResponseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).CreateResponse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_CreateResponse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).CreateResponse(ctx, req.(*CreateResponseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LLMGatewayService_CreateEmbeddings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateEmbeddingsRequest)
 	if err := dec(in); err != nil {
@@ -378,6 +673,35 @@ func _LLMGatewayService_CreateEmbeddings_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMGatewayService_CreateEmbeddingsGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmbeddingsGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).CreateEmbeddingsGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_CreateEmbeddingsGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).CreateEmbeddingsGroup(ctx, req.(*CreateEmbeddingsGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMGatewayService_CreateEmbeddingsStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CreateEmbeddingsStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LLMGatewayServiceServer).CreateEmbeddingsStream(m, &grpc.GenericServerStream[CreateEmbeddingsStreamRequest, CreateEmbeddingsStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMGatewayService_CreateEmbeddingsStreamServer = grpc.ServerStreamingServer[CreateEmbeddingsStreamResponse]
+
 func _LLMGatewayService_GetGeneration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetGenerationRequest)
 	if err := dec(in); err != nil {
@@ -396,6 +720,24 @@ func _LLMGatewayService_GetGeneration_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMGatewayService_ListGenerationsByConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGenerationsByConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMGatewayServiceServer).ListGenerationsByConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMGatewayService_ListGenerationsByConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMGatewayServiceServer).ListGenerationsByConversation(ctx, req.(*ListGenerationsByConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LLMGatewayService_ServiceDesc is the grpc.ServiceDesc for LLMGatewayService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -415,6 +757,14 @@ var LLMGatewayService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUsageCallback",
 			Handler:    _LLMGatewayService_GetUsageCallback_Handler,
 		},
+		{
+			MethodName: "SetMaintenanceMode",
+			Handler:    _LLMGatewayService_SetMaintenanceMode_Handler,
+		},
+		{
+			MethodName: "PurgeCache",
+			Handler:    _LLMGatewayService_PurgeCache_Handler,
+		},
 		{
 			MethodName: "ListModels",
 			Handler:    _LLMGatewayService_ListModels_Handler,
@@ -423,18 +773,42 @@ var LLMGatewayService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetModel",
 			Handler:    _LLMGatewayService_GetModel_Handler,
 		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _LLMGatewayService_GetCapabilities_Handler,
+		},
 		{
 			MethodName: "CreateChatCompletion",
 			Handler:    _LLMGatewayService_CreateChatCompletion_Handler,
 		},
+		{
+			MethodName: "CompareCompletions",
+			Handler:    _LLMGatewayService_CompareCompletions_Handler,
+		},
+		{
+			MethodName: "CreateCompletion",
+			Handler:    _LLMGatewayService_CreateCompletion_Handler,
+		},
+		{
+			MethodName: "CreateResponse",
+			Handler:    _LLMGatewayService_CreateResponse_Handler,
+		},
 		{
 			MethodName: "CreateEmbeddings",
 			Handler:    _LLMGatewayService_CreateEmbeddings_Handler,
 		},
+		{
+			MethodName: "CreateEmbeddingsGroup",
+			Handler:    _LLMGatewayService_CreateEmbeddingsGroup_Handler,
+		},
 		{
 			MethodName: "GetGeneration",
 			Handler:    _LLMGatewayService_GetGeneration_Handler,
 		},
+		{
+			MethodName: "ListGenerationsByConversation",
+			Handler:    _LLMGatewayService_ListGenerationsByConversation_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -442,6 +816,11 @@ var LLMGatewayService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _LLMGatewayService_CreateChatCompletionStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "CreateEmbeddingsStream",
+			Handler:       _LLMGatewayService_CreateEmbeddingsStream_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "llmgateway/v1/gateway.proto",
 }
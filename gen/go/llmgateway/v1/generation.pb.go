@@ -32,7 +32,28 @@ type Generation struct {
 	// Unix timestamp when this generation was created.
 	Created int64 `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
 	// Token usage statistics.
-	Usage         *TokenUsage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	Usage *TokenUsage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Conversation the request was tagged with, if any.
+	ConversationId string `protobuf:"bytes,5,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	// Request metadata; only set when requested with include_metadata.
+	// Upstream provider that served the request.
+	Provider string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Model name sent to the provider (after catalog routing).
+	UpstreamModel string `protobuf:"bytes,7,opt,name=upstream_model,json=upstreamModel,proto3" json:"upstream_model,omitempty"`
+	// Authenticated subject that made the request.
+	Subject string `protobuf:"bytes,8,opt,name=subject,proto3" json:"subject,omitempty"`
+	// Finish reason of the first choice; empty for embeddings.
+	FinishReason string `protobuf:"bytes,9,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+	// Request latency in milliseconds, measured by the gateway.
+	LatencyMs int64 `protobuf:"varint,10,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// The upstream provider's own request id, for support escalations.
+	ProviderRequestId string `protobuf:"bytes,11,opt,name=provider_request_id,json=providerRequestId,proto3" json:"provider_request_id,omitempty"`
+	// Empty for a completed request; "client_cancelled" when the client aborted a
+	// stream and usage covers only the tokens generated before that.
+	Status string `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	// The provider's response ID when id was issued by the gateway
+	// (llm.gateway_generation_ids); empty when id is the upstream one.
+	UpstreamId    string `protobuf:"bytes,13,opt,name=upstream_id,json=upstreamId,proto3" json:"upstream_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -95,11 +116,77 @@ func (x *Generation) GetUsage() *TokenUsage {
 	return nil
 }
 
+func (x *Generation) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *Generation) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Generation) GetUpstreamModel() string {
+	if x != nil {
+		return x.UpstreamModel
+	}
+	return ""
+}
+
+func (x *Generation) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Generation) GetFinishReason() string {
+	if x != nil {
+		return x.FinishReason
+	}
+	return ""
+}
+
+func (x *Generation) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *Generation) GetProviderRequestId() string {
+	if x != nil {
+		return x.ProviderRequestId
+	}
+	return ""
+}
+
+func (x *Generation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Generation) GetUpstreamId() string {
+	if x != nil {
+		return x.UpstreamId
+	}
+	return ""
+}
+
 type GetGenerationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Also return the stored request metadata (provider, upstream model,
+	// subject, finish reason, latency, provider request id).
+	IncludeMetadata bool `protobuf:"varint,2,opt,name=include_metadata,json=includeMetadata,proto3" json:"include_metadata,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetGenerationRequest) Reset() {
@@ -139,6 +226,13 @@ func (x *GetGenerationRequest) GetId() string {
 	return ""
 }
 
+func (x *GetGenerationRequest) GetIncludeMetadata() bool {
+	if x != nil {
+		return x.IncludeMetadata
+	}
+	return false
+}
+
 type GetGenerationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Generation    *Generation            `protobuf:"bytes,1,opt,name=generation,proto3" json:"generation,omitempty"`
@@ -183,23 +277,129 @@ func (x *GetGenerationResponse) GetGeneration() *Generation {
 	return nil
 }
 
+type ListGenerationsByConversationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListGenerationsByConversationRequest) Reset() {
+	*x = ListGenerationsByConversationRequest{}
+	mi := &file_llmgateway_v1_generation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGenerationsByConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGenerationsByConversationRequest) ProtoMessage() {}
+
+func (x *ListGenerationsByConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_generation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGenerationsByConversationRequest.ProtoReflect.Descriptor instead.
+func (*ListGenerationsByConversationRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_generation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListGenerationsByConversationRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+type ListGenerationsByConversationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The caller's generations in the conversation, oldest first.
+	Generations   []*Generation `protobuf:"bytes,1,rep,name=generations,proto3" json:"generations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGenerationsByConversationResponse) Reset() {
+	*x = ListGenerationsByConversationResponse{}
+	mi := &file_llmgateway_v1_generation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGenerationsByConversationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGenerationsByConversationResponse) ProtoMessage() {}
+
+func (x *ListGenerationsByConversationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_generation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGenerationsByConversationResponse.ProtoReflect.Descriptor instead.
+func (*ListGenerationsByConversationResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_generation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListGenerationsByConversationResponse) GetGenerations() []*Generation {
+	if x != nil {
+		return x.Generations
+	}
+	return nil
+}
+
 var File_llmgateway_v1_generation_proto protoreflect.FileDescriptor
 
 const file_llmgateway_v1_generation_proto_rawDesc = "" +
 	"\n" +
-	"\x1ellmgateway/v1/generation.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\x1a\x18llmgateway/v1/chat.proto\"}\n" +
+	"\x1ellmgateway/v1/generation.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\x1a\x18llmgateway/v1/chat.proto\"\xb0\x03\n" +
 	"\n" +
 	"Generation\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05model\x18\x02 \x01(\tR\x05model\x12\x18\n" +
 	"\acreated\x18\x03 \x01(\x03R\acreated\x12/\n" +
-	"\x05usage\x18\x04 \x01(\v2\x19.llmgateway.v1.TokenUsageR\x05usage\"+\n" +
+	"\x05usage\x18\x04 \x01(\v2\x19.llmgateway.v1.TokenUsageR\x05usage\x12'\n" +
+	"\x0fconversation_id\x18\x05 \x01(\tR\x0econversationId\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x12%\n" +
+	"\x0eupstream_model\x18\a \x01(\tR\rupstreamModel\x12\x18\n" +
+	"\asubject\x18\b \x01(\tR\asubject\x12#\n" +
+	"\rfinish_reason\x18\t \x01(\tR\ffinishReason\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\n" +
+	" \x01(\x03R\tlatencyMs\x12.\n" +
+	"\x13provider_request_id\x18\v \x01(\tR\x11providerRequestId\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\x12\x1f\n" +
+	"\vupstream_id\x18\r \x01(\tR\n" +
+	"upstreamId\"V\n" +
 	"\x14GetGenerationRequest\x12\x13\n" +
-	"\x02id\x18\x01 \x01(\tB\x03\xe0A\x02R\x02id\"R\n" +
+	"\x02id\x18\x01 \x01(\tB\x03\xe0A\x02R\x02id\x12)\n" +
+	"\x10include_metadata\x18\x02 \x01(\bR\x0fincludeMetadata\"R\n" +
 	"\x15GetGenerationResponse\x129\n" +
 	"\n" +
 	"generation\x18\x01 \x01(\v2\x19.llmgateway.v1.GenerationR\n" +
-	"generationBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
+	"generation\"T\n" +
+	"$ListGenerationsByConversationRequest\x12,\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tB\x03\xe0A\x02R\x0econversationId\"d\n" +
+	"%ListGenerationsByConversationResponse\x12;\n" +
+	"\vgenerations\x18\x01 \x03(\v2\x19.llmgateway.v1.GenerationR\vgenerationsBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
 
 var (
 	file_llmgateway_v1_generation_proto_rawDescOnce sync.Once
@@ -213,21 +413,24 @@ func file_llmgateway_v1_generation_proto_rawDescGZIP() []byte {
 	return file_llmgateway_v1_generation_proto_rawDescData
 }
 
-var file_llmgateway_v1_generation_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_llmgateway_v1_generation_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_llmgateway_v1_generation_proto_goTypes = []any{
-	(*Generation)(nil),            // 0: llmgateway.v1.Generation
-	(*GetGenerationRequest)(nil),  // 1: llmgateway.v1.GetGenerationRequest
-	(*GetGenerationResponse)(nil), // 2: llmgateway.v1.GetGenerationResponse
-	(*TokenUsage)(nil),            // 3: llmgateway.v1.TokenUsage
+	(*Generation)(nil),                            // 0: llmgateway.v1.Generation
+	(*GetGenerationRequest)(nil),                  // 1: llmgateway.v1.GetGenerationRequest
+	(*GetGenerationResponse)(nil),                 // 2: llmgateway.v1.GetGenerationResponse
+	(*ListGenerationsByConversationRequest)(nil),  // 3: llmgateway.v1.ListGenerationsByConversationRequest
+	(*ListGenerationsByConversationResponse)(nil), // 4: llmgateway.v1.ListGenerationsByConversationResponse
+	(*TokenUsage)(nil),                            // 5: llmgateway.v1.TokenUsage
 }
 var file_llmgateway_v1_generation_proto_depIdxs = []int32{
-	3, // 0: llmgateway.v1.Generation.usage:type_name -> llmgateway.v1.TokenUsage
+	5, // 0: llmgateway.v1.Generation.usage:type_name -> llmgateway.v1.TokenUsage
 	0, // 1: llmgateway.v1.GetGenerationResponse.generation:type_name -> llmgateway.v1.Generation
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0, // 2: llmgateway.v1.ListGenerationsByConversationResponse.generations:type_name -> llmgateway.v1.Generation
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_llmgateway_v1_generation_proto_init() }
@@ -242,7 +445,7 @@ func file_llmgateway_v1_generation_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_generation_proto_rawDesc), len(file_llmgateway_v1_generation_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -31,7 +31,13 @@ type Model struct {
 	// Provider identifier (optional), e.g. "openai".
 	Provider string `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
 	// Arbitrary capability tags (optional), e.g. "chat", "vision", "tools".
-	Capabilities  []string `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Capabilities []string `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// Maximum prompt + completion tokens; 0 when unknown. Set by GetModel.
+	ContextWindow uint32 `protobuf:"varint,5,opt,name=context_window,json=contextWindow,proto3" json:"context_window,omitempty"`
+	// Maximum completion tokens per request; 0 when unknown. Set by GetModel.
+	MaxOutputTokens uint32 `protobuf:"varint,6,opt,name=max_output_tokens,json=maxOutputTokens,proto3" json:"max_output_tokens,omitempty"`
+	// List price; unset when unknown. Set by GetModel.
+	Pricing       *ModelPricing `protobuf:"bytes,7,opt,name=pricing,proto3" json:"pricing,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -94,15 +100,93 @@ func (x *Model) GetCapabilities() []string {
 	return nil
 }
 
+func (x *Model) GetContextWindow() uint32 {
+	if x != nil {
+		return x.ContextWindow
+	}
+	return 0
+}
+
+func (x *Model) GetMaxOutputTokens() uint32 {
+	if x != nil {
+		return x.MaxOutputTokens
+	}
+	return 0
+}
+
+func (x *Model) GetPricing() *ModelPricing {
+	if x != nil {
+		return x.Pricing
+	}
+	return nil
+}
+
+// Model list price in USD.
+type ModelPricing struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	InputPerMillionTokens  float64                `protobuf:"fixed64,1,opt,name=input_per_million_tokens,json=inputPerMillionTokens,proto3" json:"input_per_million_tokens,omitempty"`
+	OutputPerMillionTokens float64                `protobuf:"fixed64,2,opt,name=output_per_million_tokens,json=outputPerMillionTokens,proto3" json:"output_per_million_tokens,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ModelPricing) Reset() {
+	*x = ModelPricing{}
+	mi := &file_llmgateway_v1_models_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelPricing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelPricing) ProtoMessage() {}
+
+func (x *ModelPricing) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_models_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelPricing.ProtoReflect.Descriptor instead.
+func (*ModelPricing) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ModelPricing) GetInputPerMillionTokens() float64 {
+	if x != nil {
+		return x.InputPerMillionTokens
+	}
+	return 0
+}
+
+func (x *ModelPricing) GetOutputPerMillionTokens() float64 {
+	if x != nil {
+		return x.OutputPerMillionTokens
+	}
+	return 0
+}
+
 type ListModelsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of models to return (optional). 0 returns all remaining models.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque cursor from a previous response's next_page_token (optional).
+	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListModelsRequest) Reset() {
 	*x = ListModelsRequest{}
-	mi := &file_llmgateway_v1_models_proto_msgTypes[1]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -114,7 +198,7 @@ func (x *ListModelsRequest) String() string {
 func (*ListModelsRequest) ProtoMessage() {}
 
 func (x *ListModelsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_models_proto_msgTypes[1]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -127,19 +211,36 @@ func (x *ListModelsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListModelsRequest.ProtoReflect.Descriptor instead.
 func (*ListModelsRequest) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{1}
+	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListModelsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListModelsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
 }
 
 type ListModelsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []*Model               `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Models sorted by id.
+	Data []*Model `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	// Cursor for the next page; empty when there are no more models.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListModelsResponse) Reset() {
 	*x = ListModelsResponse{}
-	mi := &file_llmgateway_v1_models_proto_msgTypes[2]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -151,7 +252,7 @@ func (x *ListModelsResponse) String() string {
 func (*ListModelsResponse) ProtoMessage() {}
 
 func (x *ListModelsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_models_proto_msgTypes[2]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -164,7 +265,7 @@ func (x *ListModelsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListModelsResponse.ProtoReflect.Descriptor instead.
 func (*ListModelsResponse) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{2}
+	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ListModelsResponse) GetData() []*Model {
@@ -174,6 +275,13 @@ func (x *ListModelsResponse) GetData() []*Model {
 	return nil
 }
 
+func (x *ListModelsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type GetModelRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -183,7 +291,7 @@ type GetModelRequest struct {
 
 func (x *GetModelRequest) Reset() {
 	*x = GetModelRequest{}
-	mi := &file_llmgateway_v1_models_proto_msgTypes[3]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -195,7 +303,7 @@ func (x *GetModelRequest) String() string {
 func (*GetModelRequest) ProtoMessage() {}
 
 func (x *GetModelRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_models_proto_msgTypes[3]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,7 +316,7 @@ func (x *GetModelRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetModelRequest.ProtoReflect.Descriptor instead.
 func (*GetModelRequest) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{3}
+	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetModelRequest) GetId() string {
@@ -227,7 +335,7 @@ type GetModelResponse struct {
 
 func (x *GetModelResponse) Reset() {
 	*x = GetModelResponse{}
-	mi := &file_llmgateway_v1_models_proto_msgTypes[4]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -239,7 +347,7 @@ func (x *GetModelResponse) String() string {
 func (*GetModelResponse) ProtoMessage() {}
 
 func (x *GetModelResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_llmgateway_v1_models_proto_msgTypes[4]
+	mi := &file_llmgateway_v1_models_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -252,7 +360,7 @@ func (x *GetModelResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetModelResponse.ProtoReflect.Descriptor instead.
 func (*GetModelResponse) Descriptor() ([]byte, []int) {
-	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{4}
+	return file_llmgateway_v1_models_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetModelResponse) GetModel() *Model {
@@ -266,15 +374,25 @@ var File_llmgateway_v1_models_proto protoreflect.FileDescriptor
 
 const file_llmgateway_v1_models_proto_rawDesc = "" +
 	"\n" +
-	"\x1allmgateway/v1/models.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\"p\n" +
+	"\x1allmgateway/v1/models.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\"\xfa\x01\n" +
 	"\x05Model\x12\x13\n" +
 	"\x02id\x18\x01 \x01(\tB\x03\xe0A\x02R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
 	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\"\n" +
-	"\fcapabilities\x18\x04 \x03(\tR\fcapabilities\"\x13\n" +
-	"\x11ListModelsRequest\">\n" +
+	"\fcapabilities\x18\x04 \x03(\tR\fcapabilities\x12%\n" +
+	"\x0econtext_window\x18\x05 \x01(\rR\rcontextWindow\x12*\n" +
+	"\x11max_output_tokens\x18\x06 \x01(\rR\x0fmaxOutputTokens\x125\n" +
+	"\apricing\x18\a \x01(\v2\x1b.llmgateway.v1.ModelPricingR\apricing\"\x82\x01\n" +
+	"\fModelPricing\x127\n" +
+	"\x18input_per_million_tokens\x18\x01 \x01(\x01R\x15inputPerMillionTokens\x129\n" +
+	"\x19output_per_million_tokens\x18\x02 \x01(\x01R\x16outputPerMillionTokens\"O\n" +
+	"\x11ListModelsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"f\n" +
 	"\x12ListModelsResponse\x12(\n" +
-	"\x04data\x18\x01 \x03(\v2\x14.llmgateway.v1.ModelR\x04data\"&\n" +
+	"\x04data\x18\x01 \x03(\v2\x14.llmgateway.v1.ModelR\x04data\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"&\n" +
 	"\x0fGetModelRequest\x12\x13\n" +
 	"\x02id\x18\x01 \x01(\tB\x03\xe0A\x02R\x02id\">\n" +
 	"\x10GetModelResponse\x12*\n" +
@@ -292,22 +410,24 @@ func file_llmgateway_v1_models_proto_rawDescGZIP() []byte {
 	return file_llmgateway_v1_models_proto_rawDescData
 }
 
-var file_llmgateway_v1_models_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_llmgateway_v1_models_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_llmgateway_v1_models_proto_goTypes = []any{
 	(*Model)(nil),              // 0: llmgateway.v1.Model
-	(*ListModelsRequest)(nil),  // 1: llmgateway.v1.ListModelsRequest
-	(*ListModelsResponse)(nil), // 2: llmgateway.v1.ListModelsResponse
-	(*GetModelRequest)(nil),    // 3: llmgateway.v1.GetModelRequest
-	(*GetModelResponse)(nil),   // 4: llmgateway.v1.GetModelResponse
+	(*ModelPricing)(nil),       // 1: llmgateway.v1.ModelPricing
+	(*ListModelsRequest)(nil),  // 2: llmgateway.v1.ListModelsRequest
+	(*ListModelsResponse)(nil), // 3: llmgateway.v1.ListModelsResponse
+	(*GetModelRequest)(nil),    // 4: llmgateway.v1.GetModelRequest
+	(*GetModelResponse)(nil),   // 5: llmgateway.v1.GetModelResponse
 }
 var file_llmgateway_v1_models_proto_depIdxs = []int32{
-	0, // 0: llmgateway.v1.ListModelsResponse.data:type_name -> llmgateway.v1.Model
-	0, // 1: llmgateway.v1.GetModelResponse.model:type_name -> llmgateway.v1.Model
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1, // 0: llmgateway.v1.Model.pricing:type_name -> llmgateway.v1.ModelPricing
+	0, // 1: llmgateway.v1.ListModelsResponse.data:type_name -> llmgateway.v1.Model
+	0, // 2: llmgateway.v1.GetModelResponse.model:type_name -> llmgateway.v1.Model
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_llmgateway_v1_models_proto_init() }
@@ -321,7 +441,7 @@ func file_llmgateway_v1_models_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_models_proto_rawDesc), len(file_llmgateway_v1_models_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
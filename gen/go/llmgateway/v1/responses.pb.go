@@ -0,0 +1,584 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: llmgateway/v1/responses.proto
+
+package llmgatewayv1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OpenAI Responses API (/v1/responses), translated onto chat completions.
+// Only non-streaming text and image input with text output is supported;
+// stateful fields (previous_response_id, store) and tools are not.
+type CreateResponseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Model string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	// A string (one user message) or an array of message items:
+	// {"role": "user" | "assistant" | "system" | "developer", "content": string | [part]}
+	// where a part is {"type": "input_text" | "output_text", "text": ...} or
+	// {"type": "input_image", "image_url": ..., "detail": ...}. Items may carry
+	// "type": "message"; other item types are rejected.
+	Input *structpb.Value `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	// Sent as a leading system message.
+	Instructions string `protobuf:"bytes,3,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	// Unset leaves the limit to the provider; an explicit 0 is rejected.
+	MaxOutputTokens *uint32 `protobuf:"varint,4,opt,name=max_output_tokens,json=maxOutputTokens,proto3,oneof" json:"max_output_tokens,omitempty"`
+	// Unset uses the provider default; an explicit 0 is sent upstream.
+	Temperature *float64 `protobuf:"fixed64,5,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`
+	// Optional user identifier.
+	User string `protobuf:"bytes,6,opt,name=user,proto3" json:"user,omitempty"`
+	// Forwarded like CreateChatCompletionRequest.metadata.
+	Metadata map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Streaming is not supported yet; true is rejected.
+	Stream        bool `protobuf:"varint,8,opt,name=stream,proto3" json:"stream,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResponseRequest) Reset() {
+	*x = CreateResponseRequest{}
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponseRequest) ProtoMessage() {}
+
+func (x *CreateResponseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponseRequest.ProtoReflect.Descriptor instead.
+func (*CreateResponseRequest) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_responses_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateResponseRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateResponseRequest) GetInput() *structpb.Value {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *CreateResponseRequest) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *CreateResponseRequest) GetMaxOutputTokens() uint32 {
+	if x != nil && x.MaxOutputTokens != nil {
+		return *x.MaxOutputTokens
+	}
+	return 0
+}
+
+func (x *CreateResponseRequest) GetTemperature() float64 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *CreateResponseRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateResponseRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CreateResponseRequest) GetStream() bool {
+	if x != nil {
+		return x.Stream
+	}
+	return false
+}
+
+type CreateResponseResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Always "response".
+	Object string `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	// unix seconds
+	CreatedAt int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Model     string `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`
+	// "completed", or "incomplete" when output stopped early (see incomplete_details).
+	Status            string                     `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Output            []*ResponseOutputItem      `protobuf:"bytes,6,rep,name=output,proto3" json:"output,omitempty"`
+	Usage             *ResponseUsage             `protobuf:"bytes,7,opt,name=usage,proto3" json:"usage,omitempty"`
+	IncompleteDetails *ResponseIncompleteDetails `protobuf:"bytes,8,opt,name=incomplete_details,json=incompleteDetails,proto3" json:"incomplete_details,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateResponseResponse) Reset() {
+	*x = CreateResponseResponse{}
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponseResponse) ProtoMessage() {}
+
+func (x *CreateResponseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponseResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponseResponse) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_responses_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateResponseResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateResponseResponse) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *CreateResponseResponse) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *CreateResponseResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateResponseResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CreateResponseResponse) GetOutput() []*ResponseOutputItem {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *CreateResponseResponse) GetUsage() *ResponseUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *CreateResponseResponse) GetIncompleteDetails() *ResponseIncompleteDetails {
+	if x != nil {
+		return x.IncompleteDetails
+	}
+	return nil
+}
+
+// An assistant message in the response output.
+type ResponseOutputItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Always "message".
+	Type          string                   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Role          string                   `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	Status        string                   `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Content       []*ResponseOutputContent `protobuf:"bytes,5,rep,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseOutputItem) Reset() {
+	*x = ResponseOutputItem{}
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseOutputItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseOutputItem) ProtoMessage() {}
+
+func (x *ResponseOutputItem) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseOutputItem.ProtoReflect.Descriptor instead.
+func (*ResponseOutputItem) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_responses_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResponseOutputItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ResponseOutputItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ResponseOutputItem) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ResponseOutputItem) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ResponseOutputItem) GetContent() []*ResponseOutputContent {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type ResponseOutputContent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Always "output_text".
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Text          string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseOutputContent) Reset() {
+	*x = ResponseOutputContent{}
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseOutputContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseOutputContent) ProtoMessage() {}
+
+func (x *ResponseOutputContent) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseOutputContent.ProtoReflect.Descriptor instead.
+func (*ResponseOutputContent) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_responses_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResponseOutputContent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ResponseOutputContent) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ResponseUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InputTokens   uint32                 `protobuf:"varint,1,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens  uint32                 `protobuf:"varint,2,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	TotalTokens   uint32                 `protobuf:"varint,3,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseUsage) Reset() {
+	*x = ResponseUsage{}
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseUsage) ProtoMessage() {}
+
+func (x *ResponseUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseUsage.ProtoReflect.Descriptor instead.
+func (*ResponseUsage) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_responses_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResponseUsage) GetInputTokens() uint32 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *ResponseUsage) GetOutputTokens() uint32 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *ResponseUsage) GetTotalTokens() uint32 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+type ResponseIncompleteDetails struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "max_output_tokens" or "content_filter".
+	Reason        string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseIncompleteDetails) Reset() {
+	*x = ResponseIncompleteDetails{}
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseIncompleteDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseIncompleteDetails) ProtoMessage() {}
+
+func (x *ResponseIncompleteDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_llmgateway_v1_responses_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseIncompleteDetails.ProtoReflect.Descriptor instead.
+func (*ResponseIncompleteDetails) Descriptor() ([]byte, []int) {
+	return file_llmgateway_v1_responses_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResponseIncompleteDetails) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_llmgateway_v1_responses_proto protoreflect.FileDescriptor
+
+const file_llmgateway_v1_responses_proto_rawDesc = "" +
+	"\n" +
+	"\x1dllmgateway/v1/responses.proto\x12\rllmgateway.v1\x1a\x1fgoogle/api/field_behavior.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xc0\x03\n" +
+	"\x15CreateResponseRequest\x12\x19\n" +
+	"\x05model\x18\x01 \x01(\tB\x03\xe0A\x02R\x05model\x121\n" +
+	"\x05input\x18\x02 \x01(\v2\x16.google.protobuf.ValueB\x03\xe0A\x02R\x05input\x12\"\n" +
+	"\finstructions\x18\x03 \x01(\tR\finstructions\x12/\n" +
+	"\x11max_output_tokens\x18\x04 \x01(\rH\x00R\x0fmaxOutputTokens\x88\x01\x01\x12%\n" +
+	"\vtemperature\x18\x05 \x01(\x01H\x01R\vtemperature\x88\x01\x01\x12\x12\n" +
+	"\x04user\x18\x06 \x01(\tR\x04user\x12N\n" +
+	"\bmetadata\x18\a \x03(\v22.llmgateway.v1.CreateResponseRequest.MetadataEntryR\bmetadata\x12\x16\n" +
+	"\x06stream\x18\b \x01(\bR\x06stream\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x14\n" +
+	"\x12_max_output_tokensB\x0e\n" +
+	"\f_temperature\"\xd5\x02\n" +
+	"\x16CreateResponseResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06object\x18\x02 \x01(\tR\x06object\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x129\n" +
+	"\x06output\x18\x06 \x03(\v2!.llmgateway.v1.ResponseOutputItemR\x06output\x122\n" +
+	"\x05usage\x18\a \x01(\v2\x1c.llmgateway.v1.ResponseUsageR\x05usage\x12W\n" +
+	"\x12incomplete_details\x18\b \x01(\v2(.llmgateway.v1.ResponseIncompleteDetailsR\x11incompleteDetails\"\xa4\x01\n" +
+	"\x12ResponseOutputItem\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12>\n" +
+	"\acontent\x18\x05 \x03(\v2$.llmgateway.v1.ResponseOutputContentR\acontent\"?\n" +
+	"\x15ResponseOutputContent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"z\n" +
+	"\rResponseUsage\x12!\n" +
+	"\finput_tokens\x18\x01 \x01(\rR\vinputTokens\x12#\n" +
+	"\routput_tokens\x18\x02 \x01(\rR\foutputTokens\x12!\n" +
+	"\ftotal_tokens\x18\x03 \x01(\rR\vtotalTokens\"3\n" +
+	"\x19ResponseIncompleteDetails\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reasonBHZFgithub.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1;llmgatewayv1b\x06proto3"
+
+var (
+	file_llmgateway_v1_responses_proto_rawDescOnce sync.Once
+	file_llmgateway_v1_responses_proto_rawDescData []byte
+)
+
+func file_llmgateway_v1_responses_proto_rawDescGZIP() []byte {
+	file_llmgateway_v1_responses_proto_rawDescOnce.Do(func() {
+		file_llmgateway_v1_responses_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_llmgateway_v1_responses_proto_rawDesc), len(file_llmgateway_v1_responses_proto_rawDesc)))
+	})
+	return file_llmgateway_v1_responses_proto_rawDescData
+}
+
+var file_llmgateway_v1_responses_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_llmgateway_v1_responses_proto_goTypes = []any{
+	(*CreateResponseRequest)(nil),     // 0: llmgateway.v1.CreateResponseRequest
+	(*CreateResponseResponse)(nil),    // 1: llmgateway.v1.CreateResponseResponse
+	(*ResponseOutputItem)(nil),        // 2: llmgateway.v1.ResponseOutputItem
+	(*ResponseOutputContent)(nil),     // 3: llmgateway.v1.ResponseOutputContent
+	(*ResponseUsage)(nil),             // 4: llmgateway.v1.ResponseUsage
+	(*ResponseIncompleteDetails)(nil), // 5: llmgateway.v1.ResponseIncompleteDetails
+	nil,                               // 6: llmgateway.v1.CreateResponseRequest.MetadataEntry
+	(*structpb.Value)(nil),            // 7: google.protobuf.Value
+}
+var file_llmgateway_v1_responses_proto_depIdxs = []int32{
+	7, // 0: llmgateway.v1.CreateResponseRequest.input:type_name -> google.protobuf.Value
+	6, // 1: llmgateway.v1.CreateResponseRequest.metadata:type_name -> llmgateway.v1.CreateResponseRequest.MetadataEntry
+	2, // 2: llmgateway.v1.CreateResponseResponse.output:type_name -> llmgateway.v1.ResponseOutputItem
+	4, // 3: llmgateway.v1.CreateResponseResponse.usage:type_name -> llmgateway.v1.ResponseUsage
+	5, // 4: llmgateway.v1.CreateResponseResponse.incomplete_details:type_name -> llmgateway.v1.ResponseIncompleteDetails
+	3, // 5: llmgateway.v1.ResponseOutputItem.content:type_name -> llmgateway.v1.ResponseOutputContent
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_llmgateway_v1_responses_proto_init() }
+func file_llmgateway_v1_responses_proto_init() {
+	if File_llmgateway_v1_responses_proto != nil {
+		return
+	}
+	file_llmgateway_v1_responses_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_llmgateway_v1_responses_proto_rawDesc), len(file_llmgateway_v1_responses_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_llmgateway_v1_responses_proto_goTypes,
+		DependencyIndexes: file_llmgateway_v1_responses_proto_depIdxs,
+		MessageInfos:      file_llmgateway_v1_responses_proto_msgTypes,
+	}.Build()
+	File_llmgateway_v1_responses_proto = out.File
+	file_llmgateway_v1_responses_proto_goTypes = nil
+	file_llmgateway_v1_responses_proto_depIdxs = nil
+}
@@ -0,0 +1,61 @@
+package llmgateway
+
+import (
+	"context"
+	"slices"
+	"sort"
+)
+
+// ModelCapabilities describes what one catalog model supports.
+type ModelCapabilities struct {
+	ID string
+	// Capabilities are the configured tags, e.g. "chat", "vision", "tools".
+	Capabilities []string
+	// Streaming, TextCompletions and TokenCounting report whether the model's
+	// provider implements StreamingProvider, CompletionProvider and TokenCounter.
+	Streaming       bool
+	TextCompletions bool
+	TokenCounting   bool
+}
+
+// Capabilities summarizes the features the catalog offers.
+type Capabilities struct {
+	Models []ModelCapabilities // sorted by ID
+	// Streaming, ToolCalling and Vision report whether any model streams or
+	// is tagged "tools" or "vision".
+	Streaming   bool
+	ToolCalling bool
+	Vision      bool
+}
+
+// Capabilities reports per-model capabilities of the catalog, derived from
+// configured tags and what each model's provider (in ctx's pool) implements.
+// Models whose provider cannot be resolved are skipped.
+func (s *Service) Capabilities(ctx context.Context) Capabilities {
+	s.modelsMu.RLock()
+	models := s.models
+	s.modelsMu.RUnlock()
+
+	var out Capabilities
+	for id, m := range models {
+		p, _, _, err := s.resolveProviderAndUpstreamModel(ctx, id)
+		if err != nil {
+			continue
+		}
+		_, streaming := p.(StreamingProvider)
+		_, completions := p.(CompletionProvider)
+		_, counting := p.(TokenCounter)
+		out.Models = append(out.Models, ModelCapabilities{
+			ID:              id,
+			Capabilities:    m.Capabilities,
+			Streaming:       streaming,
+			TextCompletions: completions,
+			TokenCounting:   counting,
+		})
+		out.Streaming = out.Streaming || streaming
+		out.ToolCalling = out.ToolCalling || slices.Contains(m.Capabilities, "tools")
+		out.Vision = out.Vision || slices.Contains(m.Capabilities, "vision")
+	}
+	sort.Slice(out.Models, func(i, j int) bool { return out.Models[i].ID < out.Models[j].ID })
+	return out
+}
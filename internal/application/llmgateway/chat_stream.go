@@ -0,0 +1,145 @@
+package llmgateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// CreateChatCompletionStream streams a chat completion, calling emit for each
+// chunk. Streams are not retried: a failure after the first chunk can't be
+// replayed transparently to the caller.
+func (s *Service) CreateChatCompletionStream(ctx context.Context, req llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error {
+	if err := s.checkMaintenance(); err != nil {
+		return err
+	}
+	if err := s.validateChatCompletionRequest(req); err != nil {
+		return err
+	}
+	if err := s.checkModelAccess(req.Subject, req.Model); err != nil {
+		return err
+	}
+	s.applyModelDefaults(&req)
+	if err := s.checkContextWindow(ctx, req); err != nil {
+		return err
+	}
+	req.Metadata = s.filterMetadata(req.Metadata)
+	req.User = s.defaultUser(req.User, req.Subject)
+	done := s.trackModelRequest("chat.completions.stream", req.Model)
+	defer done()
+
+	routedModel := req.Model
+	p, providerName, upstreamModel, err := s.resolveProviderAndUpstreamModel(ctx, routedModel)
+	if err != nil {
+		return err
+	}
+	sp, ok := p.(StreamingProvider)
+	if !ok {
+		return llm.InvalidArgument("provider does not support streaming: " + providerName)
+	}
+	tr := debugTraceFrom(ctx)
+	tr.setRoute(providerName, upstreamModel)
+	req.Model = upstreamModel
+
+	if err := s.waitModelRateLimit(ctx, routedModel); err != nil {
+		return err
+	}
+	release, err := s.upstreamSlots.acquire(ctx, tierPriority(req.ServiceTier))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	var (
+		id         string
+		upstreamID string // set when id is gateway-issued
+		created    int64
+		usage      llm.TokenUsage
+		finish     string
+		output     strings.Builder
+	)
+	filtered, flushFiltered, blocked := s.filterChatStream(func(c llm.ChatCompletionChunk) error {
+		if id == "" {
+			id, created = c.ID, c.Created
+			upstreamID = s.issueGenerationID(&id)
+		}
+		if s.newGenerationID != nil {
+			c.ID = id
+		}
+		if c.Usage != nil {
+			usage = *c.Usage
+		}
+		for _, ch := range c.Choices {
+			if ch.Index == 0 {
+				output.WriteString(ch.Delta.Content)
+				if ch.FinishReason != "" {
+					finish = ch.FinishReason
+				}
+			}
+		}
+		return emit(c)
+	})
+	start := time.Now()
+	err = sp.CreateChatCompletionStream(ctx, req, dropChatStreamReasoning(req.IncludeReasoning, s.stripChatStreamTags(routedModel, filtered)))
+	if errors.Is(err, errOutputBlocked) {
+		err = nil // the filter already ended the stream with content_filter
+	} else if err == nil {
+		err = flushFiltered()
+	}
+	latency := time.Since(start)
+	tr.recordAttempt(latency)
+	s.recordProviderOutcome(providerName, err)
+	s.observeUpstreamCall(providerName, routedModel, start, err)
+	saveGeneration := func(ctx context.Context, status string) {
+		if s.generations == nil {
+			return
+		}
+		gen := s.buildGenerationFromChat(routedModel, providerName, upstreamModel, llm.ChatCompletionResponse{
+			ID:      id,
+			Created: created,
+			Usage:   usage,
+			Choices: []llm.ChatCompletionChoice{{FinishReason: finish}},
+		})
+		gen.ConversationID, gen.Subject, gen.Latency = req.ConversationID, req.Subject, latency
+		gen.Status, gen.UpstreamID = status, upstreamID
+		_ = s.generations.Save(ctx, gen) // Best effort, don't fail the request.
+	}
+	if err != nil {
+		// Keep the tokens a cancelled client already consumed, if the provider
+		// reported usage incrementally.
+		if clientCancelled(ctx) && id != "" && usage != (llm.TokenUsage{}) {
+			saveGeneration(context.WithoutCancel(ctx), llm.GenerationStatusClientCancelled)
+		}
+		return err
+	}
+	if !blocked() {
+		s.checkUsageReported(providerName, routedModel, usage, output.Len() > 0)
+	}
+	tr.setUsage(usage.PromptTokens, usage.CompletionTokens)
+
+	// Save generation record for generation queries (best-effort).
+	saveGeneration(ctx, "")
+
+	if s.auditEnabled(req.Subject) {
+		s.audit.Record(ctx, llm.AuditRecord{
+			GenerationID:   id,
+			Operation:      "chat.completions.stream",
+			Subject:        req.Subject,
+			Model:          routedModel,
+			CreatedAt:      time.Now().Unix(),
+			ConversationID: req.ConversationID,
+			Messages:       req.Messages,
+			Output:         []string{output.String()},
+		})
+	}
+	return nil
+}
+
+// clientCancelled reports whether ctx ended because the caller went away, as
+// opposed to a deadline.
+func clientCancelled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,23 @@
+package llmgateway
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// sortChatChoices puts choices in index order; some providers return them
+// out of order, and clients often read choices by position.
+func sortChatChoices(resp *llm.ChatCompletionResponse) {
+	slices.SortStableFunc(resp.Choices, func(a, b llm.ChatCompletionChoice) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+}
+
+// sortCompletionChoices puts text completion choices in index order.
+func sortCompletionChoices(resp *llm.CompletionResponse) {
+	slices.SortStableFunc(resp.Choices, func(a, b llm.CompletionChoice) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+}
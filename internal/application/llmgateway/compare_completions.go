@@ -0,0 +1,82 @@
+package llmgateway
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// MaxCompareModels bounds the fan-out of one CompareCompletions call.
+const MaxCompareModels = 8
+
+// ComparedCompletion is one model's response in a CompareCompletions call.
+type ComparedCompletion struct {
+	Model    string
+	Response llm.ChatCompletionResponse
+}
+
+// CompareCompletions sends req to every model in models concurrently and
+// returns their responses in the order of models, e.g. for A/B evaluation.
+// req.Model is ignored. Each model is a separate chat request with its own
+// access check, usage and generation record; if any fails, the whole call fails.
+func (s *Service) CompareCompletions(ctx context.Context, req llm.ChatCompletionRequest, models []string) ([]ComparedCompletion, error) {
+	if err := s.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	if err := s.validateCompareCompletions(req, models); err != nil {
+		return nil, err
+	}
+
+	out := make([]ComparedCompletion, len(models))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, m := range models {
+		r := req
+		r.Model = m
+		g.Go(func() error {
+			resp, err := s.CreateChatCompletion(gctx, r)
+			if err != nil {
+				return fmt.Errorf("%s: %w", m, err)
+			}
+			out[i] = ComparedCompletion{Model: m, Response: resp}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Service) validateCompareCompletions(req llm.ChatCompletionRequest, models []string) error {
+	var v llm.Violations
+	switch {
+	case len(models) == 0:
+		v.Add("models", "is required")
+	case len(models) > MaxCompareModels:
+		v.Add("models", fmt.Sprintf("must have at most %d items, got %d", MaxCompareModels, len(models)))
+	}
+	for i, m := range models {
+		field := fmt.Sprintf("models[%d]", i)
+		switch {
+		case m == "":
+			v.Add(field, "must not be empty")
+		case slices.Contains(models[:i], m):
+			v.Add(field, "duplicate model "+m)
+		default:
+			// Only catalog models declare capabilities; ad-hoc provider/model IDs are passed through.
+			if spec, ok := s.lookupModel(m); ok && !slices.Contains(spec.Capabilities, "chat") {
+				v.Add(field, "model "+m+" does not support chat")
+			}
+		}
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	// Message limits are the same for every model; check them once up front.
+	req.Model = models[0]
+	return s.validateChatCompletionRequest(req)
+}
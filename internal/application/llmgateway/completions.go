@@ -0,0 +1,126 @@
+package llmgateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// CreateCompletion runs a legacy text completion. Only providers implementing
+// CompletionProvider can serve it.
+func (s *Service) CreateCompletion(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	if err := s.checkMaintenance(); err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	if err := s.validateCompletionRequest(req); err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	if err := s.checkModelAccess(req.Subject, req.Model); err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	req.User = s.defaultUser(req.User, req.Subject)
+	done := s.trackModelRequest("completions", req.Model)
+	defer done()
+	ctx, cancel := s.withRequestBudget(ctx)
+	defer cancel()
+
+	routedModel := req.Model
+	p, providerName, upstreamModel, err := s.resolveProviderAndUpstreamModel(ctx, routedModel)
+	if err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	cp, ok := p.(CompletionProvider)
+	if !ok {
+		return llm.CompletionResponse{}, llm.InvalidArgument("provider does not support text completions: " + providerName)
+	}
+	tr := debugTraceFrom(ctx)
+	tr.setRoute(providerName, upstreamModel)
+	req.Model = upstreamModel
+
+	if err := s.waitModelRateLimit(ctx, routedModel); err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	release, err := s.upstreamSlots.acquire(ctx, priorityNormal)
+	if err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	defer release()
+
+	start := time.Now()
+	var resp llm.CompletionResponse
+	err = s.withRetry(ctx, false, func(ctx context.Context) error {
+		var err error
+		callStart := time.Now()
+		resp, err = cp.CreateCompletion(ctx, req)
+		s.recordProviderOutcome(providerName, err)
+		s.observeUpstreamCall(providerName, routedModel, callStart, err)
+		return err
+	})
+	if err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	sortCompletionChoices(&resp)
+	s.filterCompletion(&resp)
+	s.dedupCompletionChoices(&resp)
+	upstreamID := s.issueGenerationID(&resp.ID)
+	s.checkUsageReported(providerName, routedModel, resp.Usage, len(resp.Choices) > 0)
+	tr.setUsage(resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+
+	// Save generation record for generation queries (best-effort).
+	if s.generations != nil {
+		gen := llm.Generation{
+			ID:                resp.ID,
+			Model:             routedModel,
+			Created:           resp.Created,
+			Usage:             resp.Usage,
+			Provider:          providerName,
+			UpstreamModel:     upstreamModel,
+			ProviderRequestID: resp.ProviderRequestID,
+			UpstreamID:        upstreamID,
+		}
+		if len(resp.Choices) > 0 {
+			gen.FinishReason = resp.Choices[0].FinishReason
+		}
+		gen.ConversationID, gen.Subject, gen.Latency = req.ConversationID, req.Subject, time.Since(start)
+		_ = s.generations.Save(ctx, gen) // Best effort, don't fail the request.
+	}
+
+	if s.auditEnabled(req.Subject) {
+		output := make([]string, 0, len(resp.Choices))
+		for _, c := range resp.Choices {
+			output = append(output, c.Text)
+		}
+		s.audit.Record(ctx, llm.AuditRecord{
+			GenerationID:   resp.ID,
+			Operation:      "completions",
+			Subject:        req.Subject,
+			Model:          routedModel,
+			CreatedAt:      time.Now().Unix(),
+			ConversationID: req.ConversationID,
+			Input:          []string{req.Prompt},
+			Output:         output,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *Service) validateCompletionRequest(req llm.CompletionRequest) error {
+	var v llm.Violations
+	if req.Model == "" {
+		v.Add("model", "is required")
+	}
+	if req.Prompt == "" {
+		v.Add("prompt", "is required")
+	}
+	if t := req.Temperature; t != nil && (*t < 0 || *t > s.maxTemperature) {
+		v.Add("temperature", fmt.Sprintf("must be between 0 and %g", s.maxTemperature))
+	}
+	validateMaxTokens(req.MaxTokens, &v)
+	validateStop(req.Stop, &v)
+	validateN(req.N, &v)
+	validateConversationID(req.ConversationID, &v)
+	return v.Err()
+}
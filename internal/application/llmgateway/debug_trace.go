@@ -0,0 +1,69 @@
+package llmgateway
+
+import (
+	"context"
+	"time"
+)
+
+// DebugTrace collects per-request diagnostics for sampled debug logging.
+// It holds routing, timing and token counts only, never prompt or response content.
+// A request is traced when its context carries a DebugTrace (see WithDebugTrace).
+type DebugTrace struct {
+	Provider      string
+	UpstreamModel string
+	// Attempts counts upstream calls including retries; UpstreamTime is their total duration.
+	Attempts     int
+	UpstreamTime time.Duration
+
+	PromptTokens     uint32
+	CompletionTokens uint32
+}
+
+type debugTraceKey struct{}
+
+// WithDebugTrace returns a context that makes Service fill the returned trace.
+// The trace must only be read after the use case returns.
+func WithDebugTrace(ctx context.Context) (context.Context, *DebugTrace) {
+	tr := &DebugTrace{}
+	return context.WithValue(ctx, debugTraceKey{}, tr), tr
+}
+
+// debugTraceFrom returns the context's trace, or nil. All DebugTrace methods are nil-safe.
+func debugTraceFrom(ctx context.Context) *DebugTrace {
+	tr, _ := ctx.Value(debugTraceKey{}).(*DebugTrace)
+	return tr
+}
+
+func (tr *DebugTrace) setRoute(provider, upstreamModel string) {
+	if tr == nil {
+		return
+	}
+	tr.Provider = provider
+	tr.UpstreamModel = upstreamModel
+}
+
+func (tr *DebugTrace) recordAttempt(d time.Duration) {
+	if tr == nil {
+		return
+	}
+	tr.Attempts++
+	tr.UpstreamTime += d
+}
+
+// addAttempts adds the upstream attempts recorded in another trace, such as
+// that of a call shared with other requests.
+func (tr *DebugTrace) addAttempts(o DebugTrace) {
+	if tr == nil {
+		return
+	}
+	tr.Attempts += o.Attempts
+	tr.UpstreamTime += o.UpstreamTime
+}
+
+func (tr *DebugTrace) setUsage(prompt, completion uint32) {
+	if tr == nil {
+		return
+	}
+	tr.PromptTokens = prompt
+	tr.CompletionTokens = completion
+}
@@ -0,0 +1,68 @@
+package llmgateway
+
+import (
+	"encoding/json"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// WithChoiceDedup collapses identical choices of unary chat and text
+// completion responses (requests with n > 1 at low temperature often yield
+// duplicates), keeping the first and renumbering indices from 0. Usage is left
+// as the provider reported it, since every choice was generated and billed.
+// Streams are not deduplicated.
+func WithChoiceDedup(enabled bool) Option {
+	return func(s *Service) { s.dedupChoices = enabled }
+}
+
+// dedupChatChoices drops choices whose message and finish reason repeat an
+// earlier choice.
+func (s *Service) dedupChatChoices(resp *llm.ChatCompletionResponse) {
+	if !s.dedupChoices || len(resp.Choices) < 2 {
+		return
+	}
+	seen := make(map[chatChoiceKey]bool, len(resp.Choices))
+	out := resp.Choices[:0:0]
+	for _, c := range resp.Choices {
+		key := newChatChoiceKey(c)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		c.Index = uint32(len(out))
+		out = append(out, c)
+	}
+	resp.Choices = out
+}
+
+// chatChoiceKey identifies a chat choice by its message and finish reason.
+// The message is JSON-encoded since it holds slices, which aren't comparable.
+type chatChoiceKey struct {
+	message      string
+	finishReason string
+}
+
+func newChatChoiceKey(c llm.ChatCompletionChoice) chatChoiceKey {
+	b, _ := json.Marshal(c.Message) // plain data; cannot fail
+	return chatChoiceKey{message: string(b), finishReason: c.FinishReason}
+}
+
+// dedupCompletionChoices drops choices whose text and finish reason repeat an
+// earlier choice.
+func (s *Service) dedupCompletionChoices(resp *llm.CompletionResponse) {
+	if !s.dedupChoices || len(resp.Choices) < 2 {
+		return
+	}
+	seen := make(map[llm.CompletionChoice]bool, len(resp.Choices))
+	out := resp.Choices[:0:0]
+	for _, c := range resp.Choices {
+		key := llm.CompletionChoice{Text: c.Text, FinishReason: c.FinishReason}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		c.Index = uint32(len(out))
+		out = append(out, c)
+	}
+	resp.Choices = out
+}
@@ -0,0 +1,29 @@
+package llmgateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// WithDefaultUserFromSubject fills an empty request `user` with a stable hash
+// of the authenticated subject, so providers can track abuse per tenant
+// without learning subject names. salt keys the hash (HMAC-SHA256); keep it
+// fixed, or upstream user ids change.
+func WithDefaultUserFromSubject(salt string) Option {
+	return func(s *Service) {
+		s.defaultUserFromSubject = true
+		s.defaultUserSalt = []byte(salt)
+	}
+}
+
+// defaultUser returns user, or the subject's hashed id when user is empty and
+// WithDefaultUserFromSubject is set.
+func (s *Service) defaultUser(user, subject string) string {
+	if user != "" || subject == "" || !s.defaultUserFromSubject {
+		return user
+	}
+	mac := hmac.New(sha256.New, s.defaultUserSalt)
+	mac.Write([]byte(subject))
+	return "llmgw-" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
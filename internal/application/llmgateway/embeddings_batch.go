@@ -0,0 +1,149 @@
+package llmgateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// DefaultEmbeddingsStreamBatchSize is the stream batch size used when neither the
+// request nor WithEmbeddingsBatchSize sets one.
+const DefaultEmbeddingsStreamBatchSize = 16
+
+// WithEmbeddingsBatchSize splits embeddings inputs into upstream requests of at
+// most n inputs. n <= 0 sends all inputs in one upstream request.
+func WithEmbeddingsBatchSize(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.embeddingsBatchSize = n
+		}
+	}
+}
+
+// forEachEmbeddingsBatch embeds req.Input in upstream calls of at most size
+// inputs (all at once if size <= 0) and calls fn with each result in order.
+// Embedding indices in results are rebased onto req.Input.
+func (s *Service) forEachEmbeddingsBatch(ctx context.Context, p Provider, providerName, upstreamModel string, req llm.EmbeddingsRequest, size int, fn func(llm.EmbeddingsResponse) error) error {
+	if size <= 0 {
+		size = len(req.Input)
+	}
+	for offset := 0; offset < len(req.Input); offset += size {
+		end := min(offset+size, len(req.Input))
+		upstreamReq := req
+		upstreamReq.Model = upstreamModel
+		upstreamReq.Input = req.Input[offset:end]
+
+		var resp llm.EmbeddingsResponse
+		err := s.withRetry(ctx, true, func(ctx context.Context) error {
+			var err error
+			callStart := time.Now()
+			resp, err = p.CreateEmbeddings(ctx, upstreamReq)
+			s.recordProviderOutcome(providerName, err)
+			s.observeUpstreamCall(providerName, req.Model, callStart, err)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		s.checkUsageReported(providerName, req.Model, llm.TokenUsage{TotalTokens: resp.Usage.TotalTokens}, len(resp.Data) > 0)
+		for i := range resp.Data {
+			resp.Data[i].Index += uint32(offset)
+		}
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeEmbeddings appends batch b to the accumulated response acc. The first
+// batch's ID, model and provider request id identify the merged response.
+func mergeEmbeddings(acc, b llm.EmbeddingsResponse) llm.EmbeddingsResponse {
+	if acc.ID == "" {
+		acc.ID = b.ID
+	}
+	if acc.ProviderRequestID == "" {
+		acc.ProviderRequestID = b.ProviderRequestID
+	}
+	if acc.Model == "" {
+		acc.Model = b.Model
+	}
+	acc.Data = append(acc.Data, b.Data...)
+	acc.Usage.PromptTokens += b.Usage.PromptTokens
+	acc.Usage.TotalTokens += b.Usage.TotalTokens
+	return acc
+}
+
+// CreateEmbeddingsStream embeds req.Input in batches of batchSize inputs and
+// calls emit with each batch's result as soon as it completes, so callers can
+// report progress. batchSize <= 0 uses the configured batch size, or
+// DefaultEmbeddingsStreamBatchSize. Each batch is recorded as its own generation.
+func (s *Service) CreateEmbeddingsStream(ctx context.Context, req llm.EmbeddingsRequest, batchSize int, emit func(llm.EmbeddingsResponse) error) error {
+	if err := s.checkMaintenance(); err != nil {
+		return err
+	}
+	if err := s.validateEmbeddingsRequest(req); err != nil {
+		return err
+	}
+	if req.AutoChunk {
+		return llm.InvalidArgument("auto_chunk is not supported for streamed embeddings")
+	}
+	if err := s.checkModelAccess(req.Subject, req.Model); err != nil {
+		return err
+	}
+	req.User = s.defaultUser(req.User, req.Subject)
+	done := s.trackModelRequest("embeddings.stream", req.Model)
+	defer done()
+
+	routedModel := req.Model
+	p, providerName, upstreamModel, err := s.resolveProviderAndUpstreamModel(ctx, routedModel)
+	if err != nil {
+		return err
+	}
+	debugTraceFrom(ctx).setRoute(providerName, upstreamModel)
+
+	if batchSize <= 0 {
+		batchSize = s.embeddingsBatchSize
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultEmbeddingsStreamBatchSize
+	}
+	if err := s.waitModelRateLimit(ctx, routedModel); err != nil {
+		return err
+	}
+	var firstID string
+	batchStart := time.Now()
+	received := batchStart.Unix()
+	err = s.forEachEmbeddingsBatch(ctx, p, providerName, upstreamModel, req, batchSize, func(resp llm.EmbeddingsResponse) error {
+		upstreamID := s.issueGenerationID(&resp.ID)
+		if firstID == "" {
+			firstID = resp.ID
+		}
+		resp.Created = received
+		if s.generations != nil {
+			gen := s.buildGenerationFromEmbeddings(routedModel, providerName, upstreamModel, resp)
+			gen.ConversationID, gen.Subject, gen.Latency = req.ConversationID, req.Subject, time.Since(batchStart)
+			gen.UpstreamID = upstreamID
+			_ = s.generations.Save(ctx, gen) // Best effort.
+		}
+		err := emit(resp)
+		batchStart = time.Now()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.auditEnabled(req.Subject) {
+		s.audit.Record(ctx, llm.AuditRecord{
+			GenerationID: firstID,
+			Operation:    "embeddings.stream",
+			Subject:      req.Subject,
+			Model:        routedModel,
+			CreatedAt:    time.Now().Unix(),
+			Input:        req.Input,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package llmgateway
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// embeddingsCacheName names the embeddings result cache in CacheLookup metrics.
+const embeddingsCacheName = "embeddings"
+
+// WithEmbeddingsCache keeps up to size embeddings responses for ttl, so
+// repeated requests skip the provider. Entries are keyed
+// "<routed model>:<coalescing key>" and can be evicted with PurgeCache.
+// size <= 0 or ttl <= 0 disables it.
+func WithEmbeddingsCache(size int, ttl time.Duration) Option {
+	return func(s *Service) {
+		if size <= 0 || ttl <= 0 {
+			s.embeddingsCache = nil
+			return
+		}
+		s.embeddingsCache = &embeddingsCache{size: size, ttl: ttl, entries: make(map[string]embeddingsCacheEntry)}
+	}
+}
+
+type embeddingsCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]embeddingsCacheEntry
+}
+
+// embeddingsCacheKey prefixes the coalescing key with the routed model, so
+// entries can be purged per model or provider by key prefix.
+func embeddingsCacheKey(model, flightKey string) string {
+	return model + ":" + flightKey
+}
+
+type embeddingsCacheEntry struct {
+	resp    llm.EmbeddingsResponse
+	expires time.Time
+}
+
+func (c *embeddingsCache) get(key string, now time.Time) (llm.EmbeddingsResponse, bool) {
+	if c == nil {
+		return llm.EmbeddingsResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !now.Before(e.expires) {
+		return llm.EmbeddingsResponse{}, false
+	}
+	return e.resp, true
+}
+
+// put stores resp; when full, expired entries are swept and resp is dropped
+// if none were.
+func (c *embeddingsCache) put(key string, resp llm.EmbeddingsResponse, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.size {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.size {
+			return
+		}
+	}
+	c.entries[key] = embeddingsCacheEntry{resp: resp, expires: now.Add(c.ttl)}
+}
+
+// CachePurge selects cache entries to evict. All takes precedence; otherwise
+// an entry must match every filter that is set.
+type CachePurge struct {
+	All bool
+	// Model matches entries of exactly this routed model.
+	Model string
+	// KeyPrefix matches entry keys ("<routed model>:<hash>") with this prefix,
+	// e.g. "openrouter/" for a whole provider.
+	KeyPrefix string
+}
+
+// PurgeCache evicts the selected response cache entries and returns how many
+// were removed; 0 when no cache is enabled.
+func (s *Service) PurgeCache(p CachePurge) (int, error) {
+	if !p.All && p.Model == "" && p.KeyPrefix == "" {
+		return 0, llm.InvalidArgument("select entries to purge: all, model or key_prefix")
+	}
+	return s.embeddingsCache.purge(p), nil
+}
+
+func (c *embeddingsCache) purge(p CachePurge) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for k := range c.entries {
+		model := k[:strings.LastIndex(k, ":")] // model IDs may contain ':'
+		if p.All || ((p.Model == "" || model == p.Model) && strings.HasPrefix(k, p.KeyPrefix)) {
+			delete(c.entries, k)
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,100 @@
+package llmgateway
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// WithEmbeddingsAutoChunk enables auto_chunk embeddings requests: inputs longer
+// than chunkChars runes are split into chunks that are embedded separately and
+// mean-pooled into one vector. chunkChars <= 0 leaves auto_chunk disabled.
+func WithEmbeddingsAutoChunk(chunkChars int) Option {
+	return func(s *Service) {
+		if chunkChars > 0 {
+			s.embeddingsChunkChars = chunkChars
+		}
+	}
+}
+
+// chunkEmbeddingsInput splits each input into chunks of at most
+// s.embeddingsChunkChars runes. It returns the request with the flattened
+// chunks as input and the number of chunks per original input. The chunks
+// are held to the same item limit as client inputs, since they are what
+// reaches the provider.
+func (s *Service) chunkEmbeddingsInput(req llm.EmbeddingsRequest) (llm.EmbeddingsRequest, []int, error) {
+	counts := make([]int, len(req.Input))
+	chunked := make([]string, 0, len(req.Input))
+	for i, in := range req.Input {
+		parts := splitRunes(in, s.embeddingsChunkChars)
+		counts[i] = len(parts)
+		chunked = append(chunked, parts...)
+		if len(chunked) > s.maxEmbeddingsInputs {
+			var v llm.Violations
+			v.Add("input", fmt.Sprintf("must split into at most %d chunks in total with auto_chunk", s.maxEmbeddingsInputs))
+			return req, nil, v.Err()
+		}
+	}
+	req.Input = chunked
+	return req, counts, nil
+}
+
+// splitRunes splits s into pieces of at most n runes, preferring to break
+// after whitespace in the second half of a piece so words stay intact.
+func splitRunes(s string, n int) []string {
+	if utf8.RuneCountInString(s) <= n {
+		return []string{s}
+	}
+	var out []string
+	for s != "" {
+		end, runes, lastSpace := 0, 0, -1
+		for end < len(s) && runes < n {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+			runes++
+			if unicode.IsSpace(r) && runes > n/2 {
+				lastSpace = end
+			}
+		}
+		if end < len(s) && lastSpace > 0 {
+			end = lastSpace
+		}
+		out = append(out, s[:end])
+		s = s[end:]
+	}
+	return out
+}
+
+// poolChunkedEmbeddings mean-pools the chunk embeddings in resp back into one
+// embedding per original input, as described by counts. resp may be shared
+// with coalesced callers, so it is not modified.
+func poolChunkedEmbeddings(resp llm.EmbeddingsResponse, counts []int) llm.EmbeddingsResponse {
+	byIndex := make(map[uint32][]float32, len(resp.Data))
+	for _, e := range resp.Data {
+		byIndex[e.Index] = e.Vector
+	}
+
+	data := make([]llm.Embedding, 0, len(counts))
+	next := uint32(0)
+	for i, n := range counts {
+		var sum []float32
+		for j := range n {
+			vec := byIndex[next+uint32(j)]
+			if sum == nil {
+				sum = make([]float32, len(vec))
+			}
+			for k := range min(len(sum), len(vec)) {
+				sum[k] += vec[k]
+			}
+		}
+		for k := range sum {
+			sum[k] /= float32(n)
+		}
+		next += uint32(n)
+		data = append(data, llm.Embedding{Index: uint32(i), Vector: sum, ChunkCount: uint32(n)})
+	}
+	resp.Data = data
+	return resp
+}
@@ -0,0 +1,82 @@
+package llmgateway
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// MaxEmbeddingsGroupModels bounds the fan-out of one CreateEmbeddingsGroup call.
+const MaxEmbeddingsGroupModels = 8
+
+// CreateEmbeddingsGroup embeds req.Input with every model in models
+// concurrently and returns the results keyed by model, e.g. to compare two
+// embedding models for RAG. req.Model is ignored. Each model is a separate
+// embeddings request with its own access check and generation record; if any
+// fails, the whole call fails.
+func (s *Service) CreateEmbeddingsGroup(ctx context.Context, req llm.EmbeddingsRequest, models []string) (map[string]llm.EmbeddingsResponse, error) {
+	if err := s.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	if err := s.validateEmbeddingsGroup(req, models); err != nil {
+		return nil, err
+	}
+
+	results := make([]llm.EmbeddingsResponse, len(models))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, m := range models {
+		r := req
+		r.Model = m
+		g.Go(func() error {
+			resp, err := s.CreateEmbeddings(gctx, r)
+			if err != nil {
+				return fmt.Errorf("%s: %w", m, err)
+			}
+			results[i] = resp
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]llm.EmbeddingsResponse, len(models))
+	for i, m := range models {
+		out[m] = results[i]
+	}
+	return out, nil
+}
+
+func (s *Service) validateEmbeddingsGroup(req llm.EmbeddingsRequest, models []string) error {
+	var v llm.Violations
+	switch {
+	case len(models) == 0:
+		v.Add("models", "is required")
+	case len(models) > MaxEmbeddingsGroupModels:
+		v.Add("models", fmt.Sprintf("must have at most %d items, got %d", MaxEmbeddingsGroupModels, len(models)))
+	}
+	for i, m := range models {
+		field := fmt.Sprintf("models[%d]", i)
+		switch {
+		case m == "":
+			v.Add(field, "must not be empty")
+		case slices.Contains(models[:i], m):
+			v.Add(field, "duplicate model "+m)
+		default:
+			// Only catalog models declare capabilities; ad-hoc provider/model IDs are passed through.
+			if spec, ok := s.lookupModel(m); ok && !slices.Contains(spec.Capabilities, "embeddings") {
+				v.Add(field, "model "+m+" does not support embeddings")
+			}
+		}
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	// Input limits are the same for every model; check them once up front.
+	req.Model = models[0]
+	return s.validateEmbeddingsRequest(req)
+}
@@ -0,0 +1,67 @@
+package llmgateway
+
+import (
+	"context"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// EmptyResponsePolicy decides what a unary chat completion does when the
+// provider succeeds but returns no choices or only empty messages.
+type EmptyResponsePolicy string
+
+const (
+	// EmptyResponsePassThrough returns the empty response as is (the default).
+	EmptyResponsePassThrough EmptyResponsePolicy = "pass_through"
+	// EmptyResponseRetry re-issues the upstream call once, then fails with
+	// llm.ErrEmptyResponse if the response is still empty.
+	EmptyResponseRetry EmptyResponsePolicy = "retry"
+	// EmptyResponseError fails with llm.ErrEmptyResponse.
+	EmptyResponseError EmptyResponsePolicy = "error"
+)
+
+// WithEmptyResponsePolicy sets how empty successful chat responses are handled.
+// Unknown values keep EmptyResponsePassThrough.
+func WithEmptyResponsePolicy(p EmptyResponsePolicy) Option {
+	return func(s *Service) {
+		switch p {
+		case EmptyResponseRetry, EmptyResponseError:
+			s.emptyResponse = p
+		}
+	}
+}
+
+// handleEmptyChat applies the empty-response policy to a successful response.
+// Every empty response is reported to metrics, whatever the policy.
+func (s *Service) handleEmptyChat(ctx context.Context, providerName, model string, resp llm.ChatCompletionResponse, call func(context.Context) (llm.ChatCompletionResponse, error)) (llm.ChatCompletionResponse, error) {
+	if !isEmptyChatResponse(resp) {
+		return resp, nil
+	}
+	s.metrics.EmptyResponse(providerName, s.metricsModel(model))
+	switch s.emptyResponse {
+	case EmptyResponseError:
+		return llm.ChatCompletionResponse{}, llm.ErrEmptyResponse
+	case EmptyResponseRetry:
+		resp, err := call(ctx)
+		if err != nil {
+			return llm.ChatCompletionResponse{}, err
+		}
+		if isEmptyChatResponse(resp) {
+			s.metrics.EmptyResponse(providerName, s.metricsModel(model))
+			return llm.ChatCompletionResponse{}, llm.ErrEmptyResponse
+		}
+		return resp, nil
+	}
+	return resp, nil
+}
+
+// isEmptyChatResponse reports whether resp carries no usable output.
+func isEmptyChatResponse(resp llm.ChatCompletionResponse) bool {
+	for _, c := range resp.Choices {
+		m := c.Message
+		if m.Content != "" || len(m.ContentParts) > 0 || len(m.ToolCalls) > 0 || m.Audio != nil {
+			return false
+		}
+	}
+	return true
+}
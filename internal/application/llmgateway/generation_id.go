@@ -0,0 +1,24 @@
+package llmgateway
+
+import "github.com/google/uuid"
+
+// WithGatewayGenerationIDs replaces upstream response IDs with gateway-issued
+// ones ("gen-" + UUID), so GetGeneration keys don't depend on the provider's ID
+// format or uniqueness. The upstream ID is kept on the generation record.
+func WithGatewayGenerationIDs(enabled bool) Option {
+	return func(s *Service) {
+		if enabled {
+			s.newGenerationID = func() string { return "gen-" + uuid.NewString() }
+		}
+	}
+}
+
+// issueGenerationID replaces *id with a gateway-issued ID when enabled and
+// returns the upstream ID it replaced, or "" when IDs are passed through.
+func (s *Service) issueGenerationID(id *string) (upstreamID string) {
+	if s.newGenerationID == nil {
+		return ""
+	}
+	upstreamID, *id = *id, s.newGenerationID()
+	return upstreamID
+}
@@ -0,0 +1,54 @@
+package llmgateway
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// ImageURLPolicy restricts the image_url content parts a chat request may
+// carry. Remote URLs make the provider fetch them, so deployments can limit
+// which hosts it is pointed at. The zero value allows any image URL.
+type ImageURLPolicy struct {
+	// DataURIOnly rejects every remote URL; images must be inlined as data: URIs.
+	DataURIOnly bool
+	// AllowedHosts restricts remote image hosts when non-empty. Entries are
+	// exact hostnames or "*.example.com" to match any subdomain.
+	AllowedHosts []string
+}
+
+// WithImageURLPolicy sets the policy for image_url content parts.
+func WithImageURLPolicy(p ImageURLPolicy) Option {
+	return func(s *Service) {
+		s.imageURLs = p
+	}
+}
+
+func (s *Service) validateImageURLs(msgs []llm.ChatMessage, v *llm.Violations) {
+	p := s.imageURLs
+	if !p.DataURIOnly && len(p.AllowedHosts) == 0 {
+		return
+	}
+	for i, m := range msgs {
+		for j, part := range m.ContentParts {
+			if part.ImageURL == nil || strings.HasPrefix(part.ImageURL.URL, "data:") {
+				continue
+			}
+			field := fmt.Sprintf("messages[%d].content[%d].image_url.url", i, j)
+			if p.DataURIOnly {
+				v.Add(field, "must be a data: URI; remote image URLs are disabled")
+				continue
+			}
+			u, err := url.Parse(part.ImageURL.URL)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+				v.Add(field, "must be an http(s) URL or a data: URI")
+				continue
+			}
+			if !llm.HostAllowed(u.Hostname(), p.AllowedHosts) {
+				v.Add(field, "host "+u.Hostname()+" is not allowed")
+			}
+		}
+	}
+}
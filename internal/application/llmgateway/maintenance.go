@@ -0,0 +1,36 @@
+package llmgateway
+
+import (
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// maintenanceState is swapped atomically so model calls check it lock-free.
+type maintenanceState struct {
+	retryAfter time.Duration
+}
+
+// SetMaintenance turns maintenance mode on or off. While on, model calls
+// (chat, completions, embeddings) fail with *llm.MaintenanceError carrying
+// retryAfter; other RPCs keep working so the process stays live.
+func (s *Service) SetMaintenance(on bool, retryAfter time.Duration) {
+	if !on {
+		s.maintenance.Store(nil)
+		return
+	}
+	s.maintenance.Store(&maintenanceState{retryAfter: max(retryAfter, 0)})
+}
+
+// InMaintenance reports whether maintenance mode is on.
+func (s *Service) InMaintenance() bool {
+	return s.maintenance.Load() != nil
+}
+
+// checkMaintenance rejects model calls while maintenance mode is on.
+func (s *Service) checkMaintenance() error {
+	if m := s.maintenance.Load(); m != nil {
+		return &llm.MaintenanceError{RetryAfter: m.retryAfter}
+	}
+	return nil
+}
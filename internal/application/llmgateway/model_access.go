@@ -0,0 +1,70 @@
+package llmgateway
+
+import (
+	"strings"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// ModelAccess restricts which routed models a subject may call. Patterns may use
+// "*" to match any run of characters, including "/" (e.g. "openrouter/*").
+// Denied wins over Allowed; an empty Allowed list allows every model not denied.
+type ModelAccess struct {
+	Allowed []string
+	Denied  []string
+}
+
+// WithModelAccess sets per-subject model restrictions. Subjects without an
+// entry, and unauthenticated requests, are unrestricted.
+func WithModelAccess(bySubject map[string]ModelAccess) Option {
+	return func(s *Service) {
+		s.modelAccess = bySubject
+	}
+}
+
+// checkModelAccess returns llm.ErrPermissionDenied if subject may not call model.
+// Patterns are matched against model both as given and with its canonical
+// provider name, so a differently cased prefix can't dodge a deny rule.
+func (s *Service) checkModelAccess(subject, model string) error {
+	acc, ok := s.modelAccess[subject]
+	if !ok || subject == "" {
+		return nil
+	}
+	canonical := s.canonicalModelID(model)
+	for _, p := range acc.Denied {
+		if matchWildcard(p, model) || matchWildcard(p, canonical) {
+			return llm.PermissionDenied("model " + model + " is not allowed for " + subject)
+		}
+	}
+	if len(acc.Allowed) == 0 {
+		return nil
+	}
+	for _, p := range acc.Allowed {
+		if matchWildcard(p, model) || matchWildcard(p, canonical) {
+			return nil
+		}
+	}
+	return llm.PermissionDenied("model " + model + " is not allowed for " + subject)
+}
+
+// matchWildcard reports whether s matches pattern, where "*" matches any
+// (possibly empty) sequence of characters and everything else is literal.
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, last)
+}
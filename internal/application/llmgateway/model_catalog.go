@@ -0,0 +1,117 @@
+package llmgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// WithModelDiscovery merges the upstream model lists of the named providers into
+// the catalog on each RefreshModels. Providers must implement UpstreamModelLister.
+func WithModelDiscovery(providerNames ...string) Option {
+	return func(s *Service) {
+		s.discoverFrom = append(s.discoverFrom, providerNames...)
+	}
+}
+
+// lookupModel finds a catalog entry by ID, falling back to the ID with its
+// canonical provider name ("DashScope/qwen-turbo" finds "dashscope/qwen-turbo").
+func (s *Service) lookupModel(id string) (ModelSpec, bool) {
+	s.modelsMu.RLock()
+	defer s.modelsMu.RUnlock()
+	if m, ok := s.models[id]; ok {
+		return m, true
+	}
+	m, ok := s.models[s.canonicalModelID(id)]
+	return m, ok
+}
+
+// applyModelDefaults fills chat request fields the caller left unset from the
+// model's catalog entry and injects its system prompt. It must run before
+// req.Model is rewritten upstream.
+func (s *Service) applyModelDefaults(req *llm.ChatCompletionRequest) {
+	spec, ok := s.lookupModel(req.Model)
+	if !ok {
+		return
+	}
+	if req.Temperature == nil && spec.DefaultTemperature != nil {
+		t := *spec.DefaultTemperature
+		req.Temperature = &t
+	}
+	if spec.SystemPrompt != "" && needsSystemPrompt(req.Messages, spec) {
+		msgs := make([]llm.ChatMessage, 0, len(req.Messages)+1)
+		msgs = append(msgs, llm.ChatMessage{Role: "system", Content: spec.SystemPrompt})
+		req.Messages = append(msgs, req.Messages...)
+	}
+}
+
+// needsSystemPrompt reports whether spec.SystemPrompt should be prepended to
+// msgs. Without SystemPromptAlways any caller system message suppresses it; with
+// it, only a leading system message that already is the prompt does.
+func needsSystemPrompt(msgs []llm.ChatMessage, spec ModelSpec) bool {
+	if spec.SystemPromptAlways {
+		return len(msgs) == 0 || msgs[0].Role != "system" || msgs[0].Content != spec.SystemPrompt
+	}
+	for _, m := range msgs {
+		if m.Role == "system" {
+			return false
+		}
+	}
+	return true
+}
+
+// RefreshModels fetches upstream model lists from discovery-enabled providers and
+// rebuilds the catalog. Discovered models are prefixed with the provider name;
+// config-defined models win on conflict. A provider that fails keeps its
+// previously discovered models, and its error is returned after the others are merged.
+func (s *Service) RefreshModels(ctx context.Context) error {
+	var errs []error
+	for _, name := range s.discoverFrom {
+		lister, ok := s.providers[name].(UpstreamModelLister)
+		if !ok {
+			errs = append(errs, fmt.Errorf("provider %s does not support model discovery", name))
+			continue
+		}
+		upstream, err := lister.ListUpstreamModels(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list %s models: %w", name, err))
+			continue
+		}
+		specs := make([]ModelSpec, 0, len(upstream))
+		for _, m := range upstream {
+			if m.ID == "" {
+				continue
+			}
+			specs = append(specs, ModelSpec{
+				ID:            name + "/" + m.ID,
+				Name:          m.Name,
+				Provider:      name,
+				Capabilities:  m.Capabilities,
+				UpstreamModel: m.ID,
+			})
+		}
+		s.modelsMu.Lock()
+		s.discovered[name] = specs
+		s.modelsMu.Unlock()
+	}
+
+	s.modelsMu.Lock()
+	merged := make(map[string]ModelSpec, len(s.configModels))
+	for _, name := range slices.Sorted(maps.Keys(s.discovered)) {
+		for _, m := range s.discovered[name] {
+			merged[m.ID] = m
+		}
+	}
+	for id, m := range s.configModels {
+		merged[id] = m
+	}
+	// Readers take the map under RLock and may keep iterating it, so it is replaced, never mutated.
+	s.models = merged
+	s.modelsMu.Unlock()
+
+	return errors.Join(errs...)
+}
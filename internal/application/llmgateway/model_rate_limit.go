@@ -0,0 +1,123 @@
+package llmgateway
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// ModelRateLimit is a gateway-wide request rate for one routed model, shared
+// by every subject calling it (e.g. an upstream's global RPM cap).
+type ModelRateLimit struct {
+	RequestsPerMinute float64
+	// Burst is how many requests may go out back to back; values < 1 count as 1.
+	Burst int
+	// MaxWait queues a request over the limit for up to this long before it
+	// is rejected; 0 rejects at once.
+	MaxWait time.Duration
+}
+
+// WithModelRateLimits limits requests per routed model ID (e.g.
+// "openrouter/openai/gpt-4o"). IDs are compared in canonical form, so a
+// provider alias or case variant shares the model's limit. Requests over a
+// limit wait up to its MaxWait and otherwise fail with *llm.RateLimitError.
+// Limits with RequestsPerMinute <= 0 are ignored.
+func WithModelRateLimits(limits map[string]ModelRateLimit) Option {
+	return func(s *Service) {
+		s.modelLimits = make(map[string]*modelBucket, len(limits))
+		for model, l := range limits {
+			if l.RequestsPerMinute <= 0 {
+				continue
+			}
+			burst := float64(max(l.Burst, 1))
+			s.modelLimits[model] = &modelBucket{
+				rate:    l.RequestsPerMinute / 60,
+				burst:   burst,
+				maxWait: l.MaxWait,
+				tokens:  burst,
+				last:    time.Now(),
+			}
+		}
+	}
+}
+
+// canonicalizeModelLimits re-keys the rate limits by canonical model ID. It
+// runs once every option is applied, as providers and aliases decide the
+// canonical form.
+func (s *Service) canonicalizeModelLimits() {
+	if len(s.modelLimits) == 0 {
+		return
+	}
+	limits := make(map[string]*modelBucket, len(s.modelLimits))
+	for model, b := range s.modelLimits {
+		limits[s.canonicalModelID(model)] = b
+	}
+	s.modelLimits = limits
+}
+
+// modelBucket is a token bucket whose tokens may go negative: each queued
+// request reserves the token it will wait for.
+type modelBucket struct {
+	rate    float64 // tokens per second
+	burst   float64
+	maxWait time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token, returning how long the caller must wait before
+// using it. ok is false, with no token taken, if that exceeds maxWait.
+func (b *modelBucket) reserve(now time.Time) (wait time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	wait = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	if wait > b.maxWait {
+		return wait, false
+	}
+	b.tokens--
+	return wait, true
+}
+
+// cancel returns a reserved token whose request gave up.
+func (b *modelBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// waitModelRateLimit blocks until routedModel's rate limit admits a request,
+// or fails with *llm.RateLimitError when that would exceed its MaxWait.
+func (s *Service) waitModelRateLimit(ctx context.Context, routedModel string) error {
+	b := s.modelLimits[s.canonicalModelID(routedModel)]
+	if b == nil {
+		return nil
+	}
+	wait, ok := b.reserve(time.Now())
+	if !ok {
+		return &llm.RateLimitError{Model: routedModel, RetryAfter: wait}
+	}
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
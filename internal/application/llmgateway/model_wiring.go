@@ -0,0 +1,38 @@
+package llmgateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateModelWiring reports config models that cannot be routed: those whose
+// provider is not registered (e.g. a typo like "openrouer", or a provider left
+// unconfigured) and those without an upstream model whose ID lacks a
+// "provider/model" form naming a registered provider. It is meant to fail
+// startup rather than every request for the model.
+func (s *Service) ValidateModelWiring() error {
+	s.modelsMu.RLock()
+	models := s.configModels
+	s.modelsMu.RUnlock()
+
+	var problems []string
+	for id, m := range models {
+		if _, ok := s.providers[m.Provider]; !ok {
+			problems = append(problems, fmt.Sprintf("model %q references unknown provider %q", id, m.Provider))
+			continue
+		}
+		if m.UpstreamModel != "" {
+			continue
+		}
+		if _, _, _, err := s.resolveProviderAndUpstreamModel(context.Background(), id); err != nil {
+			problems = append(problems, fmt.Sprintf("model %q has no upstream_model and its id does not route: %v", id, err))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid model wiring: %s", strings.Join(problems, "; "))
+}
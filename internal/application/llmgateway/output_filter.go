@@ -0,0 +1,263 @@
+package llmgateway
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// OutputFilterAction is what the output filter does when model output matches
+// one of its patterns.
+type OutputFilterAction string
+
+const (
+	// OutputFilterRedact replaces each match with the filter's Replacement (the default).
+	OutputFilterRedact OutputFilterAction = "redact"
+	// OutputFilterBlock drops the choice's content and ends it with finish
+	// reason FinishReasonContentFilter.
+	OutputFilterBlock OutputFilterAction = "block"
+)
+
+// FinishReasonContentFilter is the finish reason of output stopped by the output filter.
+const FinishReasonContentFilter = "content_filter"
+
+// Defaults for OutputFilter.
+const (
+	DefaultOutputFilterReplacement = "[REDACTED]"
+	// DefaultOutputFilterHoldback is how many bytes of streamed text are held
+	// back so a match split across chunks is still caught.
+	DefaultOutputFilterHoldback = 128
+)
+
+// OutputFilter keeps configured patterns (e.g. internal hostnames) out of
+// chat and text completion output.
+type OutputFilter struct {
+	Patterns    []*regexp.Regexp
+	Action      OutputFilterAction
+	Replacement string
+	// StreamHoldback bounds the longest match caught across stream chunks;
+	// streamed text reaches the client that many bytes late.
+	StreamHoldback int
+}
+
+// WithOutputFilter scans completion output against f.Patterns. Streams are
+// filtered too, but text released before a later block stays with the client.
+func WithOutputFilter(f OutputFilter) Option {
+	return func(s *Service) {
+		if len(f.Patterns) == 0 {
+			return
+		}
+		if f.Action != OutputFilterBlock {
+			f.Action = OutputFilterRedact
+		}
+		if f.Replacement == "" {
+			f.Replacement = DefaultOutputFilterReplacement
+		}
+		if f.StreamHoldback <= 0 {
+			f.StreamHoldback = DefaultOutputFilterHoldback
+		}
+		s.outputFilter = &f
+	}
+}
+
+// errOutputBlocked stops a provider stream once the filter blocked its output.
+var errOutputBlocked = errors.New("output blocked by filter")
+
+func (f *OutputFilter) matches(text string) bool {
+	for _, re := range f.Patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *OutputFilter) redact(text string) string {
+	for _, re := range f.Patterns {
+		text = re.ReplaceAllLiteralString(text, f.Replacement)
+	}
+	return text
+}
+
+// filterChat applies the output filter to a unary chat response, including
+// the raw content kept from before tag stripping and the audio transcript.
+// Audio itself can't be redacted, so a blocked choice drops it.
+func (s *Service) filterChat(resp *llm.ChatCompletionResponse) {
+	f := s.outputFilter
+	if f == nil {
+		return
+	}
+	for i := range resp.Choices {
+		c := &resp.Choices[i]
+		msg := &c.Message
+		blocked := f.Action == OutputFilterBlock && (f.matches(msg.Content) || f.matches(c.RawContent))
+		for _, p := range msg.ContentParts {
+			blocked = blocked || (f.Action == OutputFilterBlock && f.matches(p.Text))
+		}
+		if msg.Audio != nil {
+			blocked = blocked || (f.Action == OutputFilterBlock && f.matches(msg.Audio.Transcript))
+		}
+		if blocked {
+			msg.Content, msg.ContentParts, msg.Audio, c.RawContent = "", nil, nil, ""
+			c.FinishReason = FinishReasonContentFilter
+			continue
+		}
+		c.RawContent = f.redact(c.RawContent)
+		msg.Content = f.redact(msg.Content)
+		for j := range msg.ContentParts {
+			msg.ContentParts[j].Text = f.redact(msg.ContentParts[j].Text)
+		}
+		if msg.Audio != nil {
+			a := *msg.Audio // may be shared with the provider's response
+			a.Transcript = f.redact(a.Transcript)
+			msg.Audio = &a
+		}
+	}
+}
+
+// filterCompletion applies the output filter to a text completion response.
+func (s *Service) filterCompletion(resp *llm.CompletionResponse) {
+	f := s.outputFilter
+	if f == nil {
+		return
+	}
+	for i := range resp.Choices {
+		c := &resp.Choices[i]
+		if f.Action == OutputFilterBlock && f.matches(c.Text) {
+			c.Text, c.FinishReason = "", FinishReasonContentFilter
+			continue
+		}
+		c.Text = f.redact(c.Text)
+	}
+}
+
+// outputStream filters streamed chat chunks before passing them to next. The
+// last StreamHoldback bytes of each choice's content and reasoning are held
+// until more text arrives or the choice finishes, so matches spanning chunks
+// are seen whole.
+type outputStream struct {
+	f       *OutputFilter
+	next    func(llm.ChatCompletionChunk) error
+	pending map[streamText]string // held back raw text
+	choices map[uint32]bool       // every choice index seen
+	last    llm.ChatCompletionChunk
+	blocked bool
+}
+
+// streamText names one filtered text of a streamed choice.
+type streamText struct {
+	index     uint32
+	reasoning bool
+}
+
+// filterChatStream wraps emit with the output filter. flush must be called
+// once the provider stream ends to release held-back text; blocked reports
+// whether the filter cut the stream short.
+func (s *Service) filterChatStream(emit func(llm.ChatCompletionChunk) error) (wrapped func(llm.ChatCompletionChunk) error, flush func() error, blocked func() bool) {
+	if s.outputFilter == nil {
+		return emit, func() error { return nil }, func() bool { return false }
+	}
+	st := &outputStream{f: s.outputFilter, next: emit, pending: make(map[streamText]string), choices: make(map[uint32]bool)}
+	return st.emit, st.flush, func() bool { return st.blocked }
+}
+
+func (st *outputStream) emit(c llm.ChatCompletionChunk) error {
+	if st.blocked {
+		return errOutputBlocked
+	}
+	st.last = llm.ChatCompletionChunk{ID: c.ID, Created: c.Created, Model: c.Model}
+	c.Choices = slices.Clone(c.Choices)
+	for i := range c.Choices {
+		ch := &c.Choices[i]
+		st.choices[ch.Index] = true
+		final := ch.FinishReason != ""
+		var blocked bool
+		if ch.Delta.Content, blocked = st.filter(streamText{index: ch.Index}, ch.Delta.Content, final); blocked {
+			return st.block()
+		}
+		if ch.Delta.Reasoning, blocked = st.filter(streamText{index: ch.Index, reasoning: true}, ch.Delta.Reasoning, final); blocked {
+			return st.block()
+		}
+	}
+	return st.next(c)
+}
+
+// filter returns the redacted part of the held back text and delta that can
+// be released, holding back the rest; blocked reports a match to block.
+func (st *outputStream) filter(key streamText, delta string, final bool) (out string, blocked bool) {
+	text := st.pending[key] + delta
+	if st.f.Action == OutputFilterBlock && st.f.matches(text) {
+		return "", true
+	}
+	cut := len(text)
+	if !final {
+		cut = st.releasable(text)
+	}
+	if cut < len(text) {
+		st.pending[key] = text[cut:]
+	} else {
+		delete(st.pending, key)
+	}
+	return st.f.redact(text[:cut]), false
+}
+
+// releasable returns how much of text can be released: all but the holdback,
+// never splitting a rune or a match that reaches into the holdback.
+func (st *outputStream) releasable(text string) int {
+	cut := len(text) - st.f.StreamHoldback
+	if cut <= 0 {
+		return 0
+	}
+	for _, re := range st.f.Patterns {
+		for _, m := range re.FindAllStringIndex(text, -1) {
+			if m[0] < cut && m[1] > cut {
+				cut = m[0]
+			}
+		}
+	}
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return cut
+}
+
+// block ends every choice seen so far with FinishReasonContentFilter.
+func (st *outputStream) block() error {
+	st.blocked = true
+	final := st.last
+	for _, idx := range slices.Sorted(maps.Keys(st.choices)) {
+		final.Choices = append(final.Choices, llm.ChatCompletionChunkChoice{Index: idx, FinishReason: FinishReasonContentFilter})
+	}
+	if err := st.next(final); err != nil {
+		return err
+	}
+	return errOutputBlocked
+}
+
+func (st *outputStream) flush() error {
+	if st.blocked || len(st.pending) == 0 {
+		return nil
+	}
+	final := st.last
+	at := make(map[uint32]int) // choice index -> position in final.Choices
+	for _, key := range slices.SortedFunc(maps.Keys(st.pending), func(a, b streamText) int { return cmp.Compare(a.index, b.index) }) {
+		j, ok := at[key.index]
+		if !ok {
+			j = len(final.Choices)
+			at[key.index] = j
+			final.Choices = append(final.Choices, llm.ChatCompletionChunkChoice{Index: key.index})
+		}
+		if key.reasoning {
+			final.Choices[j].Delta.Reasoning = st.f.redact(st.pending[key])
+		} else {
+			final.Choices[j].Delta.Content = st.f.redact(st.pending[key])
+		}
+	}
+	st.pending = nil
+	return st.next(final)
+}
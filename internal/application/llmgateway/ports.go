@@ -2,6 +2,7 @@ package llmgateway
 
 import (
 	"context"
+	"time"
 
 	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
 )
@@ -13,9 +14,85 @@ type Provider interface {
 	CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error)
 }
 
+// StreamingProvider is optionally implemented by Providers that can stream chat
+// completions. emit is called for each chunk in order; an error from emit aborts the stream.
+type StreamingProvider interface {
+	CreateChatCompletionStream(ctx context.Context, req llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error
+}
+
+// CompletionProvider is optionally implemented by Providers whose upstream still
+// serves the legacy text completion endpoint (/completions).
+type CompletionProvider interface {
+	CreateCompletion(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error)
+}
+
+// UpstreamModelLister is optionally implemented by Providers that can list the
+// models available upstream. IDs are upstream names, without the provider prefix.
+type UpstreamModelLister interface {
+	ListUpstreamModels(ctx context.Context) ([]llm.Model, error)
+}
+
+// Warmer is optionally implemented by Providers that can open a connection to
+// their upstream ahead of the first request.
+type Warmer interface {
+	Warmup(ctx context.Context) error
+}
+
+// TokenCounter is optionally implemented by Providers whose upstream can count
+// the prompt tokens of a chat request (e.g. Anthropic /messages/count_tokens).
+// req.Model is the upstream model name.
+type TokenCounter interface {
+	CountTokens(ctx context.Context, req llm.ChatCompletionRequest) (uint32, error)
+}
+
 // GenerationRepository is an application port for storing and retrieving generation records.
 // Implementations live in infrastructure (e.g. in-memory, database).
 type GenerationRepository interface {
 	Save(ctx context.Context, gen llm.Generation) error
 	Get(ctx context.Context, id string) (llm.Generation, error)
+	// ListGenerationsByConversation returns the generations tagged with
+	// conversationID, oldest first.
+	ListGenerationsByConversation(ctx context.Context, conversationID string) ([]llm.Generation, error)
+}
+
+// AuditSink is an application port for persisting full request/response audit records.
+// Record must be non-blocking; implementations are expected to persist asynchronously.
+type AuditSink interface {
+	Record(ctx context.Context, rec llm.AuditRecord)
 }
+
+// Metrics is an application port for operational metrics.
+// Implementations live in infrastructure (e.g. Prometheus) and must be safe for concurrent use.
+type Metrics interface {
+	// ModelRequestStarted and ModelRequestFinished bracket a use case for a routed model.
+	// elapsed is the wall time of the whole use case, including retries.
+	// model arguments throughout are catalog model IDs, or "other" for
+	// models outside the catalog.
+	ModelRequestStarted(op, model string)
+	ModelRequestFinished(op, model string, elapsed time.Duration)
+	// EmptyResponse counts successful chat responses from provider that had no output.
+	EmptyResponse(provider, model string)
+	// SlowUpstreamCall reports a successful provider call slower than the
+	// configured threshold; elapsed covers that single attempt.
+	SlowUpstreamCall(provider, model string, elapsed time.Duration)
+	// UsageMissing counts successful responses with output but zero total
+	// tokens, a sign the provider's usage schema has drifted.
+	UsageMissing(provider, model string)
+	// CacheLookup counts a lookup in a named gateway cache (including in-flight
+	// coalescing tables); hit means no upstream call was made for it.
+	CacheLookup(cache string, hit bool)
+	// RequestDeduplicated counts a request short-circuited by coalescing or
+	// idempotency instead of reaching a provider, by operation.
+	RequestDeduplicated(op string)
+}
+
+// nopMetrics is used when no Metrics implementation is configured.
+type nopMetrics struct{}
+
+func (nopMetrics) ModelRequestStarted(string, string)                 {}
+func (nopMetrics) ModelRequestFinished(string, string, time.Duration) {}
+func (nopMetrics) EmptyResponse(string, string)                       {}
+func (nopMetrics) SlowUpstreamCall(string, string, time.Duration)     {}
+func (nopMetrics) UsageMissing(string, string)                        {}
+func (nopMetrics) CacheLookup(string, bool)                           {}
+func (nopMetrics) RequestDeduplicated(string)                         {}
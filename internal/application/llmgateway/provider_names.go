@@ -0,0 +1,40 @@
+package llmgateway
+
+import "strings"
+
+// WithProviderAliases maps alternative provider prefixes in model IDs to
+// configured provider names, e.g. {"ali": "dashscope"}. Aliases are matched
+// case-insensitively, like provider names themselves.
+func WithProviderAliases(aliases map[string]string) Option {
+	return func(s *Service) {
+		s.providerAliases = make(map[string]string, len(aliases))
+		for alias, name := range aliases {
+			s.providerAliases[strings.ToLower(alias)] = name
+		}
+	}
+}
+
+// canonicalProviderName returns the configured provider name for the provider
+// segment of a model ID: an exact match, else an alias, else the lowercased
+// name. The result may still name no provider.
+func (s *Service) canonicalProviderName(name string) string {
+	if _, ok := s.providers[name]; ok {
+		return name
+	}
+	lower := strings.ToLower(name)
+	if canonical, ok := s.providerAliases[lower]; ok {
+		return canonical
+	}
+	return lower
+}
+
+// canonicalModelID rewrites the provider segment of a "provider/model" ID to
+// its canonical name, keeping the model segment as-is. IDs without a provider
+// segment are returned unchanged.
+func (s *Service) canonicalModelID(id string) string {
+	providerName, model, ok := strings.Cut(id, "/")
+	if !ok || providerName == "" {
+		return id
+	}
+	return s.canonicalProviderName(providerName) + "/" + model
+}
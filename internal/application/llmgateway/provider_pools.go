@@ -0,0 +1,33 @@
+package llmgateway
+
+import (
+	"context"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// WithProviderPools adds named provider pools, e.g. one per tenant with its
+// own upstream credentials. Requests whose context names a pool (see
+// llm.WithProviderPool) use its provider instances; providers the pool does
+// not define come from the default pool passed to NewService.
+func WithProviderPools(pools map[string]map[string]Provider) Option {
+	return func(s *Service) {
+		s.providerPools = pools
+	}
+}
+
+// HasProviderPool reports whether name is a configured provider pool.
+func (s *Service) HasProviderPool(name string) bool {
+	_, ok := s.providerPools[name]
+	return ok
+}
+
+// providerFor returns the instance of the named provider for ctx's pool.
+func (s *Service) providerFor(ctx context.Context, name string) Provider {
+	if pool := llm.ProviderPool(ctx); pool != "" {
+		if p, ok := s.providerPools[pool][name]; ok {
+			return p
+		}
+	}
+	return s.providers[name]
+}
@@ -0,0 +1,98 @@
+package llmgateway
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// providerStatsWindow is the number of most recent calls used for the error rate.
+const providerStatsWindow = 100
+
+// ProviderStatus is a point-in-time health summary of one upstream provider.
+type ProviderStatus struct {
+	Name        string
+	LastSuccess time.Time // zero if no call has succeeded yet
+	LastError   time.Time // zero if no call has failed yet
+
+	// RecentRequests is the number of calls in the rolling window (at most providerStatsWindow).
+	RecentRequests int
+	// RecentErrorRate is the fraction of failed calls in the rolling window.
+	RecentErrorRate float64
+}
+
+// providerStats tracks call outcomes of a single provider in a fixed-size ring.
+type providerStats struct {
+	mu          sync.Mutex
+	failed      [providerStatsWindow]bool
+	n, pos      int
+	lastSuccess time.Time
+	lastError   time.Time
+}
+
+func (ps *providerStats) record(err error, now time.Time) {
+	// Caller mistakes and cancellations say nothing about upstream health.
+	if errors.Is(err, llm.ErrInvalidArgument) || errors.Is(err, context.Canceled) {
+		return
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.failed[ps.pos] = err != nil
+	ps.pos = (ps.pos + 1) % providerStatsWindow
+	if ps.n < providerStatsWindow {
+		ps.n++
+	}
+	if err != nil {
+		ps.lastError = now
+	} else {
+		ps.lastSuccess = now
+	}
+}
+
+func (ps *providerStats) snapshot(name string) ProviderStatus {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	st := ProviderStatus{
+		Name:           name,
+		LastSuccess:    ps.lastSuccess,
+		LastError:      ps.lastError,
+		RecentRequests: ps.n,
+	}
+	if ps.n > 0 {
+		failed := 0
+		for i := 0; i < ps.n; i++ {
+			if ps.failed[i] {
+				failed++
+			}
+		}
+		st.RecentErrorRate = float64(failed) / float64(ps.n)
+	}
+	return st
+}
+
+// ProviderStatuses reports one entry per configured provider, sorted by name.
+func (s *Service) ProviderStatuses() []ProviderStatus {
+	out := make([]ProviderStatus, 0, len(s.providerStats))
+	for name, ps := range s.providerStats {
+		out = append(out, ps.snapshot(name))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// ModelCount returns the number of models in the catalog.
+func (s *Service) ModelCount() int {
+	s.modelsMu.RLock()
+	defer s.modelsMu.RUnlock()
+	return len(s.models)
+}
+
+func (s *Service) recordProviderOutcome(providerName string, err error) {
+	if ps, ok := s.providerStats[providerName]; ok {
+		ps.record(err, time.Now())
+	}
+}
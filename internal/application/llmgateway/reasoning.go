@@ -0,0 +1,39 @@
+package llmgateway
+
+import "github.com/poly-workshop/llm-gateway/internal/domain/llm"
+
+// dropChatStreamReasoning wraps emit so reasoning deltas only reach callers
+// that asked for them. Chunks that carried nothing but reasoning are skipped
+// rather than sent empty.
+func dropChatStreamReasoning(include bool, emit func(llm.ChatCompletionChunk) error) func(llm.ChatCompletionChunk) error {
+	if include {
+		return emit
+	}
+	return func(c llm.ChatCompletionChunk) error {
+		reasoningOnly := c.Usage == nil
+		var choices []llm.ChatCompletionChunkChoice
+		for i, ch := range c.Choices {
+			if ch.Delta.Reasoning == "" {
+				reasoningOnly = false
+				continue
+			}
+			if choices == nil {
+				choices = make([]llm.ChatCompletionChunkChoice, len(c.Choices))
+				copy(choices, c.Choices)
+			}
+			choices[i].Delta.Reasoning = ""
+			d := choices[i].Delta
+			if ch.FinishReason != "" || d.Role != "" || d.Content != "" || len(d.ContentParts) > 0 || len(d.ToolCalls) > 0 {
+				reasoningOnly = false
+			}
+		}
+		if choices == nil {
+			return emit(c)
+		}
+		if reasoningOnly {
+			return nil
+		}
+		c.Choices = choices
+		return emit(c)
+	}
+}
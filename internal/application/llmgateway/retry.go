@@ -0,0 +1,141 @@
+package llmgateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// RetryPolicy controls retries of failed provider calls.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first; <= 1 disables retries.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry; it doubles on each further retry.
+	BaseBackoff time.Duration
+	// MaxBackoff caps both computed backoff and upstream Retry-After hints.
+	MaxBackoff time.Duration
+	// RetryConnectionReset retries an idempotent call (embeddings) once,
+	// immediately, when the connection was reset mid-request. Chat and text
+	// completions are never retried on a reset: the upstream may already have
+	// processed (and billed) them. The extra attempt is on top of MaxAttempts.
+	RetryConnectionReset bool
+}
+
+// WithRetryPolicy enables retries of transient provider failures.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *Service) {
+		if p.BaseBackoff <= 0 {
+			p.BaseBackoff = 200 * time.Millisecond
+		}
+		if p.MaxBackoff <= 0 {
+			p.MaxBackoff = 10 * time.Second
+		}
+		s.retry = p
+	}
+}
+
+// WithRequestTimeout bounds a whole unary request, so retries and their
+// backoff draw down one deadline instead of each attempt getting a fresh
+// provider timeout. An earlier caller deadline still wins; d <= 0 disables.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.requestTimeout = d
+	}
+}
+
+// withRequestBudget applies the request timeout to ctx. Streams are not
+// budgeted: they are bounded by the providers' idle timeouts instead.
+func (s *Service) withRequestBudget(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, extendedTimeout(ctx, s.requestTimeout))
+}
+
+// withRetry runs call until it succeeds, fails permanently, or attempts run out.
+// It doesn't retry when the backoff would outlast ctx's deadline. idempotent
+// marks calls that are safe to repeat even if the upstream may have seen them.
+func (s *Service) withRetry(ctx context.Context, idempotent bool, call func(ctx context.Context) error) error {
+	attempts := s.retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	retryReset := idempotent && s.retry.RetryConnectionReset
+	tr := debugTraceFrom(ctx)
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		start := time.Now()
+		actx, cancel := s.upstreamAttemptContext(ctx)
+		err = call(actx)
+		cancel()
+		tr.recordAttempt(time.Since(start))
+		if err == nil {
+			return nil
+		}
+		if retryReset && connectionReset(err) && ctx.Err() == nil {
+			retryReset = false
+			attempt-- // doesn't use up an attempt
+			continue
+		}
+		if attempt == attempts-1 || !retryable(err) {
+			break
+		}
+		delay := s.retryDelay(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			break
+		}
+		if serr := s.sleep(ctx, delay); serr != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// retryDelay is the exponential backoff for attempt, raised to the upstream's
+// Retry-After hint if larger, and capped at MaxBackoff.
+func (s *Service) retryDelay(attempt int, err error) time.Duration {
+	d := s.retry.BaseBackoff << attempt
+	var perr *llm.ProviderHTTPError
+	if errors.As(err, &perr) && perr.RetryAfter > d {
+		d = perr.RetryAfter
+	}
+	if d > s.retry.MaxBackoff || d <= 0 {
+		d = s.retry.MaxBackoff
+	}
+	return d
+}
+
+// retryable reports whether err is a transient upstream failure where the
+// request was rejected before being processed.
+func retryable(err error) bool {
+	var perr *llm.ProviderHTTPError
+	if !errors.As(err, &perr) {
+		return false
+	}
+	switch perr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// connectionReset reports whether err is a transport failure where the
+// connection to the upstream was reset, as opposed to an HTTP error status.
+func connectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -2,22 +2,198 @@ package llmgateway
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"golang.org/x/sync/singleflight"
 )
 
 // Service hosts application-level use cases for the LLM gateway.
 // It should depend only on domain concepts (no protobuf / HTTP / gRPC).
 type Service struct {
 	providers map[string]Provider
+	// providerPools are named alternatives to providers; see WithProviderPools.
+	providerPools map[string]map[string]Provider
+
+	// providerStats tracks recent call outcomes per provider for health reporting.
+	providerStats map[string]*providerStats
 
 	// models maps routed model ID (provider/model) to its metadata and optional upstream mapping.
-	models map[string]ModelSpec
+	// It is config models merged with models discovered upstream; see RefreshModels.
+	modelsMu sync.RWMutex
+	models   map[string]ModelSpec
+
+	// configModels are the config-defined models, authoritative over discovered ones.
+	configModels map[string]ModelSpec
+	// discovered holds the last successful upstream listing per provider.
+	discovered map[string][]ModelSpec
+	// discoverFrom lists providers whose upstream model lists are merged in.
+	discoverFrom []string
 
 	// generations stores generation records for generation queries.
 	generations GenerationRepository
+
+	// embeddingsFlight coalesces identical in-flight embeddings requests.
+	embeddingsFlight singleflight.Group
+	// embeddingsBatchSize caps inputs per upstream embeddings call (0 = no split).
+	embeddingsBatchSize int
+	// embeddingsChunkChars is the auto_chunk chunk size in runes (0 = auto_chunk disabled).
+	embeddingsChunkChars int
+
+	// maxTemperature is the inclusive upper bound accepted for chat temperature.
+	maxTemperature float64
+
+	// maxEmbeddingsInputs and maxEmbeddingsInputBytes bound one embeddings request.
+	maxEmbeddingsInputs     int
+	maxEmbeddingsInputBytes int
+	// maxContentPartsPerMessage and maxContentPartsPerRequest bound chat content parts.
+	maxContentPartsPerMessage int
+	maxContentPartsPerRequest int
+	// contextWindowCheck counts chat prompts against the model's context
+	// window before calling upstream; see WithContextWindowCheck.
+	contextWindowCheck bool
+
+	// retry controls retries of transient provider failures (disabled by default).
+	retry RetryPolicy
+	sleep func(ctx context.Context, d time.Duration) error
+
+	// metrics records operational metrics (no-op unless configured).
+	metrics Metrics
+	// slowUpstream and slowUpstreamByProvider are the slow-call reporting
+	// thresholds; see WithSlowUpstreamThreshold.
+	slowUpstream           time.Duration
+	slowUpstreamByProvider map[string]time.Duration
+
+	// audit receives full request/response captures for opted-in subjects only.
+	audit         AuditSink
+	auditSubjects map[string]struct{}
+
+	// modelAccess restricts which models each subject may call.
+	modelAccess map[string]ModelAccess
+	// providerAliases maps lowercased alternative provider prefixes to provider names.
+	providerAliases map[string]string
+	// outputFilter redacts or blocks sensitive patterns in completion output (nil = off).
+	outputFilter *OutputFilter
+	// defaultUserFromSubject fills an empty request user from the hashed subject.
+	defaultUserFromSubject bool
+	defaultUserSalt        []byte
+	// dedupChoices collapses identical choices of unary responses.
+	dedupChoices bool
+	// newGenerationID issues gateway-side generation IDs (nil = keep upstream IDs).
+	newGenerationID func() string
+
+	// upstreamSlots bounds concurrent upstream chat calls, prioritized by service tier (nil = unbounded).
+	upstreamSlots *prioritySlots
+	// modelLimits are gateway-wide rate limits by routed model.
+	modelLimits map[string]*modelBucket
+
+	// emptyResponse handles successful chat responses without output.
+	emptyResponse EmptyResponsePolicy
+
+	// metadataAllowlist holds request metadata keys forwarded to providers.
+	metadataAllowlist map[string]bool
+
+	// imageURLs restricts image_url content parts (zero value allows all).
+	imageURLs ImageURLPolicy
+
+	// requestTimeout bounds a unary request across all retries (0 = caller deadline only).
+	requestTimeout time.Duration
+	// upstreamTimeout bounds each upstream attempt, and detached cacheable calls.
+	upstreamTimeout time.Duration
+	embeddingsCache *embeddingsCache // nil = disabled
+
+	// maintenance is non-nil while model calls are drained for maintenance.
+	maintenance atomic.Pointer[maintenanceState]
+}
+
+// DefaultMaxTemperature is the OpenAI-compatible upper bound for temperature.
+const DefaultMaxTemperature = 2.0
+
+// Default embeddings input limits. 2048 items matches the OpenAI API.
+const (
+	DefaultMaxEmbeddingsInputs     = 2048
+	DefaultMaxEmbeddingsInputBytes = 8 << 20 // 8MiB
+)
+
+// Default chat content part limits, bounding multimodal (e.g. many-image)
+// requests per message and across all messages.
+const (
+	DefaultMaxContentPartsPerMessage = 64
+	DefaultMaxContentPartsPerRequest = 256
+)
+
+// Option customizes optional Service behavior.
+type Option func(*Service)
+
+// WithMaxTemperature overrides the inclusive upper bound for chat temperature.
+// Non-positive values keep the default.
+func WithMaxTemperature(max float64) Option {
+	return func(s *Service) {
+		if max > 0 {
+			s.maxTemperature = max
+		}
+	}
+}
+
+// WithEmbeddingsInputLimits overrides the maximum number of inputs and their
+// total size in bytes per embeddings request. Non-positive values keep the defaults.
+func WithEmbeddingsInputLimits(maxInputs, maxBytes int) Option {
+	return func(s *Service) {
+		if maxInputs > 0 {
+			s.maxEmbeddingsInputs = maxInputs
+		}
+		if maxBytes > 0 {
+			s.maxEmbeddingsInputBytes = maxBytes
+		}
+	}
+}
+
+// WithContentPartLimits overrides the maximum number of content parts per chat
+// message and per request. Non-positive values keep the defaults.
+func WithContentPartLimits(perMessage, perRequest int) Option {
+	return func(s *Service) {
+		if perMessage > 0 {
+			s.maxContentPartsPerMessage = perMessage
+		}
+		if perRequest > 0 {
+			s.maxContentPartsPerRequest = perRequest
+		}
+	}
+}
+
+// WithMetadataAllowlist sets the request metadata keys forwarded to providers.
+// Other keys are dropped; without an allowlist no metadata is forwarded.
+func WithMetadataAllowlist(keys []string) Option {
+	return func(s *Service) {
+		s.metadataAllowlist = make(map[string]bool, len(keys))
+		for _, k := range keys {
+			s.metadataAllowlist[k] = true
+		}
+	}
+}
+
+// filterMetadata returns the allowlisted subset of md, or nil if none remain.
+func (s *Service) filterMetadata(md map[string]string) map[string]string {
+	var out map[string]string
+	for k, v := range md {
+		if !s.metadataAllowlist[k] {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(md))
+		}
+		out[k] = v
+	}
+	return out
 }
 
 type ModelSpec struct {
@@ -29,19 +205,123 @@ type ModelSpec struct {
 	// UpstreamModel overrides the model name sent to upstream provider.
 	// If empty, the part after "provider/" in ID will be used.
 	UpstreamModel string
+
+	// DefaultTemperature applies to chat requests that don't set a temperature;
+	// nil leaves it to the provider.
+	DefaultTemperature *float64
+
+	// StripTags names tag blocks (e.g. "think") removed from chat output,
+	// including across stream chunks. With KeepRawContent, unary responses
+	// keep the unstripped text in the choice's RawContent.
+	StripTags      []string
+	KeepRawContent bool
+
+	// SystemPrompt is prepended to chat requests as a system message when they
+	// have none, or always if SystemPromptAlways is set. Empty disables it.
+	SystemPrompt       string
+	SystemPromptAlways bool
+
+	// ContextWindow, MaxOutputTokens and Pricing describe the model to clients
+	// (GetModel); the gateway does not enforce them. Zero/nil means unknown.
+	ContextWindow   uint32
+	MaxOutputTokens uint32
+	Pricing         *llm.ModelPricing
+}
+
+// WithMetrics sets the metrics recorder.
+func WithMetrics(m Metrics) Option {
+	return func(s *Service) {
+		if m != nil {
+			s.metrics = m
+		}
+	}
+}
+
+// WithAudit enables the audit trail for the given subjects. Subjects not listed
+// are never audited, so passing no subjects keeps auditing off.
+func WithAudit(sink AuditSink, subjects []string) Option {
+	return func(s *Service) {
+		if sink == nil || len(subjects) == 0 {
+			return
+		}
+		s.audit = sink
+		s.auditSubjects = make(map[string]struct{}, len(subjects))
+		for _, subj := range subjects {
+			s.auditSubjects[subj] = struct{}{}
+		}
+	}
 }
 
-func NewService(providers map[string]Provider, models []ModelSpec, generations GenerationRepository) *Service {
+func NewService(providers map[string]Provider, models []ModelSpec, generations GenerationRepository, opts ...Option) *Service {
 	mm := make(map[string]ModelSpec, len(models))
 	for _, m := range models {
 		mm[m.ID] = m
 	}
-	return &Service{providers: providers, models: mm, generations: generations}
+	stats := make(map[string]*providerStats, len(providers))
+	for name := range providers {
+		stats[name] = &providerStats{}
+	}
+	s := &Service{
+		providers:                 providers,
+		providerStats:             stats,
+		models:                    mm,
+		configModels:              mm,
+		discovered:                make(map[string][]ModelSpec),
+		generations:               generations,
+		maxTemperature:            DefaultMaxTemperature,
+		maxEmbeddingsInputs:       DefaultMaxEmbeddingsInputs,
+		maxEmbeddingsInputBytes:   DefaultMaxEmbeddingsInputBytes,
+		maxContentPartsPerMessage: DefaultMaxContentPartsPerMessage,
+		maxContentPartsPerRequest: DefaultMaxContentPartsPerRequest,
+		metrics:                   nopMetrics{},
+		sleep:                     sleepContext,
+		emptyResponse:             EmptyResponsePassThrough,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.canonicalizeModelLimits()
+	return s
 }
 
-func (s *Service) ListModels(_ context.Context) ([]llm.Model, error) {
-	out := make([]llm.Model, 0, len(s.models))
-	for _, m := range s.models {
+// ListModels returns up to pageSize models sorted by ID, starting after the
+// cursor in pageToken. pageSize <= 0 returns all remaining models. The returned
+// token is empty on the last page. Cursors encode the last ID seen, so they stay
+// valid even if the catalog changes between calls.
+func (s *Service) ListModels(_ context.Context, pageSize int, pageToken string) ([]llm.Model, string, error) {
+	if pageSize < 0 {
+		return nil, "", llm.InvalidArgument("page_size must not be negative")
+	}
+	var after string
+	if pageToken != "" {
+		b, err := base64.RawURLEncoding.DecodeString(pageToken)
+		if err != nil || len(b) == 0 {
+			return nil, "", llm.InvalidArgument("invalid page_token")
+		}
+		after = string(b)
+	}
+
+	s.modelsMu.RLock()
+	models := s.models
+	s.modelsMu.RUnlock()
+
+	ids := make([]string, 0, len(models))
+	for id := range models {
+		if after == "" || id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var next string
+	if pageSize > 0 && len(ids) > pageSize {
+		ids = ids[:pageSize]
+		next = base64.RawURLEncoding.EncodeToString([]byte(ids[len(ids)-1]))
+	}
+
+	out := make([]llm.Model, 0, len(ids))
+	for _, id := range ids {
+		m := models[id]
 		out = append(out, llm.Model{
 			ID:           m.ID,
 			Name:         m.Name,
@@ -49,131 +329,398 @@ func (s *Service) ListModels(_ context.Context) ([]llm.Model, error) {
 			Capabilities: m.Capabilities,
 		})
 	}
-	return out, nil
+	return out, next, nil
 }
 
 func (s *Service) GetModel(_ context.Context, id string) (llm.Model, error) {
 	if id == "" {
 		return llm.Model{}, llm.InvalidArgument("id is required")
 	}
-	m, ok := s.models[id]
+	m, ok := s.lookupModel(id)
 	if !ok {
 		return llm.Model{}, llm.InvalidArgument("unknown model: " + id)
 	}
 	return llm.Model{
-		ID:           m.ID,
-		Name:         m.Name,
-		Provider:     m.Provider,
-		Capabilities: m.Capabilities,
+		ID:              m.ID,
+		Name:            m.Name,
+		Provider:        m.Provider,
+		Capabilities:    m.Capabilities,
+		ContextWindow:   m.ContextWindow,
+		MaxOutputTokens: m.MaxOutputTokens,
+		Pricing:         m.Pricing,
 	}, nil
 }
 
 func (s *Service) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
-	if req.Model == "" {
-		return llm.EmbeddingsResponse{}, llm.InvalidArgument("model is required")
+	if err := s.checkMaintenance(); err != nil {
+		return llm.EmbeddingsResponse{}, err
 	}
-	if len(req.Input) == 0 {
-		return llm.EmbeddingsResponse{}, llm.InvalidArgument("input is required")
+	if err := s.validateEmbeddingsRequest(req); err != nil {
+		return llm.EmbeddingsResponse{}, err
+	}
+	if err := s.checkModelAccess(req.Subject, req.Model); err != nil {
+		return llm.EmbeddingsResponse{}, err
+	}
+	req.User = s.defaultUser(req.User, req.Subject)
+	done := s.trackModelRequest("embeddings", req.Model)
+	defer done()
+	ctx, cancel := s.withRequestBudget(ctx)
+	defer cancel()
+
+	input := req.Input
+	var chunkCounts []int // chunks per input; nil unless auto-chunking
+	if req.AutoChunk {
+		var err error
+		if req, chunkCounts, err = s.chunkEmbeddingsInput(req); err != nil {
+			return llm.EmbeddingsResponse{}, err
+		}
 	}
 
 	routedModel := req.Model
-	p, upstreamModel, err := s.resolveProviderAndUpstreamModel(routedModel)
+	p, providerName, upstreamModel, err := s.resolveProviderAndUpstreamModel(ctx, routedModel)
 	if err != nil {
 		return llm.EmbeddingsResponse{}, err
 	}
-	req.Model = upstreamModel
-	resp, err := p.CreateEmbeddings(ctx, req)
-	if err != nil {
-		return llm.EmbeddingsResponse{}, err
+	tr := debugTraceFrom(ctx)
+	tr.setRoute(providerName, upstreamModel)
+
+	// Embeddings are deterministic, so identical concurrent requests share one
+	// upstream call, and finished ones may be served from the cache. Both are
+	// keyed per subject, so callers never see each other's responses. The
+	// shared call runs detached from the leader, so it completes and is
+	// cached even if every caller gives up, and writes no caller's state.
+	start := time.Now()
+	key := embeddingsFlightKey(ctx, req)
+	cacheKey := embeddingsCacheKey(routedModel, key)
+	cached, hit := s.embeddingsCache.get(cacheKey, start)
+	if s.embeddingsCache != nil {
+		s.metrics.CacheLookup(embeddingsCacheName, hit)
+	}
+	var resp llm.EmbeddingsResponse
+	if hit {
+		s.metrics.RequestDeduplicated("embeddings")
+		resp = cached
+	} else {
+		// Callers granted a longer upstream timeout don't join a call bounded
+		// by a shorter one, or the reverse; the cached result is shared.
+		flightKey := key
+		if d := llm.UpstreamTimeout(ctx); d > 0 {
+			flightKey += "/" + d.String()
+		}
+		caller := new(byte) // identifies the caller whose function runs the call
+		ch := s.embeddingsFlight.DoChan(flightKey, func() (any, error) {
+			ctx, cancel := s.detachedUpstreamContext(ctx)
+			defer cancel()
+			// The call may outlive the leader, so it records into its own
+			// trace rather than the leader's.
+			ctx, ftr := WithDebugTrace(ctx)
+			if err := s.waitModelRateLimit(ctx, routedModel); err != nil {
+				return embeddingsFlightResult{leader: caller}, err
+			}
+			var resp llm.EmbeddingsResponse
+			err := s.forEachEmbeddingsBatch(ctx, p, providerName, upstreamModel, req, s.embeddingsBatchSize, func(b llm.EmbeddingsResponse) error {
+				resp = mergeEmbeddings(resp, b)
+				return nil
+			})
+			if err != nil {
+				return embeddingsFlightResult{leader: caller}, err
+			}
+			s.embeddingsCache.put(cacheKey, resp, time.Now())
+			return embeddingsFlightResult{leader: caller, resp: resp, trace: *ftr}, nil
+		})
+		var res singleflight.Result
+		select {
+		case res = <-ch:
+		case <-ctx.Done():
+			return llm.EmbeddingsResponse{}, ctx.Err()
+		}
+		r := res.Val.(embeddingsFlightResult)
+		led := r.leader == caller
+		s.metrics.CacheLookup(embeddingsFlightCache, !led)
+		if !led {
+			s.metrics.RequestDeduplicated("embeddings")
+		}
+		if res.Err != nil {
+			return llm.EmbeddingsResponse{}, res.Err
+		}
+		tr.addAttempts(r.trace)
+		resp = r.resp
 	}
 
-	// Save generation record for generation queries (best-effort).
+	// Every caller, including those served by another's call or the cache,
+	// gets its own creation time, generation ID and record. resp is a copy,
+	// so the cached response is left alone.
+	resp.Created = start.Unix()
+	upstreamID := s.issueGenerationID(&resp.ID)
 	if s.generations != nil {
-		gen := s.buildGenerationFromEmbeddings(routedModel, resp)
+		gen := s.buildGenerationFromEmbeddings(routedModel, providerName, upstreamModel, resp)
+		gen.ConversationID, gen.Subject, gen.Latency = req.ConversationID, req.Subject, time.Since(start)
+		gen.UpstreamID = upstreamID
 		_ = s.generations.Save(ctx, gen) // Best effort, don't fail the request.
 	}
+	if chunkCounts != nil {
+		resp = poolChunkedEmbeddings(resp, chunkCounts)
+	}
+	tr.setUsage(resp.Usage.PromptTokens, 0)
+
+	if s.auditEnabled(req.Subject) {
+		s.audit.Record(ctx, llm.AuditRecord{
+			GenerationID:   resp.ID,
+			Operation:      "embeddings",
+			Subject:        req.Subject,
+			Model:          routedModel,
+			CreatedAt:      time.Now().Unix(),
+			ConversationID: req.ConversationID,
+			Input:          input,
+		})
+	}
 
 	return resp, nil
 }
 
 func (s *Service) CreateChatCompletion(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
-	if req.Model == "" {
-		return llm.ChatCompletionResponse{}, llm.InvalidArgument("model is required")
+	if err := s.checkMaintenance(); err != nil {
+		return llm.ChatCompletionResponse{}, err
+	}
+	if err := s.validateChatCompletionRequest(req); err != nil {
+		return llm.ChatCompletionResponse{}, err
 	}
-	if len(req.Messages) == 0 {
-		return llm.ChatCompletionResponse{}, llm.InvalidArgument("messages is required")
+	if err := s.checkModelAccess(req.Subject, req.Model); err != nil {
+		return llm.ChatCompletionResponse{}, err
 	}
+	s.applyModelDefaults(&req)
+	if err := s.checkContextWindow(ctx, req); err != nil {
+		return llm.ChatCompletionResponse{}, err
+	}
+	req.Metadata = s.filterMetadata(req.Metadata)
+	req.User = s.defaultUser(req.User, req.Subject)
+	done := s.trackModelRequest("chat.completions", req.Model)
+	defer done()
+	ctx, cancel := s.withRequestBudget(ctx)
+	defer cancel()
 
 	routedModel := req.Model
-	p, upstreamModel, err := s.resolveProviderAndUpstreamModel(routedModel)
+	p, providerName, upstreamModel, err := s.resolveProviderAndUpstreamModel(ctx, routedModel)
 	if err != nil {
 		return llm.ChatCompletionResponse{}, err
 	}
+	tr := debugTraceFrom(ctx)
+	tr.setRoute(providerName, upstreamModel)
 	req.Model = upstreamModel
-	resp, err := p.CreateChatCompletion(ctx, req)
+
+	if err := s.waitModelRateLimit(ctx, routedModel); err != nil {
+		return llm.ChatCompletionResponse{}, err
+	}
+	release, err := s.upstreamSlots.acquire(ctx, tierPriority(req.ServiceTier))
 	if err != nil {
 		return llm.ChatCompletionResponse{}, err
 	}
+	defer release()
+
+	call := func(ctx context.Context) (llm.ChatCompletionResponse, error) {
+		var resp llm.ChatCompletionResponse
+		err := s.withRetry(ctx, false, func(ctx context.Context) error {
+			var err error
+			callStart := time.Now()
+			resp, err = p.CreateChatCompletion(ctx, req)
+			s.recordProviderOutcome(providerName, err)
+			s.observeUpstreamCall(providerName, routedModel, callStart, err)
+			return err
+		})
+		return resp, err
+	}
+	start := time.Now()
+	resp, err := call(ctx)
+	if err != nil {
+		return llm.ChatCompletionResponse{}, err
+	}
+	resp, err = s.handleEmptyChat(ctx, providerName, routedModel, resp, call)
+	if err != nil {
+		return llm.ChatCompletionResponse{}, err
+	}
+	sortChatChoices(&resp)
+	s.stripChatTags(routedModel, &resp)
+	s.filterChat(&resp)
+	s.dedupChatChoices(&resp)
+	upstreamID := s.issueGenerationID(&resp.ID)
+	s.checkUsageReported(providerName, routedModel, resp.Usage, len(resp.Choices) > 0)
+	tr.setUsage(resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	if req.EchoPrompt {
+		resp.Prompt = &llm.PromptEcho{Model: req.Model, Messages: req.Messages}
+	}
 
 	// Save generation record for generation queries (best-effort).
 	if s.generations != nil {
-		gen := s.buildGenerationFromChat(routedModel, resp)
+		gen := s.buildGenerationFromChat(routedModel, providerName, upstreamModel, resp)
+		gen.ConversationID, gen.Subject, gen.Latency = req.ConversationID, req.Subject, time.Since(start)
+		gen.UpstreamID = upstreamID
 		_ = s.generations.Save(ctx, gen) // Best effort, don't fail the request.
 	}
 
+	if s.auditEnabled(req.Subject) {
+		output := make([]string, 0, len(resp.Choices))
+		for _, c := range resp.Choices {
+			output = append(output, c.Message.Content)
+		}
+		s.audit.Record(ctx, llm.AuditRecord{
+			GenerationID:   resp.ID,
+			Operation:      "chat.completions",
+			Subject:        req.Subject,
+			Model:          routedModel,
+			CreatedAt:      time.Now().Unix(),
+			ConversationID: req.ConversationID,
+			Messages:       req.Messages,
+			Output:         output,
+		})
+	}
+
 	return resp, nil
 }
 
-func (s *Service) resolveProviderAndUpstreamModel(routedModel string) (Provider, string, error) {
+// otherModelLabel is the metrics label of models outside the catalog.
+const otherModelLabel = "other"
+
+// metricsModel returns the model label for metrics: the catalog ID, or
+// otherModelLabel for anything else, so clients can't mint label values.
+func (s *Service) metricsModel(model string) string {
+	if spec, ok := s.lookupModel(model); ok {
+		return spec.ID
+	}
+	return otherModelLabel
+}
+
+// trackModelRequest marks a request for model as in flight. The returned func
+// must be deferred so the count is released on every exit path, including panics.
+func (s *Service) trackModelRequest(op, model string) func() {
+	model = s.metricsModel(model)
+	s.metrics.ModelRequestStarted(op, model)
+	start := time.Now()
+	return func() { s.metrics.ModelRequestFinished(op, model, time.Since(start)) }
+}
+
+// embeddingsFlightCache names the embeddings coalescing table in CacheLookup metrics.
+const embeddingsFlightCache = "embeddings_inflight"
+
+// embeddingsFlightResult is a shared embeddings call's response and the
+// upstream attempts it made, which each waiting caller adds to its trace.
+// leader identifies the caller that ran the call.
+type embeddingsFlightResult struct {
+	leader *byte
+	resp   llm.EmbeddingsResponse
+	trace  DebugTrace
+}
+
+// embeddingsFlightKey identifies one subject's requests that would produce
+// identical upstream calls.
+// Fields are length-prefixed so different inputs can't collide by concatenation.
+func embeddingsFlightKey(ctx context.Context, req llm.EmbeddingsRequest) string {
+	h := sha256.New()
+	for _, f := range append([]string{req.Model, req.Subject, req.User, llm.UpstreamBaseURL(ctx), llm.ProviderPool(ctx)}, req.Input...) {
+		_ = binary.Write(h, binary.BigEndian, uint64(len(f)))
+		_, _ = h.Write([]byte(f))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Service) auditEnabled(subject string) bool {
+	if s.audit == nil || subject == "" {
+		return false
+	}
+	_, ok := s.auditSubjects[subject]
+	return ok
+}
+
+// resolveProviderAndUpstreamModel picks the provider instance of ctx's
+// provider pool for routedModel.
+func (s *Service) resolveProviderAndUpstreamModel(ctx context.Context, routedModel string) (p Provider, providerName, upstreamModel string, err error) {
 	// If explicitly declared in model specs, prefer that.
-	if m, ok := s.models[routedModel]; ok {
-		p := s.providers[m.Provider]
+	if m, ok := s.lookupModel(routedModel); ok {
+		p := s.providerFor(ctx, m.Provider)
 		if p == nil {
-			return nil, "", fmt.Errorf("no provider configured: %s", m.Provider)
+			return nil, "", "", fmt.Errorf("no provider configured: %s", m.Provider)
 		}
 		if m.UpstreamModel != "" {
-			return p, m.UpstreamModel, nil
+			return p, m.Provider, m.UpstreamModel, nil
 		}
 		// Fallthrough: derive upstream model from ID suffix.
 	}
 
 	parts := strings.SplitN(routedModel, "/", 2)
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return nil, "", llm.InvalidArgument("invalid model format, expected provider/model")
+		return nil, "", "", llm.InvalidArgument("invalid model format, expected provider/model")
 	}
-	providerName := parts[0]
-	upstreamModel := parts[1]
+	providerName = s.canonicalProviderName(parts[0])
+	upstreamModel = parts[1]
 
-	p := s.providers[providerName]
+	p = s.providerFor(ctx, providerName)
 	if p == nil {
-		return nil, "", llm.InvalidArgument("unknown provider: " + providerName)
+		return nil, "", "", llm.InvalidArgument("unknown provider: " + providerName)
 	}
-	return p, upstreamModel, nil
+	return p, providerName, upstreamModel, nil
 }
 
-// GetGeneration retrieves a generation record by ID.
-func (s *Service) GetGeneration(ctx context.Context, id string) (llm.Generation, error) {
+// GetGeneration retrieves the subject's generation record by ID. Another
+// caller's generation is reported as not found, so IDs cannot be probed.
+func (s *Service) GetGeneration(ctx context.Context, subject, id string) (llm.Generation, error) {
 	if id == "" {
 		return llm.Generation{}, llm.InvalidArgument("id is required")
 	}
 	if s.generations == nil {
 		return llm.Generation{}, llm.InvalidArgument("generation repository not configured")
 	}
-	return s.generations.Get(ctx, id)
+	gen, err := s.generations.Get(ctx, id)
+	if err != nil {
+		return llm.Generation{}, err
+	}
+	if gen.Subject != subject {
+		return llm.Generation{}, llm.NotFound("generation " + id)
+	}
+	return gen, nil
+}
+
+// ListGenerationsByConversation lists the subject's generations tagged with
+// conversationID, oldest first. Other callers' generations are never returned.
+func (s *Service) ListGenerationsByConversation(ctx context.Context, subject, conversationID string) ([]llm.Generation, error) {
+	if conversationID == "" {
+		return nil, llm.InvalidArgument("conversation_id is required")
+	}
+	if s.generations == nil {
+		return nil, llm.InvalidArgument("generation repository not configured")
+	}
+	gens, err := s.generations.ListGenerationsByConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	out := gens[:0:0]
+	for _, g := range gens {
+		if g.Subject == subject {
+			out = append(out, g)
+		}
+	}
+	return out, nil
 }
 
 // buildGenerationFromChat creates a generation record from a chat completion response.
-func (s *Service) buildGenerationFromChat(routedModel string, resp llm.ChatCompletionResponse) llm.Generation {
-	return llm.Generation{
-		ID:      resp.ID,
-		Model:   routedModel,
-		Created: resp.Created,
-		Usage:   resp.Usage,
+func (s *Service) buildGenerationFromChat(routedModel, providerName, upstreamModel string, resp llm.ChatCompletionResponse) llm.Generation {
+	gen := llm.Generation{
+		ID:                resp.ID,
+		Model:             routedModel,
+		Created:           resp.Created,
+		Usage:             resp.Usage,
+		Provider:          providerName,
+		UpstreamModel:     upstreamModel,
+		ProviderRequestID: resp.ProviderRequestID,
+	}
+	if len(resp.Choices) > 0 {
+		gen.FinishReason = resp.Choices[0].FinishReason
 	}
+	return gen
 }
 
-// buildGenerationFromEmbeddings creates a generation record from an embeddings response.
-func (s *Service) buildGenerationFromEmbeddings(routedModel string, resp llm.EmbeddingsResponse) llm.Generation {
+// buildGenerationFromEmbeddings creates a generation record from an embeddings
+// response. It records only usage and request metadata: vectors are never
+// persisted, as they would dwarf the rest of the record.
+func (s *Service) buildGenerationFromEmbeddings(routedModel, providerName, upstreamModel string, resp llm.EmbeddingsResponse) llm.Generation {
 	usage := llm.TokenUsage{
 		PromptTokens:     resp.Usage.PromptTokens,
 		CompletionTokens: 0,
@@ -182,7 +729,11 @@ func (s *Service) buildGenerationFromEmbeddings(routedModel string, resp llm.Emb
 	return llm.Generation{
 		ID:      resp.ID,
 		Model:   routedModel,
-		Created: 0, // Embeddings response doesn't include created timestamp.
+		Created: resp.Created,
 		Usage:   usage,
+
+		Provider:          providerName,
+		UpstreamModel:     upstreamModel,
+		ProviderRequestID: resp.ProviderRequestID,
 	}
 }
@@ -0,0 +1,2663 @@
+package llmgateway
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// fakeProvider is a configurable in-memory Provider for service tests.
+type fakeProvider struct {
+	chat       func(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error)
+	embeddings func(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error)
+}
+
+func (p *fakeProvider) CreateChatCompletion(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	if p.chat == nil {
+		return llm.ChatCompletionResponse{ID: "chat-1", Model: req.Model}, nil
+	}
+	return p.chat(ctx, req)
+}
+
+func (p *fakeProvider) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	if p.embeddings == nil {
+		return llm.EmbeddingsResponse{ID: "emb-1", Model: req.Model}, nil
+	}
+	return p.embeddings(ctx, req)
+}
+
+func newTestService(p Provider) *Service {
+	return NewService(map[string]Provider{"fake": p}, nil, nil)
+}
+
+func TestService_CreateChatCompletion_ReportsAllViolations(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(&fakeProvider{})
+	temperature := 3.0
+	_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Temperature: &temperature,
+		Stop:        []string{"a", "b", "c", "d", "e"},
+	})
+	if !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	var verr *llm.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *llm.ValidationError, got %T", err)
+	}
+	fields := make(map[string]bool, len(verr.Violations))
+	for _, v := range verr.Violations {
+		fields[v.Field] = true
+	}
+	for _, f := range []string{"model", "messages", "temperature", "stop"} {
+		if !fields[f] {
+			t.Fatalf("missing violation for %q: %+v", f, verr.Violations)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_TemperatureRange(t *testing.T) {
+	t.Parallel()
+
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+	tests := []struct {
+		name        string
+		temperature float64
+		opts        []Option
+		wantErr     bool
+	}{
+		{name: "zero", temperature: 0},
+		{name: "in range", temperature: 0.7},
+		{name: "upper bound", temperature: 2},
+		{name: "too high", temperature: 5, wantErr: true},
+		{name: "negative", temperature: -0.1, wantErr: true},
+		{name: "custom bound", temperature: 1.5, opts: []Option{WithMaxTemperature(1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, nil, nil, tt.opts...)
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:       "fake/model",
+			Messages:    msgs,
+			Temperature: &tt.temperature,
+		})
+		if tt.wantErr != errors.Is(err, llm.ErrInvalidArgument) {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
+
+type memAuditSink struct {
+	mu      sync.Mutex
+	records []llm.AuditRecord
+}
+
+func (s *memAuditSink) Record(_ context.Context, rec llm.AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, rec)
+}
+
+func TestService_AuditOnlyForOptedInSubjects(t *testing.T) {
+	t.Parallel()
+
+	sink := &memAuditSink{}
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, nil, nil, WithAudit(sink, []string{"audited"}))
+
+	for _, subject := range []string{"audited", "other", ""} {
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:    "fake/model",
+			Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+			Subject:  subject,
+		})
+		if err != nil {
+			t.Fatalf("CreateChatCompletion(%q) error: %v", subject, err)
+		}
+	}
+	if _, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{
+		Model:   "fake/model",
+		Input:   []string{"hello"},
+		Subject: "other",
+	}); err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+
+	if len(sink.records) != 1 {
+		t.Fatalf("expected 1 audit record, got %d", len(sink.records))
+	}
+	rec := sink.records[0]
+	if rec.Subject != "audited" || rec.Operation != "chat.completions" || len(rec.Messages) != 1 {
+		t.Fatalf("unexpected audit record: %+v", rec)
+	}
+}
+
+func TestService_CreateEmbeddings_CoalescesIdenticalRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	svc := newTestService(&fakeProvider{
+		embeddings: func(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+			calls.Add(1)
+			<-release
+			return llm.EmbeddingsResponse{ID: "emb-1", Model: req.Model, Data: []llm.Embedding{{Vector: []float32{1}}}}, nil
+		},
+	})
+
+	const n = 10
+	var started, done sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			res, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/m", Input: []string{"same"}})
+			if err == nil && res.ID != "emb-1" {
+				err = errors.New("unexpected response id " + res.ID)
+			}
+			errs <- err
+		}()
+	}
+	started.Wait()
+	time.Sleep(50 * time.Millisecond) // let every caller join the in-flight call
+	close(release)
+	done.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CreateEmbeddings error: %v", err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected exactly 1 upstream call, got %d", got)
+	}
+
+	// Different input is a different flight.
+	if _, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/m", Input: []string{"other"}}); err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected a second upstream call for different input, got %d", got)
+	}
+}
+
+func TestService_ListModels_Pagination(t *testing.T) {
+	t.Parallel()
+
+	specs := []ModelSpec{
+		{ID: "fake/e", Provider: "fake"},
+		{ID: "fake/a", Provider: "fake"},
+		{ID: "fake/d", Provider: "fake"},
+		{ID: "fake/b", Provider: "fake"},
+		{ID: "fake/c", Provider: "fake"},
+	}
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, specs, nil)
+
+	var (
+		ids   []string
+		token string
+		pages int
+	)
+	for {
+		models, next, err := svc.ListModels(context.Background(), 2, token)
+		if err != nil {
+			t.Fatalf("ListModels error: %v", err)
+		}
+		pages++
+		for _, m := range models {
+			ids = append(ids, m.ID)
+		}
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	if pages != 3 {
+		t.Fatalf("expected 3 pages, got %d", pages)
+	}
+	want := []string{"fake/a", "fake/b", "fake/c", "fake/d", "fake/e"}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected order: %v", ids)
+	}
+
+	// The same cursor yields the same page on repeated calls.
+	_, firstNext, _ := svc.ListModels(context.Background(), 2, "")
+	p1, _, _ := svc.ListModels(context.Background(), 2, firstNext)
+	p2, _, _ := svc.ListModels(context.Background(), 2, firstNext)
+	if len(p1) != 2 || p1[0].ID != "fake/c" || p2[0].ID != p1[0].ID || p2[1].ID != p1[1].ID {
+		t.Fatalf("cursor not stable: %v vs %v", p1, p2)
+	}
+
+	// page_size 0 returns everything.
+	all, next, _ := svc.ListModels(context.Background(), 0, "")
+	if len(all) != 5 || next != "" {
+		t.Fatalf("unexpected unpaged result: %d models, next=%q", len(all), next)
+	}
+
+	if _, _, err := svc.ListModels(context.Background(), 2, "!!!"); !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid page token error, got %v", err)
+	}
+}
+
+func TestService_CreateChatCompletion_ValidatesAudioModality(t *testing.T) {
+	t.Parallel()
+
+	specs := []ModelSpec{
+		{ID: "fake/omni", Provider: "fake", Capabilities: []string{"chat", "audio"}},
+		{ID: "fake/text", Provider: "fake", Capabilities: []string{"chat"}},
+	}
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, specs, nil)
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+	audio := &llm.AudioOutput{Voice: "alloy", Format: "wav"}
+
+	tests := []struct {
+		name    string
+		req     llm.ChatCompletionRequest
+		wantErr bool
+	}{
+		{name: "audio capable", req: llm.ChatCompletionRequest{Model: "fake/omni", Modalities: []string{"text", "audio"}, Audio: audio}},
+		{name: "text only model", req: llm.ChatCompletionRequest{Model: "fake/text", Modalities: []string{"text", "audio"}, Audio: audio}, wantErr: true},
+		{name: "missing audio options", req: llm.ChatCompletionRequest{Model: "fake/omni", Modalities: []string{"audio"}}, wantErr: true},
+		{name: "unknown modality", req: llm.ChatCompletionRequest{Model: "fake/omni", Modalities: []string{"video"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt.req.Messages = msgs
+		_, err := svc.CreateChatCompletion(context.Background(), tt.req)
+		if tt.wantErr != (err != nil) {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_ValidatesProviderPreferences(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(&fakeProvider{})
+	_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		ProviderPreferences: &llm.ProviderPreferences{
+			Order:          []string{"openai", "", "openai"},
+			DataCollection: "maybe",
+		},
+	})
+	var verr *llm.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *llm.ValidationError, got %v", err)
+	}
+	fields := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	want := "provider.order[1],provider.order[2],provider.data_collection"
+	if got := strings.Join(fields, ","); got != want {
+		t.Fatalf("unexpected violations: %s", got)
+	}
+}
+
+func TestService_RetryHonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	svc := newTestService(&fakeProvider{
+		chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+			calls++
+			if calls == 1 {
+				return llm.ChatCompletionResponse{}, &llm.ProviderHTTPError{Provider: "fake", StatusCode: 429, RetryAfter: 3 * time.Second}
+			}
+			return llm.ChatCompletionResponse{ID: "ok"}, nil
+		},
+	})
+	WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseBackoff: 100 * time.Millisecond, MaxBackoff: 5 * time.Second})(svc)
+	var slept []time.Duration
+	svc.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	res, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if err != nil || res.ID != "ok" {
+		t.Fatalf("unexpected result: %+v, %v", res, err)
+	}
+	if calls != 2 || len(slept) != 1 || slept[0] != 3*time.Second {
+		t.Fatalf("expected one retry after 3s, got calls=%d slept=%v", calls, slept)
+	}
+
+	// Retry-After beyond MaxBackoff is capped.
+	if d := svc.retryDelay(0, &llm.ProviderHTTPError{StatusCode: 429, RetryAfter: time.Hour}); d != 5*time.Second {
+		t.Fatalf("expected capped delay, got %v", d)
+	}
+	// Non-transient errors are not retried.
+	if retryable(&llm.ProviderHTTPError{StatusCode: 401}) || retryable(llm.InvalidArgument("bad")) {
+		t.Fatalf("unexpected retryable classification")
+	}
+}
+
+func TestService_RetriesConnectionResetOnlyWhenIdempotent(t *testing.T) {
+	t.Parallel()
+
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	var chatCalls, embedCalls, embedResets int
+	svc := newTestService(&fakeProvider{
+		chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+			chatCalls++
+			return llm.ChatCompletionResponse{}, reset
+		},
+		embeddings: func(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+			embedCalls++
+			if embedCalls <= embedResets {
+				return llm.EmbeddingsResponse{}, reset
+			}
+			return llm.EmbeddingsResponse{ID: "emb-1", Data: []llm.Embedding{{Vector: []float32{1}}}}, nil
+		},
+	})
+	WithRetryPolicy(RetryPolicy{MaxAttempts: 1, RetryConnectionReset: true})(svc)
+
+	embedResets = 1
+	res, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"a"}})
+	if err != nil || len(res.Data) != 1 {
+		t.Fatalf("expected the reset embeddings call to be retried, got %+v, %v", res, err)
+	}
+	if embedCalls != 2 {
+		t.Fatalf("expected 2 embeddings attempts, got %d", embedCalls)
+	}
+
+	// Chat may already have been processed upstream: never retried on a reset.
+	_, err = svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if !errors.Is(err, syscall.ECONNRESET) || chatCalls != 1 {
+		t.Fatalf("expected a single chat attempt, got calls=%d err=%v", chatCalls, err)
+	}
+
+	// Only one reset retry per call.
+	embedCalls, embedResets = 0, 2
+	if _, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"a"}}); err == nil {
+		t.Fatal("expected repeated resets to fail")
+	}
+	if embedCalls != 2 {
+		t.Fatalf("expected exactly one retry, got %d attempts", embedCalls)
+	}
+}
+
+// listingProvider is a fakeProvider that also lists upstream models.
+type listingProvider struct {
+	fakeProvider
+	list func(ctx context.Context) ([]llm.Model, error)
+}
+
+func (p *listingProvider) ListUpstreamModels(ctx context.Context) ([]llm.Model, error) {
+	return p.list(ctx)
+}
+
+func TestService_RefreshModels_MergesUpstreamModels(t *testing.T) {
+	t.Parallel()
+
+	upstream := []llm.Model{{ID: "m1", Name: "Upstream M1"}, {ID: "m2", Name: "Upstream M2"}}
+	var failNext bool
+	p := &listingProvider{list: func(context.Context) ([]llm.Model, error) {
+		if failNext {
+			return nil, errors.New("upstream down")
+		}
+		return upstream, nil
+	}}
+	configModels := []ModelSpec{{ID: "fake/m1", Name: "Configured M1", Provider: "fake", UpstreamModel: "m1-pinned"}}
+	svc := NewService(map[string]Provider{"fake": p}, configModels, nil, WithModelDiscovery("fake"))
+
+	if err := svc.RefreshModels(context.Background()); err != nil {
+		t.Fatalf("RefreshModels error: %v", err)
+	}
+	models, _, err := svc.ListModels(context.Background(), 0, "")
+	if err != nil {
+		t.Fatalf("ListModels error: %v", err)
+	}
+	if len(models) != 2 || models[0].ID != "fake/m1" || models[1].ID != "fake/m2" {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+	if models[0].Name != "Configured M1" {
+		t.Fatalf("config model must win on conflict, got %+v", models[0])
+	}
+	if m, _ := svc.lookupModel("fake/m2"); m.UpstreamModel != "m2" || m.Provider != "fake" {
+		t.Fatalf("unexpected discovered spec: %+v", m)
+	}
+
+	// A failed refresh keeps the previously discovered models.
+	failNext = true
+	if err := svc.RefreshModels(context.Background()); err == nil {
+		t.Fatalf("expected refresh error")
+	}
+	if svc.ModelCount() != 2 {
+		t.Fatalf("expected discovered models to survive a failed refresh, got %d", svc.ModelCount())
+	}
+}
+
+func TestService_ModelAccessPerSubject(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}, "other": &fakeProvider{}}, nil, nil, WithModelAccess(map[string]ModelAccess{
+		"billing":   {Allowed: []string{"fake/cheap", "other/*"}},
+		"analytics": {Denied: []string{"fake/*-preview"}},
+		"mixed":     {Allowed: []string{"fake/*"}, Denied: []string{"fake/secret"}},
+	}))
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+
+	tests := []struct {
+		subject string
+		model   string
+		allowed bool
+	}{
+		{subject: "billing", model: "fake/cheap", allowed: true},
+		{subject: "billing", model: "fake/expensive", allowed: false},
+		{subject: "billing", model: "other/vendor/model", allowed: true},
+		{subject: "analytics", model: "fake/m", allowed: true},
+		{subject: "analytics", model: "fake/gpt-5-preview", allowed: false},
+		{subject: "mixed", model: "fake/m", allowed: true},
+		{subject: "mixed", model: "fake/secret", allowed: false},
+		{subject: "unrestricted", model: "fake/expensive", allowed: true},
+		{subject: "", model: "fake/expensive", allowed: true},
+	}
+	for _, tt := range tests {
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: tt.model, Messages: msgs, Subject: tt.subject})
+		if tt.allowed && err != nil {
+			t.Fatalf("%s -> %s: unexpected error: %v", tt.subject, tt.model, err)
+		}
+		if !tt.allowed && !errors.Is(err, llm.ErrPermissionDenied) {
+			t.Fatalf("%s -> %s: expected permission denied, got %v", tt.subject, tt.model, err)
+		}
+	}
+
+	_, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/expensive", Input: []string{"x"}, Subject: "billing"})
+	if !errors.Is(err, llm.ErrPermissionDenied) {
+		t.Fatalf("expected embeddings to be restricted too, got %v", err)
+	}
+}
+
+func TestService_CreateEmbeddings_RecordsCreated(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeGenerations{}
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, nil, repo)
+	before := time.Now().Unix()
+	resp, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/emb", Input: []string{"hi"}})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings: %v", err)
+	}
+	if resp.Created < before || resp.Created > time.Now().Unix() {
+		t.Fatalf("response created = %d, want the receive time", resp.Created)
+	}
+	gen, err := repo.Get(context.Background(), resp.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if gen.Created == 0 || gen.Created != resp.Created {
+		t.Fatalf("saved generation created = %d, want %d", gen.Created, resp.Created)
+	}
+}
+
+func TestService_CreateEmbeddings_InputLimits(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	p := &fakeProvider{embeddings: func(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		calls.Add(1)
+		return llm.EmbeddingsResponse{ID: "emb", Model: req.Model}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithEmbeddingsInputLimits(3, 10))
+
+	tests := []struct {
+		name    string
+		input   []string
+		wantErr bool
+	}{
+		{name: "items at limit", input: []string{"a", "b", "c"}},
+		{name: "items over limit", input: []string{"a", "b", "c", "d"}, wantErr: true},
+		{name: "bytes at limit", input: []string{"12345", "67890"}},
+		{name: "bytes over limit", input: []string{"12345", "678901"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		before := calls.Load()
+		_, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/emb", Input: tt.input})
+		if tt.wantErr {
+			if !errors.Is(err, llm.ErrInvalidArgument) {
+				t.Fatalf("%s: expected invalid argument, got %v", tt.name, err)
+			}
+			if calls.Load() != before {
+				t.Fatalf("%s: oversize request must not reach the provider", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_ContentPartLimits(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	p := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		calls.Add(1)
+		return llm.ChatCompletionResponse{ID: "chat-1"}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithContentPartLimits(2, 3))
+	parts := func(n int) llm.ChatMessage {
+		m := llm.ChatMessage{Role: "user"}
+		for range n {
+			m.ContentParts = append(m.ContentParts, llm.ContentPart{Type: "text", Text: "x"})
+		}
+		return m
+	}
+
+	tests := []struct {
+		name    string
+		msgs    []llm.ChatMessage
+		wantErr bool
+	}{
+		{name: "message at limit", msgs: []llm.ChatMessage{parts(2)}},
+		{name: "message over limit", msgs: []llm.ChatMessage{parts(3)}, wantErr: true},
+		{name: "request at limit", msgs: []llm.ChatMessage{parts(2), parts(1)}},
+		{name: "request over limit", msgs: []llm.ChatMessage{parts(2), parts(2)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		before := calls.Load()
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: tt.msgs})
+		if tt.wantErr {
+			if !errors.Is(err, llm.ErrInvalidArgument) {
+				t.Fatalf("%s: expected invalid argument, got %v", tt.name, err)
+			}
+			if calls.Load() != before {
+				t.Fatalf("%s: oversize request must not reach the provider", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
+
+func TestService_UpstreamSlotsPrioritizeServiceTier(t *testing.T) {
+	t.Parallel()
+
+	entered := make(chan string, 3)
+	release := make(chan struct{})
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		entered <- req.User
+		<-release
+		return llm.ChatCompletionResponse{ID: req.User}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithUpstreamConcurrency(1))
+	call := func(user, tier string) {
+		_, _ = svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}, User: user, ServiceTier: tier,
+		})
+	}
+	queued := func() int {
+		svc.upstreamSlots.mu.Lock()
+		defer svc.upstreamSlots.mu.Unlock()
+		return svc.upstreamSlots.queued()
+	}
+	waitQueued := func(n int) {
+		t.Helper()
+		for i := 0; queued() != n; i++ {
+			if i > 1000 {
+				t.Fatalf("expected %d queued, got %d", n, queued())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() { defer wg.Done(); call("holder", "") }()
+	if got := <-entered; got != "holder" {
+		t.Fatalf("unexpected first caller %q", got)
+	}
+	// flex queues first, but default must be granted the slot before it.
+	go func() { defer wg.Done(); call("flex", ServiceTierFlex) }()
+	waitQueued(1)
+	go func() { defer wg.Done(); call("default", ServiceTierDefault) }()
+	waitQueued(2)
+
+	release <- struct{}{}
+	if got := <-entered; got != "default" {
+		t.Fatalf("expected default tier to be granted first, got %q", got)
+	}
+	release <- struct{}{}
+	if got := <-entered; got != "flex" {
+		t.Fatalf("expected flex tier last, got %q", got)
+	}
+	close(release)
+	wg.Wait()
+
+	_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}, ServiceTier: "turbo",
+	})
+	if !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid service_tier to be rejected, got %v", err)
+	}
+}
+
+type emptyCountingMetrics struct {
+	nopMetrics
+	empty atomic.Int32
+}
+
+func (m *emptyCountingMetrics) EmptyResponse(string, string) { m.empty.Add(1) }
+
+func TestService_CreateChatCompletion_EmptyResponsePolicy(t *testing.T) {
+	t.Parallel()
+
+	empty := llm.ChatCompletionResponse{ID: "empty"}
+	full := llm.ChatCompletionResponse{ID: "full", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Content: "hi"}}}}
+	audioOnly := llm.ChatCompletionResponse{ID: "audio", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Audio: &llm.AudioContent{ID: "audio_1", Data: "UklGRg=="}}}}}
+	tests := []struct {
+		policy    EmptyResponsePolicy
+		responses []llm.ChatCompletionResponse // returned in order; last repeats
+		wantID    string
+		wantErr   error
+		wantCalls int32
+		wantEmpty int32
+	}{
+		{policy: EmptyResponsePassThrough, responses: []llm.ChatCompletionResponse{empty}, wantID: "empty", wantCalls: 1, wantEmpty: 1},
+		{policy: EmptyResponseError, responses: []llm.ChatCompletionResponse{empty}, wantErr: llm.ErrEmptyResponse, wantCalls: 1, wantEmpty: 1},
+		{policy: EmptyResponseRetry, responses: []llm.ChatCompletionResponse{empty, full}, wantID: "full", wantCalls: 2, wantEmpty: 1},
+		{policy: EmptyResponseRetry, responses: []llm.ChatCompletionResponse{empty}, wantErr: llm.ErrEmptyResponse, wantCalls: 2, wantEmpty: 2},
+		{policy: EmptyResponseError, responses: []llm.ChatCompletionResponse{full}, wantID: "full", wantCalls: 1},
+		{policy: EmptyResponseError, responses: []llm.ChatCompletionResponse{audioOnly}, wantID: "audio", wantCalls: 1},
+	}
+	for _, tt := range tests {
+		var calls atomic.Int32
+		p := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+			n := int(calls.Add(1)) - 1
+			return tt.responses[min(n, len(tt.responses)-1)], nil
+		}}
+		m := &emptyCountingMetrics{}
+		svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithEmptyResponsePolicy(tt.policy), WithMetrics(m))
+
+		resp, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		})
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: expected error %v, got %v", tt.policy, tt.wantErr, err)
+		}
+		if resp.ID != tt.wantID {
+			t.Fatalf("%s: expected response %q, got %q", tt.policy, tt.wantID, resp.ID)
+		}
+		if calls.Load() != tt.wantCalls || m.empty.Load() != tt.wantEmpty {
+			t.Fatalf("%s: expected %d calls and %d empty reports, got %d and %d", tt.policy, tt.wantCalls, tt.wantEmpty, calls.Load(), m.empty.Load())
+		}
+	}
+}
+
+type slowCallMetrics struct {
+	nopMetrics
+	mu    sync.Mutex
+	calls []string // "provider model"
+}
+
+func (m *slowCallMetrics) SlowUpstreamCall(provider, model string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, provider+" "+model)
+}
+
+func TestService_CreateChatCompletion_ReportsSlowUpstreamCalls(t *testing.T) {
+	t.Parallel()
+
+	slow := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		time.Sleep(30 * time.Millisecond)
+		return llm.ChatCompletionResponse{ID: "slow"}, nil
+	}}
+	tests := []struct {
+		name        string
+		provider    Provider
+		perProvider map[string]time.Duration
+		want        []string
+	}{
+		{name: "slow", provider: slow, want: []string{"fake fake/m"}},
+		{name: "fast", provider: &fakeProvider{}},
+		{name: "disabled for provider", provider: slow, perProvider: map[string]time.Duration{"fake": 0}},
+	}
+	for _, tt := range tests {
+		m := &slowCallMetrics{}
+		svc := NewService(map[string]Provider{"fake": tt.provider}, []ModelSpec{{ID: "fake/m", Provider: "fake"}}, nil,
+			WithMetrics(m), WithSlowUpstreamThreshold(10*time.Millisecond, tt.perProvider))
+
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !slices.Equal(m.calls, tt.want) {
+			t.Fatalf("%s: expected slow calls %v, got %v", tt.name, tt.want, m.calls)
+		}
+	}
+}
+
+// modelLabelMetrics records the model labels of started requests.
+type modelLabelMetrics struct {
+	nopMetrics
+	mu     sync.Mutex
+	models []string
+}
+
+func (m *modelLabelMetrics) ModelRequestStarted(_, model string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.models = append(m.models, model)
+}
+
+func TestService_MetricsLabelOnlyCatalogModels(t *testing.T) {
+	t.Parallel()
+
+	m := &modelLabelMetrics{}
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, []ModelSpec{{ID: "fake/m", Provider: "fake"}}, nil, WithMetrics(m))
+	for _, model := range []string{"fake/m", "FAKE/m", "fake/made-up-1", "no-such-model"} {
+		_, _ = svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model: model, Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		})
+	}
+	if want := []string{"fake/m", "fake/m", "other", "other"}; !slices.Equal(m.models, want) {
+		t.Fatalf("model labels = %v, want %v", m.models, want)
+	}
+}
+
+// fakeGenerations is an in-memory GenerationRepository for service tests.
+type fakeGenerations struct {
+	mu    sync.Mutex
+	saved []llm.Generation
+}
+
+func (r *fakeGenerations) Save(_ context.Context, gen llm.Generation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, gen)
+	return nil
+}
+
+func (r *fakeGenerations) Get(_ context.Context, id string) (llm.Generation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, g := range r.saved {
+		if g.ID == id {
+			return g, nil
+		}
+	}
+	return llm.Generation{}, llm.NotFound(id)
+}
+
+func (r *fakeGenerations) ListGenerationsByConversation(_ context.Context, conversationID string) ([]llm.Generation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []llm.Generation
+	for _, g := range r.saved {
+		if g.ConversationID == conversationID {
+			out = append(out, g)
+		}
+	}
+	return out, nil
+}
+
+func TestService_ConversationIDTagsGenerations(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeGenerations{}
+	var n atomic.Int32
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		return llm.ChatCompletionResponse{
+			ID:      "chat-" + string(rune('a'+n.Add(1)-1)),
+			Model:   req.Model,
+			Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Content: "ok"}}},
+		}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, repo)
+
+	chat := func(subject, conversationID string) {
+		t.Helper()
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:          "fake/m",
+			Messages:       []llm.ChatMessage{{Role: "user", Content: "hi"}},
+			Subject:        subject,
+			ConversationID: conversationID,
+		})
+		if err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+	chat("svc:a", "conv-1")
+	chat("svc:a", "conv-2")
+	chat("svc:b", "conv-1")
+	chat("svc:a", "conv-1")
+
+	gen, err := svc.GetGeneration(context.Background(), "svc:a", "chat-a")
+	if err != nil {
+		t.Fatalf("GetGeneration: %v", err)
+	}
+	if gen.ConversationID != "conv-1" || gen.Subject != "svc:a" {
+		t.Fatalf("generation not tagged: %+v", gen)
+	}
+	if _, err := svc.GetGeneration(context.Background(), "svc:b", "chat-a"); !errors.Is(err, llm.ErrNotFound) {
+		t.Fatalf("expected another subject's generation to be NotFound, got %v", err)
+	}
+
+	gens, err := svc.ListGenerationsByConversation(context.Background(), "svc:a", "conv-1")
+	if err != nil {
+		t.Fatalf("ListGenerationsByConversation: %v", err)
+	}
+	// Other subjects' records in the same conversation are not visible.
+	if len(gens) != 2 || gens[0].ID != "chat-a" || gens[1].ID != "chat-d" {
+		t.Fatalf("unexpected generations: %+v", gens)
+	}
+
+	if _, err := svc.ListGenerationsByConversation(context.Background(), "svc:a", ""); !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument for empty conversation id, got %v", err)
+	}
+	_, err = svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:          "fake/m",
+		Messages:       []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		ConversationID: strings.Repeat("x", 129),
+	})
+	if !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument for long conversation id, got %v", err)
+	}
+}
+
+func TestService_GenerationRecordsRequestMetadata(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeGenerations{}
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		time.Sleep(2 * time.Millisecond)
+		return llm.ChatCompletionResponse{
+			ID:      "chat-meta",
+			Model:   req.Model,
+			Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Content: "ok"}, FinishReason: "length"}},
+		}, nil
+	}}
+	models := []ModelSpec{{ID: "alias", Provider: "fake", UpstreamModel: "upstream-m"}}
+	svc := NewService(map[string]Provider{"fake": p}, models, repo)
+
+	_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "alias",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		Subject:  "svc:a",
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	gen, err := svc.GetGeneration(context.Background(), "svc:a", "chat-meta")
+	if err != nil {
+		t.Fatalf("GetGeneration: %v", err)
+	}
+	if gen.Model != "alias" || gen.Provider != "fake" || gen.UpstreamModel != "upstream-m" ||
+		gen.Subject != "svc:a" || gen.FinishReason != "length" || gen.Latency < 2*time.Millisecond {
+		t.Fatalf("unexpected generation metadata: %+v", gen)
+	}
+}
+
+func TestService_CreateEmbeddingsGroup_KeyedByModel(t *testing.T) {
+	t.Parallel()
+
+	vectorProvider := func(v float32) *fakeProvider {
+		return &fakeProvider{embeddings: func(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+			data := make([]llm.Embedding, len(req.Input))
+			for i := range req.Input {
+				data[i] = llm.Embedding{Index: uint32(i), Vector: []float32{v}}
+			}
+			return llm.EmbeddingsResponse{ID: "emb-" + req.Model, Model: req.Model, Data: data}, nil
+		}}
+	}
+	models := []ModelSpec{
+		{ID: "small", Provider: "a", UpstreamModel: "embed-small", Capabilities: []string{"embeddings"}},
+		{ID: "large", Provider: "b", UpstreamModel: "embed-large", Capabilities: []string{"embeddings"}},
+		{ID: "chat", Provider: "a", Capabilities: []string{"chat"}},
+	}
+	svc := NewService(map[string]Provider{"a": vectorProvider(1), "b": vectorProvider(2)}, models, nil)
+
+	res, err := svc.CreateEmbeddingsGroup(context.Background(), llm.EmbeddingsRequest{Input: []string{"x", "y"}}, []string{"small", "large"})
+	if err != nil {
+		t.Fatalf("CreateEmbeddingsGroup: %v", err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("expected 2 results, got %+v", res)
+	}
+	for model, want := range map[string]float32{"small": 1, "large": 2} {
+		r, ok := res[model]
+		if !ok || len(r.Data) != 2 || r.Data[1].Vector[0] != want {
+			t.Fatalf("unexpected result for %s: %+v", model, r)
+		}
+	}
+
+	for name, group := range map[string][]string{
+		"not embeddings-capable": {"small", "chat"},
+		"duplicate":              {"small", "small"},
+		"empty":                  nil,
+	} {
+		_, err := svc.CreateEmbeddingsGroup(context.Background(), llm.EmbeddingsRequest{Input: []string{"x"}}, group)
+		if !errors.Is(err, llm.ErrInvalidArgument) {
+			t.Fatalf("%s: expected invalid argument, got %v", name, err)
+		}
+	}
+}
+
+func TestService_CompareCompletions_LabeledByModel(t *testing.T) {
+	t.Parallel()
+
+	answering := func(answer string, tokens uint32) *fakeProvider {
+		return &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+			return llm.ChatCompletionResponse{
+				ID:      "chat-" + req.Model,
+				Model:   req.Model,
+				Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Content: answer}, FinishReason: "stop"}},
+				Usage:   llm.TokenUsage{PromptTokens: 5, CompletionTokens: tokens, TotalTokens: 5 + tokens},
+			}, nil
+		}}
+	}
+	models := []ModelSpec{
+		{ID: "model-a", Provider: "a", UpstreamModel: "up-a", Capabilities: []string{"chat"}},
+		{ID: "model-b", Provider: "b", UpstreamModel: "up-b", Capabilities: []string{"chat", "vision"}},
+		{ID: "embed", Provider: "a", UpstreamModel: "up-e", Capabilities: []string{"embeddings"}},
+	}
+	svc := NewService(map[string]Provider{"a": answering("Paris.", 2), "b": answering("It is Paris.", 4)}, models, nil)
+	req := llm.ChatCompletionRequest{Messages: []llm.ChatMessage{{Role: "user", Content: "Capital of France?"}}}
+
+	res, err := svc.CompareCompletions(context.Background(), req, []string{"model-b", "model-a"})
+	if err != nil {
+		t.Fatalf("CompareCompletions: %v", err)
+	}
+	want := []struct {
+		model, content string
+		completion     uint32
+	}{{"model-b", "It is Paris.", 4}, {"model-a", "Paris.", 2}}
+	if len(res) != len(want) {
+		t.Fatalf("expected %d results, got %+v", len(want), res)
+	}
+	for i, w := range want {
+		r := res[i]
+		if r.Model != w.model || r.Response.Choices[0].Message.Content != w.content || r.Response.Usage.CompletionTokens != w.completion {
+			t.Fatalf("result %d: expected %s answering %q with %d tokens, got %+v", i, w.model, w.content, w.completion, r)
+		}
+	}
+
+	for name, group := range map[string][]string{
+		"not chat-capable": {"model-a", "embed"},
+		"duplicate":        {"model-a", "model-a"},
+		"empty":            nil,
+	} {
+		if _, err := svc.CompareCompletions(context.Background(), req, group); !errors.Is(err, llm.ErrInvalidArgument) {
+			t.Fatalf("%s: expected invalid argument, got %v", name, err)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_DefaultTemperature(t *testing.T) {
+	t.Parallel()
+
+	var got *float64
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		got = req.Temperature
+		return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Content: "ok"}}}}, nil
+	}}
+	def := 0.3
+	models := []ModelSpec{{ID: "tuned", Provider: "fake", UpstreamModel: "m", DefaultTemperature: &def}}
+	svc := NewService(map[string]Provider{"fake": p}, models, nil)
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+	zero, explicit := 0.0, 1.2
+
+	tests := []struct {
+		name        string
+		model       string
+		temperature *float64
+		want        *float64
+	}{
+		{name: "unset uses default", model: "tuned", want: &def},
+		{name: "explicit zero kept", model: "tuned", temperature: &zero, want: &zero},
+		{name: "explicit value kept", model: "tuned", temperature: &explicit, want: &explicit},
+		{name: "no default stays unset", model: "fake/other"},
+	}
+	for _, tt := range tests {
+		got = nil
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:       tt.model,
+			Messages:    msgs,
+			Temperature: tt.temperature,
+		})
+		if err != nil {
+			t.Fatalf("%s: CreateChatCompletion: %v", tt.name, err)
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Fatalf("%s: upstream temperature = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_SystemPrompt(t *testing.T) {
+	t.Parallel()
+
+	var got []llm.ChatMessage
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		got = req.Messages
+		return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Content: "ok"}}}}, nil
+	}}
+	const preamble = "Be safe."
+	models := []ModelSpec{
+		{ID: "guarded", Provider: "fake", UpstreamModel: "m", SystemPrompt: preamble},
+		{ID: "strict", Provider: "fake", UpstreamModel: "m", SystemPrompt: preamble, SystemPromptAlways: true},
+	}
+	svc := NewService(map[string]Provider{"fake": p}, models, nil)
+	injected := llm.ChatMessage{Role: "system", Content: preamble}
+	own := llm.ChatMessage{Role: "system", Content: "Answer in French."}
+	user := llm.ChatMessage{Role: "user", Content: "hi"}
+
+	tests := []struct {
+		name  string
+		model string
+		msgs  []llm.ChatMessage
+		want  []llm.ChatMessage
+	}{
+		{name: "injected when missing", model: "guarded", msgs: []llm.ChatMessage{user}, want: []llm.ChatMessage{injected, user}},
+		{name: "skipped when present", model: "guarded", msgs: []llm.ChatMessage{own, user}, want: []llm.ChatMessage{own, user}},
+		{name: "always prepends", model: "strict", msgs: []llm.ChatMessage{own, user}, want: []llm.ChatMessage{injected, own, user}},
+		{name: "always does not duplicate", model: "strict", msgs: []llm.ChatMessage{injected, user}, want: []llm.ChatMessage{injected, user}},
+		{name: "no prompt configured", model: "fake/other", msgs: []llm.ChatMessage{user}, want: []llm.ChatMessage{user}},
+	}
+	for _, tt := range tests {
+		got = nil
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: tt.model, Messages: tt.msgs})
+		if err != nil {
+			t.Fatalf("%s: CreateChatCompletion: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: upstream messages = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_MetadataAllowlist(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]string
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		got = req.Metadata
+		return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Content: "ok"}}}}, nil
+	}}
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+	md := map[string]string{"trace_id": "t-1", "session": "s-1", "authorization": "Bearer x"}
+
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithMetadataAllowlist([]string{"trace_id", "session"}))
+	if _, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: msgs, Metadata: md}); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	want := map[string]string{"trace_id": "t-1", "session": "s-1"}
+	if len(got) != len(want) || got["trace_id"] != "t-1" || got["session"] != "s-1" {
+		t.Fatalf("forwarded metadata = %v, want %v", got, want)
+	}
+
+	svc = newTestService(p)
+	if _, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: msgs, Metadata: md}); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("forwarded metadata without allowlist = %v, want none", got)
+	}
+}
+
+type completingProvider struct {
+	fakeProvider
+	got   llm.CompletionRequest
+	delay time.Duration
+}
+
+func (p *completingProvider) CreateCompletion(_ context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	p.got = req
+	time.Sleep(p.delay)
+	return llm.CompletionResponse{
+		ID:      "cmpl-1",
+		Created: 1700000000,
+		Model:   req.Model,
+		Choices: []llm.CompletionChoice{{Index: 0, Text: " world", FinishReason: "stop"}},
+		Usage:   llm.TokenUsage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+	}, nil
+}
+
+func TestService_CreateCompletion(t *testing.T) {
+	t.Parallel()
+
+	p := &completingProvider{}
+	gens := &fakeGenerations{}
+	svc := NewService(map[string]Provider{"fake": p, "chatonly": &fakeProvider{}}, nil, gens)
+	temp, maxTokens := 0.2, uint32(16)
+
+	resp, err := svc.CreateCompletion(context.Background(), llm.CompletionRequest{
+		Model:       "fake/instruct",
+		Prompt:      "hello",
+		MaxTokens:   &maxTokens,
+		Temperature: &temp,
+		Stop:        []string{"\n"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCompletion: %v", err)
+	}
+	if p.got.Model != "instruct" || p.got.Prompt != "hello" || *p.got.MaxTokens != 16 || *p.got.Temperature != temp || len(p.got.Stop) != 1 {
+		t.Fatalf("unexpected upstream request: %+v", p.got)
+	}
+	if len(resp.Choices) != 1 || resp.Choices[0].Text != " world" || resp.Choices[0].FinishReason != "stop" {
+		t.Fatalf("unexpected choices: %+v", resp.Choices)
+	}
+	gen, err := gens.Get(context.Background(), "cmpl-1")
+	if err != nil {
+		t.Fatalf("generation not saved: %v", err)
+	}
+	if gen.Model != "fake/instruct" || gen.Usage.TotalTokens != 2 || gen.FinishReason != "stop" {
+		t.Fatalf("unexpected generation: %+v", gen)
+	}
+
+	_, err = svc.CreateCompletion(context.Background(), llm.CompletionRequest{Model: "chatonly/m", Prompt: "hello"})
+	if !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument for provider without completions, got %v", err)
+	}
+	_, err = svc.CreateCompletion(context.Background(), llm.CompletionRequest{Model: "fake/instruct", Stop: []string{"a", "b", "c", "d", "e"}})
+	var verr *llm.ValidationError
+	if !errors.As(err, &verr) || len(verr.Violations) != 2 {
+		t.Fatalf("expected prompt and stop violations, got %v", err)
+	}
+}
+
+func TestService_CreateCompletion_ReportsSlowUpstreamCalls(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name  string
+		delay time.Duration
+		want  []string
+	}{
+		{name: "slow", delay: 30 * time.Millisecond, want: []string{"fake fake/instruct"}},
+		{name: "fast"},
+	} {
+		m := &slowCallMetrics{}
+		svc := NewService(map[string]Provider{"fake": &completingProvider{delay: tt.delay}}, []ModelSpec{{ID: "fake/instruct", Provider: "fake"}}, nil,
+			WithMetrics(m), WithSlowUpstreamThreshold(10*time.Millisecond, nil))
+
+		if _, err := svc.CreateCompletion(context.Background(), llm.CompletionRequest{Model: "fake/instruct", Prompt: "hello"}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !slices.Equal(m.calls, tt.want) {
+			t.Fatalf("%s: expected slow calls %v, got %v", tt.name, tt.want, m.calls)
+		}
+	}
+}
+
+func TestService_CreateEmbeddings_AutoChunk(t *testing.T) {
+	t.Parallel()
+
+	var upstreamInputs []string
+	p := &fakeProvider{embeddings: func(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		upstreamInputs = append(upstreamInputs, req.Input...)
+		data := make([]llm.Embedding, 0, len(req.Input))
+		for i, in := range req.Input {
+			data = append(data, llm.Embedding{Index: uint32(i), Vector: []float32{float32(len(in)), 1}})
+		}
+		return llm.EmbeddingsResponse{ID: "emb-1", Data: data, Usage: llm.EmbeddingsUsage{PromptTokens: 4, TotalTokens: 4}}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithEmbeddingsAutoChunk(10))
+
+	long := strings.Repeat("x", 25)
+	resp, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{
+		Model:     "fake/emb",
+		Input:     []string{long, "short"},
+		AutoChunk: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings: %v", err)
+	}
+	if want := []string{"xxxxxxxxxx", "xxxxxxxxxx", "xxxxx", "short"}; !slices.Equal(upstreamInputs, want) {
+		t.Fatalf("upstream inputs = %q, want %q", upstreamInputs, want)
+	}
+	if len(resp.Data) != 2 {
+		t.Fatalf("expected one pooled vector per input, got %d", len(resp.Data))
+	}
+	pooled := resp.Data[0]
+	if pooled.Index != 0 || pooled.ChunkCount != 3 || !slices.Equal(pooled.Vector, []float32{25.0 / 3, 1}) {
+		t.Fatalf("unexpected pooled embedding: %+v", pooled)
+	}
+	if short := resp.Data[1]; short.Index != 1 || short.ChunkCount != 1 || !slices.Equal(short.Vector, []float32{5, 1}) {
+		t.Fatalf("unexpected short embedding: %+v", short)
+	}
+
+	_, err = newTestService(p).CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/emb", Input: []string{long}, AutoChunk: true})
+	if !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument when auto_chunk is disabled, got %v", err)
+	}
+}
+
+func TestService_CreateEmbeddings_AutoChunkBoundsChunkCount(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	p := &fakeProvider{embeddings: func(context.Context, llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		called = true
+		return llm.EmbeddingsResponse{}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithEmbeddingsAutoChunk(10), WithEmbeddingsInputLimits(3, 1<<20))
+
+	// Two inputs pass validation but expand to four chunks.
+	_, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{
+		Model:     "fake/emb",
+		Input:     []string{strings.Repeat("x", 25), "short"},
+		AutoChunk: true,
+	})
+	var verr *llm.ValidationError
+	if !errors.As(err, &verr) || verr.Violations[0].Field != "input" {
+		t.Fatalf("expected an input violation, got %v", err)
+	}
+	if called {
+		t.Fatal("provider should not be called when chunks exceed the input limit")
+	}
+}
+
+func TestSplitRunes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		n    int
+		want []string
+	}{
+		{in: "short", n: 10, want: []string{"short"}},
+		{in: "hello world again", n: 10, want: []string{"hello ", "world ", "again"}},
+		{in: "日本語のテキスト", n: 3, want: []string{"日本語", "のテキ", "スト"}},
+	}
+	for _, tt := range tests {
+		if got := splitRunes(tt.in, tt.n); !slices.Equal(got, tt.want) {
+			t.Fatalf("splitRunes(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_MaxTokensPresence(t *testing.T) {
+	t.Parallel()
+
+	var got *uint32
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		got = req.MaxTokens
+		return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Content: "ok"}}}}, nil
+	}}
+	svc := newTestService(p)
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+	zero, positive := uint32(0), uint32(64)
+
+	tests := []struct {
+		name      string
+		maxTokens *uint32
+		wantErr   bool
+	}{
+		{name: "unset", maxTokens: nil},
+		{name: "explicit zero", maxTokens: &zero, wantErr: true},
+		{name: "positive", maxTokens: &positive},
+	}
+	for _, tt := range tests {
+		got = nil
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:     "fake/m",
+			Messages:  msgs,
+			MaxTokens: tt.maxTokens,
+		})
+		if tt.wantErr {
+			var verr *llm.ValidationError
+			if !errors.As(err, &verr) || verr.Violations[0].Field != "max_tokens" {
+				t.Fatalf("%s: expected max_tokens violation, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: CreateChatCompletion: %v", tt.name, err)
+		}
+		if got != tt.maxTokens {
+			t.Fatalf("%s: upstream max_tokens = %v, want %v", tt.name, got, tt.maxTokens)
+		}
+	}
+}
+
+func TestService_CreateChatCompletion_ImageURLPolicy(t *testing.T) {
+	t.Parallel()
+
+	image := func(u string) []llm.ChatMessage {
+		return []llm.ChatMessage{{Role: "user", ContentParts: []llm.ContentPart{
+			{Type: "text", Text: "what is this?"},
+			{Type: "image_url", ImageURL: &llm.ImageURL{URL: u}},
+		}}}
+	}
+	const dataURI = "data:image/png;base64,iVBORw0KGgo="
+
+	tests := []struct {
+		name    string
+		policy  ImageURLPolicy
+		url     string
+		wantErr bool
+	}{
+		{name: "no policy allows any host", url: "https://example.org/cat.png"},
+		{name: "allowed host", policy: ImageURLPolicy{AllowedHosts: []string{"images.example.com"}}, url: "https://images.example.com/cat.png"},
+		{name: "allowed wildcard subdomain", policy: ImageURLPolicy{AllowedHosts: []string{"*.example.com"}}, url: "https://cdn.Example.com/cat.png"},
+		{name: "denied host", policy: ImageURLPolicy{AllowedHosts: []string{"images.example.com"}}, url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "non-http scheme", policy: ImageURLPolicy{AllowedHosts: []string{"images.example.com"}}, url: "file:///etc/passwd", wantErr: true},
+		{name: "allowlist accepts data uri", policy: ImageURLPolicy{AllowedHosts: []string{"images.example.com"}}, url: dataURI},
+		{name: "data uri only rejects remote", policy: ImageURLPolicy{DataURIOnly: true}, url: "https://images.example.com/cat.png", wantErr: true},
+		{name: "data uri only accepts data uri", policy: ImageURLPolicy{DataURIOnly: true}, url: dataURI},
+	}
+	for _, tt := range tests {
+		svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, nil, nil, WithImageURLPolicy(tt.policy))
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: image(tt.url)})
+		if !tt.wantErr {
+			if err != nil {
+				t.Fatalf("%s: CreateChatCompletion: %v", tt.name, err)
+			}
+			continue
+		}
+		var verr *llm.ValidationError
+		if !errors.As(err, &verr) || verr.Violations[0].Field != "messages[0].content[1].image_url.url" {
+			t.Fatalf("%s: expected image_url violation, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestService_RequestTimeoutBudgetsRetries(t *testing.T) {
+	t.Parallel()
+
+	unavailable := &llm.ProviderHTTPError{Provider: "fake", StatusCode: 503, Message: "overloaded"}
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+
+	t.Run("slow retry bounded by overall deadline", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		p := &fakeProvider{chat: func(ctx context.Context, _ llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+			if calls.Add(1) == 1 {
+				return llm.ChatCompletionResponse{}, unavailable // failing first attempt
+			}
+			<-ctx.Done() // slow second attempt: only the shared deadline stops it
+			return llm.ChatCompletionResponse{}, ctx.Err()
+		}}
+		svc := NewService(map[string]Provider{"fake": p}, nil, nil,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond}),
+			WithRequestTimeout(50*time.Millisecond))
+
+		start := time.Now()
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: msgs})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("request ran %v, want it bounded by the 50ms budget", elapsed)
+		}
+		if n := calls.Load(); n != 2 {
+			t.Fatalf("expected 2 attempts, got %d", n)
+		}
+	})
+
+	t.Run("no retry when backoff outlasts budget", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		p := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+			calls.Add(1)
+			return llm.ChatCompletionResponse{}, unavailable
+		}}
+		svc := NewService(map[string]Provider{"fake": p}, nil, nil,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Minute}),
+			WithRequestTimeout(time.Second))
+
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: msgs})
+		if !errors.Is(err, unavailable) {
+			t.Fatalf("expected the upstream error without waiting, got %v", err)
+		}
+		if n := calls.Load(); n != 1 {
+			t.Fatalf("expected a single attempt, got %d", n)
+		}
+	})
+}
+
+func TestService_MaintenanceMode(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(&fakeProvider{})
+	chat := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+	emb := llm.EmbeddingsRequest{Model: "fake/emb", Input: []string{"x"}}
+
+	svc.SetMaintenance(true, 30*time.Second)
+	if !svc.InMaintenance() {
+		t.Fatal("expected maintenance mode on")
+	}
+	_, err := svc.CreateChatCompletion(context.Background(), chat)
+	var merr *llm.MaintenanceError
+	if !errors.As(err, &merr) || merr.RetryAfter != 30*time.Second {
+		t.Fatalf("expected MaintenanceError with retry hint, got %v", err)
+	}
+	if _, err := svc.CreateEmbeddings(context.Background(), emb); !errors.Is(err, llm.ErrMaintenance) {
+		t.Fatalf("expected embeddings to be rejected, got %v", err)
+	}
+	if _, _, err := svc.ListModels(context.Background(), 0, ""); err != nil {
+		t.Fatalf("non-model RPCs should keep working: %v", err)
+	}
+
+	svc.SetMaintenance(false, 0)
+	if _, err := svc.CreateChatCompletion(context.Background(), chat); err != nil {
+		t.Fatalf("expected service restored, got %v", err)
+	}
+	if _, err := svc.CreateEmbeddings(context.Background(), emb); err != nil {
+		t.Fatalf("expected embeddings restored, got %v", err)
+	}
+}
+
+// streamingProvider streams chunks, each reporting usage so far, stopping
+// early if emit fails.
+type streamingProvider struct {
+	fakeProvider
+	chunks []llm.ChatCompletionChunk
+}
+
+func (p *streamingProvider) CreateChatCompletionStream(_ context.Context, _ llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error {
+	for _, c := range p.chunks {
+		if err := emit(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestService_CreateChatCompletionStream_SavesPartialUsageOnClientCancel(t *testing.T) {
+	t.Parallel()
+
+	chunk := func(content string, completionTokens uint32) llm.ChatCompletionChunk {
+		return llm.ChatCompletionChunk{
+			ID:      "chat-1",
+			Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Content: content}}},
+			Usage:   &llm.TokenUsage{PromptTokens: 5, CompletionTokens: completionTokens, TotalTokens: 5 + completionTokens},
+		}
+	}
+	p := &streamingProvider{chunks: []llm.ChatCompletionChunk{chunk("a", 1), chunk("b", 2), chunk("c", 3)}}
+	gens := &fakeGenerations{}
+	svc := NewService(map[string]Provider{"fake": p}, nil, gens)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var received int
+	err := svc.CreateChatCompletionStream(ctx, llm.ChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+	}, func(llm.ChatCompletionChunk) error {
+		received++
+		if received == 2 {
+			cancel() // the client hangs up after the second chunk
+			return ctx.Err()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation to surface, got %v", err)
+	}
+
+	gen, err := gens.Get(context.Background(), "chat-1")
+	if err != nil {
+		t.Fatalf("expected a partial generation to be saved: %v", err)
+	}
+	if gen.Status != llm.GenerationStatusClientCancelled || gen.Usage.CompletionTokens != 2 {
+		t.Fatalf("unexpected partial generation: %+v", gen)
+	}
+}
+
+func TestService_CreateChatCompletionStream_NoPartialRecordWithoutUsage(t *testing.T) {
+	t.Parallel()
+
+	p := &streamingProvider{chunks: []llm.ChatCompletionChunk{
+		{ID: "chat-1", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Content: "a"}}}},
+	}}
+	gens := &fakeGenerations{}
+	svc := NewService(map[string]Provider{"fake": p}, nil, gens)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = svc.CreateChatCompletionStream(ctx, llm.ChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+	}, func(llm.ChatCompletionChunk) error {
+		cancel()
+		return ctx.Err()
+	})
+	if len(gens.saved) != 0 {
+		t.Fatalf("expected no record without reported usage, got %+v", gens.saved)
+	}
+}
+
+func TestService_CreateChatCompletion_ProviderPrefixIsCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	var upstream []string
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		upstream = append(upstream, req.Model)
+		return llm.ChatCompletionResponse{ID: "chat-1"}, nil
+	}}
+	svc := NewService(map[string]Provider{"dashscope": p},
+		[]ModelSpec{{ID: "dashscope/qwen-max", Provider: "dashscope", UpstreamModel: "qwen-max-2025"}}, nil,
+		WithProviderAliases(map[string]string{"Ali": "dashscope"}),
+		WithModelAccess(map[string]ModelAccess{"svc:a": {Denied: []string{"dashscope/secret-*"}}}),
+	)
+	chat := func(subject, model string) error {
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:    model,
+			Subject:  subject,
+			Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		})
+		return err
+	}
+
+	for _, model := range []string{"dashscope/Qwen-Turbo", "DashScope/Qwen-Turbo", "DASHSCOPE/Qwen-Turbo", "ali/Qwen-Turbo", "DashScope/qwen-max"} {
+		if err := chat("", model); err != nil {
+			t.Fatalf("%s: %v", model, err)
+		}
+	}
+	want := []string{"Qwen-Turbo", "Qwen-Turbo", "Qwen-Turbo", "Qwen-Turbo", "qwen-max-2025"}
+	if !slices.Equal(upstream, want) {
+		t.Fatalf("expected upstream models %v, got %v", want, upstream)
+	}
+
+	if err := chat("", "Unknown/Qwen-Turbo"); !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected unknown provider to be rejected, got %v", err)
+	}
+	if err := chat("svc:a", "DashScope/secret-model"); !errors.Is(err, llm.ErrPermissionDenied) {
+		t.Fatalf("expected a differently cased prefix to still be denied, got %v", err)
+	}
+}
+
+func TestService_OutputFilter(t *testing.T) {
+	t.Parallel()
+
+	hostname := regexp.MustCompile(`[a-z0-9-]+\.corp\.example\.com`)
+	p := &streamingProvider{fakeProvider: fakeProvider{
+		chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+			return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{{
+				Message:      llm.ChatMessage{Role: "assistant", Content: "connect to db-1.corp.example.com or db-2.corp.example.com"},
+				FinishReason: "stop",
+			}}}, nil
+		},
+	}}
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+
+	redacting := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}}))
+	res, err := redacting.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := res.Choices[0].Message.Content; got != "connect to [REDACTED] or [REDACTED]" || res.Choices[0].FinishReason != "stop" {
+		t.Fatalf("unexpected redacted response: %q (%s)", got, res.Choices[0].FinishReason)
+	}
+
+	blocking := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}, Action: OutputFilterBlock}))
+	res, err = blocking.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if res.Choices[0].Message.Content != "" || res.Choices[0].FinishReason != FinishReasonContentFilter {
+		t.Fatalf("expected a blocked choice, got %+v", res.Choices[0])
+	}
+}
+
+func TestService_OutputFilter_Stream(t *testing.T) {
+	t.Parallel()
+
+	hostname := regexp.MustCompile(`[a-z0-9-]+\.corp\.example\.com`)
+	delta := func(content, finish string) llm.ChatCompletionChunk {
+		return llm.ChatCompletionChunk{ID: "chat-1", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Content: content}, FinishReason: finish}}}
+	}
+	// The hostname is split across three chunks.
+	p := &streamingProvider{chunks: []llm.ChatCompletionChunk{
+		delta("connect to db-1.co", ""), delta("rp.exam", ""), delta("ple.com now, please", ""), delta(".", "stop"),
+	}}
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+	stream := func(svc *Service) (string, []string, error) {
+		var text strings.Builder
+		var finishes []string
+		err := svc.CreateChatCompletionStream(context.Background(), req, func(c llm.ChatCompletionChunk) error {
+			for _, ch := range c.Choices {
+				text.WriteString(ch.Delta.Content)
+				if ch.FinishReason != "" {
+					finishes = append(finishes, ch.FinishReason)
+				}
+			}
+			return nil
+		})
+		return text.String(), finishes, err
+	}
+
+	redacting := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}, StreamHoldback: 32}))
+	text, finishes, err := stream(redacting)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if text != "connect to [REDACTED] now, please." || !slices.Equal(finishes, []string{"stop"}) {
+		t.Fatalf("unexpected redacted stream: %q %v", text, finishes)
+	}
+
+	blocking := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}, Action: OutputFilterBlock, StreamHoldback: 32}))
+	text, finishes, err = stream(blocking)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if strings.Contains(text, "corp") || !slices.Equal(finishes, []string{FinishReasonContentFilter}) {
+		t.Fatalf("expected the stream to be cut before the hostname, got %q %v", text, finishes)
+	}
+}
+
+func TestService_OutputFilter_StreamReasoning(t *testing.T) {
+	t.Parallel()
+
+	hostname := regexp.MustCompile(`[a-z0-9-]+\.corp\.example\.com`)
+	reasoning := func(text, content, finish string) llm.ChatCompletionChunk {
+		return llm.ChatCompletionChunk{ID: "chat-1", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Reasoning: text, Content: content}, FinishReason: finish}}}
+	}
+	// The hostname is split across reasoning chunks; the answer is clean.
+	p := &streamingProvider{chunks: []llm.ChatCompletionChunk{
+		reasoning("I could ask db-1.co", "", ""), reasoning("rp.example.com, but", "", ""), reasoning(" no.", "", ""), reasoning("", "It is 4.", "stop"),
+	}}
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "2+2?"}}, IncludeReasoning: true}
+	stream := func(svc *Service) (string, string, []string, error) {
+		var thought, text strings.Builder
+		var finishes []string
+		err := svc.CreateChatCompletionStream(context.Background(), req, func(c llm.ChatCompletionChunk) error {
+			for _, ch := range c.Choices {
+				thought.WriteString(ch.Delta.Reasoning)
+				text.WriteString(ch.Delta.Content)
+				if ch.FinishReason != "" {
+					finishes = append(finishes, ch.FinishReason)
+				}
+			}
+			return nil
+		})
+		return thought.String(), text.String(), finishes, err
+	}
+
+	redacting := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}, StreamHoldback: 32}))
+	thought, text, finishes, err := stream(redacting)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if thought != "I could ask [REDACTED], but no." || text != "It is 4." || !slices.Equal(finishes, []string{"stop"}) {
+		t.Fatalf("unexpected redacted stream: reasoning %q, content %q %v", thought, text, finishes)
+	}
+
+	blocking := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}, Action: OutputFilterBlock, StreamHoldback: 32}))
+	thought, text, finishes, err = stream(blocking)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if strings.Contains(thought, "corp") || text != "" || !slices.Equal(finishes, []string{FinishReasonContentFilter}) {
+		t.Fatalf("expected the stream cut at the reasoning match, got reasoning %q, content %q %v", thought, text, finishes)
+	}
+}
+
+func TestService_StripTags(t *testing.T) {
+	t.Parallel()
+
+	p := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{
+			{Message: llm.ChatMessage{Role: "assistant", Content: "<think>2+2, carry nothing</think>\n\nIt is 4."}, FinishReason: "stop"},
+			{Index: 1, Message: llm.ChatMessage{Role: "assistant", Content: "No tags here."}, FinishReason: "stop"},
+		}}, nil
+	}}
+	models := []ModelSpec{{ID: "fake/m", Provider: "fake", StripTags: []string{"think"}, KeepRawContent: true}}
+	svc := NewService(map[string]Provider{"fake": p}, models, nil)
+
+	res, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "2+2?"}}})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := res.Choices[0].Message.Content; got != "It is 4." {
+		t.Fatalf("expected the think block stripped, got %q", got)
+	}
+	if got := res.Choices[0].RawContent; got != "<think>2+2, carry nothing</think>\n\nIt is 4." {
+		t.Fatalf("expected raw content kept, got %q", got)
+	}
+	if res.Choices[1].Message.Content != "No tags here." || res.Choices[1].RawContent != "" {
+		t.Fatalf("expected untagged choice untouched, got %+v", res.Choices[1])
+	}
+}
+
+func TestService_OutputFilter_CoversRawContent(t *testing.T) {
+	t.Parallel()
+
+	hostname := regexp.MustCompile(`[a-z0-9-]+\.corp\.example\.com`)
+	p := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{
+			{Message: llm.ChatMessage{Role: "assistant", Content: "<think>ask db-1.corp.example.com</think>It is 4."}, FinishReason: "stop"},
+		}}, nil
+	}}
+	models := []ModelSpec{{ID: "fake/m", Provider: "fake", StripTags: []string{"think"}, KeepRawContent: true}}
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "2+2?"}}}
+
+	blocking := NewService(map[string]Provider{"fake": p}, models, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}, Action: OutputFilterBlock}))
+	res, err := blocking.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if c := res.Choices[0]; c.Message.Content != "" || c.RawContent != "" || c.FinishReason != FinishReasonContentFilter {
+		t.Fatalf("expected a match in the raw content to block the choice, got %+v", c)
+	}
+
+	redacting := NewService(map[string]Provider{"fake": p}, models, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}}))
+	res, err = redacting.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if c := res.Choices[0]; c.Message.Content != "It is 4." || c.RawContent != "<think>ask [REDACTED]</think>It is 4." {
+		t.Fatalf("expected the raw content redacted, got %+v", c)
+	}
+}
+
+func TestService_OutputFilter_CoversAudioTranscript(t *testing.T) {
+	t.Parallel()
+
+	hostname := regexp.MustCompile(`[a-z0-9-]+\.corp\.example\.com`)
+	p := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		return llm.ChatCompletionResponse{ID: "chat-1", Choices: []llm.ChatCompletionChoice{
+			{Message: llm.ChatMessage{Role: "assistant", Audio: &llm.AudioContent{ID: "audio_1", Data: "UklGRg==", Transcript: "ask db-1.corp.example.com"}}, FinishReason: "stop"},
+		}}, nil
+	}}
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "who to ask?"}}}
+
+	blocking := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}, Action: OutputFilterBlock}))
+	res, err := blocking.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if c := res.Choices[0]; c.Message.Audio != nil || c.FinishReason != FinishReasonContentFilter {
+		t.Fatalf("expected a match in the transcript to block the choice and drop its audio, got %+v", c)
+	}
+
+	redacting := NewService(map[string]Provider{"fake": p}, nil, nil, WithOutputFilter(OutputFilter{Patterns: []*regexp.Regexp{hostname}}))
+	res, err = redacting.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if a := res.Choices[0].Message.Audio; a == nil || a.Transcript != "ask [REDACTED]" || a.Data != "UklGRg==" {
+		t.Fatalf("expected the transcript redacted, got %+v", a)
+	}
+}
+
+func TestService_StripTags_Stream(t *testing.T) {
+	t.Parallel()
+
+	delta := func(content, finish string) llm.ChatCompletionChunk {
+		return llm.ChatCompletionChunk{ID: "chat-1", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Content: content}, FinishReason: finish}}}
+	}
+	// Both tags are split across chunks, and a lone "<" must not be swallowed.
+	p := &streamingProvider{chunks: []llm.ChatCompletionChunk{
+		delta("<thi", ""), delta("nk>let me see", ""), delta(" carefully</th", ""), delta("ink>\n\nIt is", ""), delta(" 4 <", ""), delta(" 5.", "stop"),
+	}}
+	models := []ModelSpec{{ID: "fake/m", Provider: "fake", StripTags: []string{"think"}}}
+	svc := NewService(map[string]Provider{"fake": p}, models, nil)
+
+	var text strings.Builder
+	err := svc.CreateChatCompletionStream(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "2+2?"}}}, func(c llm.ChatCompletionChunk) error {
+		for _, ch := range c.Choices {
+			text.WriteString(ch.Delta.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if got := text.String(); got != "It is 4 < 5." {
+		t.Fatalf("unexpected stripped stream: %q", got)
+	}
+}
+
+func TestService_StreamReasoning(t *testing.T) {
+	t.Parallel()
+
+	delta := func(reasoning, content, finish string) llm.ChatCompletionChunk {
+		return llm.ChatCompletionChunk{ID: "chat-1", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Reasoning: reasoning, Content: content}, FinishReason: finish}}}
+	}
+	p := &streamingProvider{chunks: []llm.ChatCompletionChunk{
+		delta("Two plus", "", ""), delta(" two is four.", "", ""), delta("", "It is", ""), delta(" Double-check.", " 4", ""), delta("", ".", "stop"),
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil)
+
+	run := func(include bool) (chunks int, reasoning, content string) {
+		var r, c strings.Builder
+		err := svc.CreateChatCompletionStream(context.Background(), llm.ChatCompletionRequest{
+			Model:            "fake/m",
+			Messages:         []llm.ChatMessage{{Role: "user", Content: "2+2?"}},
+			IncludeReasoning: include,
+		}, func(chunk llm.ChatCompletionChunk) error {
+			chunks++
+			for _, ch := range chunk.Choices {
+				r.WriteString(ch.Delta.Reasoning)
+				c.WriteString(ch.Delta.Content)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("stream (include_reasoning=%v): %v", include, err)
+		}
+		return chunks, r.String(), c.String()
+	}
+
+	chunks, reasoning, content := run(true)
+	if chunks != 5 || reasoning != "Two plus two is four. Double-check." || content != "It is 4." {
+		t.Fatalf("with include_reasoning: %d chunks, reasoning %q, content %q", chunks, reasoning, content)
+	}
+	// Without opting in, reasoning is dropped and reasoning-only chunks are skipped.
+	chunks, reasoning, content = run(false)
+	if chunks != 3 || reasoning != "" || content != "It is 4." {
+		t.Fatalf("without include_reasoning: %d chunks, reasoning %q, content %q", chunks, reasoning, content)
+	}
+	if p.chunks[3].Choices[0].Delta.Reasoning == "" {
+		t.Fatal("dropping reasoning must not modify the provider's chunks")
+	}
+}
+
+func TestService_EmbeddingsGenerationStoresNoVectors(t *testing.T) {
+	t.Parallel()
+
+	// Generation records must stay metadata-only: no field may be able to
+	// hold an embedding vector.
+	var check func(path string, typ reflect.Type)
+	check = func(path string, typ reflect.Type) {
+		switch typ.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Pointer, reflect.Interface:
+			t.Fatalf("llm.Generation%s is a %s; generation records must not carry vectors or other unbounded data", path, typ.Kind())
+		case reflect.Struct:
+			for i := 0; i < typ.NumField(); i++ {
+				f := typ.Field(i)
+				check(path+"."+f.Name, f.Type)
+			}
+		}
+	}
+	check("", reflect.TypeOf(llm.Generation{}))
+
+	gens := &fakeGenerations{}
+	p := &fakeProvider{embeddings: func(context.Context, llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		return llm.EmbeddingsResponse{
+			ID:    "emb-1",
+			Data:  []llm.Embedding{{Index: 0, Vector: make([]float32, 3072)}},
+			Usage: llm.EmbeddingsUsage{PromptTokens: 5, TotalTokens: 5},
+		}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, gens)
+	if _, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"hello"}}); err != nil {
+		t.Fatalf("CreateEmbeddings: %v", err)
+	}
+	if len(gens.saved) != 1 {
+		t.Fatalf("expected one saved generation, got %d", len(gens.saved))
+	}
+	got := gens.saved[0]
+	if got.ID != "emb-1" || got.Usage != (llm.TokenUsage{PromptTokens: 5, TotalTokens: 5}) || got.Provider != "fake" || got.UpstreamModel != "e" {
+		t.Fatalf("unexpected embeddings generation: %+v", got)
+	}
+}
+
+func TestService_DefaultUserFromSubject(t *testing.T) {
+	t.Parallel()
+
+	var users []string
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		users = append(users, req.User)
+		return llm.ChatCompletionResponse{ID: "chat-1"}, nil
+	}}
+	chat := func(svc *Service, subject, user string) {
+		t.Helper()
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:    "fake/m",
+			Subject:  subject,
+			User:     user,
+			Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+	}
+
+	off := newTestService(p)
+	chat(off, "svc:a", "")
+	on := NewService(map[string]Provider{"fake": p}, nil, nil, WithDefaultUserFromSubject("salt"))
+	chat(on, "svc:a", "")
+	chat(on, "svc:a", "")
+	chat(on, "svc:b", "")
+	chat(on, "svc:a", "end-user-7")
+	chat(on, "", "")
+
+	if users[0] != "" {
+		t.Fatalf("expected user to stay empty when disabled, got %q", users[0])
+	}
+	if users[1] == "" || users[1] != users[2] || strings.Contains(users[1], "svc:a") {
+		t.Fatalf("expected a stable hashed user for svc:a, got %q and %q", users[1], users[2])
+	}
+	if users[3] == users[1] {
+		t.Fatalf("expected different subjects to get different users, got %q", users[3])
+	}
+	if users[4] != "end-user-7" || users[5] != "" {
+		t.Fatalf("expected an explicit user and anonymous requests to be left alone, got %q and %q", users[4], users[5])
+	}
+}
+
+type countingProvider struct {
+	fakeProvider
+	count func(ctx context.Context, req llm.ChatCompletionRequest) (uint32, error)
+}
+
+func (p *countingProvider) CountTokens(ctx context.Context, req llm.ChatCompletionRequest) (uint32, error) {
+	return p.count(ctx, req)
+}
+
+func TestService_CountTokens_PrefersUpstreamCount(t *testing.T) {
+	t.Parallel()
+
+	req := llm.ChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "how many tokens is this?"}},
+	}
+	var upstreamModel string
+	var countErr error
+	p := &countingProvider{count: func(_ context.Context, req llm.ChatCompletionRequest) (uint32, error) {
+		upstreamModel = req.Model
+		return 42, countErr
+	}}
+	svc := newTestService(p)
+
+	got, err := svc.CountTokens(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CountTokens: %v", err)
+	}
+	if got != (llm.TokenCount{PromptTokens: 42}) || upstreamModel != "m" {
+		t.Fatalf("expected the upstream count for model m, got %+v (model %q)", got, upstreamModel)
+	}
+
+	// A failed upstream count and a provider without the capability both fall
+	// back to the local estimate.
+	countErr = errors.New("count endpoint unavailable")
+	fallback, err := svc.CountTokens(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CountTokens with failing upstream: %v", err)
+	}
+	local, err := newTestService(&fakeProvider{}).CountTokens(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CountTokens without counter: %v", err)
+	}
+	if !fallback.Estimated || fallback != local || local.PromptTokens == 0 {
+		t.Fatalf("expected matching local estimates, got %+v and %+v", fallback, local)
+	}
+	for _, st := range svc.ProviderStatuses() {
+		if st.RecentRequests != 0 {
+			t.Fatalf("expected token counts to leave provider health alone, got %+v", st)
+		}
+	}
+}
+
+func TestService_ContextWindowCheck(t *testing.T) {
+	t.Parallel()
+
+	var chatCalls int
+	p := &countingProvider{count: func(context.Context, llm.ChatCompletionRequest) (uint32, error) {
+		return 900, nil
+	}}
+	p.chat = func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		chatCalls++
+		return llm.ChatCompletionResponse{Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Content: "ok"}}}}, nil
+	}
+	models := []ModelSpec{{ID: "fake/m", Provider: "fake", ContextWindow: 1000}, {ID: "fake/open", Provider: "fake"}}
+	svc := NewService(map[string]Provider{"fake": p}, models, nil, WithContextWindowCheck(true))
+
+	chat := func(model string, maxTokens uint32) error {
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:     model,
+			Messages:  []llm.ChatMessage{{Role: "user", Content: "hi"}},
+			MaxTokens: &maxTokens,
+		})
+		return err
+	}
+
+	if err := chat("fake/m", 100); err != nil {
+		t.Fatalf("prompt that fits the window: %v", err)
+	}
+	if err := chat("fake/m", 101); !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument past the context window, got %v", err)
+	}
+	if err := chat("fake/open", 5000); err != nil {
+		t.Fatalf("models without a context window should not be checked: %v", err)
+	}
+	if chatCalls != 2 {
+		t.Fatalf("expected the rejected request to skip the upstream call, got %d calls", chatCalls)
+	}
+}
+
+func TestService_GatewayGenerationIDs(t *testing.T) {
+	t.Parallel()
+
+	gens := &fakeGenerations{}
+	p := &streamingProvider{chunks: []llm.ChatCompletionChunk{
+		{ID: "upstream-stream", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Content: "hi"}}}},
+		{ID: "upstream-stream", Choices: []llm.ChatCompletionChunkChoice{{FinishReason: "stop"}}},
+	}}
+	p.chat = func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		return llm.ChatCompletionResponse{ID: "upstream-1", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Content: "hi"}}}}, nil
+	}
+	svc := NewService(map[string]Provider{"fake": p}, nil, gens, WithGatewayGenerationIDs(true))
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+
+	resp, err := svc.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	var streamIDs []string
+	err = svc.CreateChatCompletionStream(context.Background(), req, func(c llm.ChatCompletionChunk) error {
+		streamIDs = append(streamIDs, c.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream: %v", err)
+	}
+
+	if !strings.HasPrefix(resp.ID, "gen-") || resp.ID == "upstream-1" {
+		t.Fatalf("expected a gateway-issued id, got %q", resp.ID)
+	}
+	if len(streamIDs) != 2 || streamIDs[0] != streamIDs[1] || !strings.HasPrefix(streamIDs[0], "gen-") || streamIDs[0] == resp.ID {
+		t.Fatalf("expected every chunk to carry one new gateway id, got %v (unary %q)", streamIDs, resp.ID)
+	}
+	for id, upstream := range map[string]string{resp.ID: "upstream-1", streamIDs[0]: "upstream-stream"} {
+		gen, err := svc.GetGeneration(context.Background(), "", id)
+		if err != nil {
+			t.Fatalf("GetGeneration(%q): %v", id, err)
+		}
+		if gen.UpstreamID != upstream {
+			t.Fatalf("GetGeneration(%q).UpstreamID = %q, want %q", id, gen.UpstreamID, upstream)
+		}
+	}
+	if _, err := svc.GetGeneration(context.Background(), "", "upstream-1"); err == nil {
+		t.Fatal("records must not be keyed by the upstream id")
+	}
+}
+
+func TestService_ChoiceDedup(t *testing.T) {
+	t.Parallel()
+
+	usage := llm.TokenUsage{PromptTokens: 5, CompletionTokens: 9, TotalTokens: 14}
+	choice := func(i uint32, content string) llm.ChatCompletionChoice {
+		return llm.ChatCompletionChoice{Index: i, Message: llm.ChatMessage{Role: "assistant", Content: content}, FinishReason: "stop"}
+	}
+	p := &fakeProvider{chat: func(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		return llm.ChatCompletionResponse{
+			ID:      "chat-1",
+			Choices: []llm.ChatCompletionChoice{choice(0, "Paris"), choice(1, "Paris"), choice(2, "Paris, France"), choice(3, "Paris")},
+			Usage:   usage,
+		}, nil
+	}}
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "capital of France?"}}}
+
+	off, err := newTestService(p).CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if len(off.Choices) != 4 {
+		t.Fatalf("dedup is opt-in, got %d choices", len(off.Choices))
+	}
+
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithChoiceDedup(true))
+	resp, err := svc.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	want := []llm.ChatCompletionChoice{choice(0, "Paris"), choice(1, "Paris, France")}
+	if !reflect.DeepEqual(resp.Choices, want) {
+		t.Fatalf("choices = %+v, want %+v", resp.Choices, want)
+	}
+	if resp.Usage != usage {
+		t.Fatalf("usage must be kept as reported, got %+v", resp.Usage)
+	}
+}
+
+func TestService_ChoiceCountValidation(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(&completingProvider{})
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hi"}}
+	for _, tt := range []struct {
+		n       uint32
+		wantErr bool
+	}{{n: 1}, {n: 128}, {n: 0, wantErr: true}, {n: 129, wantErr: true}} {
+		n := tt.n
+		_, chatErr := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "fake/m", Messages: msgs, N: &n})
+		_, complErr := svc.CreateCompletion(context.Background(), llm.CompletionRequest{Model: "fake/m", Prompt: "hi", N: &n})
+		for op, err := range map[string]error{"chat": chatErr, "completions": complErr} {
+			var verr *llm.ValidationError
+			if got := errors.As(err, &verr) && verr.Violations[0].Field == "n"; got != tt.wantErr {
+				t.Fatalf("%s with n=%d: error = %v, wantErr %v", op, n, err, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestService_Embeddings_UpstreamFinishesAfterClientCancel(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	entered, release := make(chan struct{}), make(chan struct{})
+	p := &fakeProvider{embeddings: func(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		if calls.Add(1) == 1 {
+			close(entered)
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return llm.EmbeddingsResponse{}, ctx.Err()
+			}
+		}
+		return llm.EmbeddingsResponse{ID: "emb-1", Data: []llm.Embedding{{Vector: []float32{1, 2}}}, Usage: llm.EmbeddingsUsage{PromptTokens: 1, TotalTokens: 1}}, nil
+	}}
+	gens := &fakeGenerations{}
+	svc := NewService(map[string]Provider{"fake": p}, nil, gens,
+		WithUpstreamTimeout(time.Minute),
+		WithEmbeddingsCache(16, time.Minute),
+	)
+	req := llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"hello"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateEmbeddings(ctx, req)
+		errc <- err
+	}()
+	<-entered
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled client to get context.Canceled, got %v", err)
+	}
+	close(release)
+
+	// The upstream call outlives the client and fills the cache.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		svc.embeddingsCache.mu.Lock()
+		cached := len(svc.embeddingsCache.entries)
+		svc.embeddingsCache.mu.Unlock()
+		if cached == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("response never cached after the client cancelled")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	res, err := svc.CreateEmbeddings(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateEmbeddings: %v", err)
+	}
+	if res.ID != "emb-1" || calls.Load() != 1 {
+		t.Fatalf("expected a cached response without another upstream call, got id %q after %d calls", res.ID, calls.Load())
+	}
+	gens.mu.Lock()
+	defer gens.mu.Unlock()
+	if len(gens.saved) != 1 {
+		t.Fatalf("expected a generation only for the caller that got the response, got %d", len(gens.saved))
+	}
+}
+
+func TestService_EmbeddingsCache_PerSubjectWithOwnGenerations(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	p := &fakeProvider{embeddings: func(_ context.Context, _ llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		calls.Add(1)
+		return llm.EmbeddingsResponse{ID: "emb-1", Data: []llm.Embedding{{Vector: []float32{1}}}}, nil
+	}}
+	gens := &fakeGenerations{}
+	svc := NewService(map[string]Provider{"fake": p}, nil, gens,
+		WithEmbeddingsCache(16, time.Minute),
+		WithGatewayGenerationIDs(true),
+	)
+	embed := func(subject string) llm.EmbeddingsResponse {
+		t.Helper()
+		res, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"hello"}, Subject: subject})
+		if err != nil {
+			t.Fatalf("CreateEmbeddings(%s): %v", subject, err)
+		}
+		return res
+	}
+
+	a1, a2 := embed("svc:a"), embed("svc:a")
+	if calls.Load() != 1 {
+		t.Fatalf("expected the second request of a subject served from the cache, got %d upstream calls", calls.Load())
+	}
+	if a1.ID == a2.ID {
+		t.Fatalf("expected a cache hit to get its own generation id, both got %q", a1.ID)
+	}
+	b := embed("svc:b")
+	if calls.Load() != 2 {
+		t.Fatalf("expected another subject not to share the cache, got %d upstream calls", calls.Load())
+	}
+
+	for _, c := range []struct{ subject, id string }{{"svc:a", a1.ID}, {"svc:a", a2.ID}, {"svc:b", b.ID}} {
+		gen, err := svc.GetGeneration(context.Background(), c.subject, c.id)
+		if err != nil || gen.UpstreamID != "emb-1" {
+			t.Fatalf("GetGeneration(%s, %s) = %+v, %v", c.subject, c.id, gen, err)
+		}
+	}
+	if _, err := svc.GetGeneration(context.Background(), "svc:b", a1.ID); !errors.Is(err, llm.ErrNotFound) {
+		t.Fatalf("expected another subject's generation to be NotFound, got %v", err)
+	}
+}
+
+func TestService_Embeddings_SharedCallTracesEachWaiter(t *testing.T) {
+	t.Parallel()
+
+	var enter sync.Once
+	entered, release := make(chan struct{}), make(chan struct{})
+	p := &fakeProvider{embeddings: func(_ context.Context, _ llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		enter.Do(func() { close(entered) })
+		<-release
+		return llm.EmbeddingsResponse{ID: "emb-1", Data: []llm.Embedding{{Vector: []float32{1}}}}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithUpstreamTimeout(time.Minute))
+	req := llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"hello"}}
+
+	// The leader gives up while the shared call is still running; the call
+	// must not write to the leader's trace after it returned.
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderCtx, leaderTrace := WithDebugTrace(leaderCtx)
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateEmbeddings(leaderCtx, req)
+		leaderErr <- err
+	}()
+	<-entered
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the leader to get context.Canceled, got %v", err)
+	}
+	attempts := leaderTrace.Attempts
+
+	followerCtx, followerTrace := WithDebugTrace(context.Background())
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateEmbeddings(followerCtx, req)
+		followerErr <- err
+	}()
+	close(release)
+	if err := <-followerErr; err != nil {
+		t.Fatalf("follower CreateEmbeddings: %v", err)
+	}
+	// Whether the follower joined the shared call or made its own, its trace
+	// counts the one attempt that served it.
+	if followerTrace.Attempts != 1 {
+		t.Fatalf("expected the follower's trace to count one attempt, got %+v", followerTrace)
+	}
+	if attempts != 0 || leaderTrace.Attempts != 0 {
+		t.Fatalf("expected the cancelled leader's trace left alone, got %+v", leaderTrace)
+	}
+}
+
+func TestService_Embeddings_SharedCallOutlivesLeaderWithoutUpstreamTimeout(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	upstreamCtx := make(chan context.Context, 1)
+	release := make(chan struct{})
+	p := &fakeProvider{embeddings: func(ctx context.Context, _ llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		if calls.Add(1) == 1 {
+			upstreamCtx <- ctx
+			<-release
+		}
+		return llm.EmbeddingsResponse{ID: "emb-1", Data: []llm.Embedding{{Vector: []float32{1}}}}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithEmbeddingsCache(16, time.Minute))
+	req := llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"hello"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateEmbeddings(ctx, req)
+		errc <- err
+	}()
+	shared := <-upstreamCtx
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the leader to get context.Canceled, got %v", err)
+	}
+	if err := shared.Err(); err != nil {
+		t.Fatalf("expected the shared call to survive the leader's cancel, got %v", err)
+	}
+	if _, ok := shared.Deadline(); !ok {
+		t.Fatal("expected the shared call bounded by the leader's deadline")
+	}
+	close(release)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, hit := svc.embeddingsCache.get(embeddingsCacheKey(req.Model, embeddingsFlightKey(context.Background(), req)), time.Now()); hit {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("shared call never completed after the leader cancelled")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestService_Embeddings_ExtendedTimeoutDoesNotJoinShorterCall(t *testing.T) {
+	t.Parallel()
+
+	entered, release := make(chan struct{}, 2), make(chan struct{})
+	p := &fakeProvider{embeddings: func(ctx context.Context, _ llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		entered <- struct{}{}
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return llm.EmbeddingsResponse{}, ctx.Err()
+		}
+		return llm.EmbeddingsResponse{ID: "emb-1", Data: []llm.Embedding{{Vector: []float32{1}}}}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil, WithUpstreamTimeout(50*time.Millisecond))
+	req := llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"hello"}}
+
+	shortErr := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateEmbeddings(context.Background(), req)
+		shortErr <- err
+	}()
+	<-entered
+
+	longErr := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateEmbeddings(llm.WithUpstreamTimeout(context.Background(), time.Minute), req)
+		longErr <- err
+	}()
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the caller with an extended timeout to make its own upstream call")
+	}
+
+	if err := <-shortErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the short call to time out, got %v", err)
+	}
+	close(release)
+	if err := <-longErr; err != nil {
+		t.Fatalf("expected the extended call to outlive the short one, got %v", err)
+	}
+}
+
+func TestService_EmbeddingsCache_HitGetsItsOwnCreatedTime(t *testing.T) {
+	t.Parallel()
+
+	gens := &fakeGenerations{}
+	p := &fakeProvider{embeddings: func(context.Context, llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		return llm.EmbeddingsResponse{}, errors.New("expected a cache hit")
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, gens,
+		WithEmbeddingsCache(16, 24*time.Hour),
+		WithGatewayGenerationIDs(true),
+	)
+	req := llm.EmbeddingsRequest{Model: "fake/e", Input: []string{"hello"}}
+	fetched := time.Now().Add(-time.Hour)
+	svc.embeddingsCache.put(embeddingsCacheKey(req.Model, embeddingsFlightKey(context.Background(), req)),
+		llm.EmbeddingsResponse{ID: "emb-1", Created: fetched.Unix(), Data: []llm.Embedding{{Vector: []float32{1}}}}, fetched)
+
+	before := time.Now().Unix()
+	res, err := svc.CreateEmbeddings(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateEmbeddings: %v", err)
+	}
+	if res.Created < before {
+		t.Fatalf("expected a cache hit created at receive time (>= %d), got %d", before, res.Created)
+	}
+	gen, err := svc.GetGeneration(context.Background(), "", res.ID)
+	if err != nil || gen.Created != res.Created {
+		t.Fatalf("expected the generation created at %d, got %+v, %v", res.Created, gen, err)
+	}
+}
+
+func TestService_PurgeCache(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	p := &fakeProvider{embeddings: func(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+		calls.Add(1)
+		return llm.EmbeddingsResponse{ID: "emb-" + req.Model, Data: []llm.Embedding{{Vector: []float32{1}}}}, nil
+	}}
+	svc := NewService(map[string]Provider{"a": p, "b": p}, nil, nil, WithEmbeddingsCache(16, time.Minute))
+	fill := func(models ...string) {
+		t.Helper()
+		for _, m := range models {
+			if _, err := svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: m, Input: []string{"hello"}}); err != nil {
+				t.Fatalf("CreateEmbeddings(%s): %v", m, err)
+			}
+		}
+	}
+	fill("a/e1", "a/e2", "b/e1")
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 upstream calls, got %d", calls.Load())
+	}
+
+	if _, err := svc.PurgeCache(CachePurge{}); !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected an empty selection rejected, got %v", err)
+	}
+
+	// Targeted purges leave other entries cached.
+	if n, err := svc.PurgeCache(CachePurge{Model: "a/e1"}); err != nil || n != 1 {
+		t.Fatalf("purge by model: n=%d err=%v", n, err)
+	}
+	fill("a/e2", "b/e1")
+	if calls.Load() != 3 {
+		t.Fatalf("expected untouched entries to stay cached, got %d upstream calls", calls.Load())
+	}
+	fill("a/e1")
+	if calls.Load() != 4 {
+		t.Fatalf("expected the purged model to reach upstream again, got %d calls", calls.Load())
+	}
+	if n, err := svc.PurgeCache(CachePurge{KeyPrefix: "a/"}); err != nil || n != 2 {
+		t.Fatalf("purge by key prefix: n=%d err=%v", n, err)
+	}
+	fill("b/e1")
+	if calls.Load() != 4 {
+		t.Fatalf("expected other providers' entries to stay cached, got %d calls", calls.Load())
+	}
+
+	if n, err := svc.PurgeCache(CachePurge{All: true}); err != nil || n != 1 {
+		t.Fatalf("purge all: n=%d err=%v", n, err)
+	}
+	fill("b/e1")
+	if calls.Load() != 5 {
+		t.Fatalf("expected an empty cache after purging all, got %d calls", calls.Load())
+	}
+}
+
+type warmingProvider struct {
+	fakeProvider
+	calls atomic.Int32
+	err   error
+}
+
+func (p *warmingProvider) Warmup(context.Context) error {
+	p.calls.Add(1)
+	return p.err
+}
+
+func TestService_WarmupAttemptsEachProvider(t *testing.T) {
+	t.Parallel()
+
+	a, b, tenant := &warmingProvider{}, &warmingProvider{err: errors.New("dial tcp: no such host")}, &warmingProvider{}
+	svc := NewService(map[string]Provider{"a": a, "b": b, "cold": &fakeProvider{}}, nil, nil,
+		WithProviderPools(map[string]map[string]Provider{"acme": {"a": tenant}}))
+
+	got := svc.Warmup(context.Background())
+	names := make([]string, 0, len(got))
+	for _, r := range got {
+		names = append(names, r.Provider)
+	}
+	if want := []string{"a", "acme/a", "b"}; !slices.Equal(names, want) {
+		t.Fatalf("warmed %v, want %v", names, want)
+	}
+	if got[0].Err != nil || got[2].Err == nil {
+		t.Fatalf("expected only b's failure to be reported, got %+v", got)
+	}
+	for _, p := range []*warmingProvider{a, b, tenant} {
+		if n := p.calls.Load(); n != 1 {
+			t.Fatalf("expected one warmup per provider, got %d", n)
+		}
+	}
+}
+
+func TestService_ModelRateLimitIsSharedAcrossSubjects(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, nil, nil,
+		WithModelRateLimits(map[string]ModelRateLimit{"fake/capped": {RequestsPerMinute: 1, Burst: 2}}))
+	chat := func(subject, model string) error {
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:    model,
+			Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+			Subject:  subject,
+		})
+		return err
+	}
+
+	if err := chat("svc:a", "fake/capped"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := chat("svc:b", "fake/capped"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	err := chat("svc:c", "fake/capped")
+	var rerr *llm.RateLimitError
+	if !errors.As(err, &rerr) || rerr.Model != "fake/capped" || rerr.RetryAfter <= 0 {
+		t.Fatalf("expected a third subject to hit the shared limit, got %v", err)
+	}
+	if err := chat("svc:c", "fake/other"); err != nil {
+		t.Fatalf("expected other models to be unaffected: %v", err)
+	}
+}
+
+func TestService_ModelRateLimitCoversAliasesAndCaseVariants(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(map[string]Provider{"dashscope": &fakeProvider{}}, nil, nil,
+		WithProviderAliases(map[string]string{"ali": "dashscope"}),
+		WithModelRateLimits(map[string]ModelRateLimit{"DashScope/qwen": {RequestsPerMinute: 1, Burst: 2}}))
+	chat := func(model string) error {
+		_, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+			Model:    model,
+			Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+		})
+		return err
+	}
+
+	if err := chat("dashscope/qwen"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := chat("DASHSCOPE/qwen"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	var rerr *llm.RateLimitError
+	if err := chat("ali/qwen"); !errors.As(err, &rerr) {
+		t.Fatalf("expected an aliased model ID to share the limit, got %v", err)
+	}
+}
+
+func TestService_ModelRateLimitQueuesUpToMaxWait(t *testing.T) {
+	t.Parallel()
+
+	// 6000 RPM frees a token every 10ms.
+	svc := NewService(map[string]Provider{"fake": &fakeProvider{}}, nil, nil,
+		WithModelRateLimits(map[string]ModelRateLimit{"fake/m": {RequestsPerMinute: 6000, MaxWait: time.Second}}))
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := svc.CreateChatCompletion(context.Background(), req); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Fatalf("expected queued requests to wait for tokens, took %v", elapsed)
+	}
+}
+
+func TestService_SortsChoicesByIndex(t *testing.T) {
+	t.Parallel()
+
+	p := &fakeProvider{chat: func(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		return llm.ChatCompletionResponse{ID: "chat-1", Model: req.Model, Choices: []llm.ChatCompletionChoice{
+			{Index: 1, Message: llm.ChatMessage{Role: "assistant", Content: "second"}, FinishReason: "stop"},
+			{Index: 0, Message: llm.ChatMessage{Role: "assistant", Content: "first"}, FinishReason: "stop"},
+		}}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil)
+	resp, err := svc.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if len(resp.Choices) != 2 {
+		t.Fatalf("expected 2 choices, got %+v", resp.Choices)
+	}
+	for i, c := range resp.Choices {
+		if c.Index != uint32(i) {
+			t.Fatalf("choice %d has index %d: %+v", i, c.Index, resp.Choices)
+		}
+	}
+	if resp.Choices[0].Message.Content != "first" {
+		t.Fatalf("expected choices reordered by index, got %+v", resp.Choices)
+	}
+}
+
+func TestService_ExtendedUpstreamTimeout(t *testing.T) {
+	t.Parallel()
+
+	var remaining time.Duration
+	p := &fakeProvider{chat: func(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return llm.ChatCompletionResponse{ID: "chat-1", Model: req.Model}, nil
+	}}
+	svc := NewService(map[string]Provider{"fake": p}, nil, nil,
+		WithRequestTimeout(time.Second), WithUpstreamTimeout(time.Second))
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+
+	if _, err := svc.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if remaining > time.Second {
+		t.Fatalf("expected the configured timeouts by default, got %v left", remaining)
+	}
+
+	ctx := llm.WithUpstreamTimeout(context.Background(), 10*time.Minute)
+	if _, err := svc.CreateChatCompletion(ctx, req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if remaining < 9*time.Minute {
+		t.Fatalf("expected the extended timeout to override request and attempt timeouts, got %v left", remaining)
+	}
+}
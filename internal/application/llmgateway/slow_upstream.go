@@ -0,0 +1,36 @@
+package llmgateway
+
+import "time"
+
+// WithSlowUpstreamThreshold reports upstream calls that complete slower than a
+// threshold through Metrics.SlowUpstreamCall. perProvider overrides global for
+// the named providers; a zero threshold disables reporting for its scope.
+func WithSlowUpstreamThreshold(global time.Duration, perProvider map[string]time.Duration) Option {
+	return func(s *Service) {
+		s.slowUpstream = global
+		s.slowUpstreamByProvider = perProvider
+	}
+}
+
+// slowUpstreamThreshold returns the threshold for providerName, or 0 if off.
+func (s *Service) slowUpstreamThreshold(providerName string) time.Duration {
+	if d, ok := s.slowUpstreamByProvider[providerName]; ok {
+		return d
+	}
+	return s.slowUpstream
+}
+
+// observeUpstreamCall reports a successful provider call that started at start
+// if it took longer than the provider's slow threshold. model is the routed model.
+func (s *Service) observeUpstreamCall(providerName, model string, start time.Time, err error) {
+	if err != nil {
+		return
+	}
+	threshold := s.slowUpstreamThreshold(providerName)
+	if threshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(start); elapsed > threshold {
+		s.metrics.SlowUpstreamCall(providerName, s.metricsModel(model), elapsed)
+	}
+}
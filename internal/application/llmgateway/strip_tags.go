@@ -0,0 +1,167 @@
+package llmgateway
+
+import (
+	"strings"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// tagStripper removes <tag>...</tag> blocks (e.g. "think") from model output.
+// An unclosed block runs to the end of the text, as a truncated reasoning
+// block has no answer after it.
+type tagStripper struct {
+	tags []string
+}
+
+func (s *Service) tagStripperFor(model string) *tagStripper {
+	spec, ok := s.lookupModel(model)
+	if !ok || len(spec.StripTags) == 0 {
+		return nil
+	}
+	return &tagStripper{tags: spec.StripTags}
+}
+
+// openTag returns the earliest opening tag in text, its position and the
+// matching closing tag; i is -1 if there is none.
+func (t *tagStripper) openTag(text string) (i int, open, close string) {
+	i = -1
+	for _, tag := range t.tags {
+		o := "<" + tag + ">"
+		if j := strings.Index(text, o); j >= 0 && (i < 0 || j < i) {
+			i, open, close = j, o, "</"+tag+">"
+		}
+	}
+	return i, open, close
+}
+
+// strip removes every tag block from a complete text.
+func (t *tagStripper) strip(text string) string {
+	var cs tagStreamChoice
+	return cs.feed(t, text, true)
+}
+
+// stripChatTags removes the model's configured tag blocks from unary chat
+// output, keeping the original in RawContent if the model asks for it.
+func (s *Service) stripChatTags(model string, resp *llm.ChatCompletionResponse) {
+	t := s.tagStripperFor(model)
+	if t == nil {
+		return
+	}
+	spec, _ := s.lookupModel(model)
+	for i := range resp.Choices {
+		c := &resp.Choices[i]
+		raw := c.Message.Content
+		c.Message.Content = t.strip(raw)
+		if spec.KeepRawContent && c.Message.Content != raw {
+			c.RawContent = raw
+		}
+	}
+}
+
+// tagStream suppresses tag blocks in streamed chat chunks. Text that could be
+// the start of an opening or closing tag is held until the next chunk decides
+// it, so tags split across chunks are still caught.
+type tagStream struct {
+	t       *tagStripper
+	next    func(llm.ChatCompletionChunk) error
+	choices map[uint32]*tagStreamChoice
+}
+
+type tagStreamChoice struct {
+	pending string // held raw text not yet decided
+	close   string // closing tag while inside a block
+	emitted bool   // whether any content was released
+	trim    bool   // trim leading whitespace left by a removed block
+}
+
+// stripChatStreamTags wraps emit so the model's configured tag blocks never
+// reach the client; RawContent is not kept for streams.
+func (s *Service) stripChatStreamTags(model string, emit func(llm.ChatCompletionChunk) error) func(llm.ChatCompletionChunk) error {
+	t := s.tagStripperFor(model)
+	if t == nil {
+		return emit
+	}
+	st := &tagStream{t: t, next: emit, choices: make(map[uint32]*tagStreamChoice)}
+	return st.emit
+}
+
+func (st *tagStream) emit(c llm.ChatCompletionChunk) error {
+	choices := make([]llm.ChatCompletionChunkChoice, len(c.Choices))
+	copy(choices, c.Choices)
+	c.Choices = choices
+	for i := range c.Choices {
+		ch := &c.Choices[i]
+		cs := st.choices[ch.Index]
+		if cs == nil {
+			cs = &tagStreamChoice{}
+			st.choices[ch.Index] = cs
+		}
+		ch.Delta.Content = cs.feed(st.t, ch.Delta.Content, ch.FinishReason != "")
+	}
+	return st.next(c)
+}
+
+// feed consumes the next delta and returns the text to release. At the end of
+// the choice, held text outside a block is released and an unclosed block dropped.
+func (cs *tagStreamChoice) feed(t *tagStripper, delta string, final bool) string {
+	text := cs.pending + delta
+	cs.pending = ""
+	var out strings.Builder
+	for text != "" {
+		if cs.close != "" {
+			j := strings.Index(text, cs.close)
+			if j < 0 {
+				cs.pending = text[len(text)-partialSuffix(text, cs.close):]
+				break
+			}
+			text = text[j+len(cs.close):]
+			cs.close = ""
+			cs.trim = !cs.emitted
+			continue
+		}
+		i, open, close := t.openTag(text)
+		if i < 0 {
+			keep := 0
+			if !final {
+				for _, tag := range t.tags {
+					keep = max(keep, partialSuffix(text, "<"+tag+">"))
+				}
+			}
+			cs.release(&out, text[:len(text)-keep])
+			cs.pending = text[len(text)-keep:]
+			break
+		}
+		cs.release(&out, text[:i])
+		text = text[i+len(open):]
+		cs.close = close
+	}
+	if final {
+		cs.pending, cs.close = "", ""
+	}
+	return out.String()
+}
+
+func (cs *tagStreamChoice) release(out *strings.Builder, text string) {
+	if cs.trim {
+		text = strings.TrimLeft(text, " \t\r\n")
+		if text == "" {
+			return
+		}
+		cs.trim = false
+	}
+	if text != "" {
+		cs.emitted = true
+		out.WriteString(text)
+	}
+}
+
+// partialSuffix returns the length of the longest suffix of text that is a
+// proper prefix of tag.
+func partialSuffix(text, tag string) int {
+	for n := min(len(text), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(text, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
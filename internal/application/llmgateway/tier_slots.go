@@ -0,0 +1,108 @@
+package llmgateway
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Service tiers accepted on chat requests (OpenAI's service_tier).
+const (
+	ServiceTierAuto    = "auto"
+	ServiceTierDefault = "default"
+	ServiceTierFlex    = "flex"
+)
+
+// Slot priorities: higher values are granted first under contention.
+const (
+	priorityLow = iota
+	priorityNormal
+	numPriorities
+)
+
+// tierPriority maps a service tier to its upstream slot priority. Flex traffic
+// is latency-tolerant, so it yields to everything else.
+func tierPriority(tier string) int {
+	if tier == ServiceTierFlex {
+		return priorityLow
+	}
+	return priorityNormal
+}
+
+// WithUpstreamConcurrency caps concurrent upstream chat calls at n. Under
+// contention, waiting requests are granted slots by service tier priority
+// (flex last), FIFO within a tier. n <= 0 leaves upstream calls unbounded.
+func WithUpstreamConcurrency(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.upstreamSlots = newPrioritySlots(n)
+		}
+	}
+}
+
+// prioritySlots is a counting semaphore whose waiters are served highest
+// priority first. A nil *prioritySlots grants everything immediately.
+type prioritySlots struct {
+	mu      sync.Mutex
+	free    int
+	waiters [numPriorities][]chan struct{}
+}
+
+func newPrioritySlots(n int) *prioritySlots {
+	return &prioritySlots{free: n}
+}
+
+// acquire blocks until a slot is granted or ctx is done. The returned func
+// releases the slot and must be called exactly once on success.
+func (p *prioritySlots) acquire(ctx context.Context, prio int) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+	p.mu.Lock()
+	if p.free > 0 && p.queued() == 0 {
+		p.free--
+		p.mu.Unlock()
+		return p.release, nil
+	}
+	ch := make(chan struct{})
+	p.waiters[prio] = append(p.waiters[prio], ch)
+	p.mu.Unlock()
+
+	select {
+	case <-ch:
+		return p.release, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		if i := slices.Index(p.waiters[prio], ch); i >= 0 {
+			p.waiters[prio] = slices.Delete(p.waiters[prio], i, i+1)
+			p.mu.Unlock()
+			return nil, ctx.Err()
+		}
+		p.mu.Unlock()
+		// Granted concurrently with cancellation: hand the slot on.
+		p.release()
+		return nil, ctx.Err()
+	}
+}
+
+func (p *prioritySlots) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for prio := numPriorities - 1; prio >= 0; prio-- {
+		if q := p.waiters[prio]; len(q) > 0 {
+			p.waiters[prio] = q[1:]
+			close(q[0])
+			return
+		}
+	}
+	p.free++
+}
+
+// queued returns the number of waiters; p.mu must be held.
+func (p *prioritySlots) queued() int {
+	n := 0
+	for _, q := range p.waiters {
+		n += len(q)
+	}
+	return n
+}
@@ -0,0 +1,91 @@
+package llmgateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// Local estimate parameters: roughly four bytes per token for English text,
+// plus a fixed overhead per message for role and framing tokens.
+const (
+	estimateBytesPerToken    = 4
+	estimateTokensPerMessage = 4
+)
+
+// WithContextWindowCheck makes chat requests count their prompt with
+// CountTokens first and rejects those that, with max_tokens, would not fit the
+// model's catalog context window, sparing the upstream call. Models without a
+// context window are not checked.
+func WithContextWindowCheck(enabled bool) Option {
+	return func(s *Service) {
+		s.contextWindowCheck = enabled
+	}
+}
+
+// checkContextWindow applies WithContextWindowCheck to req, which must still
+// carry the catalog model ID.
+func (s *Service) checkContextWindow(ctx context.Context, req llm.ChatCompletionRequest) error {
+	if !s.contextWindowCheck {
+		return nil
+	}
+	spec, ok := s.lookupModel(req.Model)
+	if !ok || spec.ContextWindow == 0 {
+		return nil
+	}
+	count, err := s.CountTokens(ctx, req)
+	if err != nil {
+		return err
+	}
+	need := uint64(count.PromptTokens)
+	if req.MaxTokens != nil {
+		need += uint64(*req.MaxTokens)
+	}
+	if need > uint64(spec.ContextWindow) {
+		return llm.InvalidArgument(fmt.Sprintf("prompt exceeds the model's context window of %d tokens; shorten the messages or lower max_tokens", spec.ContextWindow))
+	}
+	return nil
+}
+
+// CountTokens returns the prompt size of req for budget checks. It asks the
+// provider when it implements TokenCounter and falls back to a local estimate
+// otherwise or when the upstream count fails. Tokenizer calls are not
+// completions, so their outcome is left out of the provider's health.
+func (s *Service) CountTokens(ctx context.Context, req llm.ChatCompletionRequest) (llm.TokenCount, error) {
+	if err := s.checkModelAccess(req.Subject, req.Model); err != nil {
+		return llm.TokenCount{}, err
+	}
+	p, _, upstreamModel, err := s.resolveProviderAndUpstreamModel(ctx, req.Model)
+	if err != nil {
+		return llm.TokenCount{}, err
+	}
+	if tc, ok := p.(TokenCounter); ok {
+		req.Model = upstreamModel
+		n, err := tc.CountTokens(ctx, req)
+		if err == nil {
+			return llm.TokenCount{PromptTokens: n}, nil
+		}
+		if ctx.Err() != nil {
+			return llm.TokenCount{}, err
+		}
+	}
+	return llm.TokenCount{PromptTokens: estimatePromptTokens(req), Estimated: true}, nil
+}
+
+// estimatePromptTokens approximates the prompt tokens of req from its text.
+// Images and audio are not counted.
+func estimatePromptTokens(req llm.ChatCompletionRequest) uint32 {
+	var bytes, messages int
+	for _, m := range req.Messages {
+		messages++
+		bytes += len(m.Role) + len(m.Name) + len(m.Content)
+		for _, p := range m.ContentParts {
+			bytes += len(p.Text)
+		}
+		for _, tc := range m.ToolCalls {
+			bytes += len(tc.Function.Name) + len(tc.Function.Arguments)
+		}
+	}
+	return uint32((bytes+estimateBytesPerToken-1)/estimateBytesPerToken + messages*estimateTokensPerMessage)
+}
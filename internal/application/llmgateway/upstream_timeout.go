@@ -0,0 +1,51 @@
+package llmgateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// WithUpstreamTimeout bounds each upstream attempt separately from the
+// client-facing request timeout; an earlier request deadline still wins.
+// Shared requests (embeddings) run detached from the caller and are bounded
+// by d alone, so a result a client stopped waiting for still completes and
+// fills the embeddings cache. d <= 0 disables it.
+func WithUpstreamTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.upstreamTimeout = d
+	}
+}
+
+// extendedTimeout returns d, or the longer upstream timeout the caller was
+// granted (see llm.WithUpstreamTimeout). d <= 0 (no limit) is kept.
+func extendedTimeout(ctx context.Context, d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return max(d, llm.UpstreamTimeout(ctx))
+}
+
+// upstreamAttemptContext bounds one upstream attempt by the upstream timeout.
+func (s *Service) upstreamAttemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.upstreamTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, extendedTimeout(ctx, s.upstreamTimeout))
+}
+
+// detachedUpstreamContext returns a context for an upstream call shared by
+// several callers. It keeps ctx's values but not its cancellation, so one
+// caller giving up does not fail the others. It is bounded by the upstream
+// timeout or, without one, by ctx's deadline.
+func (s *Service) detachedUpstreamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if d := extendedTimeout(ctx, s.upstreamTimeout); d > 0 {
+		return context.WithTimeout(detached, d)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return detached, func() {}
+}
@@ -0,0 +1,12 @@
+package llmgateway
+
+import "github.com/poly-workshop/llm-gateway/internal/domain/llm"
+
+// checkUsageReported reports a successful response that has output but zero
+// total tokens. Providers decode leniently, so a renamed or dropped usage
+// field would otherwise silently zero billing.
+func (s *Service) checkUsageReported(providerName, model string, usage llm.TokenUsage, hasOutput bool) {
+	if hasOutput && usage.TotalTokens == 0 {
+		s.metrics.UsageMissing(providerName, s.metricsModel(model))
+	}
+}
@@ -0,0 +1,176 @@
+package llmgateway
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// validateChatCompletionRequest reports all invalid fields at once so clients
+// don't have to fix them one round-trip at a time.
+func (s *Service) validateChatCompletionRequest(req llm.ChatCompletionRequest) error {
+	var v llm.Violations
+	if req.Model == "" {
+		v.Add("model", "is required")
+	}
+	if len(req.Messages) == 0 {
+		v.Add("messages", "is required")
+	}
+	if t := req.Temperature; t != nil && (*t < 0 || *t > s.maxTemperature) {
+		v.Add("temperature", fmt.Sprintf("must be between 0 and %g", s.maxTemperature))
+	}
+	validateMaxTokens(req.MaxTokens, &v)
+	validateStop(req.Stop, &v)
+	validateN(req.N, &v)
+	s.validateModalities(req, &v)
+	s.validateContentParts(req.Messages, &v)
+	validateCacheControl(req.Messages, &v)
+	s.validateImageURLs(req.Messages, &v)
+	validateProviderPreferences(req.ProviderPreferences, &v)
+	validateConversationID(req.ConversationID, &v)
+	switch req.ServiceTier {
+	case "", ServiceTierAuto, ServiceTierDefault, ServiceTierFlex:
+	default:
+		v.Add("service_tier", `must be "auto", "default" or "flex"`)
+	}
+	return v.Err()
+}
+
+// validateContentParts bounds the content parts of each message and of the
+// whole request, so a request with hundreds of images fails before it costs
+// anything upstream.
+func (s *Service) validateContentParts(msgs []llm.ChatMessage, v *llm.Violations) {
+	total := 0
+	for i, m := range msgs {
+		n := len(m.ContentParts)
+		if n > s.maxContentPartsPerMessage {
+			v.Add(fmt.Sprintf("messages[%d].content", i), fmt.Sprintf("must have at most %d parts, got %d", s.maxContentPartsPerMessage, n))
+		}
+		total += n
+	}
+	if total > s.maxContentPartsPerRequest {
+		v.Add("messages", fmt.Sprintf("must have at most %d content parts in total, got %d", s.maxContentPartsPerRequest, total))
+	}
+}
+
+// validateEmbeddingsRequest also bounds input size so one request can't exhaust
+// gateway memory; it runs before any batching or upstream call.
+func (s *Service) validateEmbeddingsRequest(req llm.EmbeddingsRequest) error {
+	var v llm.Violations
+	if req.Model == "" {
+		v.Add("model", "is required")
+	}
+	if len(req.Input) == 0 {
+		v.Add("input", "is required")
+	}
+	if len(req.Input) > s.maxEmbeddingsInputs {
+		v.Add("input", fmt.Sprintf("must have at most %d items, got %d", s.maxEmbeddingsInputs, len(req.Input)))
+	}
+	size := 0
+	for _, in := range req.Input {
+		size += len(in)
+	}
+	if size > s.maxEmbeddingsInputBytes {
+		v.Add("input", fmt.Sprintf("must total at most %d bytes, got %d", s.maxEmbeddingsInputBytes, size))
+	}
+	if req.AutoChunk && s.embeddingsChunkChars == 0 {
+		v.Add("auto_chunk", "is not enabled on this gateway")
+	}
+	validateConversationID(req.ConversationID, &v)
+	return v.Err()
+}
+
+func (s *Service) validateModalities(req llm.ChatCompletionRequest, v *llm.Violations) {
+	wantsAudio := false
+	for i, m := range req.Modalities {
+		switch m {
+		case "text":
+		case "audio":
+			wantsAudio = true
+		default:
+			v.Add(fmt.Sprintf("modalities[%d]", i), `must be "text" or "audio"`)
+		}
+	}
+	if !wantsAudio {
+		return
+	}
+	if req.Audio == nil || req.Audio.Voice == "" || req.Audio.Format == "" {
+		v.Add("audio", `voice and format are required when modalities includes "audio"`)
+	}
+	// Only catalog models declare capabilities; ad-hoc provider/model IDs are passed through.
+	if spec, ok := s.lookupModel(req.Model); ok && !slices.Contains(spec.Capabilities, "audio") {
+		v.Add("modalities", "model "+req.Model+" does not support audio output")
+	}
+}
+
+// validateMaxTokens rejects an explicit 0, which providers disagree on
+// (no limit vs. error); callers wanting the provider default leave it unset.
+func validateMaxTokens(n *uint32, v *llm.Violations) {
+	if n != nil && *n == 0 {
+		v.Add("max_tokens", "must be positive when set")
+	}
+}
+
+// maxStopSequences is OpenAI's limit on stop sequences.
+const maxStopSequences = 4
+
+func validateStop(stop []string, v *llm.Violations) {
+	if len(stop) > maxStopSequences {
+		v.Add("stop", fmt.Sprintf("must have at most %d sequences, got %d", maxStopSequences, len(stop)))
+	}
+}
+
+// maxChoices is OpenAI's limit on n.
+const maxChoices = 128
+
+func validateN(n *uint32, v *llm.Violations) {
+	if n != nil && (*n == 0 || *n > maxChoices) {
+		v.Add("n", fmt.Sprintf("must be between 1 and %d when set", maxChoices))
+	}
+}
+
+func validateProviderPreferences(pp *llm.ProviderPreferences, v *llm.Violations) {
+	if pp == nil {
+		return
+	}
+	seen := make(map[string]bool, len(pp.Order))
+	for i, name := range pp.Order {
+		field := fmt.Sprintf("provider.order[%d]", i)
+		switch {
+		case name == "":
+			v.Add(field, "must not be empty")
+		case seen[name]:
+			v.Add(field, "duplicate provider "+name)
+		}
+		seen[name] = true
+	}
+	switch pp.DataCollection {
+	case "", "allow", "deny":
+	default:
+		v.Add("provider.data_collection", `must be "allow" or "deny"`)
+	}
+}
+
+func validateCacheControl(msgs []llm.ChatMessage, v *llm.Violations) {
+	check := func(field string, cc *llm.CacheControl) {
+		if cc != nil && cc.Type != "ephemeral" {
+			v.Add(field, `type must be "ephemeral"`)
+		}
+	}
+	for i, m := range msgs {
+		check(fmt.Sprintf("messages[%d].cache_control", i), m.CacheControl)
+		for j, p := range m.ContentParts {
+			check(fmt.Sprintf("messages[%d].content[%d].cache_control", i, j), p.CacheControl)
+		}
+	}
+}
+
+// maxConversationIDLen keeps conversation ids usable as index keys.
+const maxConversationIDLen = 128
+
+func validateConversationID(id string, v *llm.Violations) {
+	if len(id) > maxConversationIDLen {
+		v.Add("conversation_id", fmt.Sprintf("must be at most %d bytes", maxConversationIDLen))
+	}
+}
@@ -0,0 +1,57 @@
+package llmgateway
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WarmupResult is the outcome of warming up one provider instance.
+type WarmupResult struct {
+	// Provider is the provider name, prefixed with "<pool>/" for instances
+	// of a provider pool.
+	Provider string
+	Duration time.Duration
+	Err      error
+}
+
+// Warmup primes the connections of every provider that implements Warmer,
+// including those of provider pools, concurrently. Failures are reported in
+// the results, never returned: a cold upstream only makes its first request
+// slower. Results are sorted by provider name.
+func (s *Service) Warmup(ctx context.Context) []WarmupResult {
+	targets := make(map[string]Warmer)
+	for name, p := range s.providers {
+		if w, ok := p.(Warmer); ok {
+			targets[name] = w
+		}
+	}
+	for pool, providers := range s.providerPools {
+		for name, p := range providers {
+			if w, ok := p.(Warmer); ok {
+				targets[pool+"/"+name] = w
+			}
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		out = make([]WarmupResult, 0, len(targets))
+	)
+	for name, w := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := w.Warmup(ctx)
+			mu.Lock()
+			out = append(out, WarmupResult{Provider: name, Duration: time.Since(start), Err: err})
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
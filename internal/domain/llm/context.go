@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"time"
+)
+
+type upstreamBaseURLKey struct{}
+
+// WithUpstreamBaseURL sends provider calls made with the returned context to
+// base instead of the provider's configured base URL. Callers must have
+// authorized the override; providers apply it unconditionally.
+func WithUpstreamBaseURL(ctx context.Context, base string) context.Context {
+	return context.WithValue(ctx, upstreamBaseURLKey{}, base)
+}
+
+// UpstreamBaseURL returns the base URL override in ctx, or "" if there is none.
+func UpstreamBaseURL(ctx context.Context) string {
+	base, _ := ctx.Value(upstreamBaseURLKey{}).(string)
+	return base
+}
+
+type providerPoolKey struct{}
+
+// WithProviderPool routes provider calls made with the returned context to
+// the named provider pool (e.g. a tenant's own credentials). Callers must have
+// authorized the caller for the pool; "" is the default pool.
+func WithProviderPool(ctx context.Context, pool string) context.Context {
+	return context.WithValue(ctx, providerPoolKey{}, pool)
+}
+
+// ProviderPool returns the provider pool named in ctx, or "" for the default pool.
+func ProviderPool(ctx context.Context) string {
+	pool, _ := ctx.Value(providerPoolKey{}).(string)
+	return pool
+}
+
+type upstreamTimeoutKey struct{}
+
+// WithUpstreamTimeout lets provider calls made with the returned context run
+// for up to d, overriding shorter configured request, attempt and provider
+// timeouts (e.g. for batch jobs). Callers must have authorized and bounded d.
+func WithUpstreamTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, upstreamTimeoutKey{}, d)
+}
+
+// UpstreamTimeout returns the extended upstream timeout in ctx, or 0 if there is none.
+func UpstreamTimeout(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(upstreamTimeoutKey{}).(time.Duration)
+	return d
+}
@@ -3,6 +3,8 @@ package llm
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 var ErrInvalidArgument = errors.New("invalid argument")
@@ -14,3 +16,123 @@ func InvalidArgument(msg string) error {
 	return fmt.Errorf("%w: %s", ErrInvalidArgument, msg)
 }
 
+var ErrNotFound = errors.New("not found")
+
+func NotFound(msg string) error {
+	if msg == "" {
+		return ErrNotFound
+	}
+	return fmt.Errorf("%w: %s", ErrNotFound, msg)
+}
+
+// ErrEmptyResponse is returned when a provider succeeds without producing any output.
+var ErrEmptyResponse = errors.New("provider returned an empty response")
+
+// ErrStreamIdle is returned when a streamed upstream response sends nothing
+// for longer than the stream idle timeout.
+var ErrStreamIdle = errors.New("upstream stream idle timeout")
+
+// ErrResponseTooLarge is returned when an upstream response body exceeds the
+// provider's size limit.
+var ErrResponseTooLarge = errors.New("upstream response too large")
+
+// ErrMaintenance is returned for model calls while the gateway is drained for maintenance.
+var ErrMaintenance = errors.New("gateway is in maintenance mode")
+
+// MaintenanceError carries how long clients should wait before retrying.
+// It matches ErrMaintenance via errors.Is.
+type MaintenanceError struct {
+	RetryAfter time.Duration // 0 if unknown
+}
+
+func (e *MaintenanceError) Error() string {
+	if e.RetryAfter <= 0 {
+		return ErrMaintenance.Error()
+	}
+	return fmt.Sprintf("%s; retry after %s", ErrMaintenance, e.RetryAfter)
+}
+
+func (e *MaintenanceError) Unwrap() error { return ErrMaintenance }
+
+// ErrRateLimited is returned when a model's gateway-wide rate limit is hit.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitError names the limited model and when a request may succeed.
+// It matches ErrRateLimited via errors.Is.
+type RateLimitError struct {
+	Model      string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: model %s; retry after %s", ErrRateLimited, e.Model, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error { return ErrRateLimited }
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+func PermissionDenied(msg string) error {
+	if msg == "" {
+		return ErrPermissionDenied
+	}
+	return fmt.Errorf("%w: %s", ErrPermissionDenied, msg)
+}
+
+// FieldViolation describes a single invalid request field.
+type FieldViolation struct {
+	Field       string // e.g. "model", "messages"
+	Description string
+}
+
+// ValidationError reports every invalid field of a request at once.
+// It matches ErrInvalidArgument via errors.Is.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+	return ErrInvalidArgument.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error { return ErrInvalidArgument }
+
+// Violations accumulates field violations while validating a request.
+type Violations []FieldViolation
+
+func (v *Violations) Add(field, description string) {
+	*v = append(*v, FieldViolation{Field: field, Description: description})
+}
+
+// Err returns a *ValidationError if any violation was recorded, nil otherwise.
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+// ProviderHTTPError is returned by providers when the upstream responds with an
+// HTTP error status (other than 400, which maps to ErrInvalidArgument).
+type ProviderHTTPError struct {
+	Provider   string // e.g. "dashscope"
+	StatusCode int
+	Message    string
+
+	// RetryAfter is the upstream's Retry-After hint; 0 if absent.
+	RetryAfter time.Duration
+	// RequestID is the upstream's id for the failed request, for support
+	// tickets; empty if it sent none.
+	RequestID string
+}
+
+func (e *ProviderHTTPError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("%s http %d: %s (request %s)", e.Provider, e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("%s http %d: %s", e.Provider, e.StatusCode, e.Message)
+}
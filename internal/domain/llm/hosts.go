@@ -0,0 +1,20 @@
+package llm
+
+import "strings"
+
+// HostAllowed reports whether host matches one of allowed, ignoring case.
+// Entries are exact hostnames or "*.example.com" to match any subdomain.
+func HostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if suffix, ok := strings.CutPrefix(a, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == a {
+			return true
+		}
+	}
+	return false
+}
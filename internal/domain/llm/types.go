@@ -1,15 +1,32 @@
 package llm
 
+import "time"
+
 type Model struct {
 	ID           string
 	Name         string
 	Provider     string
 	Capabilities []string
+
+	// Limits and pricing from the model catalog; zero/nil when not configured.
+	ContextWindow   uint32 // max prompt + completion tokens
+	MaxOutputTokens uint32
+	Pricing         *ModelPricing
+}
+
+// ModelPricing is a model's list price in USD per million tokens.
+type ModelPricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
 }
 
 type Embedding struct {
 	Index  uint32
 	Vector []float32
+
+	// ChunkCount is the number of chunks mean-pooled into Vector when the
+	// request used AutoChunk; 0 otherwise.
+	ChunkCount uint32
 }
 
 // ImageURL represents an image URL with optional detail level for vision models.
@@ -18,11 +35,46 @@ type ImageURL struct {
 	Detail string // "auto", "low", or "high"
 }
 
+// AudioContent is audio produced by the model (see ChatMessage.Audio).
+type AudioContent struct {
+	ID         string
+	Data       string // base64-encoded audio bytes
+	Format     string // e.g. "wav", "mp3"
+	Transcript string
+	ExpiresAt  int64 // unix seconds; 0 if unknown
+}
+
+// CacheControl marks a prompt prefix as cacheable (Anthropic prompt caching).
+// It is forwarded by providers that support it and ignored by the rest.
+type CacheControl struct {
+	Type string // "ephemeral"
+}
+
 // ContentPart represents a part of a multimodal message content.
 type ContentPart struct {
-	Type     string // "text" or "image_url"
-	Text     string
-	ImageURL *ImageURL
+	Type         string // "text" or "image_url"
+	Text         string
+	ImageURL     *ImageURL
+	CacheControl *CacheControl
+}
+
+// AudioOutput configures audio output when a request asks for the "audio" modality.
+type AudioOutput struct {
+	Voice  string // e.g. "alloy"
+	Format string // e.g. "wav", "mp3"
+}
+
+// ProviderPreferences are OpenRouter's upstream routing preferences (the "provider" request object).
+// Only the OpenRouter provider sends them; other providers ignore them.
+type ProviderPreferences struct {
+	// Order lists upstream providers to try first, e.g. ["anthropic", "openai"].
+	Order []string
+	// AllowFallbacks permits providers outside Order; nil means OpenRouter's default (true).
+	AllowFallbacks *bool
+	// RequireParameters restricts routing to providers supporting every request parameter.
+	RequireParameters bool
+	// DataCollection is "allow" or "deny"; empty means OpenRouter's default.
+	DataCollection string
 }
 
 // OpenAI-style chat message.
@@ -35,18 +87,67 @@ type ChatMessage struct {
 	// If provided, this takes precedence over the Content field.
 	ContentParts []ContentPart
 	Name         string
+	// CacheControl marks the whole message as a cache breakpoint; for
+	// multimodal messages prefer annotating individual ContentParts.
+	CacheControl *CacheControl
+	// ToolCalls are function calls requested by the model.
+	ToolCalls []ToolCall
+	// Reasoning is the reasoning text a reasoning model streams separately
+	// from Content (streamed deltas only).
+	Reasoning string
+	// Audio is the audio output of an assistant response when the request
+	// asked for the "audio" modality; Content keeps the text, if any.
+	Audio *AudioContent
+}
+
+// ToolCall is a function call requested by the model. In streamed deltas the
+// fields arrive as fragments keyed by Index: ID, Type and Function.Name are
+// sent once, Function.Arguments is split across deltas and must be concatenated.
+type ToolCall struct {
+	Index    uint32
+	ID       string
+	Type     string // "function"
+	Function ToolCallFunction
+}
+
+type ToolCallFunction struct {
+	Name      string
+	Arguments string // JSON-encoded arguments
+}
+
+// TokenCount is the prompt size of a chat request before it is sent.
+type TokenCount struct {
+	PromptTokens uint32
+	// Estimated is true when the count is a local approximation rather than
+	// the provider's own tokenizer.
+	Estimated bool
 }
 
 type TokenUsage struct {
 	PromptTokens     uint32
 	CompletionTokens uint32
 	TotalTokens      uint32
+
+	// Optional breakdowns; each is 0 when the provider doesn't report it.
+	// CacheReadTokens and CacheWriteTokens are the parts of PromptTokens read
+	// from or written to the provider's prompt cache.
+	CacheReadTokens  uint32
+	CacheWriteTokens uint32
+	// ReasoningTokens is the part of CompletionTokens spent on hidden reasoning.
+	ReasoningTokens uint32
+	// PromptAudioTokens and CompletionAudioTokens are the audio parts of the
+	// prompt and completion.
+	PromptAudioTokens     uint32
+	CompletionAudioTokens uint32
 }
 
 type ChatCompletionChoice struct {
 	Index        uint32
 	Message      ChatMessage
 	FinishReason string
+	// RawContent is the message content before configured tag blocks were
+	// stripped; empty unless the model keeps it and something was stripped.
+	RawContent string
 }
 
 type ChatCompletionRequest struct {
@@ -55,9 +156,48 @@ type ChatCompletionRequest struct {
 
 	Messages []ChatMessage
 
-	Temperature float64
-	MaxTokens   uint32
-	User        string
+	// Temperature is nil when the caller didn't set it.
+	Temperature *float64
+	// MaxTokens is nil when the caller didn't set it; it is then omitted upstream.
+	MaxTokens *uint32
+	User      string
+	// Stop lists up to 4 sequences where the upstream stops generating.
+	Stop []string
+	// N is the number of choices to generate; nil leaves it to the provider (1).
+	N *uint32
+
+	// Modalities lists requested output types, e.g. ["text"] or ["text", "audio"].
+	// Empty means provider default (text).
+	Modalities []string
+	// Audio configures audio output; required when Modalities includes "audio".
+	Audio *AudioOutput
+
+	// ProviderPreferences tunes OpenRouter upstream routing; ignored by other providers.
+	ProviderPreferences *ProviderPreferences
+
+	// ConversationID optionally groups requests for audit and usage; never sent upstream.
+	ConversationID string
+
+	// Metadata is caller tracking metadata forwarded to providers that accept
+	// it (OpenRouter "metadata"). Only allowlisted keys reach the provider.
+	Metadata map[string]string
+
+	// ServiceTier is OpenAI's service_tier ("auto", "default" or "flex"); empty means provider default.
+	// It is forwarded to providers that support it and also sets the request's
+	// priority for gateway upstream slots.
+	ServiceTier string
+
+	// Subject is the authenticated caller (e.g. service token name), if any.
+	// Set by the transport layer; never sent upstream.
+	Subject string
+
+	// EchoPrompt returns the normalized prompt in the response's Prompt.
+	// The transport layer only sets it for authorized callers.
+	EchoPrompt bool
+
+	// IncludeReasoning forwards streamed reasoning deltas to the caller;
+	// otherwise the gateway drops them.
+	IncludeReasoning bool
 }
 
 type ChatCompletionResponse struct {
@@ -67,6 +207,36 @@ type ChatCompletionResponse struct {
 
 	Choices []ChatCompletionChoice
 	Usage   TokenUsage
+
+	// Prompt is set when the request asked for EchoPrompt.
+	Prompt *PromptEcho
+
+	// ProviderRequestID is the upstream's own request id, if it sent one.
+	ProviderRequestID string
+}
+
+// PromptEcho is the prompt exactly as sent upstream, for debugging.
+type PromptEcho struct {
+	Model    string // upstream model name
+	Messages []ChatMessage
+}
+
+// ChatCompletionChunk is one streamed delta of a chat completion.
+type ChatCompletionChunk struct {
+	ID      string
+	Created int64
+	Model   string
+
+	Choices []ChatCompletionChunkChoice
+	// Usage is set only on the final chunk, when the provider reports it.
+	Usage *TokenUsage
+}
+
+type ChatCompletionChunkChoice struct {
+	Index uint32
+	// Delta carries the role (first chunk only) and the next piece of content.
+	Delta        ChatMessage
+	FinishReason string
 }
 
 type EmbeddingsRequest struct {
@@ -74,6 +244,17 @@ type EmbeddingsRequest struct {
 	Model string
 	Input []string
 	User  string
+
+	// AutoChunk splits inputs that are too long into chunks and returns one
+	// mean-pooled vector per input. Never sent upstream.
+	AutoChunk bool
+
+	// ConversationID optionally groups requests for audit and usage; never sent upstream.
+	ConversationID string
+
+	// Subject is the authenticated caller (e.g. service token name), if any.
+	// Set by the transport layer; never sent upstream.
+	Subject string
 }
 
 // EmbeddingsUsage represents token usage for embeddings (input only).
@@ -85,8 +266,56 @@ type EmbeddingsUsage struct {
 type EmbeddingsResponse struct {
 	ID    string
 	Model string
-	Data  []Embedding
-	Usage EmbeddingsUsage
+	// Created is when the gateway received the request (Unix seconds);
+	// providers don't return one for embeddings.
+	Created int64
+	Data    []Embedding
+	Usage   EmbeddingsUsage
+
+	// ProviderRequestID is the upstream's own request id, if it sent one.
+	// For batched requests it is the first batch's.
+	ProviderRequestID string
+}
+
+// CompletionRequest is a legacy OpenAI text completion (/completions) request.
+type CompletionRequest struct {
+	// Routed model id, e.g. "openrouter/openai/gpt-3.5-turbo-instruct".
+	Model  string
+	Prompt string
+
+	// MaxTokens is nil when the caller didn't set it; it is then omitted upstream.
+	MaxTokens *uint32
+	// Temperature is nil when the caller didn't set it.
+	Temperature *float64
+	Stop        []string
+	User        string
+	// N is the number of choices to generate; nil leaves it to the provider (1).
+	N *uint32
+
+	// ConversationID optionally groups requests for audit and usage; never sent upstream.
+	ConversationID string
+
+	// Subject is the authenticated caller (e.g. service token name), if any.
+	// Set by the transport layer; never sent upstream.
+	Subject string
+}
+
+type CompletionChoice struct {
+	Index        uint32
+	Text         string
+	FinishReason string
+}
+
+type CompletionResponse struct {
+	ID      string
+	Created int64
+	Model   string
+
+	Choices []CompletionChoice
+	Usage   TokenUsage
+
+	// ProviderRequestID is the upstream's own request id, if it sent one.
+	ProviderRequestID string
 }
 
 // Generation represents a completed generation with usage information.
@@ -95,4 +324,50 @@ type Generation struct {
 	Model   string
 	Created int64
 	Usage   TokenUsage
+
+	// ConversationID is the conversation the request was tagged with, if any.
+	ConversationID string
+	// Subject is the caller that made the request; used to scope listings.
+	Subject string
+
+	// Request metadata, kept so a past request can be audited without full
+	// prompt capture. Zero when not recorded.
+	Provider      string
+	UpstreamModel string // model name sent to the provider
+	FinishReason  string // of the first choice; empty for embeddings
+	Latency       time.Duration
+	// ProviderRequestID is the upstream's own request id, for support escalations.
+	ProviderRequestID string
+	// UpstreamID is the provider's response ID when ID was issued by the
+	// gateway; empty when ID is the upstream one.
+	UpstreamID string
+	// Status is empty for a completed request, or GenerationStatusClientCancelled
+	// when Usage only covers the part of a stream generated before the client left.
+	Status string
+}
+
+// GenerationStatusClientCancelled marks a partial generation whose client
+// cancelled the stream.
+const GenerationStatusClientCancelled = "client_cancelled"
+
+// AuditRecord is a full capture of a request and its response for compliance.
+// It is only produced for subjects that explicitly opted in.
+type AuditRecord struct {
+	GenerationID string
+	// Operation is "chat.completions", "chat.completions.stream", "completions",
+	// "embeddings" or "embeddings.stream". Responses API requests run through
+	// the chat flow and are recorded as "chat.completions".
+	Operation string
+	Subject   string
+	Model     string
+	CreatedAt int64 // unix seconds
+	// ConversationID is set when the request was tagged with a conversation.
+	ConversationID string
+
+	// Request content. A completion's prompt is the single Input entry.
+	Messages []ChatMessage
+	Input    []string
+
+	// Response content (one entry per chat or completion choice).
+	Output []string
 }
@@ -0,0 +1,226 @@
+// Package admission bounds the number of requests the gateway serves at once.
+package admission
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PriorityFunc ranks a request for the admission queue; higher values are
+// admitted first. req is nil for streaming RPCs.
+type PriorityFunc func(ctx context.Context, req any) int
+
+// Limiter caps total concurrent in-flight requests across the whole process.
+// Requests beyond the cap are shed immediately unless a queue is configured
+// with WithQueue, in which case they wait for a slot highest priority first.
+type Limiter struct {
+	mu       sync.Mutex
+	max      int
+	inFlight int
+
+	queueSize    int
+	queueTimeout time.Duration
+	priority     PriorityFunc
+	waiters      []*waiter // by priority descending, then arrival
+}
+
+type waiter struct {
+	priority int
+	// done receives true when the waiter is admitted, false when it is
+	// pushed out of a full queue by a higher-priority request.
+	done chan bool
+}
+
+// Option customizes a Limiter.
+type Option func(*Limiter)
+
+// WithQueue lets up to size requests wait for a slot for at most timeout
+// (0 = until the caller's deadline) instead of being shed at the cap. When the
+// queue is full, a request with higher priority than the lowest queued one
+// takes its place and the displaced request is shed.
+func WithQueue(size int, timeout time.Duration) Option {
+	return func(l *Limiter) {
+		l.queueSize = size
+		l.queueTimeout = timeout
+	}
+}
+
+// WithPriority ranks queued requests with f; without it all requests have
+// equal priority and are admitted FIFO.
+func WithPriority(f PriorityFunc) Option {
+	return func(l *Limiter) { l.priority = f }
+}
+
+// NewLimiter returns a limiter admitting at most max concurrent requests.
+// max <= 0 returns nil, which admits everything.
+func NewLimiter(max int, opts ...Option) *Limiter {
+	if max <= 0 {
+		return nil
+	}
+	l := &Limiter{max: max}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// TryAcquire takes a slot if one is free. Callers must Release after a successful acquire.
+func (l *Limiter) TryAcquire() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight < l.max {
+		l.inFlight++
+		return true
+	}
+	return false
+}
+
+// Acquire takes a slot, queueing at priority when the limiter is full and a
+// queue is configured. It fails with Unavailable when shed and with the
+// context error when ctx ends first. Callers must Release after a successful acquire.
+func (l *Limiter) Acquire(ctx context.Context, priority int) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.inFlight < l.max {
+		l.inFlight++
+		l.mu.Unlock()
+		return nil
+	}
+	w := &waiter{priority: priority, done: make(chan bool, 1)}
+	if !l.enqueue(w) {
+		l.mu.Unlock()
+		return errOverloaded
+	}
+	l.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if l.queueTimeout > 0 {
+		t := time.NewTimer(l.queueTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	var err error
+	select {
+	case admitted := <-w.done:
+		if admitted {
+			return nil
+		}
+		return errOverloaded
+	case <-timeout:
+		err = errOverloaded
+	case <-ctx.Done():
+		err = status.FromContextError(ctx.Err()).Err()
+	}
+
+	l.mu.Lock()
+	if i := slices.Index(l.waiters, w); i >= 0 {
+		l.waiters = slices.Delete(l.waiters, i, i+1)
+		l.mu.Unlock()
+		return err
+	}
+	l.mu.Unlock()
+	// Admitted or displaced concurrently with giving up.
+	if <-w.done {
+		l.Release()
+	}
+	return err
+}
+
+// enqueue adds w in priority order, displacing the lowest-priority (latest
+// arrived) waiter if the queue is full and w outranks it. l.mu must be held.
+func (l *Limiter) enqueue(w *waiter) bool {
+	if l.queueSize <= 0 {
+		return false
+	}
+	if len(l.waiters) >= l.queueSize {
+		last := l.waiters[len(l.waiters)-1]
+		if w.priority <= last.priority {
+			return false
+		}
+		l.waiters = l.waiters[:len(l.waiters)-1]
+		last.done <- false
+	}
+	i := len(l.waiters)
+	for i > 0 && l.waiters[i-1].priority < w.priority {
+		i--
+	}
+	l.waiters = slices.Insert(l.waiters, i, w)
+	return true
+}
+
+// Release frees a slot, handing it to the highest-priority waiter if any.
+func (l *Limiter) Release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.waiters) > 0 {
+		w := l.waiters[0]
+		l.waiters = l.waiters[1:]
+		w.done <- true
+		return
+	}
+	l.inFlight--
+}
+
+// InFlight returns the number of currently admitted requests.
+func (l *Limiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+// Queued returns the number of requests waiting for a slot.
+func (l *Limiter) Queued() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
+
+func (l *Limiter) priorityOf(ctx context.Context, req any) int {
+	if l == nil || l.priority == nil || l.queueSize <= 0 {
+		return 0
+	}
+	return l.priority(ctx, req)
+}
+
+var errOverloaded = status.Error(codes.Unavailable, "gateway overloaded, retry later")
+
+func UnaryServerInterceptor(l *Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.Acquire(ctx, l.priorityOf(ctx, req)); err != nil {
+			return nil, err
+		}
+		defer l.Release()
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor(l *Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if err := l.Acquire(ctx, l.priorityOf(ctx, nil)); err != nil {
+			return err
+		}
+		defer l.Release()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,151 @@
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor_ShedsBeyondCap(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(2)
+	intercept := UnaryServerInterceptor(l)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test/Method"}
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	blocking := func(context.Context, any) (any, error) {
+		entered <- struct{}{}
+		<-release
+		return "ok", nil
+	}
+
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := intercept(context.Background(), nil, info, blocking)
+			done <- err
+		}()
+		<-entered
+	}
+	if l.InFlight() != 2 {
+		t.Fatalf("expected 2 in flight, got %d", l.InFlight())
+	}
+
+	_, err := intercept(context.Background(), nil, info, func(context.Context, any) (any, error) { return "ok", nil })
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable beyond cap, got %v", err)
+	}
+
+	release <- struct{}{}
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := intercept(context.Background(), nil, info, func(context.Context, any) (any, error) { return "ok", nil }); err != nil {
+		t.Fatalf("expected request to be admitted once a slot frees, got %v", err)
+	}
+
+	close(release)
+	<-done
+	if l.InFlight() != 0 {
+		t.Fatalf("expected 0 in flight, got %d", l.InFlight())
+	}
+}
+
+func TestNilLimiterAdmitsEverything(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(0)
+	for i := 0; i < 3; i++ {
+		if !l.TryAcquire() {
+			t.Fatalf("nil limiter must admit")
+		}
+	}
+}
+
+func TestLimiter_QueueAdmitsHigherPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(1, WithQueue(2, 0))
+	if !l.TryAcquire() {
+		t.Fatal("expected the first request to be admitted")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	admitted := make(chan string, 3)
+	enqueue := func(name string, prio int) chan error {
+		done := make(chan error, 1)
+		go func() {
+			err := l.Acquire(ctx, prio)
+			if err == nil {
+				admitted <- name
+			}
+			done <- err
+		}()
+		return done
+	}
+	waitQueued := func(n int) {
+		t.Helper()
+		for i := 0; l.Queued() != n; i++ {
+			if i > 1000 {
+				t.Fatalf("expected %d queued, got %d", n, l.Queued())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	low := enqueue("low", 0)
+	waitQueued(1)
+	normal := enqueue("normal", 1)
+	waitQueued(2)
+	// The queue is full: a high-priority request displaces the low one.
+	high := enqueue("high", 5)
+	if err := <-low; status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected the displaced low-priority request to be shed, got %v", err)
+	}
+	waitQueued(2)
+	if err := l.Acquire(ctx, 0); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected a request outranked by the whole full queue to be shed, got %v", err)
+	}
+
+	l.Release()
+	if err := <-high; err != nil {
+		t.Fatalf("high: %v", err)
+	}
+	l.Release()
+	if err := <-normal; err != nil {
+		t.Fatalf("normal: %v", err)
+	}
+	if first, second := <-admitted, <-admitted; first != "high" || second != "normal" {
+		t.Fatalf("expected high then normal, got %s then %s", first, second)
+	}
+	l.Release()
+	if l.InFlight() != 0 || l.Queued() != 0 {
+		t.Fatalf("expected an idle limiter, got %d in flight and %d queued", l.InFlight(), l.Queued())
+	}
+}
+
+func TestLimiter_QueueTimeout(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(1, WithQueue(1, 10*time.Millisecond))
+	if !l.TryAcquire() {
+		t.Fatal("expected the first request to be admitted")
+	}
+	if err := l.Acquire(context.Background(), 0); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable after the queue timeout, got %v", err)
+	}
+	if l.Queued() != 0 {
+		t.Fatalf("timed-out request should leave the queue, %d queued", l.Queued())
+	}
+	l.Release()
+	if l.InFlight() != 0 {
+		t.Fatalf("expected 0 in flight, got %d", l.InFlight())
+	}
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// FileSink implements application.llmgateway.AuditSink by appending JSON lines to a file.
+// Records are written by a background goroutine; when the buffer is full, new records
+// are dropped (and logged) rather than blocking the request path.
+type FileSink struct {
+	f  *os.File
+	ch chan llm.AuditRecord
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func NewFileSink(path string, buffer int) (*FileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit path is empty")
+	}
+	if buffer <= 0 {
+		buffer = 1024
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit file: %w", err)
+	}
+	s := &FileSink{
+		f:    f,
+		ch:   make(chan llm.AuditRecord, buffer),
+		done: make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *FileSink) Record(_ context.Context, rec llm.AuditRecord) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- rec:
+	default:
+		slog.Warn("audit buffer full, dropping record", "generation_id", rec.GenerationID, "subject", rec.Subject)
+	}
+}
+
+// Close stops accepting records, flushes the buffer and closes the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.ch)
+	s.mu.Unlock()
+
+	<-s.done
+	return s.f.Close()
+}
+
+func (s *FileSink) run() {
+	defer close(s.done)
+	enc := json.NewEncoder(s.f)
+	for rec := range s.ch {
+		if err := enc.Encode(toEntry(rec)); err != nil {
+			slog.Warn("audit write failed", "generation_id", rec.GenerationID, "error", err)
+		}
+	}
+}
+
+type entry struct {
+	GenerationID string    `json:"generation_id"`
+	Operation    string    `json:"operation"`
+	Subject      string    `json:"subject"`
+	Model        string    `json:"model"`
+	CreatedAt    int64     `json:"created_at"`
+	Conversation string    `json:"conversation_id,omitempty"`
+	Messages     []message `json:"messages,omitempty"`
+	Input        []string  `json:"input,omitempty"`
+	Output       []string  `json:"output,omitempty"`
+}
+
+type message struct {
+	Role         string        `json:"role"`
+	Content      string        `json:"content,omitempty"`
+	ContentParts []contentPart `json:"content_parts,omitempty"`
+	Name         string        `json:"name,omitempty"`
+}
+
+type contentPart struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+func toEntry(rec llm.AuditRecord) entry {
+	msgs := make([]message, 0, len(rec.Messages))
+	for _, m := range rec.Messages {
+		msg := message{Role: m.Role, Content: m.Content, Name: m.Name}
+		for _, cp := range m.ContentParts {
+			part := contentPart{Type: cp.Type, Text: cp.Text}
+			if cp.ImageURL != nil {
+				part.ImageURL = cp.ImageURL.URL
+			}
+			msg.ContentParts = append(msg.ContentParts, part)
+		}
+		msgs = append(msgs, msg)
+	}
+	return entry{
+		GenerationID: rec.GenerationID,
+		Operation:    rec.Operation,
+		Subject:      rec.Subject,
+		Model:        rec.Model,
+		CreatedAt:    rec.CreatedAt,
+		Conversation: rec.ConversationID,
+		Messages:     msgs,
+		Input:        rec.Input,
+		Output:       rec.Output,
+	}
+}
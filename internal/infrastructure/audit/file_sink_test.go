@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+func TestFileSink_RecordWritesJSONLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(path, 4)
+	if err != nil {
+		t.Fatalf("NewFileSink error: %v", err)
+	}
+	sink.Record(context.Background(), llm.AuditRecord{
+		GenerationID: "gen-1",
+		Operation:    "chat.completions",
+		Subject:      "svc",
+		Messages:     []llm.ChatMessage{{Role: "user", Content: "hello"}},
+		Output:       []string{"hi"},
+	})
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	// Records after Close are ignored rather than panicking.
+	sink.Record(context.Background(), llm.AuditRecord{GenerationID: "gen-2"})
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d: %q", len(lines), raw)
+	}
+	var got entry
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("decode line: %v", err)
+	}
+	if got.GenerationID != "gen-1" || got.Subject != "svc" || len(got.Messages) != 1 || got.Output[0] != "hi" {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+}
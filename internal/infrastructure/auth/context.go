@@ -1,14 +1,19 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"slices"
+)
 
 type ctxKey int
 
 const (
-	ctxKeySubject ctxKey = iota
-	ctxKeyMethod
+	ctxKeyPrincipal ctxKey = iota
 )
 
+// ScopeAdmin grants operator-only features, such as prompt echoes.
+const ScopeAdmin = "admin"
+
 type Method string
 
 const (
@@ -16,32 +21,37 @@ const (
 	MethodSignature    Method = "signature"
 )
 
-func WithSubject(ctx context.Context, subject string) context.Context {
-	if subject == "" {
-		return ctx
-	}
-	return context.WithValue(ctx, ctxKeySubject, subject)
+// RequestPrincipal is the authenticated caller of a request. The auth
+// interceptors resolve it once; handlers and middleware read it with
+// PrincipalFromContext instead of re-deriving identity from metadata.
+type RequestPrincipal struct {
+	// Subject identifies the caller for quotas, audit and callbacks
+	// (the service token name, or "service" for unnamed tokens).
+	Subject string
+	Method  Method
+	// TokenName is the configured name of the service token used directly or
+	// to issue the temporary credentials; may be empty.
+	TokenName string
+	Scopes    []string
 }
 
-func SubjectFromContext(ctx context.Context) string {
-	if ctx == nil {
-		return ""
-	}
-	v, _ := ctx.Value(ctxKeySubject).(string)
-	return v
+func (p RequestPrincipal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
 }
 
-func WithMethod(ctx context.Context, m Method) context.Context {
-	if m == "" {
+func WithPrincipal(ctx context.Context, p RequestPrincipal) context.Context {
+	if p.Subject == "" {
 		return ctx
 	}
-	return context.WithValue(ctx, ctxKeyMethod, m)
+	return context.WithValue(ctx, ctxKeyPrincipal, p)
 }
 
-func MethodFromContext(ctx context.Context) Method {
+// PrincipalFromContext returns the request's principal; ok is false when the
+// request is unauthenticated (e.g. auth is disabled).
+func PrincipalFromContext(ctx context.Context) (RequestPrincipal, bool) {
 	if ctx == nil {
-		return ""
+		return RequestPrincipal{}, false
 	}
-	v, _ := ctx.Value(ctxKeyMethod).(Method)
-	return v
+	p, ok := ctx.Value(ctxKeyPrincipal).(RequestPrincipal)
+	return p, ok
 }
@@ -0,0 +1,38 @@
+package auth
+
+// FailureReason classifies a rejected authentication attempt. The set is
+// fixed so it can label metrics without growing their cardinality.
+type FailureReason string
+
+const (
+	// FailureMissingToken: no service token or signature credentials were sent,
+	// or a service token was required and absent.
+	FailureMissingToken FailureReason = "missing_token"
+	// FailureInvalidToken: unknown service token or access key ID.
+	FailureInvalidToken FailureReason = "invalid_token"
+	// FailureExpired: the temporary credentials have expired.
+	FailureExpired FailureReason = "expired"
+	// FailureClockSkew: the signature timestamp is outside the allowed skew.
+	FailureClockSkew FailureReason = "clock_skew"
+	// FailureReplay: a verified signature was presented again.
+	FailureReplay FailureReason = "replay"
+	// FailureBadSignature: the signature is malformed or does not match.
+	FailureBadSignature FailureReason = "bad_signature"
+)
+
+// FailureMetrics records authentication failures. Implementations must not
+// add the subject or other caller-controlled labels.
+type FailureMetrics interface {
+	AuthFailure(reason FailureReason)
+}
+
+// WithFailureMetrics records every request the interceptors reject.
+func WithFailureMetrics(f FailureMetrics) Option {
+	return func(m *Manager) { m.failures = f }
+}
+
+func (m *Manager) recordFailure(reason FailureReason) {
+	if m.failures != nil {
+		m.failures.AuthFailure(reason)
+	}
+}
@@ -27,18 +27,20 @@ const (
 	mdBodySHA256 = "x-llmgw-body-sha256"
 )
 
+// MDUpstreamBaseURL carries an admin's per-request provider base URL override.
+// Signed requests must include it in the signature.
+const MDUpstreamBaseURL = "x-llmgw-upstream-base-url"
+
 func UnaryServerInterceptor(mgr *Manager) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		if mgr == nil || !mgr.Enabled() {
 			return handler(ctx, req)
 		}
-		subject, method, err := authenticate(ctx, mgr, info.FullMethod)
+		p, err := authenticate(ctx, mgr, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
-		ctx = WithSubject(ctx, subject)
-		ctx = WithMethod(ctx, method)
-		return handler(ctx, req)
+		return handler(WithPrincipal(ctx, p), req)
 	}
 }
 
@@ -47,13 +49,13 @@ func StreamServerInterceptor(mgr *Manager) grpc.StreamServerInterceptor {
 		if mgr == nil || !mgr.Enabled() {
 			return handler(srv, ss)
 		}
-		subject, method, err := authenticate(ss.Context(), mgr, info.FullMethod)
+		p, err := authenticate(ss.Context(), mgr, info.FullMethod)
 		if err != nil {
 			return err
 		}
 		wrapped := &serverStreamWithContext{
 			ServerStream: ss,
-			ctx:          WithMethod(WithSubject(ss.Context(), subject), method),
+			ctx:          WithPrincipal(ss.Context(), p),
 		}
 		return handler(srv, wrapped)
 	}
@@ -66,20 +68,22 @@ type serverStreamWithContext struct {
 
 func (s *serverStreamWithContext) Context() context.Context { return s.ctx }
 
-func authenticate(ctx context.Context, mgr *Manager, fullMethod string) (subject string, method Method, err error) {
+func authenticate(ctx context.Context, mgr *Manager, fullMethod string) (RequestPrincipal, error) {
 	md, _ := metadata.FromIncomingContext(ctx)
 
 	// 1) ServiceToken direct access
 	if tok := first(md.Get(mdServiceToken)); tok != "" {
-		if subject, ok := mgr.AuthenticateServiceToken(ctx, tok); ok {
-			return subject, MethodServiceToken, nil
+		if p, ok := mgr.PrincipalForServiceToken(ctx, tok); ok {
+			return p, nil
 		}
-		return "", "", status.Error(codes.Unauthenticated, "invalid service token")
+		mgr.recordFailure(FailureInvalidToken)
+		return RequestPrincipal{}, status.Error(codes.Unauthenticated, "invalid service token")
 	}
 
 	// For issuing temp credentials, ServiceToken is required.
 	if strings.HasSuffix(fullMethod, "/IssueTemporaryCredentials") {
-		return "", "", status.Error(codes.Unauthenticated, "service token required")
+		mgr.recordFailure(FailureMissingToken)
+		return RequestPrincipal{}, status.Error(codes.Unauthenticated, "service token required")
 	}
 
 	// 2) Temporary credentials signature access
@@ -94,11 +98,15 @@ func authenticate(ctx context.Context, mgr *Manager, fullMethod string) (subject
 		HTTPQuery:      first(md.Get(mdHTTPQuery)),
 		BodySHA256:     first(md.Get(mdBodySHA256)),
 		GRPCFullMethod: fullMethod,
+
+		UpstreamBaseURL: first(md.Get(MDUpstreamBaseURL)),
 	}
-	if subject, ok := mgr.AuthenticateSignature(ctx, in, time.Now()); ok {
-		return subject, MethodSignature, nil
+	p, reason := mgr.verifySignature(in, time.Now())
+	if reason == "" {
+		return p, nil
 	}
-	return "", "", status.Error(codes.Unauthenticated, "invalid signature")
+	mgr.recordFailure(reason)
+	return RequestPrincipal{}, status.Error(codes.Unauthenticated, "invalid signature")
 }
 
 func first(v []string) string {
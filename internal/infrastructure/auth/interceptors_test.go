@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptor_PopulatesPrincipal(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager([]ServiceToken{{Name: "billing", Token: "tok", Scopes: []string{"admin"}}}, time.Hour, 0)
+	intercept := UnaryServerInterceptor(m)
+	const method = "/llmgateway.v1.LLMGatewayService/ListModels"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	var got RequestPrincipal
+	handler := func(ctx context.Context, _ any) (any, error) {
+		p, ok := PrincipalFromContext(ctx)
+		if !ok {
+			t.Fatalf("principal missing in handler")
+		}
+		got = p
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(mdServiceToken, "tok"))
+	if _, err := intercept(ctx, nil, info, handler); err != nil {
+		t.Fatalf("service token: unexpected error: %v", err)
+	}
+	want := RequestPrincipal{Subject: "billing", Method: MethodServiceToken, TokenName: "billing", Scopes: []string{"admin"}}
+	if got.Subject != want.Subject || got.Method != want.Method || got.TokenName != want.TokenName || !slices.Equal(got.Scopes, want.Scopes) {
+		t.Fatalf("unexpected principal: %+v", got)
+	}
+
+	// Temporary credentials inherit the issuing token's identity and scopes.
+	creds, err := m.IssueTemporaryCredentials(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("IssueTemporaryCredentials error: %v", err)
+	}
+	in := signedInput(t, creds, time.Now())
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		mdAccessKeyID, in.AccessKeyID,
+		mdSignature, in.Signature,
+		mdTimestamp, strconv.FormatInt(in.Timestamp, 10),
+		mdNonce, in.Nonce,
+	))
+	if _, err := intercept(ctx, nil, info, handler); err != nil {
+		t.Fatalf("signature: unexpected error: %v", err)
+	}
+	if got.Subject != "billing" || got.Method != MethodSignature || !got.HasScope("admin") {
+		t.Fatalf("unexpected principal: %+v", got)
+	}
+}
+
+type failureCounter map[FailureReason]int
+
+func (c failureCounter) AuthFailure(reason FailureReason) { c[reason]++ }
+
+func TestUnaryServerInterceptor_RecordsFailureReasons(t *testing.T) {
+	t.Parallel()
+
+	failures := failureCounter{}
+	m := NewManager([]ServiceToken{{Name: "svc", Token: "tok"}}, time.Hour, time.Minute, WithFailureMetrics(failures))
+	creds, err := m.IssueTemporaryCredentials(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("IssueTemporaryCredentials error: %v", err)
+	}
+	intercept := UnaryServerInterceptor(m)
+	handler := func(context.Context, any) (any, error) { return nil, nil }
+	call := func(method string, md metadata.MD) error {
+		_, err := intercept(metadata.NewIncomingContext(context.Background(), md), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+	const listModels = "/llmgateway.v1.LLMGatewayService/ListModels"
+	signed := func(in SignatureInput) metadata.MD {
+		return metadata.Pairs(
+			mdAccessKeyID, in.AccessKeyID,
+			mdSignature, in.Signature,
+			mdTimestamp, strconv.FormatInt(in.Timestamp, 10),
+			mdNonce, in.Nonce,
+		)
+	}
+
+	valid := signedInput(t, creds, time.Now())
+	unknownKey := valid
+	unknownKey.AccessKeyID = "nope"
+	tampered := valid
+	tampered.Signature = hmacSHA256Hex("wrong secret", canonicalString(valid))
+	skewed := signedInput(t, creds, time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name   string
+		method string
+		md     metadata.MD
+		want   FailureReason
+	}{
+		{"no credentials", listModels, metadata.MD{}, FailureMissingToken},
+		{"issue without token", "/llmgateway.v1.LLMGatewayService/IssueTemporaryCredentials", metadata.MD{}, FailureMissingToken},
+		{"bad service token", listModels, metadata.Pairs(mdServiceToken, "wrong"), FailureInvalidToken},
+		{"unknown access key", listModels, signed(unknownKey), FailureInvalidToken},
+		{"clock skew", listModels, signed(skewed), FailureClockSkew},
+		{"bad signature", listModels, signed(tampered), FailureBadSignature},
+	}
+	for _, tt := range tests {
+		before := failures[tt.want]
+		if err := call(tt.method, tt.md); err == nil {
+			t.Fatalf("%s: expected rejection", tt.name)
+		}
+		if failures[tt.want] != before+1 {
+			t.Fatalf("%s: %s count = %d, want %d (all: %v)", tt.name, tt.want, failures[tt.want], before+1, failures)
+		}
+	}
+
+	if err := call(listModels, signed(valid)); err != nil {
+		t.Fatalf("first use of a signature: %v", err)
+	}
+	if err := call(listModels, signed(valid)); err == nil || failures[FailureReplay] != 1 {
+		t.Fatalf("expected the replayed signature rejected as replay, err=%v failures=%v", err, failures)
+	}
+
+	m.mu.Lock()
+	for id, rec := range m.temps {
+		rec.expiresAt = time.Now().Add(-time.Second)
+		m.temps[id] = rec
+	}
+	m.mu.Unlock()
+	if err := call(listModels, signed(signedInput(t, creds, time.Now()))); err == nil || failures[FailureExpired] != 1 {
+		t.Fatalf("expected expired credentials rejected, err=%v failures=%v", err, failures)
+	}
+
+	total := 0
+	for _, n := range failures {
+		total += n
+	}
+	if total != len(tests)+2 {
+		t.Fatalf("expected exactly one failure recorded per rejection, got %v", failures)
+	}
+}
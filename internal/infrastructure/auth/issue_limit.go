@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithIssueRateLimit limits IssueTemporaryCredentials per service token
+// subject to perMinute issuances on average, with bursts of up to burst. perMinute <= 0
+// disables the limit; burst < 1 is treated as 1.
+func WithIssueRateLimit(perMinute float64, burst int) Option {
+	return func(m *Manager) {
+		if perMinute <= 0 {
+			m.issueLimit = nil
+			return
+		}
+		m.issueLimit = newIssueLimiter(perMinute/60, max(burst, 1))
+	}
+}
+
+// issueLimiter is a token bucket per service token subject. Only configured
+// tokens reach it, so the bucket map stays bounded, and the token secret
+// itself is never held as a key.
+type issueLimiter struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*issueBucket
+}
+
+type issueBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newIssueLimiter(rate float64, burst int) *issueLimiter {
+	return &issueLimiter{rate: rate, burst: float64(burst), buckets: make(map[string]*issueBucket)}
+}
+
+// allow takes one token from key's bucket, reporting false if it is empty.
+func (l *issueLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &issueBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+elapsed*l.rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -18,11 +18,20 @@ import (
 var (
 	ErrUnauthenticated = errors.New("unauthenticated")
 	ErrForbidden       = errors.New("forbidden")
+	// ErrRateLimited is returned when a service token issues credentials faster
+	// than its issuance rate limit allows.
+	ErrRateLimited = errors.New("rate limited")
 )
 
 type ServiceToken struct {
 	Name  string
 	Token string
+	// Scopes grant extra privileges (e.g. admin RPCs) to callers of this token
+	// and to temporary credentials issued from it.
+	Scopes []string
+	// Priority orders this token's requests, and those of temporary
+	// credentials issued from it, in the admission queue; higher goes first.
+	Priority int
 }
 
 type TemporaryCredentials struct {
@@ -35,7 +44,7 @@ type TemporaryCredentials struct {
 type tempRecord struct {
 	secret    string
 	expiresAt time.Time
-	subject   string
+	issuer    ServiceToken // token the credentials were issued from
 }
 
 type Manager struct {
@@ -43,14 +52,20 @@ type Manager struct {
 
 	serviceTokens map[string]ServiceToken // token -> info
 	tempTTL       time.Duration
+	clockSkew     time.Duration // max allowed |now - signature timestamp|
 
 	mu    sync.RWMutex
 	temps map[string]tempRecord // accessKeyID -> record
 
 	usageCallbackAllowlist map[string]map[string]struct{} // subject -> set(url)
+
+	issueLimit *issueLimiter  // nil = unlimited
+	nonces     nonceCache     // verified signatures, to reject replays
+	failures   FailureMetrics // nil = not recorded
+	now        func() time.Time
 }
 
-func NewManager(serviceTokens []ServiceToken, tempTTL time.Duration) *Manager {
+func NewManager(serviceTokens []ServiceToken, tempTTL, clockSkew time.Duration, opts ...Option) *Manager {
 	st := make(map[string]ServiceToken, len(serviceTokens))
 	for _, t := range serviceTokens {
 		if t.Token == "" {
@@ -61,42 +76,78 @@ func NewManager(serviceTokens []ServiceToken, tempTTL time.Duration) *Manager {
 	if tempTTL <= 0 {
 		tempTTL = 15 * time.Minute
 	}
-	return &Manager{
+	if clockSkew <= 0 {
+		clockSkew = 5 * time.Minute
+	}
+	m := &Manager{
 		enabled:                len(st) > 0,
 		serviceTokens:          st,
 		tempTTL:                tempTTL,
+		clockSkew:              clockSkew,
 		temps:                  make(map[string]tempRecord),
 		usageCallbackAllowlist: make(map[string]map[string]struct{}),
+		now:                    time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 func (m *Manager) Enabled() bool { return m != nil && m.enabled }
 
-func (m *Manager) AuthenticateServiceToken(_ context.Context, token string) (subject string, ok bool) {
+func (m *Manager) AuthenticateServiceToken(ctx context.Context, token string) (subject string, ok bool) {
+	p, ok := m.PrincipalForServiceToken(ctx, token)
+	return p.Subject, ok
+}
+
+// PrincipalForServiceToken authenticates a service token and describes its caller.
+func (m *Manager) PrincipalForServiceToken(_ context.Context, token string) (RequestPrincipal, bool) {
 	if !m.Enabled() {
-		return "", true
+		return RequestPrincipal{}, true
 	}
 	if token == "" {
-		return "", false
+		return RequestPrincipal{}, false
 	}
 	t, ok := m.serviceTokens[token]
 	if !ok {
-		return "", false
+		return RequestPrincipal{}, false
 	}
-	if t.Name != "" {
-		return t.Name, true
+	return principalFor(t, MethodServiceToken), true
+}
+
+func principalFor(t ServiceToken, method Method) RequestPrincipal {
+	return RequestPrincipal{
+		Subject:   SubjectForServiceToken(t.Name),
+		Method:    method,
+		TokenName: t.Name,
+		Scopes:    t.Scopes,
+	}
+}
+
+// SubjectForServiceToken returns the subject assigned to callers of a service token.
+func SubjectForServiceToken(name string) string {
+	if name != "" {
+		return name
 	}
-	return "service", true
+	return "service"
 }
 
 func (m *Manager) IssueTemporaryCredentials(_ context.Context, serviceToken string) (TemporaryCredentials, error) {
 	if !m.Enabled() {
 		return TemporaryCredentials{}, fmt.Errorf("%w: auth not configured", ErrForbidden)
 	}
-	subject, ok := m.AuthenticateServiceToken(context.Background(), serviceToken)
+	if serviceToken == "" {
+		return TemporaryCredentials{}, ErrUnauthenticated
+	}
+	issuer, ok := m.serviceTokens[serviceToken]
 	if !ok {
 		return TemporaryCredentials{}, ErrUnauthenticated
 	}
+	subject := SubjectForServiceToken(issuer.Name)
+	if m.issueLimit != nil && !m.issueLimit.allow(subject, m.now()) {
+		return TemporaryCredentials{}, fmt.Errorf("%w: credential issuance for %s", ErrRateLimited, subject)
+	}
 
 	akid, err := randHex(16)
 	if err != nil {
@@ -106,10 +157,10 @@ func (m *Manager) IssueTemporaryCredentials(_ context.Context, serviceToken stri
 	if err != nil {
 		return TemporaryCredentials{}, err
 	}
-	exp := time.Now().Add(m.tempTTL)
+	exp := m.now().Add(m.tempTTL)
 
 	m.mu.Lock()
-	m.temps[akid] = tempRecord{secret: secret, expiresAt: exp, subject: subject}
+	m.temps[akid] = tempRecord{secret: secret, expiresAt: exp, issuer: issuer}
 	m.mu.Unlock()
 
 	return TemporaryCredentials{
@@ -180,29 +231,45 @@ type SignatureInput struct {
 
 	// gRPC signing context (fallback).
 	GRPCFullMethod string
+
+	// UpstreamBaseURL is the per-request provider base URL override, if any.
+	UpstreamBaseURL string
+}
+
+func (m *Manager) AuthenticateSignature(ctx context.Context, in SignatureInput, now time.Time) (subject string, ok bool) {
+	p, ok := m.PrincipalForSignature(ctx, in, now)
+	return p.Subject, ok
+}
+
+// PrincipalForSignature verifies a temporary-credentials signature. The caller
+// inherits the identity and scopes of the service token the credentials were issued from.
+func (m *Manager) PrincipalForSignature(_ context.Context, in SignatureInput, now time.Time) (RequestPrincipal, bool) {
+	p, reason := m.verifySignature(in, now)
+	return p, reason == ""
 }
 
-func (m *Manager) AuthenticateSignature(_ context.Context, in SignatureInput, now time.Time) (subject string, ok bool) {
+// verifySignature is PrincipalForSignature, returning the FailureReason on failure.
+func (m *Manager) verifySignature(in SignatureInput, now time.Time) (RequestPrincipal, FailureReason) {
 	if !m.Enabled() {
-		return "", true
+		return RequestPrincipal{}, ""
 	}
 	if in.AccessKeyID == "" || in.Signature == "" || in.Timestamp == 0 || in.Nonce == "" {
-		return "", false
+		return RequestPrincipal{}, FailureMissingToken
 	}
 	// Allow small clock skew.
 	ts := time.Unix(in.Timestamp, 0)
-	if ts.Before(now.Add(-5*time.Minute)) || ts.After(now.Add(5*time.Minute)) {
-		return "", false
+	if ts.Before(now.Add(-m.clockSkew)) || ts.After(now.Add(m.clockSkew)) {
+		return RequestPrincipal{}, FailureClockSkew
 	}
 
 	m.mu.RLock()
 	rec, ok := m.temps[in.AccessKeyID]
 	m.mu.RUnlock()
 	if !ok {
-		return "", false
+		return RequestPrincipal{}, FailureInvalidToken
 	}
 	if now.After(rec.expiresAt) {
-		return "", false
+		return RequestPrincipal{}, FailureExpired
 	}
 
 	canonical := canonicalString(in)
@@ -211,15 +278,21 @@ func (m *Manager) AuthenticateSignature(_ context.Context, in SignatureInput, no
 	a, errA := hex.DecodeString(expected)
 	b, errB := hex.DecodeString(in.Signature)
 	if errA != nil || errB != nil {
-		return "", false
+		return RequestPrincipal{}, FailureBadSignature
 	}
 	if !hmac.Equal(a, b) {
-		return "", false
+		return RequestPrincipal{}, FailureBadSignature
 	}
-	return rec.subject, true
+	// Only verified signatures are remembered, so forged requests can't fill
+	// the nonce cache.
+	if !m.nonces.remember(in.AccessKeyID, in.Timestamp, in.Nonce, ts.Add(m.clockSkew), now) {
+		return RequestPrincipal{}, FailureReplay
+	}
+	return principalFor(rec.issuer, MethodSignature), ""
 }
 
 func canonicalString(in SignatureInput) string {
+	var s string
 	// Prefer HTTP canonicalization when we have enough context.
 	if in.HTTPMethod != "" && in.HTTPPath != "" {
 		resource := in.HTTPPath
@@ -227,10 +300,17 @@ func canonicalString(in SignatureInput) string {
 			resource = resource + "?" + in.HTTPQuery
 		}
 		// Include callback URL if present to prevent tampering.
-		return fmt.Sprintf("%d\n%s\n%s\n%s\n%s\n%s", in.Timestamp, in.Nonce, in.HTTPMethod, resource, in.BodySHA256, in.CallbackURL)
+		s = fmt.Sprintf("%d\n%s\n%s\n%s\n%s\n%s", in.Timestamp, in.Nonce, in.HTTPMethod, resource, in.BodySHA256, in.CallbackURL)
+	} else {
+		// gRPC fallback: bind to fullMethod only.
+		s = fmt.Sprintf("%d\n%s\nGRPC\n%s\n%s", in.Timestamp, in.Nonce, in.GRPCFullMethod, in.CallbackURL)
+	}
+	// An upstream override is appended only when present, so signatures of
+	// requests without one are unchanged.
+	if in.UpstreamBaseURL != "" {
+		s += "\n" + in.UpstreamBaseURL
 	}
-	// gRPC fallback: bind to fullMethod only.
-	return fmt.Sprintf("%d\n%s\nGRPC\n%s\n%s", in.Timestamp, in.Nonce, in.GRPCFullMethod, in.CallbackURL)
+	return s
 }
 
 func hmacSHA256Hex(secret, msg string) string {
@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func signedInput(t *testing.T, creds TemporaryCredentials, ts time.Time) SignatureInput {
+	t.Helper()
+	in := SignatureInput{
+		AccessKeyID:    creds.AccessKeyID,
+		Timestamp:      ts.Unix(),
+		Nonce:          "nonce",
+		GRPCFullMethod: "/llmgateway.v1.LLMGatewayService/ListModels",
+	}
+	in.Signature = hmacSHA256Hex(creds.AccessKeySecret, canonicalString(in))
+	return in
+}
+
+func TestManager_AuthenticateSignature_CustomClockSkew(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager([]ServiceToken{{Name: "svc", Token: "tok"}}, time.Hour, 30*time.Second)
+	creds, err := m.IssueTemporaryCredentials(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("IssueTemporaryCredentials error: %v", err)
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	tests := []struct {
+		name   string
+		offset time.Duration
+		wantOK bool
+	}{
+		{name: "exact", offset: 0, wantOK: true},
+		{name: "past edge", offset: -30 * time.Second, wantOK: true},
+		{name: "future edge", offset: 30 * time.Second, wantOK: true},
+		{name: "past beyond", offset: -31 * time.Second, wantOK: false},
+		{name: "future beyond", offset: 31 * time.Second, wantOK: false},
+	}
+	for _, tt := range tests {
+		in := signedInput(t, creds, now.Add(tt.offset))
+		subject, ok := m.AuthenticateSignature(context.Background(), in, now)
+		if ok != tt.wantOK {
+			t.Fatalf("%s: ok=%v, want %v", tt.name, ok, tt.wantOK)
+		}
+		if ok && subject != "svc" {
+			t.Fatalf("%s: unexpected subject %q", tt.name, subject)
+		}
+	}
+}
+
+func TestManager_AuthenticateSignature_RejectsReplayedNonce(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager([]ServiceToken{{Name: "svc", Token: "tok"}}, time.Hour, 30*time.Second)
+	creds, err := m.IssueTemporaryCredentials(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("IssueTemporaryCredentials error: %v", err)
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	in := signedInput(t, creds, now)
+	if _, ok := m.AuthenticateSignature(context.Background(), in, now); !ok {
+		t.Fatal("first use should be accepted")
+	}
+	if _, ok := m.AuthenticateSignature(context.Background(), in, now.Add(time.Second)); ok {
+		t.Fatal("replayed signature should be rejected")
+	}
+
+	in.Nonce = "other"
+	in.Signature = hmacSHA256Hex(creds.AccessKeySecret, canonicalString(in))
+	if _, ok := m.AuthenticateSignature(context.Background(), in, now.Add(time.Second)); !ok {
+		t.Fatal("a fresh nonce should be accepted")
+	}
+}
+
+func TestNonceCache_ForgetsExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	var c nonceCache
+	now := time.Unix(1700000000, 0)
+	if !c.remember("ak", 1, "n", now.Add(time.Minute), now) {
+		t.Fatal("first use should be remembered")
+	}
+	if c.remember("ak", 1, "n", now.Add(time.Minute), now.Add(30*time.Second)) {
+		t.Fatal("repeat within the window should be rejected")
+	}
+	if !c.remember("ak", 1, "n", now.Add(3*time.Minute), now.Add(2*time.Minute)) {
+		t.Fatal("an expired entry should not block a later use")
+	}
+}
+
+func TestNewManager_DefaultClockSkew(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager([]ServiceToken{{Name: "svc", Token: "tok"}}, time.Hour, 0)
+	if m.clockSkew != 5*time.Minute {
+		t.Fatalf("unexpected default clock skew: %v", m.clockSkew)
+	}
+}
+
+func TestManager_IssueTemporaryCredentials_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager([]ServiceToken{{Name: "a", Token: "tok-a"}, {Name: "b", Token: "tok-b"}}, time.Hour, 0,
+		WithIssueRateLimit(60, 2)) // one per second, bursts of two
+	now := time.Unix(1700000000, 0)
+	m.now = func() time.Time { return now }
+	issue := func(token string) error {
+		_, err := m.IssueTemporaryCredentials(context.Background(), token)
+		return err
+	}
+
+	for i := range 2 {
+		if err := issue("tok-a"); err != nil {
+			t.Fatalf("burst issuance %d: %v", i, err)
+		}
+	}
+	if err := issue("tok-a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited after burst, got %v", err)
+	}
+	if err := issue("tok-b"); err != nil {
+		t.Fatalf("other service token should have its own bucket: %v", err)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if err := issue("tok-a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited before a token refills, got %v", err)
+	}
+	now = now.Add(time.Second)
+	if err := issue("tok-a"); err != nil {
+		t.Fatalf("expected issuance to recover after refill: %v", err)
+	}
+	if err := issue("tok-a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited after consuming the refilled token, got %v", err)
+	}
+}
+
+func TestManager_IssueTemporaryCredentials_RateLimitedPerSubject(t *testing.T) {
+	t.Parallel()
+
+	// Two secrets for one token name (e.g. mid-rotation) share a bucket.
+	m := NewManager([]ServiceToken{{Name: "a", Token: "tok-old"}, {Name: "a", Token: "tok-new"}}, time.Hour, 0,
+		WithIssueRateLimit(60, 1))
+	m.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	if _, err := m.IssueTemporaryCredentials(context.Background(), "tok-old"); err != nil {
+		t.Fatalf("first issuance: %v", err)
+	}
+	if _, err := m.IssueTemporaryCredentials(context.Background(), "tok-new"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected the subject's bucket to be shared across its tokens, got %v", err)
+	}
+}
+
+func TestManager_Priority(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager([]ServiceToken{{Name: "batch", Token: "tok-b"}, {Name: "interactive", Token: "tok-i", Priority: 10}}, time.Hour, 0)
+	creds, err := m.IssueTemporaryCredentials(context.Background(), "tok-i")
+	if err != nil {
+		t.Fatalf("IssueTemporaryCredentials error: %v", err)
+	}
+	for _, tt := range []struct {
+		name string
+		md   metadata.MD
+		want int
+	}{
+		{name: "service token", md: metadata.Pairs("x-service-token", "tok-i"), want: 10},
+		{name: "default priority", md: metadata.Pairs("x-service-token", "tok-b"), want: 0},
+		{name: "temporary credentials inherit", md: metadata.Pairs("x-access-key-id", creds.AccessKeyID), want: 10},
+		{name: "unknown token", md: metadata.Pairs("x-service-token", "nope"), want: 0},
+		{name: "anonymous", want: 0},
+	} {
+		ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+		if got := m.Priority(ctx); got != tt.want {
+			t.Fatalf("%s: Priority = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
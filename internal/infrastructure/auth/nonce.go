@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// nonceCache rejects replayed signatures. A signed request is unique by its
+// (access key, timestamp, nonce) triple, so a verified triple is remembered
+// until its timestamp leaves the clock skew window, after which the skew
+// check rejects it anyway. It is per process, so replicas don't share it.
+type nonceCache struct {
+	mu      sync.Mutex
+	seen    map[string]time.Time // key -> expiry
+	pruneAt int
+}
+
+// remember records the triple and reports false if it was already seen.
+// Expired entries are pruned whenever the map doubles, so it stays bounded
+// by the signatures verified within one skew window.
+func (c *nonceCache) remember(accessKeyID string, timestamp int64, nonce string, expires, now time.Time) bool {
+	key := accessKeyID + "\n" + strconv.FormatInt(timestamp, 10) + "\n" + nonce
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if exp, ok := c.seen[key]; ok && !now.After(exp) {
+		return false
+	}
+	if c.seen == nil {
+		c.seen = make(map[string]time.Time)
+	}
+	if len(c.seen) >= c.pruneAt {
+		for k, exp := range c.seen {
+			if now.After(exp) {
+				delete(c.seen, k)
+			}
+		}
+		c.pruneAt = max(1024, 2*len(c.seen))
+	}
+	c.seen[key] = expires
+	return true
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Priority returns the admission priority of the service token behind the
+// request's credentials (the token itself, or the issuer of its temporary
+// credentials), or 0 if none is found. It runs before authentication, so the
+// credentials are identified but not verified: a forged request can at most
+// jump the admission queue before auth rejects it.
+func (m *Manager) Priority(ctx context.Context) int {
+	if !m.Enabled() {
+		return 0
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tok := first(md.Get(mdServiceToken)); tok != "" {
+		return m.serviceTokens[tok].Priority
+	}
+	akid := first(md.Get(mdAccessKeyID))
+	if akid == "" {
+		return 0
+	}
+	m.mu.RLock()
+	rec, ok := m.temps[akid]
+	m.mu.RUnlock()
+	if !ok || m.now().After(rec.expiresAt) {
+		return 0
+	}
+	return rec.issuer.Priority
+}
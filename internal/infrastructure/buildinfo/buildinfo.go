@@ -0,0 +1,12 @@
+// Package buildinfo exposes build metadata injected at link time, e.g.:
+//
+//	go build -ldflags "-X github.com/poly-workshop/llm-gateway/internal/infrastructure/buildinfo.Version=v1.2.3" ./cmd/...
+package buildinfo
+
+// Version is the gateway build version. It defaults to "dev" for local builds.
+var Version = "dev"
+
+// UserAgent is the default User-Agent sent on upstream requests.
+func UserAgent() string {
+	return "llm-gateway/" + Version
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/poly-workshop/go-webmods/app"
@@ -11,6 +13,44 @@ import (
 type GRPCAppConfig struct {
 	GRPC struct {
 		Listen string `mapstructure:"listen"`
+		// MaxConcurrentRequests caps in-flight requests gateway-wide; 0 disables the cap.
+		MaxConcurrentRequests int `mapstructure:"max_concurrent_requests"`
+		// MaxStreamsPerSubject caps open streams per authenticated caller; 0 disables the cap.
+		MaxStreamsPerSubject int `mapstructure:"max_streams_per_subject"`
+		// AdmissionQueueSize lets that many requests over the cap wait for a
+		// slot by priority for up to AdmissionQueueTimeout; 0 sheds them at once.
+		AdmissionQueueSize    int           `mapstructure:"admission_queue_size"`
+		AdmissionQueueTimeout time.Duration `mapstructure:"admission_queue_timeout"`
+		// ShutdownTimeout bounds draining in-flight RPCs and streams on shutdown.
+		ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+		// Gzip compresses responses for clients that advertise gzip support.
+		Gzip bool `mapstructure:"gzip"`
+		// UsageTrailers sends completion token usage as x-usage-* trailers.
+		UsageTrailers bool `mapstructure:"usage_trailers"`
+		// MaxUpstreamTimeout bounds the x-upstream-timeout header authenticated
+		// callers (e.g. batch jobs) may send; 0 rejects the header.
+		MaxUpstreamTimeout time.Duration `mapstructure:"max_upstream_timeout"`
+		// MaintenanceRetryAfter is the retry hint given to callers rejected by
+		// maintenance mode when it is toggled by signal.
+		MaintenanceRetryAfter time.Duration `mapstructure:"maintenance_retry_after"`
+		// DisabledMethods are LLMGatewayService methods (e.g. "CreateEmbeddings")
+		// rejected with Unimplemented; re-read on SIGHUP.
+		DisabledMethods []string `mapstructure:"disabled_methods"`
+		// UpstreamOverrideHosts are the hosts (exact or "*.suffix") admins may
+		// point a single request at via x-llmgw-upstream-base-url; empty disables it.
+		UpstreamOverrideHosts []string `mapstructure:"upstream_override_hosts"`
+		// Keepalive tunes connection keepalive; zero values keep gRPC defaults.
+		Keepalive struct {
+			MaxConnectionIdle     time.Duration `mapstructure:"max_connection_idle"`
+			MaxConnectionAge      time.Duration `mapstructure:"max_connection_age"`
+			MaxConnectionAgeGrace time.Duration `mapstructure:"max_connection_age_grace"`
+			// Time is the server ping interval on idle connections.
+			Time    time.Duration `mapstructure:"time"`
+			Timeout time.Duration `mapstructure:"timeout"`
+			// MinPingInterval is the most often clients may ping.
+			MinPingInterval     time.Duration `mapstructure:"min_ping_interval"`
+			PermitWithoutStream bool          `mapstructure:"permit_without_stream"`
+		} `mapstructure:"keepalive"`
 	} `mapstructure:"grpc"`
 
 	Health struct {
@@ -18,45 +58,321 @@ type GRPCAppConfig struct {
 	} `mapstructure:"health"`
 
 	Auth struct {
-		TempTTL       time.Duration `mapstructure:"temp_ttl"`
-		ServiceTokens []struct {
+		TempTTL   time.Duration `mapstructure:"temp_ttl"`
+		ClockSkew time.Duration `mapstructure:"clock_skew"`
+		// IssuePerMinute and IssueBurst rate-limit temporary credential
+		// issuance per service token (token bucket). IssuePerMinute defaults
+		// to 60 when unset; 0 disables the limit.
+		IssuePerMinute *float64 `mapstructure:"issue_per_minute"`
+		IssueBurst     int      `mapstructure:"issue_burst"`
+		ServiceTokens  []struct {
 			Name  string `mapstructure:"name"`
 			Token string `mapstructure:"token"`
+			// Scopes grant extra privileges, e.g. "admin" for operator RPCs.
+			Scopes []string `mapstructure:"scopes"`
+			// Priority orders this service's requests in the admission queue; higher goes first.
+			Priority int `mapstructure:"priority"`
+			// Audit opts this service into full prompt/response capture.
+			Audit bool `mapstructure:"audit"`
+			// AllowedModels / DeniedModels restrict callable routed model IDs ("*" wildcards).
+			AllowedModels []string `mapstructure:"allowed_models"`
+			DeniedModels  []string `mapstructure:"denied_models"`
 		} `mapstructure:"service_tokens"`
 	} `mapstructure:"auth"`
 
+	Log struct {
+		// DebugSampleRate (0..1) is the fraction of requests logged in detail at debug level.
+		DebugSampleRate float64 `mapstructure:"debug_sample_rate"`
+	} `mapstructure:"log"`
+
+	Metrics struct {
+		// LatencyBuckets are histogram bounds in seconds; empty uses the built-in defaults.
+		LatencyBuckets []float64 `mapstructure:"latency_buckets"`
+		SLO            struct {
+			// P99Threshold enables a warning when a model's rolling p99 exceeds it; 0 disables.
+			P99Threshold time.Duration `mapstructure:"p99_threshold"`
+			Window       time.Duration `mapstructure:"window"`
+		} `mapstructure:"slo"`
+	} `mapstructure:"metrics"`
+
+	Audit struct {
+		Path   string `mapstructure:"path"`
+		Buffer int    `mapstructure:"buffer"`
+	} `mapstructure:"audit"`
+
+	UsageCallback struct {
+		// BatchSize > 0 delivers usage callbacks as JSON arrays of up to that
+		// many events, flushed at least every BatchInterval; 0 sends one POST per event.
+		BatchSize     int           `mapstructure:"batch_size"`
+		BatchInterval time.Duration `mapstructure:"batch_interval"`
+	} `mapstructure:"usage_callback"`
+
+	Generations struct {
+		// Store selects the generation repository: "memory" (default) or "postgres".
+		Store string `mapstructure:"store"`
+		// MemoryCapacity bounds the in-memory generation store; oldest records are evicted first.
+		MemoryCapacity int `mapstructure:"memory_capacity"`
+
+		Postgres struct {
+			DSN             string        `mapstructure:"dsn"`
+			MaxOpenConns    int           `mapstructure:"max_open_conns"`
+			MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+			ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+		} `mapstructure:"postgres"`
+	} `mapstructure:"generations"`
+
+	// Secrets resolves providers' api_key_secrets from a secret store.
+	Secrets struct {
+		// Provider selects the store: "file" reads each secret from Dir/<name>.
+		Provider string `mapstructure:"provider"`
+		Dir      string `mapstructure:"dir"`
+	} `mapstructure:"secrets"`
+
 	LLM struct {
 		Providers struct {
 			DashScope struct {
-				BaseURL string        `mapstructure:"base_url"`
-				APIKey  string        `mapstructure:"api_key"`
-				Timeout time.Duration `mapstructure:"timeout"`
+				BaseURL string            `mapstructure:"base_url"`
+				APIKey  string            `mapstructure:"api_key"`
+				APIKeys []string          `mapstructure:"api_keys"`
+				Timeout time.Duration     `mapstructure:"timeout"`
+				Headers map[string]string `mapstructure:"headers"`
+				// WeightedKeys are extra keys that take traffic in proportion to their
+				// weight and stop being used once their daily quota is spent.
+				WeightedKeys []WeightedAPIKey `mapstructure:"weighted_keys"`
+				// APIKeySecrets names secrets whose values are added to the key pool;
+				// they are resolved at startup and again on SIGHUP.
+				APIKeySecrets []string `mapstructure:"api_key_secrets"`
+				// StreamIdleTimeout aborts a streamed completion that sends nothing for this
+				// long; Timeout only bounds unary calls.
+				StreamIdleTimeout time.Duration `mapstructure:"stream_idle_timeout"`
+				// SlowRequestThreshold overrides llm.slow_request_threshold for this provider.
+				SlowRequestThreshold *time.Duration `mapstructure:"slow_request_threshold"`
+				// DiscoverModels merges the upstream /models list into the catalog.
+				DiscoverModels bool `mapstructure:"discover_models"`
 			} `mapstructure:"dashscope"`
 			OpenRouter struct {
-				BaseURL string        `mapstructure:"base_url"`
-				APIKey  string        `mapstructure:"api_key"`
-				Timeout time.Duration `mapstructure:"timeout"`
+				BaseURL string            `mapstructure:"base_url"`
+				APIKey  string            `mapstructure:"api_key"`
+				APIKeys []string          `mapstructure:"api_keys"`
+				Timeout time.Duration     `mapstructure:"timeout"`
+				Headers map[string]string `mapstructure:"headers"`
+				// WeightedKeys are extra keys that take traffic in proportion to their
+				// weight and stop being used once their daily quota is spent.
+				WeightedKeys []WeightedAPIKey `mapstructure:"weighted_keys"`
+				// APIKeySecrets names secrets whose values are added to the key pool;
+				// they are resolved at startup and again on SIGHUP.
+				APIKeySecrets []string `mapstructure:"api_key_secrets"`
+				// StreamIdleTimeout aborts a streamed completion that sends nothing for this
+				// long; Timeout only bounds unary calls.
+				StreamIdleTimeout time.Duration `mapstructure:"stream_idle_timeout"`
+				// SlowRequestThreshold overrides llm.slow_request_threshold for this provider.
+				SlowRequestThreshold *time.Duration `mapstructure:"slow_request_threshold"`
+				// DiscoverModels merges the upstream /models list into the catalog.
+				DiscoverModels bool `mapstructure:"discover_models"`
 			} `mapstructure:"openrouter"`
+			// Azure is registered only when BaseURL (the resource endpoint) is set.
+			// Routed models' upstream names are Azure deployment names.
+			Azure struct {
+				BaseURL    string            `mapstructure:"base_url"`
+				APIVersion string            `mapstructure:"api_version"`
+				APIKey     string            `mapstructure:"api_key"`
+				APIKeys    []string          `mapstructure:"api_keys"`
+				Timeout    time.Duration     `mapstructure:"timeout"`
+				Headers    map[string]string `mapstructure:"headers"`
+				// WeightedKeys are extra keys that take traffic in proportion to their
+				// weight and stop being used once their daily quota is spent.
+				WeightedKeys []WeightedAPIKey `mapstructure:"weighted_keys"`
+				// APIKeySecrets names secrets whose values are added to the key pool;
+				// they are resolved at startup and again on SIGHUP.
+				APIKeySecrets []string `mapstructure:"api_key_secrets"`
+				// StreamIdleTimeout aborts a streamed completion that sends nothing for this
+				// long; Timeout only bounds unary calls.
+				StreamIdleTimeout time.Duration `mapstructure:"stream_idle_timeout"`
+				// SlowRequestThreshold overrides llm.slow_request_threshold for this provider.
+				SlowRequestThreshold *time.Duration `mapstructure:"slow_request_threshold"`
+			} `mapstructure:"azure"`
 		} `mapstructure:"providers"`
 
+		// TenantPools give some callers their own upstream credentials.
+		TenantPools []TenantPool `mapstructure:"tenant_pools"`
+
+		// SlowRequestThreshold logs successful upstream calls slower than this at
+		// warn; 0 disables it.
+		SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"`
+
+		// DefaultUserFromSubject fills an empty request `user` with a hash of the
+		// authenticated subject, keyed by DefaultUserSalt.
+		DefaultUserFromSubject bool   `mapstructure:"default_user_from_subject"`
+		DefaultUserSalt        string `mapstructure:"default_user_salt"`
+
+		// GatewayGenerationIDs returns gateway-issued generation IDs instead of
+		// upstream response IDs; the upstream ID is kept on the record.
+		GatewayGenerationIDs bool `mapstructure:"gateway_generation_ids"`
+
+		// DedupChoices collapses identical choices of unary chat and text
+		// completion responses; usage is unchanged.
+		DedupChoices bool `mapstructure:"dedup_choices"`
+
+		// ProviderAliases maps alternative provider prefixes in model IDs to
+		// provider names, e.g. {ali = "dashscope"}. Prefixes are case-insensitive.
+		ProviderAliases map[string]string `mapstructure:"provider_aliases"`
+
+		// HTTP configures the upstream connection layer for all providers.
+		HTTP struct {
+			// ShareTransport makes all providers use one connection pool;
+			// otherwise each provider gets its own transport with these settings.
+			ShareTransport      bool          `mapstructure:"share_transport"`
+			ProxyURL            string        `mapstructure:"proxy_url"`
+			DialTimeout         time.Duration `mapstructure:"dial_timeout"`
+			TLSHandshakeTimeout time.Duration `mapstructure:"tls_handshake_timeout"`
+			MaxIdleConnsPerHost int           `mapstructure:"max_idle_conns_per_host"`
+			IdleConnTimeout     time.Duration `mapstructure:"idle_conn_timeout"`
+		} `mapstructure:"http"`
+
+		// UserAgent overrides the default "llm-gateway/<version>" sent upstream.
+		UserAgent string `mapstructure:"user_agent"`
+
+		// MaxResponseBytes bounds non-streamed upstream response bodies for all
+		// providers; 0 keeps the built-in 64MiB limit.
+		MaxResponseBytes int64 `mapstructure:"max_response_bytes"`
+
+		// ModelRefreshInterval is how often discovered upstream models are re-fetched.
+		ModelRefreshInterval time.Duration `mapstructure:"model_refresh_interval"`
+
+		// EmptyResponse handles successful chat responses without output:
+		// "pass_through" (default), "retry" (once, then error) or "error".
+		EmptyResponse string `mapstructure:"empty_response"`
+
+		// MetadataAllowlist lists request metadata keys forwarded to providers;
+		// other keys are dropped. Empty forwards nothing.
+		MetadataAllowlist []string `mapstructure:"metadata_allowlist"`
+
+		// Images restricts image_url content parts in chat requests.
+		Images struct {
+			// DataURIOnly rejects remote image URLs; images must be data: URIs.
+			DataURIOnly bool `mapstructure:"data_uri_only"`
+			// AllowedHosts restricts remote image hosts ("*.example.com" matches
+			// subdomains); empty allows any host.
+			AllowedHosts []string `mapstructure:"allowed_hosts"`
+		} `mapstructure:"images"`
+
+		// OutputFilter keeps sensitive patterns out of completion output.
+		OutputFilter struct {
+			// Patterns are Go regular expressions; empty disables the filter.
+			Patterns []string `mapstructure:"patterns"`
+			// Action is "redact" (default) or "block".
+			Action      string `mapstructure:"action"`
+			Replacement string `mapstructure:"replacement"`
+			// StreamHoldback is how many bytes of streamed text are held back to
+			// catch matches split across chunks; 0 keeps the default.
+			StreamHoldback int `mapstructure:"stream_holdback"`
+		} `mapstructure:"output_filter"`
+
+		// WireLog captures raw upstream requests and responses for debugging.
+		// Never enable in production: bodies hold user prompts and output.
+		WireLog struct {
+			Enabled bool `mapstructure:"enabled"`
+			// Providers limits capture to these providers; empty captures all.
+			Providers []string `mapstructure:"providers"`
+			// Path appends JSON records to this file; empty writes to the debug log.
+			Path string `mapstructure:"path"`
+			// RedactContent replaces prompt and output text; credentials are always redacted.
+			RedactContent bool `mapstructure:"redact_content"`
+			// MaxBodyBytes caps logged bytes per body; 0 keeps the default.
+			MaxBodyBytes int `mapstructure:"max_body_bytes"`
+		} `mapstructure:"wire_log"`
+
+		// Warmup connects to every provider on startup so the first requests
+		// skip DNS and TLS handshakes. Failures are logged, never fatal.
+		Warmup struct {
+			Enabled bool `mapstructure:"enabled"`
+			// Timeout bounds the whole warmup; 0 means 5s.
+			Timeout time.Duration `mapstructure:"timeout"`
+		} `mapstructure:"warmup"`
+
 		Models []struct {
 			ID            string   `mapstructure:"id"`
 			Name          string   `mapstructure:"name"`
 			Provider      string   `mapstructure:"provider"`
 			Capabilities  []string `mapstructure:"capabilities"`
 			UpstreamModel string   `mapstructure:"upstream_model"`
+			// DefaultTemperature applies when a chat request doesn't set temperature.
+			DefaultTemperature *float64 `mapstructure:"default_temperature"`
+			// SystemPrompt is prepended to chat requests without a system message;
+			// with SystemPromptAlways, to every chat request.
+			SystemPrompt       string `mapstructure:"system_prompt"`
+			SystemPromptAlways bool   `mapstructure:"system_prompt_always"`
+			// StripTags are tag names (e.g. "think") whose blocks are removed
+			// from chat output; KeepRawContent returns the unstripped text too.
+			StripTags      []string `mapstructure:"strip_tags"`
+			KeepRawContent bool     `mapstructure:"keep_raw_content"`
+			// ContextWindow, MaxOutputTokens and the USD prices per million tokens
+			// are returned by GetModel; 0 means unknown.
+			ContextWindow         uint32  `mapstructure:"context_window"`
+			MaxOutputTokens       uint32  `mapstructure:"max_output_tokens"`
+			InputPricePerMillion  float64 `mapstructure:"input_price_per_million"`
+			OutputPricePerMillion float64 `mapstructure:"output_price_per_million"`
+			// RequestsPerMinute caps the model's requests across all subjects;
+			// 0 disables it. RateLimitMaxWait queues requests over the cap for
+			// up to that long before rejecting them.
+			RequestsPerMinute float64       `mapstructure:"requests_per_minute"`
+			RateLimitBurst    int           `mapstructure:"rate_limit_burst"`
+			RateLimitMaxWait  time.Duration `mapstructure:"rate_limit_max_wait"`
 		} `mapstructure:"models"`
+
+		Limits struct {
+			MaxTemperature float64 `mapstructure:"max_temperature"`
+			// MaxEmbeddingsInputs / MaxEmbeddingsInputBytes bound one embeddings request; 0 keeps the defaults.
+			MaxEmbeddingsInputs     int `mapstructure:"max_embeddings_inputs"`
+			MaxEmbeddingsInputBytes int `mapstructure:"max_embeddings_input_bytes"`
+			// MaxContentPartsPerMessage / MaxContentPartsPerRequest bound chat content parts
+			// (e.g. images); 0 keeps the defaults.
+			MaxContentPartsPerMessage int `mapstructure:"max_content_parts_per_message"`
+			MaxContentPartsPerRequest int `mapstructure:"max_content_parts_per_request"`
+			// UpstreamConcurrency caps concurrent upstream chat calls (flex tier yields); 0 = unbounded.
+			UpstreamConcurrency int `mapstructure:"upstream_concurrency"`
+			// EmbeddingsBatchSize caps inputs per upstream embeddings call; 0 sends all at once.
+			EmbeddingsBatchSize int `mapstructure:"embeddings_batch_size"`
+			// EmbeddingsAutoChunkChars is the chunk size in runes for auto_chunk
+			// embeddings requests; 0 disables auto_chunk.
+			EmbeddingsAutoChunkChars int `mapstructure:"embeddings_auto_chunk_chars"`
+			// CheckContextWindow counts chat prompts (upstream when the provider
+			// can) and rejects those over the model's context_window.
+			CheckContextWindow bool `mapstructure:"check_context_window"`
+		} `mapstructure:"limits"`
+
+		Retry struct {
+			MaxAttempts int           `mapstructure:"max_attempts"`
+			BaseBackoff time.Duration `mapstructure:"base_backoff"`
+			MaxBackoff  time.Duration `mapstructure:"max_backoff"`
+			// RetryConnectionReset retries embeddings once when the upstream
+			// connection is reset; chat and completions are never retried on it.
+			RetryConnectionReset bool `mapstructure:"retry_connection_reset"`
+			// RequestTimeout bounds a whole unary request across all attempts
+			// and backoff; 0 relies on the caller's deadline only.
+			RequestTimeout time.Duration `mapstructure:"request_timeout"`
+			// UpstreamTimeout bounds each upstream attempt independently of the
+			// client; embeddings run detached from the client, bounded by it alone.
+			UpstreamTimeout time.Duration `mapstructure:"upstream_timeout"`
+		} `mapstructure:"retry"`
+		// EmbeddingsCache keeps recent embeddings responses; 0 size or ttl disables it.
+		EmbeddingsCache struct {
+			Size int           `mapstructure:"size"`
+			TTL  time.Duration `mapstructure:"ttl"`
+		} `mapstructure:"embeddings_cache"`
 	} `mapstructure:"llm"`
 }
 
 func LoadGRPC() (GRPCAppConfig, error) {
-	cfg := GRPCAppConfig{}
-
 	v := app.Config()
 	if v == nil {
-		return cfg, fmt.Errorf("app.Config() is nil: did you call app.Init(...) first?")
+		return GRPCAppConfig{}, fmt.Errorf("app.Config() is nil: did you call app.Init(...) first?")
 	}
+	return loadGRPC(v)
+}
 
+func loadGRPC(v *viper.Viper) (GRPCAppConfig, error) {
+	cfg := GRPCAppConfig{}
 	if err := unmarshalViper(v, &cfg); err != nil {
 		return cfg, err
 	}
@@ -67,15 +383,203 @@ func LoadGRPC() (GRPCAppConfig, error) {
 	if cfg.Health.Listen == "" {
 		return cfg, fmt.Errorf("missing config: health.listen")
 	}
+	if cfg.GRPC.MaxConcurrentRequests < 0 {
+		return cfg, fmt.Errorf("invalid config: grpc.max_concurrent_requests must not be negative")
+	}
+	if cfg.GRPC.MaxUpstreamTimeout < 0 {
+		return cfg, fmt.Errorf("invalid config: grpc.max_upstream_timeout must not be negative")
+	}
+	if cfg.GRPC.MaxStreamsPerSubject < 0 {
+		return cfg, fmt.Errorf("invalid config: grpc.max_streams_per_subject must not be negative")
+	}
+	if cfg.GRPC.AdmissionQueueSize < 0 || cfg.GRPC.AdmissionQueueTimeout < 0 {
+		return cfg, fmt.Errorf("invalid config: grpc.admission_queue_size and grpc.admission_queue_timeout must not be negative")
+	}
+	if cfg.GRPC.ShutdownTimeout < 0 {
+		return cfg, fmt.Errorf("invalid config: grpc.shutdown_timeout must not be negative")
+	}
+	if cfg.GRPC.ShutdownTimeout == 0 {
+		cfg.GRPC.ShutdownTimeout = 5 * time.Second
+	}
+	if k := cfg.GRPC.Keepalive; k.MaxConnectionIdle < 0 || k.MaxConnectionAge < 0 || k.MaxConnectionAgeGrace < 0 ||
+		k.Time < 0 || k.Timeout < 0 || k.MinPingInterval < 0 {
+		return cfg, fmt.Errorf("invalid config: grpc.keepalive durations must not be negative")
+	}
+	if cfg.GRPC.MaintenanceRetryAfter < 0 {
+		return cfg, fmt.Errorf("invalid config: grpc.maintenance_retry_after must not be negative")
+	}
+	if cfg.GRPC.MaintenanceRetryAfter == 0 {
+		cfg.GRPC.MaintenanceRetryAfter = 30 * time.Second
+	}
+	if cfg.UsageCallback.BatchSize < 0 || cfg.UsageCallback.BatchInterval < 0 {
+		return cfg, fmt.Errorf("invalid config: usage_callback.batch_size and usage_callback.batch_interval must not be negative")
+	}
 	if cfg.LLM.Providers.DashScope.BaseURL == "" {
 		cfg.LLM.Providers.DashScope.BaseURL = "https://dashscope.aliyuncs.com/compatible-mode/v1"
 	}
 	if cfg.LLM.Providers.OpenRouter.BaseURL == "" {
 		cfg.LLM.Providers.OpenRouter.BaseURL = "https://openrouter.ai/api/v1"
 	}
+	for _, t := range cfg.Auth.ServiceTokens {
+		if t.Audit && cfg.Audit.Path == "" {
+			return cfg, fmt.Errorf("missing config: audit.path (required when a service token enables audit)")
+		}
+	}
+	if cfg.Generations.MemoryCapacity < 0 {
+		return cfg, fmt.Errorf("invalid config: generations.memory_capacity must not be negative")
+	}
+	switch cfg.Generations.Store {
+	case "", "memory":
+	case "postgres":
+		if cfg.Generations.Postgres.DSN == "" {
+			return cfg, fmt.Errorf("missing config: generations.postgres.dsn (required when generations.store is postgres)")
+		}
+		if pg := cfg.Generations.Postgres; pg.MaxOpenConns < 0 || pg.MaxIdleConns < 0 || pg.ConnMaxLifetime < 0 {
+			return cfg, fmt.Errorf("invalid config: generations.postgres pool settings must not be negative")
+		}
+	default:
+		return cfg, fmt.Errorf("invalid config: generations.store must be memory or postgres")
+	}
+	if cfg.Log.DebugSampleRate < 0 || cfg.Log.DebugSampleRate > 1 {
+		return cfg, fmt.Errorf("invalid config: log.debug_sample_rate must be between 0 and 1")
+	}
+	for i, b := range cfg.Metrics.LatencyBuckets {
+		if b <= 0 || (i > 0 && b <= cfg.Metrics.LatencyBuckets[i-1]) {
+			return cfg, fmt.Errorf("invalid config: metrics.latency_buckets must be positive and strictly ascending")
+		}
+	}
+	if cfg.Metrics.SLO.P99Threshold < 0 || cfg.Metrics.SLO.Window < 0 {
+		return cfg, fmt.Errorf("invalid config: metrics.slo durations must not be negative")
+	}
+	if h := cfg.LLM.HTTP; h.DialTimeout < 0 || h.TLSHandshakeTimeout < 0 || h.IdleConnTimeout < 0 || h.MaxIdleConnsPerHost < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.http timeouts and pool sizes must not be negative")
+	}
+	switch cfg.LLM.EmptyResponse {
+	case "", "pass_through", "retry", "error":
+	default:
+		return cfg, fmt.Errorf("invalid config: llm.empty_response must be pass_through, retry or error")
+	}
+	if dups := duplicateModelIDs(cfg); len(dups) > 0 {
+		return cfg, fmt.Errorf("invalid config: duplicate llm.models ids: %s", strings.Join(dups, ", "))
+	}
+	for _, m := range cfg.LLM.Models {
+		if m.InputPricePerMillion < 0 || m.OutputPricePerMillion < 0 {
+			return cfg, fmt.Errorf("invalid config: llm.models %s prices must not be negative", m.ID)
+		}
+	}
+	for _, p := range cfg.LLM.OutputFilter.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return cfg, fmt.Errorf("invalid config: llm.output_filter.patterns: %w", err)
+		}
+	}
+	switch cfg.LLM.OutputFilter.Action {
+	case "", "redact", "block":
+	default:
+		return cfg, fmt.Errorf("invalid config: llm.output_filter.action must be redact or block")
+	}
+	if cfg.LLM.OutputFilter.StreamHoldback < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.output_filter.stream_holdback must not be negative")
+	}
+	for _, p := range cfg.LLM.WireLog.Providers {
+		switch p {
+		case "dashscope", "openrouter", "azure":
+		default:
+			return cfg, fmt.Errorf("invalid config: llm.wire_log.providers: unknown provider %q", p)
+		}
+	}
+	if cfg.LLM.WireLog.MaxBodyBytes < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.wire_log.max_body_bytes must not be negative")
+	}
+	if cfg.LLM.Warmup.Timeout < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.warmup.timeout must not be negative")
+	}
+	if cfg.LLM.Warmup.Timeout == 0 {
+		cfg.LLM.Warmup.Timeout = 5 * time.Second
+	}
+	for _, m := range cfg.LLM.Models {
+		if m.RequestsPerMinute < 0 || m.RateLimitBurst < 0 || m.RateLimitMaxWait < 0 {
+			return cfg, fmt.Errorf("invalid config: llm.models %q rate limit settings must not be negative", m.ID)
+		}
+	}
+	if err := validateTenantPools(cfg); err != nil {
+		return cfg, err
+	}
+	if err := validateSecrets(cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.LLM.MaxResponseBytes < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.max_response_bytes must not be negative")
+	}
+	if cfg.LLM.ModelRefreshInterval < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.model_refresh_interval must not be negative")
+	}
+	if cfg.LLM.ModelRefreshInterval == 0 {
+		cfg.LLM.ModelRefreshInterval = 10 * time.Minute
+	}
+	if cfg.LLM.Providers.DashScope.StreamIdleTimeout < 0 || cfg.LLM.Providers.OpenRouter.StreamIdleTimeout < 0 ||
+		cfg.LLM.Providers.Azure.StreamIdleTimeout < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.providers.*.stream_idle_timeout must not be negative")
+	}
+	if slowRequestThresholdNegative(cfg) {
+		return cfg, fmt.Errorf("invalid config: llm.slow_request_threshold must not be negative")
+	}
+	if weightedKeysNegative(cfg) {
+		return cfg, fmt.Errorf("invalid config: llm.providers.*.weighted_keys weight and daily_quota must not be negative")
+	}
+	if cfg.LLM.Limits.MaxEmbeddingsInputs < 0 || cfg.LLM.Limits.MaxEmbeddingsInputBytes < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.limits embeddings input limits must not be negative")
+	}
+	if cfg.LLM.Limits.MaxContentPartsPerMessage < 0 || cfg.LLM.Limits.MaxContentPartsPerRequest < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.limits content part limits must not be negative")
+	}
+	if cfg.LLM.Limits.UpstreamConcurrency < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.limits.upstream_concurrency must not be negative")
+	}
+	if cfg.LLM.Limits.EmbeddingsBatchSize < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.limits.embeddings_batch_size must not be negative")
+	}
+	if cfg.LLM.Retry.RequestTimeout < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.retry.request_timeout must not be negative")
+	}
+	if cfg.LLM.Retry.UpstreamTimeout < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.retry.upstream_timeout must not be negative")
+	}
+	if cfg.LLM.EmbeddingsCache.Size < 0 || cfg.LLM.EmbeddingsCache.TTL < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.embeddings_cache.size and llm.embeddings_cache.ttl must not be negative")
+	}
+	if cfg.LLM.Limits.EmbeddingsAutoChunkChars < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.limits.embeddings_auto_chunk_chars must not be negative")
+	}
+	if cfg.LLM.Limits.MaxTemperature < 0 {
+		return cfg, fmt.Errorf("invalid config: llm.limits.max_temperature must not be negative")
+	}
+	for _, m := range cfg.LLM.Models {
+		if t := m.DefaultTemperature; t != nil && (*t < 0 || (cfg.LLM.Limits.MaxTemperature > 0 && *t > cfg.LLM.Limits.MaxTemperature)) {
+			return cfg, fmt.Errorf("invalid config: llm.models[%s].default_temperature must be between 0 and llm.limits.max_temperature", m.ID)
+		}
+	}
+	if cfg.LLM.DefaultUserFromSubject && cfg.LLM.DefaultUserSalt == "" {
+		return cfg, fmt.Errorf("missing config: llm.default_user_salt (required when llm.default_user_from_subject is enabled)")
+	}
 	if cfg.Auth.TempTTL == 0 {
 		cfg.Auth.TempTTL = 15 * time.Minute
 	}
+	if cfg.Auth.ClockSkew < 0 {
+		return cfg, fmt.Errorf("invalid config: auth.clock_skew must not be negative")
+	}
+	if cfg.Auth.ClockSkew == 0 {
+		cfg.Auth.ClockSkew = 5 * time.Minute
+	}
+	if cfg.Auth.IssuePerMinute == nil {
+		perMinute := 60.0
+		cfg.Auth.IssuePerMinute = &perMinute
+	}
+	if *cfg.Auth.IssuePerMinute < 0 || cfg.Auth.IssueBurst < 0 {
+		return cfg, fmt.Errorf("invalid config: auth.issue_per_minute and auth.issue_burst must not be negative")
+	}
+	if cfg.Auth.IssueBurst == 0 {
+		cfg.Auth.IssueBurst = 10
+	}
 
 	return cfg, nil
 }
@@ -83,6 +587,19 @@ func LoadGRPC() (GRPCAppConfig, error) {
 type HTTPAppConfig struct {
 	HTTP struct {
 		Listen string `mapstructure:"listen"`
+		// ShutdownTimeout bounds draining in-flight requests and streams on shutdown.
+		ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+		// JSONFieldNames is the response field name style: "snake_case" (default)
+		// or "camel_case". Requests accept both.
+		JSONFieldNames string `mapstructure:"json_field_names"`
+		// ModelsCacheTTL caches GET /v1/models per service token; 0 disables it.
+		ModelsCacheTTL time.Duration `mapstructure:"models_cache_ttl"`
+		// ValidateRequests checks chat and embeddings bodies against the
+		// embedded JSON schemas and rejects violations with a 400.
+		ValidateRequests bool `mapstructure:"validate_requests"`
+		// TrailerHeaders are gRPC trailers (e.g. x-usage-total-tokens) copied
+		// into unary HTTP response headers.
+		TrailerHeaders []string `mapstructure:"trailer_headers"`
 	} `mapstructure:"http"`
 
 	GRPC struct {
@@ -109,13 +626,144 @@ func LoadHTTP() (HTTPAppConfig, error) {
 	if cfg.GRPC.Target == "" {
 		return cfg, fmt.Errorf("missing config: grpc.target")
 	}
+	if cfg.HTTP.ShutdownTimeout < 0 {
+		return cfg, fmt.Errorf("invalid config: http.shutdown_timeout must not be negative")
+	}
+	if cfg.HTTP.ModelsCacheTTL < 0 {
+		return cfg, fmt.Errorf("invalid config: http.models_cache_ttl must not be negative")
+	}
+	if cfg.HTTP.ShutdownTimeout == 0 {
+		cfg.HTTP.ShutdownTimeout = 5 * time.Second
+	}
 
 	return cfg, nil
 }
 
+// duplicateModelIDs returns each llm.models id that appears more than once, in
+// config order. Without this check the last entry would silently win.
+func duplicateModelIDs(cfg GRPCAppConfig) []string {
+	seen := make(map[string]int, len(cfg.LLM.Models))
+	var dups []string
+	for _, m := range cfg.LLM.Models {
+		seen[m.ID]++
+		if seen[m.ID] == 2 {
+			dups = append(dups, m.ID)
+		}
+	}
+	return dups
+}
+
+// slowRequestThresholdNegative reports whether the global or any per-provider
+// slow request threshold is negative.
+func slowRequestThresholdNegative(cfg GRPCAppConfig) bool {
+	p := cfg.LLM.Providers
+	for _, d := range []*time.Duration{p.DashScope.SlowRequestThreshold, p.OpenRouter.SlowRequestThreshold, p.Azure.SlowRequestThreshold} {
+		if d != nil && *d < 0 {
+			return true
+		}
+	}
+	return cfg.LLM.SlowRequestThreshold < 0
+}
+
+// TenantPool routes the listed subjects, or requests naming it in the
+// x-tenant header, to providers with other credentials.
+type TenantPool struct {
+	Name string `mapstructure:"name"`
+	// Subjects (service token names) that use this pool; a subject in several
+	// pools uses the first unless it sends x-tenant.
+	Subjects []string `mapstructure:"subjects"`
+	// Providers overrides credentials per provider name ("dashscope",
+	// "openrouter", "azure"). Other settings are the default provider's, and
+	// providers not listed here are shared with the default pool.
+	Providers map[string]TenantProvider `mapstructure:"providers"`
+}
+
+// TenantProvider is a tenant pool's credentials for one provider.
+type TenantProvider struct {
+	// BaseURL overrides the default provider's base URL when set.
+	BaseURL string   `mapstructure:"base_url"`
+	APIKeys []string `mapstructure:"api_keys"`
+	// APIKeySecrets names secrets resolved into additional keys.
+	APIKeySecrets []string `mapstructure:"api_key_secrets"`
+}
+
+// WeightedAPIKey is an upstream API key with its share of the provider's traffic.
+type WeightedAPIKey struct {
+	Key string `mapstructure:"key"`
+	// Weight is the key's relative share of traffic; 0 counts as 1.
+	Weight int `mapstructure:"weight"`
+	// DailyQuota caps requests sent with the key per UTC day; 0 means unlimited.
+	DailyQuota int `mapstructure:"daily_quota"`
+}
+
+// weightedKeysNegative reports whether any provider's weighted key has a
+// negative weight or daily quota.
+func weightedKeysNegative(cfg GRPCAppConfig) bool {
+	p := cfg.LLM.Providers
+	for _, keys := range [][]WeightedAPIKey{p.DashScope.WeightedKeys, p.OpenRouter.WeightedKeys, p.Azure.WeightedKeys} {
+		for _, k := range keys {
+			if k.Weight < 0 || k.DailyQuota < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func unmarshalViper(v *viper.Viper, out any) error {
 	if err := v.Unmarshal(out); err != nil {
 		return fmt.Errorf("unmarshal config: %w", err)
 	}
 	return nil
 }
+
+func validateTenantPools(cfg GRPCAppConfig) error {
+	seen := make(map[string]bool, len(cfg.LLM.TenantPools))
+	for _, p := range cfg.LLM.TenantPools {
+		if p.Name == "" || seen[p.Name] {
+			return fmt.Errorf("invalid config: llm.tenant_pools names must be unique and non-empty")
+		}
+		seen[p.Name] = true
+		if len(p.Providers) == 0 {
+			return fmt.Errorf("invalid config: llm.tenant_pools %q has no providers", p.Name)
+		}
+		for name, tp := range p.Providers {
+			switch name {
+			case "dashscope", "openrouter":
+			case "azure":
+				if cfg.LLM.Providers.Azure.BaseURL == "" {
+					return fmt.Errorf("invalid config: llm.tenant_pools %q overrides azure, which is not configured", p.Name)
+				}
+			default:
+				return fmt.Errorf("invalid config: llm.tenant_pools %q has unknown provider %q", p.Name, name)
+			}
+			if len(tp.APIKeys) == 0 && len(tp.APIKeySecrets) == 0 {
+				return fmt.Errorf("invalid config: llm.tenant_pools %q provider %q needs api_keys or api_key_secrets", p.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSecrets(cfg GRPCAppConfig) error {
+	switch cfg.Secrets.Provider {
+	case "":
+		p := cfg.LLM.Providers
+		uses := len(p.DashScope.APIKeySecrets) > 0 || len(p.OpenRouter.APIKeySecrets) > 0 || len(p.Azure.APIKeySecrets) > 0
+		for _, tp := range cfg.LLM.TenantPools {
+			for _, prov := range tp.Providers {
+				uses = uses || len(prov.APIKeySecrets) > 0
+			}
+		}
+		if uses {
+			return fmt.Errorf("invalid config: api_key_secrets require secrets.provider")
+		}
+	case "file":
+		if cfg.Secrets.Dir == "" {
+			return fmt.Errorf("invalid config: secrets.dir is required for the file provider")
+		}
+	default:
+		return fmt.Errorf("invalid config: secrets.provider must be file")
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadGRPC_DuplicateModelIDs(t *testing.T) {
+	t.Parallel()
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	err := v.ReadConfig(strings.NewReader(`
+[grpc]
+listen = ":50051"
+
+[health]
+listen = ":8081"
+
+[[llm.models]]
+id = "dashscope/qwen-plus"
+provider = "dashscope"
+
+[[llm.models]]
+id = "openrouter/gpt-4o"
+provider = "openrouter"
+
+[[llm.models]]
+id = "dashscope/qwen-plus"
+provider = "dashscope"
+upstream_model = "qwen-plus-latest"
+
+[[llm.models]]
+id = "openrouter/gpt-4o"
+provider = "openrouter"
+
+[[llm.models]]
+id = "dashscope/qwen-plus"
+provider = "dashscope"
+`))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+
+	_, err = loadGRPC(v)
+	if err == nil {
+		t.Fatal("expected error for duplicate model ids")
+	}
+	want := "invalid config: duplicate llm.models ids: dashscope/qwen-plus, openrouter/gpt-4o"
+	if err.Error() != want {
+		t.Fatalf("unexpected error:\n got: %v\nwant: %s", err, want)
+	}
+}
+
+func TestLoadGRPC_ShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		toml string
+		want time.Duration
+	}{
+		{name: "default", want: 5 * time.Second},
+		{name: "configured", toml: `shutdown_timeout = "2m"`, want: 2 * time.Minute},
+	} {
+		v := viper.New()
+		v.SetConfigType("toml")
+		err := v.ReadConfig(strings.NewReader(`
+[grpc]
+listen = ":50051"
+` + tt.toml + `
+
+[health]
+listen = ":8081"
+`))
+		if err != nil {
+			t.Fatalf("%s: read config: %v", tt.name, err)
+		}
+		cfg, err := loadGRPC(v)
+		if err != nil {
+			t.Fatalf("%s: loadGRPC: %v", tt.name, err)
+		}
+		if cfg.GRPC.ShutdownTimeout != tt.want {
+			t.Fatalf("%s: shutdown_timeout = %v, want %v", tt.name, cfg.GRPC.ShutdownTimeout, tt.want)
+		}
+	}
+}
+
+func TestLoadGRPC_IssueRateLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		toml string
+		want float64
+	}{
+		{name: "default", want: 60},
+		{name: "configured", toml: "issue_per_minute = 5", want: 5},
+		{name: "disabled", toml: "issue_per_minute = 0", want: 0},
+	} {
+		v := viper.New()
+		v.SetConfigType("toml")
+		err := v.ReadConfig(strings.NewReader(`
+[grpc]
+listen = ":50051"
+
+[health]
+listen = ":8081"
+
+[auth]
+` + tt.toml + `
+`))
+		if err != nil {
+			t.Fatalf("%s: read config: %v", tt.name, err)
+		}
+		cfg, err := loadGRPC(v)
+		if err != nil {
+			t.Fatalf("%s: loadGRPC: %v", tt.name, err)
+		}
+		if got := *cfg.Auth.IssuePerMinute; got != tt.want {
+			t.Fatalf("%s: issue_per_minute = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadGRPC_DefaultUserSaltRequired(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name    string
+		toml    string
+		wantErr bool
+	}{
+		{name: "disabled", toml: "default_user_from_subject = false"},
+		{name: "enabled with salt", toml: "default_user_from_subject = true\ndefault_user_salt = \"s3cret\""},
+		{name: "enabled without salt", toml: "default_user_from_subject = true", wantErr: true},
+	} {
+		v := viper.New()
+		v.SetConfigType("toml")
+		err := v.ReadConfig(strings.NewReader(`
+[grpc]
+listen = ":50051"
+
+[health]
+listen = ":8081"
+
+[llm]
+` + tt.toml + `
+`))
+		if err != nil {
+			t.Fatalf("%s: read config: %v", tt.name, err)
+		}
+		_, err = loadGRPC(v)
+		if tt.wantErr != (err != nil) {
+			t.Fatalf("%s: loadGRPC error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "llm.default_user_salt") {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
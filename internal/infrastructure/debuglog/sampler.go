@@ -0,0 +1,92 @@
+// Package debuglog emits verbose debug logs for a deterministic sample of requests.
+package debuglog
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDHeader = "x-request-id"
+
+// Sampler selects a fixed fraction of requests by hashing their request ID, so
+// the same request ID is always sampled (or not) and can be reproduced.
+type Sampler struct {
+	threshold uint64 // sampled iff hash%sampleSpace < threshold
+}
+
+const sampleSpace = 1_000_000
+
+// NewSampler samples rate (0..1) of requests. A rate <= 0 returns nil, which samples nothing.
+func NewSampler(rate float64) *Sampler {
+	if rate <= 0 {
+		return nil
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	return &Sampler{threshold: uint64(rate * sampleSpace)}
+}
+
+func (s *Sampler) Sampled(requestID string) bool {
+	if s == nil || requestID == "" {
+		return false
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(requestID))
+	return h.Sum64()%sampleSpace < s.threshold
+}
+
+// UnaryServerInterceptor logs routing, timing and token counts (never prompt text)
+// at debug level for sampled requests. It must run before the request ID
+// interceptor: requests without an x-request-id get one assigned here so the
+// sampling decision and the request's logs share the same ID.
+func UnaryServerInterceptor(s *Sampler, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if s == nil {
+			return handler(ctx, req)
+		}
+		ctx, requestID := ensureRequestID(ctx)
+		if !s.Sampled(requestID) {
+			return handler(ctx, req)
+		}
+
+		ctx, tr := llmgateway.WithDebugTrace(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		total := time.Since(start)
+
+		logger.DebugContext(ctx, "sampled request",
+			"request_id", requestID,
+			"method", info.FullMethod,
+			"code", status.Code(err).String(),
+			"provider", tr.Provider,
+			"upstream_model", tr.UpstreamModel,
+			"attempts", tr.Attempts,
+			"upstream_ms", tr.UpstreamTime.Milliseconds(),
+			"gateway_ms", (total - tr.UpstreamTime).Milliseconds(),
+			"total_ms", total.Milliseconds(),
+			"prompt_tokens", tr.PromptTokens,
+			"completion_tokens", tr.CompletionTokens,
+		)
+		return resp, err
+	}
+}
+
+func ensureRequestID(ctx context.Context) (context.Context, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(requestIDHeader); len(ids) > 0 && ids[0] != "" {
+		return ctx, ids[0]
+	}
+	id := uuid.New().String()
+	md = md.Copy()
+	md.Set(requestIDHeader, id)
+	return metadata.NewIncomingContext(ctx, md), id
+}
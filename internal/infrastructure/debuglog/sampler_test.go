@@ -0,0 +1,43 @@
+package debuglog
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSampler_SelectsConfiguredFraction(t *testing.T) {
+	t.Parallel()
+
+	const n = 100_000
+	for _, rate := range []float64{0.01, 0.1, 0.5} {
+		s := NewSampler(rate)
+		sampled := 0
+		for i := 0; i < n; i++ {
+			if s.Sampled(fmt.Sprintf("req-%d", i)) {
+				sampled++
+			}
+		}
+		got := float64(sampled) / n
+		if got < rate*0.8 || got > rate*1.2 {
+			t.Fatalf("rate %v: sampled fraction %v out of tolerance", rate, got)
+		}
+	}
+}
+
+func TestSampler_DeterministicPerRequestID(t *testing.T) {
+	t.Parallel()
+
+	a, b := NewSampler(0.3), NewSampler(0.3)
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("req-%d", i)
+		if a.Sampled(id) != b.Sampled(id) || a.Sampled(id) != a.Sampled(id) {
+			t.Fatalf("sampling of %q is not deterministic", id)
+		}
+	}
+	if NewSampler(0).Sampled("req-1") {
+		t.Fatalf("rate 0 must sample nothing")
+	}
+	if !NewSampler(1).Sampled("req-1") {
+		t.Fatalf("rate 1 must sample everything")
+	}
+}
@@ -0,0 +1,95 @@
+package generationstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// DefaultMemoryCapacity bounds the in-memory store when no capacity is given.
+const DefaultMemoryCapacity = 10000
+
+// Memory implements application.llmgateway.GenerationRepository in process
+// memory. It keeps the most recent capacity records; older ones are evicted
+// first. Records are lost on restart.
+type Memory struct {
+	mu       sync.Mutex
+	capacity int
+	byID     map[string]llm.Generation
+	order    []string // ids, oldest first
+	// byConversation indexes ids per conversation, oldest first.
+	byConversation map[string][]string
+}
+
+// NewMemory builds a store holding at most capacity records
+// (DefaultMemoryCapacity if capacity <= 0).
+func NewMemory(capacity int) *Memory {
+	if capacity <= 0 {
+		capacity = DefaultMemoryCapacity
+	}
+	return &Memory{
+		capacity:       capacity,
+		byID:           make(map[string]llm.Generation),
+		byConversation: make(map[string][]string),
+	}
+}
+
+func (m *Memory) Save(_ context.Context, gen llm.Generation) error {
+	if gen.ID == "" {
+		return llm.InvalidArgument("generation id is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.byID[gen.ID]; ok {
+		// Overwrite in place; the record keeps its age and index entry.
+		m.byID[gen.ID] = gen
+		return nil
+	}
+	m.byID[gen.ID] = gen
+	m.order = append(m.order, gen.ID)
+	if gen.ConversationID != "" {
+		m.byConversation[gen.ConversationID] = append(m.byConversation[gen.ConversationID], gen.ID)
+	}
+	for len(m.order) > m.capacity {
+		m.evictOldest()
+	}
+	return nil
+}
+
+func (m *Memory) evictOldest() {
+	id := m.order[0]
+	m.order = m.order[1:]
+	gen := m.byID[id]
+	delete(m.byID, id)
+	if c := gen.ConversationID; c != "" {
+		ids := m.byConversation[c][1:] // the oldest record is first in its conversation too
+		if len(ids) == 0 {
+			delete(m.byConversation, c)
+		} else {
+			m.byConversation[c] = ids
+		}
+	}
+}
+
+func (m *Memory) Get(_ context.Context, id string) (llm.Generation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	gen, ok := m.byID[id]
+	if !ok {
+		return llm.Generation{}, llm.NotFound("generation " + id)
+	}
+	return gen, nil
+}
+
+func (m *Memory) ListGenerationsByConversation(_ context.Context, conversationID string) ([]llm.Generation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := m.byConversation[conversationID]
+	out := make([]llm.Generation, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, m.byID[id])
+	}
+	return out, nil
+}
@@ -0,0 +1,41 @@
+package generationstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+func TestMemory_ListGenerationsByConversation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewMemory(3)
+	for _, g := range []llm.Generation{
+		{ID: "g1", ConversationID: "c1"},
+		{ID: "g2", ConversationID: "c2"},
+		{ID: "g3", ConversationID: "c1"},
+		{ID: "g4"},
+	} {
+		if err := m.Save(ctx, g); err != nil {
+			t.Fatalf("Save(%s): %v", g.ID, err)
+		}
+	}
+
+	// g1 was evicted and must disappear from the conversation index too.
+	if _, err := m.Get(ctx, "g1"); !errors.Is(err, llm.ErrNotFound) {
+		t.Fatalf("expected not found for evicted record, got %v", err)
+	}
+	gens, err := m.ListGenerationsByConversation(ctx, "c1")
+	if err != nil {
+		t.Fatalf("ListGenerationsByConversation: %v", err)
+	}
+	if len(gens) != 1 || gens[0].ID != "g3" {
+		t.Fatalf("unexpected generations: %+v", gens)
+	}
+	if gens, _ := m.ListGenerationsByConversation(ctx, "missing"); len(gens) != 0 {
+		t.Fatalf("expected no generations, got %+v", gens)
+	}
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+)
+
+// DetailSource reports provider and model state for the detail endpoint.
+// *llmgateway.Service implements it.
+type DetailSource interface {
+	ProviderStatuses() []llmgateway.ProviderStatus
+	ModelCount() int
+}
+
+type detailReport struct {
+	Models    int              `json:"models"`
+	Providers []providerDetail `json:"providers"`
+}
+
+type providerDetail struct {
+	Name            string  `json:"name"`
+	LastSuccessUnix int64   `json:"last_success_unix,omitempty"`
+	LastErrorUnix   int64   `json:"last_error_unix,omitempty"`
+	RecentRequests  int     `json:"recent_requests"`
+	RecentErrorRate float64 `json:"recent_error_rate"`
+}
+
+// Detail serves a JSON triage view (e.g. /healthz/detail) with per-provider status.
+func Detail(src DetailSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		statuses := src.ProviderStatuses()
+		report := detailReport{
+			Models:    src.ModelCount(),
+			Providers: make([]providerDetail, 0, len(statuses)),
+		}
+		for _, st := range statuses {
+			d := providerDetail{
+				Name:            st.Name,
+				RecentRequests:  st.RecentRequests,
+				RecentErrorRate: st.RecentErrorRate,
+			}
+			if !st.LastSuccess.IsZero() {
+				d.LastSuccessUnix = st.LastSuccess.Unix()
+			}
+			if !st.LastError.IsZero() {
+				d.LastErrorUnix = st.LastError.Unix()
+			}
+			report.Providers = append(report.Providers, d)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+type stubProvider struct{ err error }
+
+func (p stubProvider) CreateChatCompletion(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	return llm.ChatCompletionResponse{}, p.err
+}
+
+func (p stubProvider) CreateEmbeddings(context.Context, llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	return llm.EmbeddingsResponse{}, p.err
+}
+
+func TestDetail_ReportsEveryProvider(t *testing.T) {
+	t.Parallel()
+
+	svc := llmgateway.NewService(map[string]llmgateway.Provider{
+		"good": stubProvider{},
+		"bad":  stubProvider{err: errors.New("upstream down")},
+		"idle": stubProvider{},
+	}, []llmgateway.ModelSpec{{ID: "good/m", Provider: "good"}}, nil)
+	for _, model := range []string{"good/m", "bad/m"} {
+		_, _ = svc.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: model, Input: []string{"x"}})
+	}
+
+	rec := httptest.NewRecorder()
+	Detail(svc)(rec, httptest.NewRequest(http.MethodGet, "/healthz/detail", nil))
+
+	var got detailReport
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Models != 1 {
+		t.Fatalf("unexpected model count: %d", got.Models)
+	}
+	byName := map[string]providerDetail{}
+	for _, p := range got.Providers {
+		byName[p.Name] = p
+	}
+	if len(byName) != 3 {
+		t.Fatalf("expected 3 providers, got %+v", got.Providers)
+	}
+	if d := byName["good"]; d.LastSuccessUnix == 0 || d.RecentErrorRate != 0 || d.RecentRequests != 1 {
+		t.Fatalf("unexpected good provider detail: %+v", d)
+	}
+	if d := byName["bad"]; d.LastErrorUnix == 0 || d.RecentErrorRate != 1 {
+		t.Fatalf("unexpected bad provider detail: %+v", d)
+	}
+	if d := byName["idle"]; d.RecentRequests != 0 {
+		t.Fatalf("unexpected idle provider detail: %+v", d)
+	}
+}
+
+func TestReadyz_MaintenanceDrains(t *testing.T) {
+	t.Parallel()
+
+	svc := llmgateway.NewService(map[string]llmgateway.Provider{"good": stubProvider{}}, nil, nil)
+	check := func() int {
+		rec := httptest.NewRecorder()
+		Readyz(MaintenanceReadyChecker(svc))(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+
+	if code := check(); code != http.StatusOK {
+		t.Fatalf("readyz = %d before maintenance", code)
+	}
+	svc.SetMaintenance(true, 0)
+	if code := check(); code != http.StatusServiceUnavailable {
+		t.Fatalf("readyz = %d during maintenance, want 503", code)
+	}
+	rec := httptest.NewRecorder()
+	Livez(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("livez = %d during maintenance, want 200", rec.Code)
+	}
+	svc.SetMaintenance(false, 0)
+	if code := check(); code != http.StatusOK {
+		t.Fatalf("readyz = %d after maintenance", code)
+	}
+}
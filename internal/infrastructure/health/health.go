@@ -2,6 +2,7 @@ package health
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -45,3 +46,19 @@ func GRPCDialReadyChecker(target string) ReadyzChecker {
 	}
 }
 
+// MaintenanceSource reports whether the gateway is drained for maintenance.
+// *llmgateway.Service implements it.
+type MaintenanceSource interface {
+	InMaintenance() bool
+}
+
+// MaintenanceReadyChecker fails readiness while src is in maintenance mode, so
+// load balancers drain the pod while liveness stays OK.
+func MaintenanceReadyChecker(src MaintenanceSource) ReadyzChecker {
+	return func(context.Context) error {
+		if src.InMaintenance() {
+			return errors.New("draining: maintenance mode")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,214 @@
+package azureopenai
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/buildinfo"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/keypool"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/providerhttp"
+)
+
+// DefaultAPIVersion is the Azure OpenAI data-plane API version used when none is configured.
+const DefaultAPIVersion = "2024-10-21"
+
+// Provider implements application.llmgateway.Provider for Azure OpenAI.
+// Azure routes by deployment rather than model: the upstream model name of a
+// routed model (see ModelSpec.UpstreamModel) is used as the deployment name.
+type Provider struct {
+	baseURL    string // resource endpoint, e.g. https://<resource>.openai.azure.com
+	apiVersion string
+	apiKeys    *keypool.Pool
+	// weightedKeys take precedence over the plain apiKeys passed to NewProvider.
+	weightedKeys []keypool.Key
+
+	// api sends the requests; it is built by NewProvider from the fields below.
+	api               *providerhttp.API
+	streamIdleTimeout time.Duration
+	transport         http.RoundTripper // shared with other providers when set
+	// maxResponseBytes bounds non-streamed response bodies; 0 uses the default.
+	maxResponseBytes int64
+
+	userAgent string
+	headers   map[string]string // extra static headers sent upstream
+
+	requestTransformer  providerhttp.RequestTransformer
+	responseTransformer providerhttp.ResponseTransformer
+}
+
+// Option customizes optional Provider behavior.
+type Option func(*Provider)
+
+// WithUserAgent overrides the default "llm-gateway/<version>" User-Agent.
+func WithUserAgent(ua string) Option {
+	return func(p *Provider) {
+		if ua != "" {
+			p.userAgent = ua
+		}
+	}
+}
+
+// WithHeaders adds static headers to every upstream request.
+// They cannot override Content-Type or api-key.
+func WithHeaders(headers map[string]string) Option {
+	return func(p *Provider) {
+		p.headers = headers
+	}
+}
+
+// WithWeightedKeys adds keys with their own traffic weight and daily quota to
+// the rotation. A key also listed in apiKeys uses the weighted settings.
+func WithWeightedKeys(keys []keypool.Key) Option {
+	return func(p *Provider) {
+		p.weightedKeys = keys
+	}
+}
+
+// WithTransport sets the transport used for upstream requests, typically one
+// built by providerhttp.NewTransport and shared between providers.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(p *Provider) {
+		p.transport = rt
+	}
+}
+
+// WithRequestTransformer rewrites every outgoing JSON body, e.g. for custom
+// gateways fronting bespoke model endpoints.
+func WithRequestTransformer(t providerhttp.RequestTransformer) Option {
+	return func(p *Provider) {
+		p.requestTransformer = t
+	}
+}
+
+// WithResponseTransformer rewrites non-streamed JSON responses before decoding.
+func WithResponseTransformer(t providerhttp.ResponseTransformer) Option {
+	return func(p *Provider) {
+		p.responseTransformer = t
+	}
+}
+
+// WithMaxResponseBytes bounds how much of a non-streamed upstream response is
+// read; larger responses fail with providerhttp.ErrResponseTooLarge.
+// Non-positive values keep providerhttp.DefaultMaxResponseBytes.
+func WithMaxResponseBytes(n int64) Option {
+	return func(p *Provider) {
+		if n > 0 {
+			p.maxResponseBytes = n
+		}
+	}
+}
+
+// WithStreamIdleTimeout sets how long a streamed completion may go without
+// sending data before it is aborted. Non-positive values keep the default.
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(p *Provider) {
+		if d > 0 {
+			p.streamIdleTimeout = d
+		}
+	}
+}
+
+// NewProvider builds a provider for the Azure resource at baseURL that rotates
+// requests across apiKeys. An empty apiVersion uses DefaultAPIVersion.
+func NewProvider(baseURL, apiVersion string, apiKeys []string, timeout time.Duration, opts ...Option) *Provider {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if apiVersion == "" {
+		apiVersion = DefaultAPIVersion
+	}
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	p := &Provider{
+		baseURL:           baseURL,
+		apiVersion:        apiVersion,
+		streamIdleTimeout: providerhttp.DefaultStreamIdleTimeout,
+		userAgent:         buildinfo.UserAgent(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.apiKeys = keypool.NewWeighted(keypool.Combine(p.weightedKeys, apiKeys), keypool.DefaultCooldown)
+	p.api = &providerhttp.API{
+		Provider: "azureopenai",
+		Keys:     p.apiKeys,
+		// Azure authenticates with an api-key header rather than a bearer token.
+		Authorize: func(r *http.Request, apiKey string) {
+			r.Header.Set("api-key", apiKey)
+		},
+		HTTP:                providerhttp.NewClient(providerhttp.ClientOptions{Timeout: timeout, Transport: p.transport}),
+		Stream:              providerhttp.NewClient(providerhttp.ClientOptions{Transport: p.transport}),
+		StreamIdleTimeout:   p.streamIdleTimeout,
+		MaxResponseBytes:    p.maxResponseBytes,
+		UserAgent:           p.userAgent,
+		Headers:             p.headers,
+		RequestTransformer:  p.requestTransformer,
+		ResponseTransformer: p.responseTransformer,
+	}
+	return p
+}
+
+func (p *Provider) CreateChatCompletion(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	var out providerhttp.ChatResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.deploymentURL(ctx, req.Model, "chat/completions"), providerhttp.NewChatRequest(req, false), &out)
+	if err != nil {
+		return llm.ChatCompletionResponse{}, err
+	}
+	return out.ChatCompletion(req, requestID), nil
+}
+
+// CreateChatCompletionStream streams a chat completion, calling emit for each chunk.
+// The stream is bounded by the idle timeout rather than the unary request timeout.
+func (p *Provider) CreateChatCompletionStream(ctx context.Context, req llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error {
+	return p.api.DoStream(ctx, p.deploymentURL(ctx, req.Model, "chat/completions"), providerhttp.NewChatRequest(req, true), func(data []byte) error {
+		c, err := providerhttp.DecodeChatChunk(data)
+		if err != nil {
+			return err
+		}
+		return emit(c)
+	})
+}
+
+// CreateCompletion runs a legacy text completion against the upstream /completions endpoint.
+func (p *Provider) CreateCompletion(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	var out providerhttp.CompletionResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.deploymentURL(ctx, req.Model, "completions"), providerhttp.NewCompletionRequest(req), &out)
+	if err != nil {
+		return llm.CompletionResponse{}, err
+	}
+	return out.Completion(requestID), nil
+}
+
+func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	var out providerhttp.EmbeddingsResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.deploymentURL(ctx, req.Model, "embeddings"), providerhttp.NewEmbeddingsRequest(req), &out)
+	if err != nil {
+		return llm.EmbeddingsResponse{}, err
+	}
+	return out.Embeddings(requestID), nil
+}
+
+// SetKeys replaces the provider's API keys, combined as by NewProvider and
+// WithWeightedKeys, without disturbing requests in flight.
+func (p *Provider) SetKeys(apiKeys []string, weighted []keypool.Key) {
+	p.apiKeys.Replace(keypool.Combine(weighted, apiKeys))
+}
+
+// Warmup opens a connection to the upstream so the first request skips the
+// DNS and TLS handshake; see providerhttp.Warmup.
+func (p *Provider) Warmup(ctx context.Context) error {
+	return providerhttp.Warmup(ctx, p.api.HTTP, p.baseURL, p.userAgent)
+}
+
+// deploymentURL returns the data-plane URL of op on the given deployment.
+func (p *Provider) deploymentURL(ctx context.Context, deployment, op string) string {
+	u := providerhttp.JoinURL(providerhttp.BaseURL(ctx, p.baseURL), "openai", "deployments", url.PathEscape(deployment), op)
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	return u + sep + "api-version=" + url.QueryEscape(p.apiVersion)
+}
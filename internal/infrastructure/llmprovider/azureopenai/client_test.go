@@ -0,0 +1,77 @@
+package azureopenai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+func TestProvider_CreateChatCompletion_UsesDeploymentURLAndAPIKeyHeader(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4o-prod/chat/completions" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2024-06-01" {
+			t.Errorf("unexpected api-version: %q", got)
+		}
+		if got := r.Header.Get("api-key"); got != "testkey" {
+			t.Errorf("unexpected api-key header: %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+  "id":"chatcmpl_x",
+  "created": 123,
+  "model":"gpt-4o",
+  "choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],
+  "usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3}
+}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL+"/", "2024-06-01", []string{"testkey"}, 2*time.Second)
+	res, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "gpt-4o-prod", // deployment name
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if len(res.Choices) != 1 || res.Choices[0].Message.Content != "hi" || res.Usage.TotalTokens != 3 {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+}
+
+func TestProvider_CreateEmbeddings_DefaultAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Deployment names are path-escaped so they stay a single segment.
+		if got := r.URL.EscapedPath(); got != "/openai/deployments/embed%2Fv3/embeddings" {
+			t.Errorf("unexpected path: %s", got)
+		}
+		if got := r.URL.Query().Get("api-version"); got != DefaultAPIVersion {
+			t.Errorf("unexpected api-version: %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"text-embedding-3-small","data":[{"index":0,"embedding":[0.5]}],"usage":{"prompt_tokens":1,"total_tokens":1}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, "", []string{"testkey"}, 2*time.Second)
+	res, err := p.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "embed/v3", Input: []string{"a"}})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if len(res.Data) != 1 || res.Data[0].Vector[0] != 0.5 {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+}
@@ -1,230 +1,242 @@
 package dashscope
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/buildinfo"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/keypool"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/providerhttp"
 )
 
 // Provider implements application.llmgateway.Provider for DashScope OpenAI-compatible mode.
 type Provider struct {
 	baseURL string
-	apiKey  string
-
-	httpClient *http.Client
+	apiKeys *keypool.Pool
+	// weightedKeys take precedence over the plain apiKeys passed to NewProvider.
+	weightedKeys []keypool.Key
+
+	// api sends the requests; it is built by NewProvider from the fields below.
+	api               *providerhttp.API
+	streamIdleTimeout time.Duration
+	transport         http.RoundTripper // shared with other providers when set
+	// maxResponseBytes bounds non-streamed response bodies; 0 uses the default.
+	maxResponseBytes int64
+
+	userAgent string
+	headers   map[string]string // extra static headers sent upstream
+
+	requestTransformer  providerhttp.RequestTransformer
+	responseTransformer providerhttp.ResponseTransformer
 }
 
-func NewProvider(baseURL, apiKey string, timeout time.Duration) *Provider {
-	baseURL = strings.TrimRight(baseURL, "/")
-	if timeout <= 0 {
-		timeout = 20 * time.Second
-	}
-	return &Provider{
-		baseURL: baseURL,
-		apiKey:  apiKey,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
-	}
-}
+// Option customizes optional Provider behavior.
+type Option func(*Provider)
 
-func (p *Provider) CreateChatCompletion(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
-	// OpenAI-compatible request/response shapes (minimal subset).
-	// For vision models, content can be an array of content parts.
-	type imageURL struct {
-		URL    string `json:"url"`
-		Detail string `json:"detail,omitempty"`
-	}
-	type contentPart struct {
-		Type     string    `json:"type"`
-		Text     string    `json:"text,omitempty"`
-		ImageURL *imageURL `json:"image_url,omitempty"`
-	}
-	// message supports both simple text content and multimodal content.
-	// Content field is used for text-only, ContentParts for multimodal.
-	type message struct {
-		Role         string        `json:"role"`
-		Content      any           `json:"content"` // string or []contentPart
-		Name         string        `json:"name,omitempty"`
-	}
-	type responseMessage struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-		Name    string `json:"name,omitempty"`
-	}
-	type chatReq struct {
-		Model       string    `json:"model"`
-		Messages    []message `json:"messages"`
-		Temperature float64   `json:"temperature,omitempty"`
-		MaxTokens   uint32    `json:"max_tokens,omitempty"`
-		User        string    `json:"user,omitempty"`
-	}
-	type usage struct {
-		PromptTokens     uint32 `json:"prompt_tokens"`
-		CompletionTokens uint32 `json:"completion_tokens"`
-		TotalTokens      uint32 `json:"total_tokens"`
-	}
-	type choice struct {
-		Index        uint32          `json:"index"`
-		Message      responseMessage `json:"message"`
-		FinishReason string          `json:"finish_reason"`
-	}
-	type chatResp struct {
-		ID      string   `json:"id"`
-		Created int64    `json:"created"`
-		Model   string   `json:"model"`
-		Choices []choice `json:"choices"`
-		Usage   usage    `json:"usage"`
-	}
-
-	msgs := make([]message, 0, len(req.Messages))
-	for _, m := range req.Messages {
-		var content any
-		if len(m.ContentParts) > 0 {
-			// Multimodal message with content parts (for vision models).
-			parts := make([]contentPart, 0, len(m.ContentParts))
-			for _, cp := range m.ContentParts {
-				part := contentPart{Type: cp.Type, Text: cp.Text}
-				if cp.ImageURL != nil {
-					part.ImageURL = &imageURL{URL: cp.ImageURL.URL, Detail: cp.ImageURL.Detail}
-				}
-				parts = append(parts, part)
-			}
-			content = parts
-		} else {
-			// Simple text message.
-			content = m.Content
+// WithUserAgent overrides the default "llm-gateway/<version>" User-Agent.
+func WithUserAgent(ua string) Option {
+	return func(p *Provider) {
+		if ua != "" {
+			p.userAgent = ua
 		}
-		msgs = append(msgs, message{Role: m.Role, Content: content, Name: m.Name})
 	}
+}
 
-	body := chatReq{
-		Model:       req.Model,
-		Messages:    msgs,
-		Temperature: req.Temperature,
-		MaxTokens:   req.MaxTokens,
-		User:        req.User,
+// WithHeaders adds static headers to every upstream request.
+// They cannot override Content-Type or Authorization.
+func WithHeaders(headers map[string]string) Option {
+	return func(p *Provider) {
+		p.headers = headers
 	}
+}
 
-	var out chatResp
-	if err := p.doJSON(ctx, http.MethodPost, p.baseURL+"/chat/completions", body, &out); err != nil {
-		return llm.ChatCompletionResponse{}, err
+// WithWeightedKeys adds keys with their own traffic weight and daily quota to
+// the rotation. A key also listed in apiKeys uses the weighted settings.
+func WithWeightedKeys(keys []keypool.Key) Option {
+	return func(p *Provider) {
+		p.weightedKeys = keys
 	}
-
-	choices := make([]llm.ChatCompletionChoice, 0, len(out.Choices))
-	for _, c := range out.Choices {
-		choices = append(choices, llm.ChatCompletionChoice{
-			Index: c.Index,
-			Message: llm.ChatMessage{
-				Role:    c.Message.Role,
-				Content: c.Message.Content,
-				Name:    c.Message.Name,
-			},
-			FinishReason: c.FinishReason,
-		})
-	}
-
-	return llm.ChatCompletionResponse{
-		ID:      out.ID,
-		Created: out.Created,
-		Model:   out.Model,
-		Choices: choices,
-		Usage: llm.TokenUsage{
-			PromptTokens:     out.Usage.PromptTokens,
-			CompletionTokens: out.Usage.CompletionTokens,
-			TotalTokens:      out.Usage.TotalTokens,
-		},
-	}, nil
 }
 
-func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
-	type embReq struct {
-		Model string   `json:"model"`
-		Input []string `json:"input"`
-		User  string   `json:"user,omitempty"`
-	}
-	type embDatum struct {
-		Index     uint32    `json:"index"`
-		Embedding []float32 `json:"embedding"`
+// WithTransport sets the transport used for upstream requests, typically one
+// built by providerhttp.NewTransport and shared between providers.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(p *Provider) {
+		p.transport = rt
 	}
-	type embUsage struct {
-		PromptTokens uint32 `json:"prompt_tokens"`
-		TotalTokens  uint32 `json:"total_tokens"`
+}
+
+// WithRequestTransformer rewrites every outgoing JSON body, e.g. for custom
+// gateways fronting bespoke model endpoints.
+func WithRequestTransformer(t providerhttp.RequestTransformer) Option {
+	return func(p *Provider) {
+		p.requestTransformer = t
 	}
-	type embResp struct {
-		ID    string     `json:"id"`
-		Model string     `json:"model"`
-		Data  []embDatum `json:"data"`
-		Usage embUsage   `json:"usage"`
+}
+
+// WithResponseTransformer rewrites non-streamed JSON responses before decoding.
+func WithResponseTransformer(t providerhttp.ResponseTransformer) Option {
+	return func(p *Provider) {
+		p.responseTransformer = t
 	}
+}
 
-	var out embResp
-	if err := p.doJSON(ctx, http.MethodPost, p.baseURL+"/embeddings", embReq{Model: req.Model, Input: req.Input, User: req.User}, &out); err != nil {
-		return llm.EmbeddingsResponse{}, err
+// WithMaxResponseBytes bounds how much of a non-streamed upstream response is
+// read; larger responses fail with providerhttp.ErrResponseTooLarge.
+// Non-positive values keep providerhttp.DefaultMaxResponseBytes.
+func WithMaxResponseBytes(n int64) Option {
+	return func(p *Provider) {
+		if n > 0 {
+			p.maxResponseBytes = n
+		}
 	}
+}
 
-	data := make([]llm.Embedding, 0, len(out.Data))
-	for _, d := range out.Data {
-		data = append(data, llm.Embedding{Index: d.Index, Vector: d.Embedding})
+// WithStreamIdleTimeout sets how long a streamed completion may go without
+// sending data before it is aborted. Non-positive values keep the default.
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(p *Provider) {
+		if d > 0 {
+			p.streamIdleTimeout = d
+		}
 	}
-	return llm.EmbeddingsResponse{
-		ID:    out.ID,
-		Model: out.Model,
-		Data:  data,
-		Usage: llm.EmbeddingsUsage{
-			PromptTokens: out.Usage.PromptTokens,
-			TotalTokens:  out.Usage.TotalTokens,
-		},
-	}, nil
 }
 
-func (p *Provider) doJSON(ctx context.Context, method, url string, in any, out any) error {
-	if p.apiKey == "" {
-		return fmt.Errorf("dashscope api key is empty")
+// NewProvider builds a provider that rotates requests across apiKeys.
+func NewProvider(baseURL string, apiKeys []string, timeout time.Duration, opts ...Option) *Provider {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if timeout <= 0 {
+		timeout = 20 * time.Second
 	}
-	b, err := json.Marshal(in)
+	p := &Provider{
+		baseURL:           baseURL,
+		streamIdleTimeout: providerhttp.DefaultStreamIdleTimeout,
+		userAgent:         buildinfo.UserAgent(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.apiKeys = keypool.NewWeighted(keypool.Combine(p.weightedKeys, apiKeys), keypool.DefaultCooldown)
+	p.api = &providerhttp.API{
+		Provider:            "dashscope",
+		Keys:                p.apiKeys,
+		HTTP:                providerhttp.NewClient(providerhttp.ClientOptions{Timeout: timeout, Transport: p.transport}),
+		Stream:              providerhttp.NewClient(providerhttp.ClientOptions{Transport: p.transport}),
+		StreamIdleTimeout:   p.streamIdleTimeout,
+		MaxResponseBytes:    p.maxResponseBytes,
+		UserAgent:           p.userAgent,
+		Headers:             p.headers,
+		RequestTransformer:  p.requestTransformer,
+		ResponseTransformer: p.responseTransformer,
+	}
+	return p
+}
+
+func (p *Provider) CreateChatCompletion(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	var out providerhttp.ChatResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.endpoint(ctx, "chat/completions"), providerhttp.NewChatRequest(req, false), &out)
 	if err != nil {
-		return err
+		return llm.ChatCompletionResponse{}, err
 	}
+	return out.ChatCompletion(req, requestID), nil
+}
+
+// CreateChatCompletionStream streams a chat completion, calling emit for each chunk.
+// The stream is bounded by the idle timeout rather than the unary request timeout.
+// Reasoning models (QwQ, Qwen3 thinking) stream their reasoning as reasoning_content.
+func (p *Provider) CreateChatCompletionStream(ctx context.Context, req llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error {
+	return p.api.DoStream(ctx, p.endpoint(ctx, "chat/completions"), providerhttp.NewChatRequest(req, true), func(data []byte) error {
+		c, err := providerhttp.DecodeChatChunk(data)
+		if err != nil {
+			return err
+		}
+		return emit(c)
+	})
+}
 
-	r, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(b))
+func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	var out providerhttp.EmbeddingsResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.endpoint(ctx, "embeddings"), providerhttp.NewEmbeddingsRequest(req), &out)
 	if err != nil {
-		return err
+		return llm.EmbeddingsResponse{}, err
 	}
-	r.Header.Set("Content-Type", "application/json")
-	r.Header.Set("Authorization", "Bearer "+p.apiKey)
+	return out.Embeddings(requestID), nil
+}
 
-	resp, err := p.httpClient.Do(r)
-	if err != nil {
-		return err
+// CountTokens counts the prompt tokens of req with DashScope's native
+// tokenizer endpoint, which sits beside the compatible-mode base URL at
+// /api/v1/tokenizer. Only message text is sent; images and audio are not counted.
+func (p *Provider) CountTokens(ctx context.Context, req llm.ChatCompletionRequest) (uint32, error) {
+	type tokMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type tokInput struct {
+		Messages []tokMessage `json:"messages"`
+	}
+	type tokReq struct {
+		Model string   `json:"model"`
+		Input tokInput `json:"input"`
+	}
+	type tokResp struct {
+		Usage struct {
+			InputTokens uint32 `json:"input_tokens"`
+		} `json:"usage"`
 	}
-	defer resp.Body.Close()
 
-	raw, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		msg := strings.TrimSpace(string(raw))
-		if msg == "" {
-			msg = resp.Status
-		}
-		if resp.StatusCode == http.StatusBadRequest {
-			return llm.InvalidArgument(msg)
+	messages := make([]tokMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		content := m.Content
+		if len(m.ContentParts) > 0 {
+			var b strings.Builder
+			for _, part := range m.ContentParts {
+				b.WriteString(part.Text)
+			}
+			content = b.String()
 		}
-		return fmt.Errorf("dashscope http %d: %s", resp.StatusCode, msg)
+		messages = append(messages, tokMessage{Role: m.Role, Content: content})
 	}
 
-	if out == nil {
-		return nil
+	var out tokResp
+	if _, err := p.api.DoJSON(ctx, http.MethodPost, p.tokenizerEndpoint(ctx), tokReq{Model: req.Model, Input: tokInput{Messages: messages}}, &out); err != nil {
+		return 0, err
 	}
-	if err := json.Unmarshal(raw, out); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+	return out.Usage.InputTokens, nil
+}
+
+// ListUpstreamModels lists the models served by the upstream /models endpoint.
+func (p *Provider) ListUpstreamModels(ctx context.Context) ([]llm.Model, error) {
+	var out providerhttp.ModelList
+	if _, err := p.api.DoJSON(ctx, http.MethodGet, p.endpoint(ctx, "models"), nil, &out); err != nil {
+		return nil, err
 	}
-	return nil
+	return out.Models("dashscope"), nil
 }
 
+// SetKeys replaces the provider's API keys, combined as by NewProvider and
+// WithWeightedKeys, without disturbing requests in flight.
+func (p *Provider) SetKeys(apiKeys []string, weighted []keypool.Key) {
+	p.apiKeys.Replace(keypool.Combine(weighted, apiKeys))
+}
+
+// Warmup opens a connection to the upstream so the first request skips the
+// DNS and TLS handshake; see providerhttp.Warmup.
+func (p *Provider) Warmup(ctx context.Context) error {
+	return providerhttp.Warmup(ctx, p.api.HTTP, p.baseURL, p.userAgent)
+}
+
+// endpoint returns the URL of path under the configured base URL, which may
+// carry any path prefix.
+func (p *Provider) endpoint(ctx context.Context, path string) string {
+	return providerhttp.JoinURL(providerhttp.BaseURL(ctx, p.baseURL), path)
+}
+
+// tokenizerEndpoint returns the native tokenizer URL for the configured
+// compatible-mode base URL.
+func (p *Provider) tokenizerEndpoint(ctx context.Context) string {
+	base := strings.TrimSuffix(strings.TrimRight(providerhttp.BaseURL(ctx, p.baseURL), "/"), "/compatible-mode/v1")
+	return providerhttp.JoinURL(base, "api/v1/tokenizer")
+}
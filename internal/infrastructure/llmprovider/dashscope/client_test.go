@@ -3,12 +3,16 @@ package dashscope
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/providerhttp"
 )
 
 func TestProvider_CreateChatCompletion(t *testing.T) {
@@ -40,7 +44,7 @@ func TestProvider_CreateChatCompletion(t *testing.T) {
 	}))
 	t.Cleanup(srv.Close)
 
-	p := NewProvider(srv.URL, "testkey", 2*time.Second)
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
 	res, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
 		Model: "qwen-turbo",
 		Messages: []llm.ChatMessage{
@@ -87,7 +91,7 @@ func TestProvider_CreateEmbeddings(t *testing.T) {
 	}))
 	t.Cleanup(srv.Close)
 
-	p := NewProvider(srv.URL, "testkey", 2*time.Second)
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
 	res, err := p.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{
 		Model: "text-embedding-v3",
 		Input: []string{"hello"},
@@ -100,3 +104,376 @@ func TestProvider_CreateEmbeddings(t *testing.T) {
 	}
 }
 
+func TestProvider_CountTokens(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tokenizer" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		var req struct {
+			Model string `json:"model"`
+			Input struct {
+				Messages []struct {
+					Role    string `json:"role"`
+					Content string `json:"content"`
+				} `json:"messages"`
+			} `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Model != "qwen-turbo" || len(req.Input.Messages) != 2 || req.Input.Messages[1].Content != "look at this" {
+			t.Fatalf("unexpected tokenizer request: %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"output":{"token_ids":[1,2,3]},"usage":{"input_tokens":42},"request_id":"req-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	// The tokenizer sits beside the compatible-mode base URL.
+	p := NewProvider(srv.URL+"/compatible-mode/v1", []string{"testkey"}, 2*time.Second)
+	n, err := p.CountTokens(context.Background(), llm.ChatCompletionRequest{
+		Model: "qwen-turbo",
+		Messages: []llm.ChatMessage{
+			{Role: "system", Content: "be brief"},
+			{Role: "user", ContentParts: []llm.ContentPart{
+				{Type: "text", Text: "look at "},
+				{Type: "image_url", ImageURL: &llm.ImageURL{URL: "https://example.com/a.png"}},
+				{Type: "text", Text: "this"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CountTokens error: %v", err)
+	}
+	if n != 42 {
+		t.Fatalf("CountTokens = %d, want 42", n)
+	}
+}
+
+func TestProvider_RotatesAndSkipsRejectedKeys(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		seen = append(seen, key)
+		mu.Unlock()
+		if key == "bad" {
+			http.Error(w, "invalid api key", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"text-embedding-v3","data":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"k1", "bad", "k2"}, 2*time.Second)
+	req := llm.EmbeddingsRequest{Model: "text-embedding-v3", Input: []string{"hello"}}
+	for i := 0; i < 7; i++ {
+		_, _ = p.CreateEmbeddings(context.Background(), req)
+	}
+
+	counts := map[string]int{}
+	for _, k := range seen {
+		counts[k]++
+	}
+	if counts["bad"] != 1 {
+		t.Fatalf("expected rejected key to be used once then skipped, got %v", counts)
+	}
+	if counts["k1"] != 3 || counts["k2"] != 3 {
+		t.Fatalf("expected remaining traffic split across healthy keys, got %v", counts)
+	}
+}
+
+func TestProvider_CreateChatCompletion_AudioOutput(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if mods, _ := req["modalities"].([]any); len(mods) != 2 || mods[1] != "audio" {
+			t.Fatalf("unexpected modalities: %#v", req["modalities"])
+		}
+		if audio, _ := req["audio"].(map[string]any); audio["voice"] != "Cherry" || audio["format"] != "wav" {
+			t.Fatalf("unexpected audio options: %#v", req["audio"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+  "id":"chatcmpl_a",
+  "model":"qwen-omni-turbo",
+  "choices":[{"index":0,"message":{"role":"assistant","content":"hello","audio":{"id":"audio_1","data":"UklGRg==","transcript":"hello","expires_at":1700000000}},"finish_reason":"stop"}]
+}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	res, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:      "qwen-omni-turbo",
+		Messages:   []llm.ChatMessage{{Role: "user", Content: "say hello"}},
+		Modalities: []string{"text", "audio"},
+		Audio:      &llm.AudioOutput{Voice: "Cherry", Format: "wav"},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	msg := res.Choices[0].Message
+	if msg.Content != "hello" || len(msg.ContentParts) != 0 {
+		t.Fatalf("unexpected content: %+v", msg)
+	}
+	if a := msg.Audio; a == nil || a.ID != "audio_1" || a.Data != "UklGRg==" || a.Format != "wav" || a.Transcript != "hello" || a.ExpiresAt != 1700000000 {
+		t.Fatalf("unexpected audio: %+v", msg.Audio)
+	}
+}
+
+func TestProvider_SendsUserAgentAndHeaders(t *testing.T) {
+	t.Parallel()
+
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"text-embedding-v3","data":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	req := llm.EmbeddingsRequest{Model: "text-embedding-v3", Input: []string{"hello"}}
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	if _, err := p.CreateEmbeddings(context.Background(), req); err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if ua := got.Get("User-Agent"); ua != "llm-gateway/dev" {
+		t.Fatalf("unexpected default user agent: %q", ua)
+	}
+
+	p = NewProvider(srv.URL, []string{"testkey"}, 2*time.Second,
+		WithUserAgent("custom/1.0"),
+		WithHeaders(map[string]string{"X-Title": "gw", "Authorization": "ignored"}),
+	)
+	if _, err := p.CreateEmbeddings(context.Background(), req); err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if got.Get("User-Agent") != "custom/1.0" || got.Get("X-Title") != "gw" {
+		t.Fatalf("unexpected headers: %v", got)
+	}
+	if got.Get("Authorization") != "Bearer testkey" {
+		t.Fatalf("static headers must not override auth: %q", got.Get("Authorization"))
+	}
+}
+
+func TestProvider_RateLimitedErrorCarriesRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "text-embedding-v3", Input: []string{"hello"}})
+	var perr *llm.ProviderHTTPError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *llm.ProviderHTTPError, got %T: %v", err, err)
+	}
+	if perr.StatusCode != http.StatusTooManyRequests || perr.RetryAfter != 7*time.Second {
+		t.Fatalf("unexpected error: %+v", perr)
+	}
+}
+
+func TestProvider_CreateChatCompletion_IgnoresProviderPreferences(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl_x","model":"qwen-turbo","choices":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:               "qwen-turbo",
+		Messages:            []llm.ChatMessage{{Role: "user", Content: "hello"}},
+		ProviderPreferences: &llm.ProviderPreferences{Order: []string{"openai"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if _, ok := got["provider"]; ok {
+		t.Fatalf("dashscope must not send provider preferences: %#v", got["provider"])
+	}
+}
+
+func TestProvider_CreateChatCompletion_ContextLengthExceeded(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":"invalid_parameter_error","message":"<400> InternalError.Algo.InvalidParameter: Range of input length should be [1, 30720]","type":"invalid_request_error"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "qwen-turbo",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "very long prompt"}},
+	})
+	if !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "context window of 30720 tokens") {
+		t.Fatalf("expected context window message with limit, got %v", err)
+	}
+}
+
+func TestProvider_ListUpstreamModels(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/models" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"qwen-turbo","object":"model","owned_by":"system"},{"id":"qwen-max","object":"model","owned_by":"system"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	models, err := p.ListUpstreamModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListUpstreamModels error: %v", err)
+	}
+	if len(models) != 2 || models[0].ID != "qwen-turbo" || models[1].ID != "qwen-max" {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+}
+
+// sseServer writes each chunk as a server-sent event, sleeping gap before each one.
+func sseServer(t *testing.T, chunks []string, gap time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req["stream"] != true {
+			t.Errorf("expected stream=true, got %#v", req["stream"])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		f := w.(http.Flusher)
+		f.Flush()
+		for _, c := range chunks {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(gap):
+			}
+			_, _ = w.Write([]byte("data: " + c + "\n\n"))
+			f.Flush()
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProvider_CreateChatCompletionStream_SlowButSteady(t *testing.T) {
+	t.Parallel()
+
+	chunks := make([]string, 0, 8)
+	for i := 0; i < 7; i++ {
+		chunks = append(chunks, `{"id":"c1","model":"qwen-turbo","choices":[{"index":0,"delta":{"content":"x"}}]}`)
+	}
+	chunks = append(chunks, `{"id":"c1","model":"qwen-turbo","choices":[],"usage":{"prompt_tokens":1,"completion_tokens":7,"total_tokens":8}}`)
+	srv := sseServer(t, chunks, 50*time.Millisecond)
+
+	// The whole stream takes ~400ms, far beyond the unary timeout, but no gap exceeds the idle timeout.
+	p := NewProvider(srv.URL, []string{"k"}, 100*time.Millisecond, WithStreamIdleTimeout(300*time.Millisecond))
+	var content strings.Builder
+	var usage *llm.TokenUsage
+	err := p.CreateChatCompletionStream(context.Background(), llm.ChatCompletionRequest{Model: "qwen-turbo"}, func(c llm.ChatCompletionChunk) error {
+		for _, ch := range c.Choices {
+			content.WriteString(ch.Delta.Content)
+		}
+		if c.Usage != nil {
+			usage = c.Usage
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream error: %v", err)
+	}
+	if content.String() != "xxxxxxx" {
+		t.Fatalf("unexpected content: %q", content.String())
+	}
+	if usage == nil || usage.TotalTokens != 8 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestProvider_CreateChatCompletionStream_Stalled(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"id":"c1","choices":[{"index":0,"delta":{"content":"x"}}]}` + "\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done() // stall until the client gives up
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"k"}, 10*time.Second, WithStreamIdleTimeout(100*time.Millisecond))
+	var got int
+	start := time.Now()
+	err := p.CreateChatCompletionStream(context.Background(), llm.ChatCompletionRequest{Model: "qwen-turbo"}, func(llm.ChatCompletionChunk) error {
+		got++
+		return nil
+	})
+	if !errors.Is(err, providerhttp.ErrStreamIdle) {
+		t.Fatalf("expected ErrStreamIdle, got %v", err)
+	}
+	if got != 1 {
+		t.Fatalf("expected the chunk before the stall, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("stalled stream took %v to abort", elapsed)
+	}
+}
+
+func TestNewProvider_SharedTransportKeepsPerProviderTimeouts(t *testing.T) {
+	t.Parallel()
+
+	shared, err := providerhttp.NewTransport(providerhttp.TransportOptions{})
+	if err != nil {
+		t.Fatalf("NewTransport error: %v", err)
+	}
+	a := NewProvider("http://a", []string{"k"}, 5*time.Second, WithTransport(shared))
+	b := NewProvider("http://b", []string{"k"}, 30*time.Second, WithTransport(shared))
+
+	for _, c := range []*http.Client{a.api.HTTP, a.api.Stream, b.api.HTTP, b.api.Stream} {
+		if c.Transport != shared {
+			t.Fatalf("expected shared transport, got %#v", c.Transport)
+		}
+	}
+	if a.api.HTTP.Timeout != 5*time.Second || b.api.HTTP.Timeout != 30*time.Second {
+		t.Fatalf("unexpected timeouts: %v, %v", a.api.HTTP.Timeout, b.api.HTTP.Timeout)
+	}
+	if a.api.Stream.Timeout != 0 {
+		t.Fatalf("stream client must not have a total timeout, got %v", a.api.Stream.Timeout)
+	}
+}
@@ -0,0 +1,210 @@
+package keypool
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCooldown is how long a rejected key is sidelined before it is tried again.
+const DefaultCooldown = time.Minute
+
+// Key is an upstream API key with its share of traffic.
+type Key struct {
+	Value string
+	// Weight is the key's relative share of traffic; values <= 0 count as 1.
+	Weight int
+	// DailyQuota caps how many times the key is handed out per UTC day; 0 means
+	// unlimited. An exhausted key is skipped until the next day.
+	DailyQuota int
+}
+
+// Pool hands out upstream API keys by smooth weighted round-robin (plain
+// round-robin when all weights are equal) and temporarily sidelines keys the
+// upstream rejected (e.g. 401) or throttled (e.g. 429).
+type Pool struct {
+	mu       sync.Mutex
+	keys     []*entry
+	benched  map[string]time.Time // key -> sidelined until
+	cooldown time.Duration
+	day      time.Time // UTC day the usage counters belong to
+
+	now func() time.Time
+}
+
+type entry struct {
+	Key
+	current int // smooth weighted round-robin state
+	used    int // times handed out on Pool.day
+}
+
+// New builds a pool of equally weighted keys, dropping empty and duplicate
+// entries.
+func New(keys []string, cooldown time.Duration) *Pool {
+	weighted := make([]Key, len(keys))
+	for i, k := range keys {
+		weighted[i] = Key{Value: k}
+	}
+	return NewWeighted(weighted, cooldown)
+}
+
+// NewWeighted builds a pool from keys, dropping empty entries. For duplicate
+// values the first entry wins.
+func NewWeighted(keys []Key, cooldown time.Duration) *Pool {
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &Pool{
+		keys:     entries(keys),
+		benched:  make(map[string]time.Time),
+		cooldown: cooldown,
+		now:      time.Now,
+	}
+}
+
+// Combine lists weighted followed by plain keys with default settings, the
+// order in which a key listed in both keeps its weighted settings.
+func Combine(weighted []Key, plain []string) []Key {
+	keys := append([]Key(nil), weighted...)
+	for _, k := range plain {
+		keys = append(keys, Key{Value: k})
+	}
+	return keys
+}
+
+// entries normalizes keys the way NewWeighted documents.
+func entries(keys []Key) []*entry {
+	seen := make(map[string]struct{}, len(keys))
+	uniq := make([]*entry, 0, len(keys))
+	for _, k := range keys {
+		if k.Value == "" {
+			continue
+		}
+		if _, ok := seen[k.Value]; ok {
+			continue
+		}
+		seen[k.Value] = struct{}{}
+		if k.Weight <= 0 {
+			k.Weight = 1
+		}
+		if k.DailyQuota < 0 {
+			k.DailyQuota = 0
+		}
+		uniq = append(uniq, &entry{Key: k})
+	}
+	return uniq
+}
+
+// Replace swaps the pool's keys for keys, normalized as by NewWeighted. Keys
+// kept across the swap keep today's usage and any sideline; callers holding a
+// removed key finish their request with it.
+func (p *Pool) Replace(keys []Key) {
+	next := entries(keys)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := make(map[string]*entry, len(p.keys))
+	for _, e := range p.keys {
+		old[e.Value] = e
+	}
+	kept := make(map[string]struct{}, len(next))
+	for _, e := range next {
+		kept[e.Value] = struct{}{}
+		if o, ok := old[e.Value]; ok {
+			e.used = o.used
+		}
+	}
+	for k := range p.benched {
+		if _, ok := kept[k]; !ok {
+			delete(p.benched, k)
+		}
+	}
+	p.keys = next
+}
+
+// Len returns the number of distinct keys in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys)
+}
+
+// Next returns the next usable key. If every key is sidelined or out of quota,
+// the one that becomes usable first is returned so requests keep flowing. ok is
+// false only when the pool is empty.
+func (p *Pool) Next() (key string, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return "", false
+	}
+
+	now := p.now()
+	day := now.UTC().Truncate(24 * time.Hour)
+	if !day.Equal(p.day) {
+		p.day = day
+		for _, e := range p.keys {
+			e.used = 0
+		}
+	}
+
+	var (
+		best, fallback *entry
+		fallbackUntil  time.Time
+		total          int
+	)
+	for _, e := range p.keys {
+		var until time.Time
+		if b, benched := p.benched[e.Value]; benched {
+			if now.Before(b) {
+				until = b
+			} else {
+				delete(p.benched, e.Value)
+			}
+		}
+		if e.DailyQuota > 0 && e.used >= e.DailyQuota {
+			if tomorrow := day.Add(24 * time.Hour); tomorrow.After(until) {
+				until = tomorrow
+			}
+		}
+		if !until.IsZero() {
+			if fallback == nil || until.Before(fallbackUntil) {
+				fallback, fallbackUntil = e, until
+			}
+			continue
+		}
+		e.current += e.Weight
+		total += e.Weight
+		if best == nil || e.current > best.current {
+			best = e
+		}
+	}
+	if best == nil {
+		best = fallback
+	} else {
+		best.current -= total
+	}
+	best.used++
+	return best.Value, true
+}
+
+// Usage returns how many times each key was handed out today (UTC).
+func (p *Pool) Usage() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	day := p.now().UTC().Truncate(24 * time.Hour)
+	usage := make(map[string]int, len(p.keys))
+	for _, e := range p.keys {
+		if day.Equal(p.day) {
+			usage[e.Value] = e.used
+		} else {
+			usage[e.Value] = 0
+		}
+	}
+	return usage
+}
+
+// Sideline removes key from rotation for the pool's cooldown.
+func (p *Pool) Sideline(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.benched[key] = p.now().Add(p.cooldown)
+}
@@ -0,0 +1,137 @@
+package keypool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPool_RoundRobin(t *testing.T) {
+	t.Parallel()
+
+	p := New([]string{"a", "b", "", "c", "a"}, 0)
+	if p.Len() != 3 {
+		t.Fatalf("expected 3 distinct keys, got %d", p.Len())
+	}
+	counts := map[string]int{}
+	for i := 0; i < 9; i++ {
+		k, ok := p.Next()
+		if !ok {
+			t.Fatalf("Next returned !ok")
+		}
+		counts[k]++
+	}
+	for _, k := range []string{"a", "b", "c"} {
+		if counts[k] != 3 {
+			t.Fatalf("uneven distribution: %v", counts)
+		}
+	}
+}
+
+func TestPool_SidelineSkipsKeyUntilCooldown(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	p := New([]string{"a", "b"}, time.Minute)
+	p.now = func() time.Time { return now }
+
+	p.Sideline("a")
+	for i := 0; i < 4; i++ {
+		if k, _ := p.Next(); k != "b" {
+			t.Fatalf("expected sidelined key to be skipped, got %q", k)
+		}
+	}
+
+	// All keys sidelined: fall back to the one that recovers first.
+	p.Sideline("b")
+	if k, _ := p.Next(); k != "a" {
+		t.Fatalf("expected earliest-recovering key, got %q", k)
+	}
+
+	now = now.Add(2 * time.Minute)
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		k, _ := p.Next()
+		seen[k] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("expected both keys back in rotation, got %v", seen)
+	}
+}
+
+func TestPool_Empty(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := New(nil, 0).Next(); ok {
+		t.Fatalf("expected empty pool to return !ok")
+	}
+}
+
+func TestPool_WeightedDistribution(t *testing.T) {
+	t.Parallel()
+
+	p := NewWeighted([]Key{{Value: "big", Weight: 3}, {Value: "small", Weight: 1}, {Value: "", Weight: 5}}, 0)
+	if p.Len() != 2 {
+		t.Fatalf("expected 2 keys, got %d", p.Len())
+	}
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		k, _ := p.Next()
+		counts[k]++
+	}
+	if counts["big"] < 280 || counts["big"] > 320 || counts["small"] < 80 || counts["small"] > 120 {
+		t.Fatalf("expected roughly 3:1 split, got %v", counts)
+	}
+	if u := p.Usage(); u["big"] != counts["big"] || u["small"] != counts["small"] {
+		t.Fatalf("usage %v does not match handed out keys %v", u, counts)
+	}
+}
+
+func TestPool_ExhaustedKeyIsSkippedUntilNextDay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	p := NewWeighted([]Key{{Value: "a", Weight: 10, DailyQuota: 2}, {Value: "b"}}, 0)
+	p.now = func() time.Time { return now }
+
+	counts := map[string]int{}
+	for i := 0; i < 10; i++ {
+		k, _ := p.Next()
+		counts[k]++
+	}
+	if counts["a"] != 2 || counts["b"] != 8 {
+		t.Fatalf("expected a to stop at its quota, got %v", counts)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if u := p.Usage(); u["a"] != 0 || u["b"] != 0 {
+		t.Fatalf("expected usage to reset on a new day, got %v", u)
+	}
+	if k, _ := p.Next(); k != "a" {
+		t.Fatalf("expected a back in rotation after the daily reset, got %q", k)
+	}
+}
+
+func TestPool_ReplaceKeepsUsageOfRetainedKeys(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	p := NewWeighted([]Key{{Value: "a", DailyQuota: 1}, {Value: "b"}}, time.Minute)
+	p.now = func() time.Time { return now }
+	if k, _ := p.Next(); k != "a" {
+		t.Fatalf("expected a first, got %q", k)
+	}
+	p.Sideline("b")
+
+	p.Replace([]Key{{Value: "a", DailyQuota: 1}, {Value: "c"}})
+	if p.Len() != 2 {
+		t.Fatalf("expected 2 keys after replace, got %d", p.Len())
+	}
+	for i := 0; i < 3; i++ {
+		if k, _ := p.Next(); k != "c" {
+			t.Fatalf("expected the new key while a is out of quota, got %q", k)
+		}
+	}
+	if _, ok := p.benched["b"]; ok {
+		t.Fatal("expected the removed key's sideline to be dropped")
+	}
+}
@@ -1,27 +1,112 @@
 package openrouter
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/buildinfo"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/keypool"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/providerhttp"
 )
 
 // Provider implements application.llmgateway.Provider for OpenRouter API.
 type Provider struct {
 	baseURL string
-	apiKey  string
+	apiKeys *keypool.Pool
+	// weightedKeys take precedence over the plain apiKeys passed to NewProvider.
+	weightedKeys []keypool.Key
 
-	httpClient *http.Client
+	// api sends the requests; it is built by NewProvider from the fields below.
+	api               *providerhttp.API
+	streamIdleTimeout time.Duration
+	transport         http.RoundTripper // shared with other providers when set
+	// maxResponseBytes bounds non-streamed response bodies; 0 uses the default.
+	maxResponseBytes int64
+
+	userAgent string
+	headers   map[string]string // extra static headers sent upstream
+
+	requestTransformer  providerhttp.RequestTransformer
+	responseTransformer providerhttp.ResponseTransformer
+}
+
+// Option customizes optional Provider behavior.
+type Option func(*Provider)
+
+// WithUserAgent overrides the default "llm-gateway/<version>" User-Agent.
+func WithUserAgent(ua string) Option {
+	return func(p *Provider) {
+		if ua != "" {
+			p.userAgent = ua
+		}
+	}
+}
+
+// WithHeaders adds static headers to every upstream request.
+// They cannot override Content-Type or Authorization.
+func WithHeaders(headers map[string]string) Option {
+	return func(p *Provider) {
+		p.headers = headers
+	}
+}
+
+// WithWeightedKeys adds keys with their own traffic weight and daily quota to
+// the rotation. A key also listed in apiKeys uses the weighted settings.
+func WithWeightedKeys(keys []keypool.Key) Option {
+	return func(p *Provider) {
+		p.weightedKeys = keys
+	}
+}
+
+// WithTransport sets the transport used for upstream requests, typically one
+// built by providerhttp.NewTransport and shared between providers.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(p *Provider) {
+		p.transport = rt
+	}
+}
+
+// WithRequestTransformer rewrites every outgoing JSON body, e.g. for custom
+// gateways fronting bespoke model endpoints.
+func WithRequestTransformer(t providerhttp.RequestTransformer) Option {
+	return func(p *Provider) {
+		p.requestTransformer = t
+	}
+}
+
+// WithResponseTransformer rewrites non-streamed JSON responses before decoding.
+func WithResponseTransformer(t providerhttp.ResponseTransformer) Option {
+	return func(p *Provider) {
+		p.responseTransformer = t
+	}
+}
+
+// WithMaxResponseBytes bounds how much of a non-streamed upstream response is
+// read; larger responses fail with providerhttp.ErrResponseTooLarge.
+// Non-positive values keep providerhttp.DefaultMaxResponseBytes.
+func WithMaxResponseBytes(n int64) Option {
+	return func(p *Provider) {
+		if n > 0 {
+			p.maxResponseBytes = n
+		}
+	}
+}
+
+// WithStreamIdleTimeout sets how long a streamed completion may go without
+// sending data before it is aborted. Non-positive values keep the default.
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(p *Provider) {
+		if d > 0 {
+			p.streamIdleTimeout = d
+		}
+	}
 }
 
-func NewProvider(baseURL, apiKey string, timeout time.Duration) *Provider {
+// NewProvider builds a provider that rotates requests across apiKeys.
+func NewProvider(baseURL string, apiKeys []string, timeout time.Duration, opts ...Option) *Provider {
 	baseURL = strings.TrimRight(baseURL, "/")
 	if baseURL == "" {
 		baseURL = "https://openrouter.ai/api/v1"
@@ -29,26 +114,49 @@ func NewProvider(baseURL, apiKey string, timeout time.Duration) *Provider {
 	if timeout <= 0 {
 		timeout = 60 * time.Second
 	}
-	return &Provider{
-		baseURL: baseURL,
-		apiKey:  apiKey,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+	p := &Provider{
+		baseURL:           baseURL,
+		streamIdleTimeout: providerhttp.DefaultStreamIdleTimeout,
+		userAgent:         buildinfo.UserAgent(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.apiKeys = keypool.NewWeighted(keypool.Combine(p.weightedKeys, apiKeys), keypool.DefaultCooldown)
+	p.api = &providerhttp.API{
+		Provider:            "openrouter",
+		Keys:                p.apiKeys,
+		HTTP:                providerhttp.NewClient(providerhttp.ClientOptions{Timeout: timeout, Transport: p.transport}),
+		Stream:              providerhttp.NewClient(providerhttp.ClientOptions{Transport: p.transport}),
+		StreamIdleTimeout:   p.streamIdleTimeout,
+		MaxResponseBytes:    p.maxResponseBytes,
+		UserAgent:           p.userAgent,
+		Headers:             p.headers,
+		RequestTransformer:  p.requestTransformer,
+		ResponseTransformer: p.responseTransformer,
 	}
+	return p
 }
 
 func (p *Provider) CreateChatCompletion(ctx context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
-	// OpenAI-compatible request/response shapes (minimal subset).
-	// For vision models, content can be an array of content parts.
-	type imageURL struct {
-		URL    string `json:"url"`
-		Detail string `json:"detail,omitempty"`
+	var out providerhttp.ChatResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.endpoint(ctx, "chat/completions"), p.chatBody(req, false), &out)
+	if err != nil {
+		return llm.ChatCompletionResponse{}, err
+	}
+	return out.ChatCompletion(req, requestID), nil
+}
+
+// chatBody builds the upstream chat completions request body: the shared
+// OpenAI-compatible body plus OpenRouter's routing and caching fields.
+func (p *Provider) chatBody(req llm.ChatCompletionRequest, stream bool) any {
+	// cacheControl is Anthropic's prompt caching breakpoint, passed through by OpenRouter.
+	type cacheControl struct {
+		Type string `json:"type"`
 	}
 	type contentPart struct {
-		Type     string    `json:"type"`
-		Text     string    `json:"text,omitempty"`
-		ImageURL *imageURL `json:"image_url,omitempty"`
+		providerhttp.ChatContentPart
+		CacheControl *cacheControl `json:"cache_control,omitempty"`
 	}
 	// message supports both simple text content and multimodal content.
 	type message struct {
@@ -56,34 +164,22 @@ func (p *Provider) CreateChatCompletion(ctx context.Context, req llm.ChatComplet
 		Content any    `json:"content"` // string or []contentPart
 		Name    string `json:"name,omitempty"`
 	}
-	type responseMessage struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-		Name    string `json:"name,omitempty"`
+	type providerPrefs struct {
+		Order             []string `json:"order,omitempty"`
+		AllowFallbacks    *bool    `json:"allow_fallbacks,omitempty"`
+		RequireParameters bool     `json:"require_parameters,omitempty"`
+		DataCollection    string   `json:"data_collection,omitempty"`
 	}
 	type chatReq struct {
-		Model       string    `json:"model"`
-		Messages    []message `json:"messages"`
-		Temperature float64   `json:"temperature,omitempty"`
-		MaxTokens   uint32    `json:"max_tokens,omitempty"`
-		User        string    `json:"user,omitempty"`
-	}
-	type usage struct {
-		PromptTokens     uint32 `json:"prompt_tokens"`
-		CompletionTokens uint32 `json:"completion_tokens"`
-		TotalTokens      uint32 `json:"total_tokens"`
-	}
-	type choice struct {
-		Index        uint32          `json:"index"`
-		Message      responseMessage `json:"message"`
-		FinishReason string          `json:"finish_reason"`
-	}
-	type chatResp struct {
-		ID      string   `json:"id"`
-		Created int64    `json:"created"`
-		Model   string   `json:"model"`
-		Choices []choice `json:"choices"`
-		Usage   usage    `json:"usage"`
+		providerhttp.ChatRequest
+		// Messages shadows ChatRequest.Messages to carry cache_control.
+		Messages    []message         `json:"messages"`
+		Provider    *providerPrefs    `json:"provider,omitempty"`
+		ServiceTier string            `json:"service_tier,omitempty"`
+		Metadata    map[string]string `json:"metadata,omitempty"`
+		// IncludeReasoning asks models that would otherwise hide their
+		// reasoning to return it.
+		IncludeReasoning bool `json:"include_reasoning,omitempty"`
 	}
 
 	msgs := make([]message, 0, len(req.Messages))
@@ -93,13 +189,23 @@ func (p *Provider) CreateChatCompletion(ctx context.Context, req llm.ChatComplet
 			// Multimodal message with content parts (for vision models).
 			parts := make([]contentPart, 0, len(m.ContentParts))
 			for _, cp := range m.ContentParts {
-				part := contentPart{Type: cp.Type, Text: cp.Text}
+				part := contentPart{ChatContentPart: providerhttp.ChatContentPart{Type: cp.Type, Text: cp.Text}}
 				if cp.ImageURL != nil {
-					part.ImageURL = &imageURL{URL: cp.ImageURL.URL, Detail: cp.ImageURL.Detail}
+					part.ImageURL = &providerhttp.ChatImageURL{URL: cp.ImageURL.URL, Detail: cp.ImageURL.Detail}
+				}
+				if cp.CacheControl != nil {
+					part.CacheControl = &cacheControl{Type: cp.CacheControl.Type}
 				}
 				parts = append(parts, part)
 			}
+			// A message-level breakpoint caches through the end of the message.
+			if m.CacheControl != nil && parts[len(parts)-1].CacheControl == nil {
+				parts[len(parts)-1].CacheControl = &cacheControl{Type: m.CacheControl.Type}
+			}
 			content = parts
+		} else if m.CacheControl != nil {
+			// cache_control is only accepted on content parts, so wrap the text.
+			content = []contentPart{{ChatContentPart: providerhttp.ChatContentPart{Type: "text", Text: m.Content}, CacheControl: &cacheControl{Type: m.CacheControl.Type}}}
 		} else {
 			// Simple text message.
 			content = m.Content
@@ -108,124 +214,78 @@ func (p *Provider) CreateChatCompletion(ctx context.Context, req llm.ChatComplet
 	}
 
 	body := chatReq{
-		Model:       req.Model,
+		ChatRequest: providerhttp.NewChatRequest(req, stream),
 		Messages:    msgs,
-		Temperature: req.Temperature,
-		MaxTokens:   req.MaxTokens,
-		User:        req.User,
-	}
-
-	var out chatResp
-	if err := p.doJSON(ctx, http.MethodPost, p.baseURL+"/chat/completions", body, &out); err != nil {
-		return llm.ChatCompletionResponse{}, err
-	}
+		ServiceTier: req.ServiceTier,
+		Metadata:    req.Metadata,
 
-	choices := make([]llm.ChatCompletionChoice, 0, len(out.Choices))
-	for _, c := range out.Choices {
-		choices = append(choices, llm.ChatCompletionChoice{
-			Index: c.Index,
-			Message: llm.ChatMessage{
-				Role:    c.Message.Role,
-				Content: c.Message.Content,
-				Name:    c.Message.Name,
-			},
-			FinishReason: c.FinishReason,
-		})
-	}
-
-	return llm.ChatCompletionResponse{
-		ID:      out.ID,
-		Created: out.Created,
-		Model:   out.Model,
-		Choices: choices,
-		Usage: llm.TokenUsage{
-			PromptTokens:     out.Usage.PromptTokens,
-			CompletionTokens: out.Usage.CompletionTokens,
-			TotalTokens:      out.Usage.TotalTokens,
-		},
-	}, nil
-}
-
-func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
-	type embReq struct {
-		Model string   `json:"model"`
-		Input []string `json:"input"`
-		User  string   `json:"user,omitempty"`
-	}
-	type embDatum struct {
-		Index     uint32    `json:"index"`
-		Embedding []float32 `json:"embedding"`
-	}
-	type embUsage struct {
-		PromptTokens uint32 `json:"prompt_tokens"`
-		TotalTokens  uint32 `json:"total_tokens"`
+		IncludeReasoning: req.IncludeReasoning,
 	}
-	type embResp struct {
-		ID    string     `json:"id"`
-		Model string     `json:"model"`
-		Data  []embDatum `json:"data"`
-		Usage embUsage   `json:"usage"`
-	}
-
-	var out embResp
-	if err := p.doJSON(ctx, http.MethodPost, p.baseURL+"/embeddings", embReq{Model: req.Model, Input: req.Input, User: req.User}, &out); err != nil {
-		return llm.EmbeddingsResponse{}, err
+	if pp := req.ProviderPreferences; pp != nil {
+		body.Provider = &providerPrefs{
+			Order:             pp.Order,
+			AllowFallbacks:    pp.AllowFallbacks,
+			RequireParameters: pp.RequireParameters,
+			DataCollection:    pp.DataCollection,
+		}
 	}
+	return body
+}
 
-	data := make([]llm.Embedding, 0, len(out.Data))
-	for _, d := range out.Data {
-		data = append(data, llm.Embedding{Index: d.Index, Vector: d.Embedding})
-	}
-	return llm.EmbeddingsResponse{
-		ID:    out.ID,
-		Model: out.Model,
-		Data:  data,
-		Usage: llm.EmbeddingsUsage{
-			PromptTokens: out.Usage.PromptTokens,
-			TotalTokens:  out.Usage.TotalTokens,
-		},
-	}, nil
+// CreateChatCompletionStream streams a chat completion, calling emit for each chunk.
+// The stream is bounded by the idle timeout rather than the unary request timeout.
+func (p *Provider) CreateChatCompletionStream(ctx context.Context, req llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error {
+	return p.api.DoStream(ctx, p.endpoint(ctx, "chat/completions"), p.chatBody(req, true), func(data []byte) error {
+		c, err := providerhttp.DecodeChatChunk(data)
+		if err != nil {
+			return err
+		}
+		return emit(c)
+	})
 }
 
-func (p *Provider) doJSON(ctx context.Context, method, url string, in any, out any) error {
-	if p.apiKey == "" {
-		return fmt.Errorf("openrouter api key is empty")
-	}
-	b, err := json.Marshal(in)
+// CreateCompletion runs a legacy text completion against the upstream /completions endpoint.
+func (p *Provider) CreateCompletion(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	var out providerhttp.CompletionResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.endpoint(ctx, "completions"), providerhttp.NewCompletionRequest(req), &out)
 	if err != nil {
-		return err
+		return llm.CompletionResponse{}, err
 	}
+	return out.Completion(requestID), nil
+}
 
-	r, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(b))
+func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	var out providerhttp.EmbeddingsResponse
+	requestID, err := p.api.DoJSON(ctx, http.MethodPost, p.endpoint(ctx, "embeddings"), providerhttp.NewEmbeddingsRequest(req), &out)
 	if err != nil {
-		return err
+		return llm.EmbeddingsResponse{}, err
 	}
-	r.Header.Set("Content-Type", "application/json")
-	r.Header.Set("Authorization", "Bearer "+p.apiKey)
+	return out.Embeddings(requestID), nil
+}
 
-	resp, err := p.httpClient.Do(r)
-	if err != nil {
-		return err
+// ListUpstreamModels lists the models served by the upstream /models endpoint.
+func (p *Provider) ListUpstreamModels(ctx context.Context) ([]llm.Model, error) {
+	var out providerhttp.ModelList
+	if _, err := p.api.DoJSON(ctx, http.MethodGet, p.endpoint(ctx, "models"), nil, &out); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return out.Models("openrouter"), nil
+}
 
-	raw, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		msg := strings.TrimSpace(string(raw))
-		if msg == "" {
-			msg = resp.Status
-		}
-		if resp.StatusCode == http.StatusBadRequest {
-			return llm.InvalidArgument(msg)
-		}
-		return fmt.Errorf("openrouter http %d: %s", resp.StatusCode, msg)
-	}
+// SetKeys replaces the provider's API keys, combined as by NewProvider and
+// WithWeightedKeys, without disturbing requests in flight.
+func (p *Provider) SetKeys(apiKeys []string, weighted []keypool.Key) {
+	p.apiKeys.Replace(keypool.Combine(weighted, apiKeys))
+}
 
-	if out == nil {
-		return nil
-	}
-	if err := json.Unmarshal(raw, out); err != nil {
-		return fmt.Errorf("decode response: %w", err)
-	}
-	return nil
+// Warmup opens a connection to the upstream so the first request skips the
+// DNS and TLS handshake; see providerhttp.Warmup.
+func (p *Provider) Warmup(ctx context.Context) error {
+	return providerhttp.Warmup(ctx, p.api.HTTP, p.baseURL, p.userAgent)
+}
+
+// endpoint returns the URL of path under the configured base URL, which may
+// carry any path prefix.
+func (p *Provider) endpoint(ctx context.Context, path string) string {
+	return providerhttp.JoinURL(providerhttp.BaseURL(ctx, p.baseURL), path)
 }
@@ -0,0 +1,541 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/providerhttp"
+)
+
+func TestProvider_CreateChatCompletion_SendsProviderPreferences(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"gen-1","model":"openai/gpt-4o","choices":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	noFallbacks := false
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "openai/gpt-4o",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}},
+		ProviderPreferences: &llm.ProviderPreferences{
+			Order:          []string{"azure", "openai"},
+			AllowFallbacks: &noFallbacks,
+			DataCollection: "deny",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+
+	want := map[string]any{
+		"order":           []any{"azure", "openai"},
+		"allow_fallbacks": false,
+		"data_collection": "deny",
+	}
+	if !reflect.DeepEqual(got["provider"], want) {
+		t.Fatalf("unexpected provider preferences: %#v", got["provider"])
+	}
+}
+
+func TestProvider_CreateChatCompletion_SendsServiceTier(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"gen-1","model":"openai/gpt-4o","choices":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:       "openai/gpt-4o",
+		Messages:    []llm.ChatMessage{{Role: "user", Content: "hello"}},
+		ServiceTier: "flex",
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if got["service_tier"] != "flex" {
+		t.Fatalf("expected service_tier to be forwarded, got %#v", got["service_tier"])
+	}
+}
+
+func TestProvider_CreateChatCompletion_ContextLengthExceeded(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"This endpoint's maximum context length is 128000 tokens. However, you requested about 210000 tokens (200000 of text input, 10000 in the output). Please reduce the length of either one.","code":400}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "openai/gpt-4o",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "very long prompt"}},
+	})
+	if !errors.Is(err, llm.ErrInvalidArgument) {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "context window of 128000 tokens") {
+		t.Fatalf("expected context window message with limit, got %v", err)
+	}
+}
+
+func TestProvider_ListUpstreamModels(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/models" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"openai/gpt-4o","name":"OpenAI: GPT-4o","context_length":128000},{"id":"anthropic/claude-3.5-sonnet","name":"Anthropic: Claude 3.5 Sonnet"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	models, err := p.ListUpstreamModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListUpstreamModels error: %v", err)
+	}
+	if len(models) != 2 || models[0].ID != "openai/gpt-4o" || models[1].ID != "anthropic/claude-3.5-sonnet" {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+}
+
+func TestProvider_CreateChatCompletion_CacheControl(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+  "id":"gen-1",
+  "model":"anthropic/claude-sonnet-4",
+  "choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],
+  "usage":{"prompt_tokens":1200,"completion_tokens":5,"total_tokens":1205,"prompt_tokens_details":{"cached_tokens":1000,"cache_write_tokens":150}}
+}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ephemeral := &llm.CacheControl{Type: "ephemeral"}
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	res, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model: "anthropic/claude-sonnet-4",
+		Messages: []llm.ChatMessage{
+			{Role: "system", Content: "long shared instructions", CacheControl: ephemeral},
+			{Role: "user", ContentParts: []llm.ContentPart{
+				{Type: "text", Text: "big document", CacheControl: ephemeral},
+				{Type: "text", Text: "question"},
+			}},
+			{Role: "user", Content: "plain"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+
+	cc := map[string]any{"type": "ephemeral"}
+	msgs := got["messages"].([]any)
+	wantSystem := []any{map[string]any{"type": "text", "text": "long shared instructions", "cache_control": cc}}
+	if c := msgs[0].(map[string]any)["content"]; !reflect.DeepEqual(c, wantSystem) {
+		t.Fatalf("unexpected system content: %#v", c)
+	}
+	wantUser := []any{
+		map[string]any{"type": "text", "text": "big document", "cache_control": cc},
+		map[string]any{"type": "text", "text": "question"},
+	}
+	if c := msgs[1].(map[string]any)["content"]; !reflect.DeepEqual(c, wantUser) {
+		t.Fatalf("unexpected user content: %#v", c)
+	}
+	if c := msgs[2].(map[string]any)["content"]; c != "plain" {
+		t.Fatalf("expected unannotated message to stay a string, got %#v", c)
+	}
+
+	if res.Usage.CacheReadTokens != 1000 || res.Usage.CacheWriteTokens != 150 || res.Usage.PromptTokens != 1200 {
+		t.Fatalf("unexpected usage: %+v", res.Usage)
+	}
+}
+
+type fieldTransformer struct{}
+
+func (fieldTransformer) TransformRequest(_ context.Context, endpoint string, body map[string]any) error {
+	if !strings.HasSuffix(endpoint, "/chat/completions") {
+		return errors.New("unexpected endpoint " + endpoint)
+	}
+	body["x_custom"] = "injected"
+	body["prompt_messages"] = body["messages"]
+	delete(body, "messages")
+	return nil
+}
+
+func (fieldTransformer) TransformResponse(_ context.Context, _ string, body map[string]any) error {
+	// The bespoke endpoint names the choices list "outputs".
+	body["choices"] = body["outputs"]
+	return nil
+}
+
+func TestProvider_CreateChatCompletion_Transformers(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"gen-1","model":"m","outputs":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second,
+		WithRequestTransformer(fieldTransformer{}),
+		WithResponseTransformer(fieldTransformer{}),
+	)
+	res, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "m",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+
+	if got["x_custom"] != "injected" {
+		t.Fatalf("custom field did not reach upstream: %#v", got)
+	}
+	if _, ok := got["messages"]; ok || got["prompt_messages"] == nil {
+		t.Fatalf("expected messages renamed to prompt_messages: %#v", got)
+	}
+	if len(res.Choices) != 1 || res.Choices[0].Message.Content != "hi" {
+		t.Fatalf("response transformer not applied: %+v", res)
+	}
+}
+
+func TestProvider_BaseURLPathPrefix(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"m","data":[],"usage":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	for base, want := range map[string]string{
+		srv.URL:            "/embeddings",
+		srv.URL + "/":      "/embeddings",
+		srv.URL + "/api":   "/api/embeddings",
+		srv.URL + "/api/":  "/api/embeddings",
+		srv.URL + "/a/b/c": "/a/b/c/embeddings",
+	} {
+		p := NewProvider(base, []string{"testkey"}, 2*time.Second)
+		if _, err := p.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "m", Input: []string{"x"}}); err != nil {
+			t.Fatalf("%s: CreateEmbeddings error: %v", base, err)
+		}
+		if gotPath != want {
+			t.Fatalf("%s: upstream path = %q, want %q", base, gotPath, want)
+		}
+	}
+}
+
+func TestProvider_BaseURLOverride(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	staging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"m","data":[],"usage":{}}`))
+	}))
+	t.Cleanup(staging.Close)
+
+	p := NewProvider("http://127.0.0.1:1/unreachable", []string{"testkey"}, 2*time.Second)
+	ctx := llm.WithUpstreamBaseURL(context.Background(), staging.URL+"/v1")
+	if _, err := p.CreateEmbeddings(ctx, llm.EmbeddingsRequest{Model: "m", Input: []string{"x"}}); err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if gotPath != "/v1/embeddings" {
+		t.Fatalf("upstream path = %q, want /v1/embeddings", gotPath)
+	}
+}
+
+func TestProvider_CreateChatCompletion_SendsMetadata(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"gen-1","model":"openai/gpt-4o","choices":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hello"}}
+	if _, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "openai/gpt-4o",
+		Messages: msgs,
+		Metadata: map[string]string{"trace_id": "t-1"},
+	}); err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if want := map[string]any{"trace_id": "t-1"}; !reflect.DeepEqual(got["metadata"], want) {
+		t.Fatalf("metadata = %#v, want %#v", got["metadata"], want)
+	}
+
+	got = nil
+	if _, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "openai/gpt-4o", Messages: msgs}); err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if _, ok := got["metadata"]; ok {
+		t.Fatalf("expected metadata to be omitted when empty, got %#v", got["metadata"])
+	}
+}
+
+func TestProvider_CreateChatCompletion_HTMLErrorPage(t *testing.T) {
+	t.Parallel()
+
+	page := "<!DOCTYPE html><html><head><title>502 Bad Gateway</title></head><body>" +
+		strings.Repeat("<div>cloudflare</div>", 500) + "</body></html>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(page))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model:    "openai/gpt-4o",
+		Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	var httpErr *llm.ProviderHTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502 ProviderHTTPError, got %v", err)
+	}
+	if !strings.HasPrefix(httpErr.Message, "upstream returned non-JSON error (status 502 Bad Gateway): <!DOCTYPE html>") {
+		t.Fatalf("unexpected message: %q", httpErr.Message)
+	}
+	if len(httpErr.Message) >= len(page) {
+		t.Fatalf("expected html body to be truncated, got %d bytes", len(httpErr.Message))
+	}
+}
+
+func TestProvider_CreateCompletion(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gotPath string
+		got     map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"cmpl-1","object":"text_completion","created":1700000000,"model":"openai/gpt-3.5-turbo-instruct",
+			"choices":[{"index":0,"text":" world","finish_reason":"stop"}],
+			"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	temp, maxTokens := 0.0, uint32(8)
+	p := NewProvider(srv.URL+"/api/v1", []string{"testkey"}, 2*time.Second)
+	resp, err := p.CreateCompletion(context.Background(), llm.CompletionRequest{
+		Model:       "openai/gpt-3.5-turbo-instruct",
+		Prompt:      "hello",
+		MaxTokens:   &maxTokens,
+		Temperature: &temp,
+		Stop:        []string{"\n"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCompletion error: %v", err)
+	}
+	if gotPath != "/api/v1/completions" {
+		t.Fatalf("path = %q, want /api/v1/completions", gotPath)
+	}
+	want := map[string]any{
+		"model":       "openai/gpt-3.5-turbo-instruct",
+		"prompt":      "hello",
+		"max_tokens":  float64(8),
+		"temperature": float64(0),
+		"stop":        []any{"\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected request body: %#v", got)
+	}
+	if resp.ID != "cmpl-1" || resp.Created != 1700000000 || len(resp.Choices) != 1 ||
+		resp.Choices[0].Text != " world" || resp.Choices[0].FinishReason != "stop" || resp.Usage.TotalTokens != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestProvider_CreateChatCompletion_MaxTokensOmittedWhenUnset(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"gen-1","model":"openai/gpt-4o","choices":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	msgs := []llm.ChatMessage{{Role: "user", Content: "hello"}}
+	if _, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "openai/gpt-4o", Messages: msgs}); err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if v, ok := got["max_tokens"]; ok {
+		t.Fatalf("expected max_tokens to be omitted when unset, got %#v", v)
+	}
+
+	n := uint32(32)
+	if _, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{Model: "openai/gpt-4o", Messages: msgs, MaxTokens: &n}); err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if got["max_tokens"] != float64(32) {
+		t.Fatalf("max_tokens = %#v, want 32", got["max_tokens"])
+	}
+}
+
+func TestProvider_CapturesUpstreamRequestID(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-"+strings.TrimPrefix(r.URL.Path, "/"))
+		_, _ = w.Write([]byte(`{"id":"gen-1","model":"openai/gpt-4o","choices":[],"data":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	chat, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model: "openai/gpt-4o", Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion error: %v", err)
+	}
+	if chat.ProviderRequestID != "req-chat/completions" {
+		t.Fatalf("chat ProviderRequestID = %q", chat.ProviderRequestID)
+	}
+	emb, err := p.CreateEmbeddings(context.Background(), llm.EmbeddingsRequest{Model: "openai/text-embedding-3-small", Input: []string{"hi"}})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if emb.ProviderRequestID != "req-embeddings" {
+		t.Fatalf("embeddings ProviderRequestID = %q", emb.ProviderRequestID)
+	}
+}
+
+func TestProvider_ErrorCarriesUpstreamRequestID(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req-failed")
+		http.Error(w, "upstream exploded", http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second)
+	_, err := p.CreateChatCompletion(context.Background(), llm.ChatCompletionRequest{
+		Model: "openai/gpt-4o", Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	var perr *llm.ProviderHTTPError
+	if !errors.As(err, &perr) || perr.RequestID != "req-failed" {
+		t.Fatalf("expected a ProviderHTTPError with the upstream request id, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "req-failed") {
+		t.Fatalf("expected the request id in the message, got %q", err.Error())
+	}
+}
+
+func TestProvider_CreateChatCompletion_RejectsOversizedResponse(t *testing.T) {
+	t.Parallel()
+
+	body := `{"id":"gen-1","model":"m","choices":[{"message":{"role":"assistant","content":"` + strings.Repeat("x", 4096) + `"}}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	req := llm.ChatCompletionRequest{Model: "m", Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}}}
+	small := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second, WithMaxResponseBytes(1024))
+	if _, err := small.CreateChatCompletion(context.Background(), req); !errors.Is(err, providerhttp.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	large := NewProvider(srv.URL, []string{"testkey"}, 2*time.Second, WithMaxResponseBytes(int64(len(body))))
+	resp, err := large.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("response at the limit: %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; len(got) != 4096 {
+		t.Fatalf("unexpected content length %d", len(got))
+	}
+}
+
+func TestProvider_SetKeys_NewRequestsUseNewKey(t *testing.T) {
+	t.Parallel()
+
+	var auth atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"gen-1","model":"m","choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(srv.URL, []string{"leaked"}, 2*time.Second)
+	req := llm.ChatCompletionRequest{Model: "m", Messages: []llm.ChatMessage{{Role: "user", Content: "hello"}}}
+	if _, err := p.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := auth.Load(); got != "Bearer leaked" {
+		t.Fatalf("unexpected Authorization before reload: %v", got)
+	}
+
+	p.SetKeys([]string{"rotated"}, nil)
+	for i := 0; i < 3; i++ {
+		if _, err := p.CreateChatCompletion(context.Background(), req); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+		if got := auth.Load(); got != "Bearer rotated" {
+			t.Fatalf("expected the rotated key after SetKeys, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package providerhttp
+
+import (
+	"context"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// BaseURL returns the per-request base URL override in ctx (see
+// llm.WithUpstreamBaseURL), or def if there is none.
+func BaseURL(ctx context.Context, def string) string {
+	if base := llm.UpstreamBaseURL(ctx); base != "" {
+		return base
+	}
+	return def
+}
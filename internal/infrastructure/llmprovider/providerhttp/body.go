@@ -0,0 +1,35 @@
+package providerhttp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// ErrResponseTooLarge is returned when an upstream response body exceeds the
+// provider's size limit. It is llm.ErrResponseTooLarge, so the transports can
+// map it without importing this package.
+var ErrResponseTooLarge = llm.ErrResponseTooLarge
+
+// DefaultMaxResponseBytes bounds non-streamed upstream response bodies when a
+// provider sets no limit. Large embeddings batches stay well below it.
+const DefaultMaxResponseBytes = 64 << 20 // 64MiB
+
+// ReadBody reads r up to max bytes. A longer body yields its first max bytes
+// (enough to parse an error message from) and an error wrapping
+// ErrResponseTooLarge; the rest is left unread. max <= 0 means
+// DefaultMaxResponseBytes.
+func ReadBody(r io.Reader, max int64) ([]byte, error) {
+	if max <= 0 {
+		max = DefaultMaxResponseBytes
+	}
+	b, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return b, err
+	}
+	if int64(len(b)) > max {
+		return b[:max], fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, max)
+	}
+	return b, nil
+}
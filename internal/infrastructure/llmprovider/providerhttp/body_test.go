@@ -0,0 +1,23 @@
+package providerhttp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+	t.Parallel()
+
+	b, err := ReadBody(strings.NewReader("hello"), 5)
+	if err != nil || string(b) != "hello" {
+		t.Fatalf("body at the limit: %q, %v", b, err)
+	}
+	b, err = ReadBody(strings.NewReader("hello!"), 5)
+	if !errors.Is(err, ErrResponseTooLarge) || string(b) != "hello" {
+		t.Fatalf("oversized body: %q, %v", b, err)
+	}
+	if b, err = ReadBody(strings.NewReader("hello!"), 0); err != nil || string(b) != "hello!" {
+		t.Fatalf("default limit: %q, %v", b, err)
+	}
+}
@@ -0,0 +1,69 @@
+package providerhttp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TransportOptions configures the connection layer shared by provider clients.
+// Zero values keep net/http's defaults.
+type TransportOptions struct {
+	// ProxyURL routes upstream traffic through an HTTP(S) proxy. Empty uses
+	// the HTTPS_PROXY/NO_PROXY environment.
+	ProxyURL string
+
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+	// MaxIdleConnsPerHost bounds the keep-alive pool per upstream host;
+	// net/http's default of 2 is far too small for a gateway.
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// DefaultMaxIdleConnsPerHost is used when TransportOptions leaves it unset.
+const DefaultMaxIdleConnsPerHost = 64
+
+// NewTransport builds an upstream transport. Share one between providers to
+// pool connections and apply proxy/TLS settings in one place.
+func NewTransport(opts TransportOptions) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.ProxyURL != "" {
+		u, err := url.Parse(opts.ProxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", opts.ProxyURL)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if opts.DialTimeout > 0 {
+		t.DialContext = (&net.Dialer{Timeout: opts.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if opts.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
+	t.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	return t, nil
+}
+
+// ClientOptions configures one provider HTTP client.
+type ClientOptions struct {
+	// Timeout bounds a whole request including reading the body; 0 means no
+	// limit (streaming clients rely on an IdleTimeout instead).
+	Timeout time.Duration
+	// Transport is typically shared between providers; nil uses http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// NewClient builds a provider HTTP client. Timeouts stay per client even when
+// the transport is shared.
+func NewClient(opts ClientOptions) *http.Client {
+	return &http.Client{Timeout: opts.Timeout, Transport: opts.Transport}
+}
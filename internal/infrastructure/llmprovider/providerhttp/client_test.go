@@ -0,0 +1,27 @@
+package providerhttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	t.Parallel()
+
+	tr, err := NewTransport(TransportOptions{ProxyURL: "http://proxy.internal:3128", IdleConnTimeout: time.Minute})
+	if err != nil {
+		t.Fatalf("NewTransport error: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/v1", nil)
+	if u, err := tr.Proxy(req); err != nil || u == nil || u.Host != "proxy.internal:3128" {
+		t.Fatalf("unexpected proxy: %v, %v", u, err)
+	}
+	if tr.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost || tr.IdleConnTimeout != time.Minute {
+		t.Fatalf("unexpected pool settings: %d, %v", tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+
+	if _, err := NewTransport(TransportOptions{ProxyURL: "proxy.internal"}); err == nil {
+		t.Fatal("expected error for proxy url without scheme")
+	}
+}
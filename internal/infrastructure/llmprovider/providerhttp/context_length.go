@@ -0,0 +1,45 @@
+package providerhttp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Upstream phrasings of "prompt too long". The first capture group, when
+// present, is the model's context window in tokens.
+var contextLengthPatterns = []*regexp.Regexp{
+	// OpenAI / OpenRouter: "This model's maximum context length is 8192 tokens."
+	regexp.MustCompile(`(?i)maximum context length is (\d+)`),
+	// DashScope: "Range of input length should be [1, 30720]"
+	regexp.MustCompile(`(?i)range of input length should be \[\d+,\s*(\d+)\]`),
+	regexp.MustCompile(`(?i)context_length_exceeded|context window|prompt is too long`),
+}
+
+// ContextLengthExceeded reports whether an upstream error body says the prompt
+// does not fit the model's context window, and the window size if stated (0 otherwise).
+func ContextLengthExceeded(body string) (limit int, ok bool) {
+	for _, re := range contextLengthPatterns {
+		m := re.FindStringSubmatch(body)
+		if m == nil {
+			continue
+		}
+		if len(m) > 1 {
+			limit, _ = strconv.Atoi(m[1])
+		}
+		return limit, true
+	}
+	return 0, false
+}
+
+// ContextLengthMessage is the client-facing message for a context-length error.
+func ContextLengthMessage(limit int) string {
+	var b strings.Builder
+	b.WriteString("prompt exceeds the model's context window")
+	if limit > 0 {
+		fmt.Fprintf(&b, " of %d tokens", limit)
+	}
+	b.WriteString("; shorten the messages or lower max_tokens")
+	return b.String()
+}
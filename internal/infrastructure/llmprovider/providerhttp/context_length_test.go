@@ -0,0 +1,24 @@
+package providerhttp
+
+import "testing"
+
+func TestContextLengthExceeded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body      string
+		wantOK    bool
+		wantLimit int
+	}{
+		{body: `{"error":{"message":"This endpoint's maximum context length is 128000 tokens. However, you requested about 200000 tokens","code":400}}`, wantOK: true, wantLimit: 128000},
+		{body: `{"error":{"code":"invalid_parameter_error","message":"Range of input length should be [1, 30720]"}}`, wantOK: true, wantLimit: 30720},
+		{body: `{"error":{"code":"context_length_exceeded","message":"too long"}}`, wantOK: true},
+		{body: `{"error":{"message":"temperature must be <= 2"}}`},
+	}
+	for _, tt := range tests {
+		limit, ok := ContextLengthExceeded(tt.body)
+		if ok != tt.wantOK || limit != tt.wantLimit {
+			t.Fatalf("ContextLengthExceeded(%s) = (%d, %v), want (%d, %v)", tt.body, limit, ok, tt.wantLimit, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package providerhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxNonJSONErrorBody bounds how much of a non-JSON upstream error body is kept
+// in the error message.
+const MaxNonJSONErrorBody = 256
+
+// ErrorMessage returns the message for an upstream error response. JSON bodies
+// are passed through; anything else (typically an HTML page from a CDN or load
+// balancer) is whitespace-collapsed, truncated and labelled with the status.
+func ErrorMessage(resp *http.Response, raw []byte) string {
+	body := strings.TrimSpace(string(raw))
+	if body == "" {
+		return resp.Status
+	}
+	if json.Valid([]byte(body)) {
+		return body
+	}
+	body = strings.Join(strings.Fields(body), " ")
+	if len(body) > MaxNonJSONErrorBody {
+		cut := MaxNonJSONErrorBody
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = body[:cut] + "..."
+	}
+	return fmt.Sprintf("upstream returned non-JSON error (status %s): %s", resp.Status, body)
+}
@@ -0,0 +1,37 @@
+package providerhttp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+	html := "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>" + strings.Repeat("<p>cdn</p>\n", 200) + "</body></html>"
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "empty uses status", raw: "  ", want: "502 Bad Gateway"},
+		{name: "json passes through", raw: ` {"error":{"message":"bad"}} `, want: `{"error":{"message":"bad"}}`},
+		{name: "plain text labelled", raw: "upstream\n  down", want: "upstream returned non-JSON error (status 502 Bad Gateway): upstream down"},
+	}
+	for _, tt := range tests {
+		if got := ErrorMessage(resp, []byte(tt.raw)); got != tt.want {
+			t.Fatalf("%s: ErrorMessage = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	got := ErrorMessage(resp, []byte(html))
+	if !strings.HasPrefix(got, "upstream returned non-JSON error (status 502 Bad Gateway): <html> <head>") {
+		t.Fatalf("unexpected html message: %q", got)
+	}
+	if !strings.HasSuffix(got, "...") || len(got) > MaxNonJSONErrorBody+100 {
+		t.Fatalf("html body not truncated (%d bytes): %q", len(got), got)
+	}
+}
@@ -0,0 +1,164 @@
+package providerhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/keypool"
+)
+
+// API sends authenticated requests to an OpenAI-compatible upstream and maps
+// its error statuses to domain errors. Providers build one in NewProvider and
+// keep only their endpoints and wire shapes.
+type API struct {
+	Provider string // names the provider in errors, e.g. "dashscope"
+	Keys     *keypool.Pool
+	// Authorize sets apiKey on r; nil sends it as a bearer token.
+	Authorize func(r *http.Request, apiKey string)
+
+	HTTP *http.Client
+	// Stream has no total timeout; streams are bounded by StreamIdleTimeout instead.
+	Stream            *http.Client
+	StreamIdleTimeout time.Duration
+	// MaxResponseBytes bounds non-streamed response bodies; 0 uses DefaultMaxResponseBytes.
+	MaxResponseBytes int64
+
+	UserAgent string
+	// Headers are sent with every request; they cannot override Content-Type
+	// or the API key.
+	Headers map[string]string
+
+	RequestTransformer  RequestTransformer
+	ResponseTransformer ResponseTransformer
+}
+
+// DoJSON sends in (if non-nil) and decodes the response into out (if
+// non-nil). It returns the upstream's request id (see RequestID), if any.
+func (a *API) DoJSON(ctx context.Context, method, url string, in any, out any) (string, error) {
+	r, apiKey, err := a.newRequest(ctx, method, url, in)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := Client(ctx, a.HTTP).Do(r)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	requestID := RequestID(resp.Header)
+	raw, readErr := ReadBody(resp.Body, a.MaxResponseBytes)
+	if err := a.checkStatus(resp, apiKey, raw); err != nil {
+		return "", err
+	}
+	if readErr != nil {
+		return "", fmt.Errorf("read response: %w", readErr)
+	}
+
+	if out == nil {
+		return requestID, nil
+	}
+	raw, err = TransformResponse(ctx, a.ResponseTransformer, url, raw)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	return requestID, nil
+}
+
+// DoStream POSTs in and calls fn with each server-sent event of the response.
+func (a *API) DoStream(ctx context.Context, url string, in any, fn func(data []byte) error) error {
+	ctx, idle, cancel := NewIdleTimeout(ctx, a.StreamIdleTimeout)
+	defer cancel()
+
+	r, apiKey, err := a.newRequest(ctx, http.MethodPost, url, in)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Accept", "text/event-stream")
+
+	resp, err := a.Stream.Do(r)
+	if err != nil {
+		return StreamErr(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		raw, _ := ReadBody(resp.Body, a.MaxResponseBytes)
+		return a.checkStatus(resp, apiKey, raw)
+	}
+	return StreamErr(ctx, ReadSSE(idle.Reader(resp.Body), fn))
+}
+
+// newRequest builds an authenticated upstream request with a JSON body (if in is non-nil).
+func (a *API) newRequest(ctx context.Context, method, url string, in any) (*http.Request, string, error) {
+	apiKey, ok := a.Keys.Next()
+	if !ok {
+		return nil, "", fmt.Errorf("%s api key is empty", a.Provider)
+	}
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, "", err
+		}
+		b, err = TransformRequest(ctx, a.RequestTransformer, url, b)
+		if err != nil {
+			return nil, "", err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, "", err
+	}
+	for k, v := range a.Headers {
+		r.Header.Set(k, v)
+	}
+	r.Header.Set("User-Agent", a.UserAgent)
+	if in != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	if a.Authorize != nil {
+		a.Authorize(r, apiKey)
+	} else {
+		r.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return r, apiKey, nil
+}
+
+// checkStatus maps an upstream error status to a domain error; raw is the response body.
+func (a *API) checkStatus(resp *http.Response, apiKey string, raw []byte) error {
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusTooManyRequests {
+		// Let other keys take traffic while this one is rejected or throttled.
+		a.Keys.Sideline(apiKey)
+	}
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	msg := ErrorMessage(resp, raw)
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusRequestEntityTooLarge {
+		if limit, ok := ContextLengthExceeded(msg); ok {
+			return llm.InvalidArgument(ContextLengthMessage(limit))
+		}
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		return llm.InvalidArgument(msg)
+	}
+	return &llm.ProviderHTTPError{
+		Provider:   a.Provider,
+		StatusCode: resp.StatusCode,
+		Message:    msg,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		RequestID:  RequestID(resp.Header),
+	}
+}
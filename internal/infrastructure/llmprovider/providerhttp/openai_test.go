@@ -0,0 +1,77 @@
+package providerhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/keypool"
+)
+
+func TestAPI_DoJSON(t *testing.T) {
+	t.Parallel()
+
+	var auth, custom string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, custom = r.Header.Get("Authorization"), r.Header.Get("X-Custom")
+		w.Header().Set("X-Request-Id", "req-1")
+		if r.URL.Path == "/fail" {
+			http.Error(w, "upstream exploded", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"x"}`))
+	}))
+	defer srv.Close()
+
+	api := &API{
+		Provider: "test",
+		Keys:     keypool.New([]string{"k"}, time.Minute),
+		HTTP:     srv.Client(),
+		Headers:  map[string]string{"X-Custom": "v"},
+	}
+	var out struct {
+		ID string `json:"id"`
+	}
+	requestID, err := api.DoJSON(context.Background(), http.MethodPost, srv.URL+"/ok", map[string]string{"a": "b"}, &out)
+	if err != nil {
+		t.Fatalf("DoJSON: %v", err)
+	}
+	if out.ID != "x" || requestID != "req-1" || auth != "Bearer k" || custom != "v" {
+		t.Fatalf("unexpected call: id=%q request=%q auth=%q custom=%q", out.ID, requestID, auth, custom)
+	}
+
+	_, err = api.DoJSON(context.Background(), http.MethodGet, srv.URL+"/fail", nil, &out)
+	var perr *llm.ProviderHTTPError
+	if !errors.As(err, &perr) || perr.Provider != "test" || perr.StatusCode != http.StatusBadGateway || perr.RequestID != "req-1" {
+		t.Fatalf("expected a ProviderHTTPError, got %v", err)
+	}
+
+	api.Authorize = func(r *http.Request, apiKey string) { r.Header.Set("api-key", apiKey) }
+	if _, err := api.DoJSON(context.Background(), http.MethodGet, srv.URL+"/ok", nil, nil); err != nil {
+		t.Fatalf("DoJSON with Authorize: %v", err)
+	}
+	if auth != "" {
+		t.Fatalf("Authorize should replace the bearer token, got %q", auth)
+	}
+}
+
+func TestDecodeChatChunk_Reasoning(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []string{
+		`{"choices":[{"delta":{"reasoning":"think"}}]}`,
+		`{"choices":[{"delta":{"reasoning_content":"think"}}]}`,
+	} {
+		c, err := DecodeChatChunk([]byte(data))
+		if err != nil {
+			t.Fatalf("DecodeChatChunk(%s): %v", data, err)
+		}
+		if len(c.Choices) != 1 || c.Choices[0].Delta.Reasoning != "think" {
+			t.Fatalf("DecodeChatChunk(%s) = %+v", data, c)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package providerhttp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// OpenAI-compatible wire shapes (minimal subset) shared by the providers.
+// Providers with extra request fields embed ChatRequest in their own body type.
+
+// ChatImageURL is the image_url of a chat content part.
+type ChatImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ChatContentPart is one part of a multimodal chat message.
+type ChatContentPart struct {
+	Type     string        `json:"type"`
+	Text     string        `json:"text,omitempty"`
+	ImageURL *ChatImageURL `json:"image_url,omitempty"`
+}
+
+// ChatRequestMessage supports both simple text content and multimodal content.
+type ChatRequestMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"` // string or []ChatContentPart
+	Name    string `json:"name,omitempty"`
+}
+
+// ChatAudioOutput configures audio output.
+type ChatAudioOutput struct {
+	Voice  string `json:"voice"`
+	Format string `json:"format"`
+}
+
+// ChatStreamOptions asks for a final usage chunk on streams.
+type ChatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// ChatRequest is the chat completions request body.
+type ChatRequest struct {
+	Model         string               `json:"model"`
+	Messages      []ChatRequestMessage `json:"messages"`
+	Temperature   *float64             `json:"temperature,omitempty"`
+	MaxTokens     *uint32              `json:"max_tokens,omitempty"`
+	Stop          []string             `json:"stop,omitempty"`
+	N             *uint32              `json:"n,omitempty"`
+	User          string               `json:"user,omitempty"`
+	Modalities    []string             `json:"modalities,omitempty"`
+	Audio         *ChatAudioOutput     `json:"audio,omitempty"`
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *ChatStreamOptions   `json:"stream_options,omitempty"`
+}
+
+// NewChatRequest builds the chat completions request body for req.
+func NewChatRequest(req llm.ChatCompletionRequest, stream bool) ChatRequest {
+	msgs := make([]ChatRequestMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		var content any
+		if len(m.ContentParts) > 0 {
+			// Multimodal message with content parts (for vision models).
+			parts := make([]ChatContentPart, 0, len(m.ContentParts))
+			for _, cp := range m.ContentParts {
+				part := ChatContentPart{Type: cp.Type, Text: cp.Text}
+				if cp.ImageURL != nil {
+					part.ImageURL = &ChatImageURL{URL: cp.ImageURL.URL, Detail: cp.ImageURL.Detail}
+				}
+				parts = append(parts, part)
+			}
+			content = parts
+		} else {
+			// Simple text message.
+			content = m.Content
+		}
+		msgs = append(msgs, ChatRequestMessage{Role: m.Role, Content: content, Name: m.Name})
+	}
+
+	body := ChatRequest{
+		Model:       req.Model,
+		Messages:    msgs,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stop:        req.Stop,
+		N:           req.N,
+		User:        req.User,
+		Modalities:  req.Modalities,
+	}
+	if req.Audio != nil {
+		body.Audio = &ChatAudioOutput{Voice: req.Audio.Voice, Format: req.Audio.Format}
+	}
+	if stream {
+		// Ask for a final usage chunk so streamed calls are still metered.
+		body.Stream = true
+		body.StreamOptions = &ChatStreamOptions{IncludeUsage: true}
+	}
+	return body
+}
+
+// ChatResponse is a non-streamed chat completions response.
+type ChatResponse struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index   uint32 `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+			Name    string `json:"name,omitempty"`
+			Audio   *struct {
+				ID         string `json:"id"`
+				Data       string `json:"data"`
+				Transcript string `json:"transcript"`
+				ExpiresAt  int64  `json:"expires_at"`
+			} `json:"audio,omitempty"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// ChatCompletion converts r to the domain shape. req is the request it
+// answers; requestID is the upstream's request id.
+func (r ChatResponse) ChatCompletion(req llm.ChatCompletionRequest, requestID string) llm.ChatCompletionResponse {
+	choices := make([]llm.ChatCompletionChoice, 0, len(r.Choices))
+	for _, c := range r.Choices {
+		msg := llm.ChatMessage{
+			Role:    c.Message.Role,
+			Content: c.Message.Content,
+			Name:    c.Message.Name,
+		}
+		if a := c.Message.Audio; a != nil {
+			// The upstream doesn't echo the format, so take it from the request.
+			format := ""
+			if req.Audio != nil {
+				format = req.Audio.Format
+			}
+			msg.Audio = &llm.AudioContent{
+				ID:         a.ID,
+				Data:       a.Data,
+				Format:     format,
+				Transcript: a.Transcript,
+				ExpiresAt:  a.ExpiresAt,
+			}
+		}
+		choices = append(choices, llm.ChatCompletionChoice{
+			Index:        c.Index,
+			Message:      msg,
+			FinishReason: c.FinishReason,
+		})
+	}
+
+	return llm.ChatCompletionResponse{
+		ID:                r.ID,
+		Created:           r.Created,
+		Model:             r.Model,
+		Choices:           choices,
+		Usage:             r.Usage.TokenUsage(),
+		ProviderRequestID: requestID,
+	}
+}
+
+// ChatChunk is one server-sent event of a streamed chat completion.
+type ChatChunk struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index uint32 `json:"index"`
+		Delta struct {
+			Role      string `json:"role"`
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    uint32 `json:"index"`
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+			// Reasoning models stream their reasoning in one of these,
+			// depending on the provider (OpenRouter, DashScope QwQ/Qwen3).
+			Reasoning        string `json:"reasoning"`
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+}
+
+// DecodeChatChunk decodes one stream event into the domain shape.
+func DecodeChatChunk(data []byte) (llm.ChatCompletionChunk, error) {
+	var c ChatChunk
+	if err := json.Unmarshal(data, &c); err != nil {
+		return llm.ChatCompletionChunk{}, fmt.Errorf("decode stream chunk: %w", err)
+	}
+	out := llm.ChatCompletionChunk{
+		ID:      c.ID,
+		Created: c.Created,
+		Model:   c.Model,
+		Choices: make([]llm.ChatCompletionChunkChoice, 0, len(c.Choices)),
+	}
+	for _, ch := range c.Choices {
+		d := llm.ChatMessage{Role: ch.Delta.Role, Content: ch.Delta.Content, Reasoning: ch.Delta.Reasoning + ch.Delta.ReasoningContent}
+		for _, tc := range ch.Delta.ToolCalls {
+			d.ToolCalls = append(d.ToolCalls, llm.ToolCall{
+				Index:    tc.Index,
+				ID:       tc.ID,
+				Type:     tc.Type,
+				Function: llm.ToolCallFunction{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+			})
+		}
+		out.Choices = append(out.Choices, llm.ChatCompletionChunkChoice{
+			Index:        ch.Index,
+			Delta:        d,
+			FinishReason: ch.FinishReason,
+		})
+	}
+	if c.Usage != nil {
+		u := c.Usage.TokenUsage()
+		out.Usage = &u
+	}
+	return out, nil
+}
+
+// CompletionRequest is the legacy /completions request body.
+type CompletionRequest struct {
+	Model       string   `json:"model"`
+	Prompt      string   `json:"prompt"`
+	MaxTokens   *uint32  `json:"max_tokens,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+	N           *uint32  `json:"n,omitempty"`
+	User        string   `json:"user,omitempty"`
+}
+
+// NewCompletionRequest builds the legacy completions request body for req.
+func NewCompletionRequest(req llm.CompletionRequest) CompletionRequest {
+	return CompletionRequest{
+		Model:       req.Model,
+		Prompt:      req.Prompt,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stop:        req.Stop,
+		N:           req.N,
+		User:        req.User,
+	}
+}
+
+// CompletionResponse is a legacy /completions response.
+type CompletionResponse struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index        uint32 `json:"index"`
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// Completion converts r to the domain shape; requestID is the upstream's request id.
+func (r CompletionResponse) Completion(requestID string) llm.CompletionResponse {
+	choices := make([]llm.CompletionChoice, 0, len(r.Choices))
+	for _, c := range r.Choices {
+		choices = append(choices, llm.CompletionChoice{Index: c.Index, Text: c.Text, FinishReason: c.FinishReason})
+	}
+	return llm.CompletionResponse{
+		ID:                r.ID,
+		Created:           r.Created,
+		Model:             r.Model,
+		Choices:           choices,
+		Usage:             r.Usage.TokenUsage(),
+		ProviderRequestID: requestID,
+	}
+}
+
+// EmbeddingsRequest is the embeddings request body.
+type EmbeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+	User  string   `json:"user,omitempty"`
+}
+
+// NewEmbeddingsRequest builds the embeddings request body for req.
+func NewEmbeddingsRequest(req llm.EmbeddingsRequest) EmbeddingsRequest {
+	return EmbeddingsRequest{Model: req.Model, Input: req.Input, User: req.User}
+}
+
+// EmbeddingsResponse is an embeddings response.
+type EmbeddingsResponse struct {
+	ID    string `json:"id"`
+	Model string `json:"model"`
+	Data  []struct {
+		Index     uint32    `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens uint32 `json:"prompt_tokens"`
+		TotalTokens  uint32 `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Embeddings converts r to the domain shape; requestID is the upstream's request id.
+func (r EmbeddingsResponse) Embeddings(requestID string) llm.EmbeddingsResponse {
+	data := make([]llm.Embedding, 0, len(r.Data))
+	for _, d := range r.Data {
+		data = append(data, llm.Embedding{Index: d.Index, Vector: d.Embedding})
+	}
+	return llm.EmbeddingsResponse{
+		ID:    r.ID,
+		Model: r.Model,
+		Data:  data,
+		Usage: llm.EmbeddingsUsage{
+			PromptTokens: r.Usage.PromptTokens,
+			TotalTokens:  r.Usage.TotalTokens,
+		},
+		ProviderRequestID: requestID,
+	}
+}
+
+// ModelList is the response of the upstream /models endpoint.
+type ModelList struct {
+	Data []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+// Models converts l to domain models of the given provider.
+func (l ModelList) Models(provider string) []llm.Model {
+	models := make([]llm.Model, 0, len(l.Data))
+	for _, m := range l.Data {
+		models = append(models, llm.Model{ID: m.ID, Name: m.Name, Provider: provider})
+	}
+	return models
+}
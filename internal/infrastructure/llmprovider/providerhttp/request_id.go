@@ -0,0 +1,18 @@
+package providerhttp
+
+import "net/http"
+
+// requestIDHeaders are the response headers upstreams use for their own
+// request id, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "Openai-Request-Id", "Apim-Request-Id"}
+
+// RequestID returns the upstream's request id from response headers, or "".
+// It is what a provider's support needs to trace a call.
+func RequestID(h http.Header) string {
+	for _, k := range requestIDHeaders {
+		if v := h.Get(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
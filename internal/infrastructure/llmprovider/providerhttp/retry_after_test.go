@@ -0,0 +1,30 @@
+package providerhttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{in: "", want: 0},
+		{in: "3", want: 3 * time.Second},
+		{in: " 10 ", want: 10 * time.Second},
+		{in: "-1", want: 0},
+		{in: "soon", want: 0},
+		{in: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{in: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+	}
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.in, now); got != tt.want {
+			t.Fatalf("ParseRetryAfter(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package providerhttp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// ErrStreamIdle is returned when a streamed upstream response sends nothing
+// for longer than the stream idle timeout. It is llm.ErrStreamIdle, so the
+// transports can map it without importing this package.
+var ErrStreamIdle = llm.ErrStreamIdle
+
+// DefaultStreamIdleTimeout is used when a provider sets no stream idle timeout.
+const DefaultStreamIdleTimeout = 60 * time.Second
+
+// maxSSELine bounds a single server-sent event line; chunks with large tool
+// arguments or audio deltas can exceed bufio's 64KiB default.
+const maxSSELine = 4 << 20
+
+// IdleTimeout cancels a stream's context when it goes quiet. Unlike
+// http.Client.Timeout it bounds the gap between reads, not the total duration,
+// so a slow but steady stream runs for as long as it keeps sending.
+type IdleTimeout struct {
+	d     time.Duration
+	timer *time.Timer
+}
+
+// NewIdleTimeout derives a context that is cancelled with ErrStreamIdle once
+// no data has been read through Reader for d. d <= 0 disables the timeout.
+// The returned cancel func must be called to release the timer.
+func NewIdleTimeout(ctx context.Context, d time.Duration) (context.Context, *IdleTimeout, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	t := &IdleTimeout{d: d}
+	if d > 0 {
+		t.timer = time.AfterFunc(d, func() { cancel(ErrStreamIdle) })
+	}
+	return ctx, t, func() {
+		if t.timer != nil {
+			t.timer.Stop()
+		}
+		cancel(context.Canceled)
+	}
+}
+
+// Reader wraps r so every successful read pushes the idle deadline back.
+func (t *IdleTimeout) Reader(r io.Reader) io.Reader {
+	if t.timer == nil {
+		return r
+	}
+	return &idleReader{r: r, t: t}
+}
+
+type idleReader struct {
+	r io.Reader
+	t *IdleTimeout
+}
+
+func (ir *idleReader) Read(p []byte) (int, error) {
+	n, err := ir.r.Read(p)
+	if n > 0 {
+		ir.t.timer.Reset(ir.t.d)
+	}
+	return n, err
+}
+
+// StreamErr reports ErrStreamIdle when ctx was cancelled by an IdleTimeout,
+// and err otherwise.
+func StreamErr(ctx context.Context, err error) error {
+	if err != nil && errors.Is(context.Cause(ctx), ErrStreamIdle) {
+		return ErrStreamIdle
+	}
+	return err
+}
+
+// ReadSSE calls fn with the data of each server-sent event in r until EOF or
+// the OpenAI-style "[DONE]" sentinel. Comments and other fields are ignored.
+func ReadSSE(r io.Reader, fn func(data []byte) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxSSELine)
+	var data []byte
+	dispatch := func() error {
+		if len(data) == 0 {
+			return nil
+		}
+		d := data
+		data = nil
+		return fn(d)
+	}
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			if err := dispatch(); err != nil {
+				return err
+			}
+			continue
+		}
+		v, ok := bytes.CutPrefix(line, []byte("data:"))
+		if !ok {
+			continue
+		}
+		v = bytes.TrimPrefix(v, []byte(" "))
+		if bytes.Equal(v, []byte("[DONE]")) {
+			return nil
+		}
+		if len(data) > 0 {
+			data = append(data, '\n')
+		}
+		data = append(data, v...)
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return dispatch()
+}
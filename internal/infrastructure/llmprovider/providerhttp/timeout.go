@@ -0,0 +1,21 @@
+package providerhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// Client returns c, or a copy with the longer timeout when ctx carries an
+// extended upstream timeout (see llm.WithUpstreamTimeout). The copy shares
+// c's transport and connection pool.
+func Client(ctx context.Context, c *http.Client) *http.Client {
+	d := llm.UpstreamTimeout(ctx)
+	if c.Timeout <= 0 || d <= c.Timeout {
+		return c
+	}
+	extended := *c
+	extended.Timeout = d
+	return &extended
+}
@@ -0,0 +1,30 @@
+package providerhttp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+func TestClient_ExtendsTimeout(t *testing.T) {
+	t.Parallel()
+
+	c := &http.Client{Timeout: time.Minute}
+	if got := Client(context.Background(), c); got != c {
+		t.Fatal("expected the client itself without an extended timeout")
+	}
+	if got := Client(llm.WithUpstreamTimeout(context.Background(), time.Second), c); got != c {
+		t.Fatal("a shorter upstream timeout must not shorten the client's")
+	}
+	got := Client(llm.WithUpstreamTimeout(context.Background(), time.Hour), c)
+	if got.Timeout != time.Hour || c.Timeout != time.Minute {
+		t.Fatalf("expected an extended copy, got %v (original %v)", got.Timeout, c.Timeout)
+	}
+	stream := &http.Client{}
+	if got := Client(llm.WithUpstreamTimeout(context.Background(), time.Hour), stream); got != stream {
+		t.Fatal("a client without a timeout must stay unbounded")
+	}
+}
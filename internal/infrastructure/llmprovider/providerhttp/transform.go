@@ -0,0 +1,51 @@
+package providerhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RequestTransformer rewrites a provider's outgoing JSON body, e.g. to rename
+// fields for a bespoke endpoint. endpoint is the upstream URL being called;
+// body is the decoded top-level JSON object and may be modified in place.
+type RequestTransformer interface {
+	TransformRequest(ctx context.Context, endpoint string, body map[string]any) error
+}
+
+// ResponseTransformer rewrites a provider's JSON response before it is
+// decoded. Streamed responses are not transformed.
+type ResponseTransformer interface {
+	TransformResponse(ctx context.Context, endpoint string, body map[string]any) error
+}
+
+// TransformRequest applies t to the encoded body b. A nil t returns b unchanged.
+func TransformRequest(ctx context.Context, t RequestTransformer, endpoint string, b []byte) ([]byte, error) {
+	if t == nil {
+		return b, nil
+	}
+	return transformJSON(b, func(body map[string]any) error {
+		return t.TransformRequest(ctx, endpoint, body)
+	})
+}
+
+// TransformResponse applies t to the raw response body b. A nil t returns b unchanged.
+func TransformResponse(ctx context.Context, t ResponseTransformer, endpoint string, b []byte) ([]byte, error) {
+	if t == nil {
+		return b, nil
+	}
+	return transformJSON(b, func(body map[string]any) error {
+		return t.TransformResponse(ctx, endpoint, body)
+	})
+}
+
+func transformJSON(b []byte, fn func(map[string]any) error) ([]byte, error) {
+	var body map[string]any
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil, fmt.Errorf("transform: body is not a JSON object: %w", err)
+	}
+	if err := fn(body); err != nil {
+		return nil, fmt.Errorf("transform: %w", err)
+	}
+	return json.Marshal(body)
+}
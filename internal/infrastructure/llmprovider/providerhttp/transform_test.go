@@ -0,0 +1,38 @@
+package providerhttp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type transformFunc func(body map[string]any) error
+
+func (f transformFunc) TransformRequest(_ context.Context, _ string, body map[string]any) error {
+	return f(body)
+}
+
+func TestTransformRequest(t *testing.T) {
+	t.Parallel()
+
+	in := []byte(`{"model":"m"}`)
+	if out, err := TransformRequest(context.Background(), nil, "", in); err != nil || string(out) != string(in) {
+		t.Fatalf("nil transformer changed body: %s, %v", out, err)
+	}
+
+	out, err := TransformRequest(context.Background(), transformFunc(func(body map[string]any) error {
+		body["extra"] = true
+		return nil
+	}), "", in)
+	if err != nil || string(out) != `{"extra":true,"model":"m"}` {
+		t.Fatalf("unexpected result: %s, %v", out, err)
+	}
+
+	boom := errors.New("boom")
+	if _, err := TransformRequest(context.Background(), transformFunc(func(map[string]any) error { return boom }), "", in); !errors.Is(err, boom) {
+		t.Fatalf("expected transformer error, got %v", err)
+	}
+	if _, err := TransformRequest(context.Background(), transformFunc(func(map[string]any) error { return nil }), "", []byte(`[1]`)); err == nil {
+		t.Fatal("expected error for non-object body")
+	}
+}
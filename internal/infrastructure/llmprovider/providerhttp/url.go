@@ -0,0 +1,23 @@
+package providerhttp
+
+import (
+	"net/url"
+	"strings"
+)
+
+// JoinURL appends path elements to base, which may be mounted under any
+// prefix (e.g. "https://host/api" or "https://host/openai/v1/"). Slashes
+// between base and elements are normalized, so the prefix is kept and no "//"
+// appears; elements are taken as already escaped. A query on base is preserved.
+func JoinURL(base string, elems ...string) string {
+	u, err := url.Parse(base)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		// Not an absolute URL; fall back to plain concatenation.
+		parts := []string{strings.TrimRight(base, "/")}
+		for _, e := range elems {
+			parts = append(parts, strings.Trim(e, "/"))
+		}
+		return strings.Join(parts, "/")
+	}
+	return u.JoinPath(elems...).String()
+}
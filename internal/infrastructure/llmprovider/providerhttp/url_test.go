@@ -0,0 +1,27 @@
+package providerhttp
+
+import "testing"
+
+func TestJoinURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		base  string
+		elems []string
+		want  string
+	}{
+		{base: "https://host", elems: []string{"chat/completions"}, want: "https://host/chat/completions"},
+		{base: "https://host/", elems: []string{"chat/completions"}, want: "https://host/chat/completions"},
+		{base: "https://host/api", elems: []string{"chat/completions"}, want: "https://host/api/chat/completions"},
+		{base: "https://host/api/", elems: []string{"chat/completions"}, want: "https://host/api/chat/completions"},
+		{base: "https://host/api//", elems: []string{"/embeddings"}, want: "https://host/api/embeddings"},
+		{base: "https://host/compatible-mode/v1", elems: []string{"models"}, want: "https://host/compatible-mode/v1/models"},
+		{base: "https://host/api?tenant=a", elems: []string{"models"}, want: "https://host/api/models?tenant=a"},
+		{base: "https://host", elems: []string{"openai", "deployments", "embed%2Fv3", "embeddings"}, want: "https://host/openai/deployments/embed%2Fv3/embeddings"},
+	}
+	for _, tt := range tests {
+		if got := JoinURL(tt.base, tt.elems...); got != tt.want {
+			t.Errorf("JoinURL(%q, %q) = %q, want %q", tt.base, tt.elems, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package providerhttp
+
+import "github.com/poly-workshop/llm-gateway/internal/domain/llm"
+
+// Usage is the OpenAI-compatible "usage" object of chat completion responses,
+// including the optional token breakdowns some providers report.
+type Usage struct {
+	PromptTokens     uint32 `json:"prompt_tokens"`
+	CompletionTokens uint32 `json:"completion_tokens"`
+	TotalTokens      uint32 `json:"total_tokens"`
+
+	PromptTokensDetails *struct {
+		CachedTokens uint32 `json:"cached_tokens"`
+		// CacheWriteTokens is reported by OpenRouter for Anthropic prompt caching.
+		CacheWriteTokens uint32 `json:"cache_write_tokens"`
+		AudioTokens      uint32 `json:"audio_tokens"`
+	} `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *struct {
+		ReasoningTokens uint32 `json:"reasoning_tokens"`
+		AudioTokens     uint32 `json:"audio_tokens"`
+	} `json:"completion_tokens_details,omitempty"`
+}
+
+// TokenUsage converts u to the domain shape.
+func (u Usage) TokenUsage() llm.TokenUsage {
+	out := llm.TokenUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if d := u.PromptTokensDetails; d != nil {
+		out.CacheReadTokens = d.CachedTokens
+		out.CacheWriteTokens = d.CacheWriteTokens
+		out.PromptAudioTokens = d.AudioTokens
+	}
+	if d := u.CompletionTokensDetails; d != nil {
+		out.ReasoningTokens = d.ReasoningTokens
+		out.CompletionAudioTokens = d.AudioTokens
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package providerhttp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+func TestUsage_TokenUsage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want llm.TokenUsage
+	}{
+		{
+			name: "basic",
+			body: `{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}`,
+			want: llm.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		},
+		{
+			name: "details",
+			body: `{"prompt_tokens":100,"completion_tokens":80,"total_tokens":180,
+				"prompt_tokens_details":{"cached_tokens":64,"audio_tokens":12},
+				"completion_tokens_details":{"reasoning_tokens":50,"audio_tokens":20}}`,
+			want: llm.TokenUsage{
+				PromptTokens: 100, CompletionTokens: 80, TotalTokens: 180,
+				CacheReadTokens: 64, ReasoningTokens: 50, PromptAudioTokens: 12, CompletionAudioTokens: 20,
+			},
+		},
+		{
+			name: "null details",
+			body: `{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2,"prompt_tokens_details":null,"completion_tokens_details":null}`,
+			want: llm.TokenUsage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+		},
+	}
+	for _, tt := range tests {
+		var u Usage
+		if err := json.Unmarshal([]byte(tt.body), &u); err != nil {
+			t.Fatalf("%s: unmarshal: %v", tt.name, err)
+		}
+		if got := u.TokenUsage(); got != tt.want {
+			t.Fatalf("%s: TokenUsage() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package providerhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Warmup sends an unauthenticated HEAD to baseURL so DNS, TCP and TLS are done
+// and the connection sits in client's pool before real traffic arrives. Any
+// HTTP response counts as success: only reaching the host matters.
+func Warmup(ctx context.Context, client *http.Client, baseURL, userAgent string) error {
+	r, err := http.NewRequestWithContext(ctx, http.MethodHead, baseURL, nil)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(r)
+	if err != nil {
+		return err
+	}
+	// Drain so the connection is returned to the pool for reuse.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
@@ -0,0 +1,31 @@
+package providerhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWarmup_AnyResponseSucceeds(t *testing.T) {
+	t.Parallel()
+
+	var method string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if err := Warmup(context.Background(), srv.Client(), srv.URL, "llm-gateway/test"); err != nil {
+		t.Fatalf("Warmup: %v", err)
+	}
+	if method != http.MethodHead {
+		t.Fatalf("expected HEAD, got %q", method)
+	}
+
+	srv.Close()
+	if err := Warmup(context.Background(), srv.Client(), srv.URL, "llm-gateway/test"); err == nil {
+		t.Fatal("expected an error once the host is unreachable")
+	}
+}
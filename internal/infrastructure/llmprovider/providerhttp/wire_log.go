@@ -0,0 +1,254 @@
+package providerhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultWireLogMaxBody bounds the bytes of each body written to the wire log.
+const DefaultWireLogMaxBody = 64 << 10
+
+const wireLogRedacted = "[REDACTED]"
+
+// WireLogOptions configures NewWireLogTransport.
+type WireLogOptions struct {
+	// Provider labels every record.
+	Provider string
+	// Logger receives the records at debug level.
+	Logger *slog.Logger
+	// RedactContent also replaces prompt and output text in bodies (message
+	// content, prompts, embeddings input, tool arguments, returned text).
+	RedactContent bool
+	// MaxBody caps logged bytes per body; <= 0 uses DefaultWireLogMaxBody.
+	MaxBody int
+}
+
+// NewWireLogTransport logs raw upstream requests and responses through next,
+// for debugging outside production. Credentials are always redacted: header
+// values that look like keys or tokens, and key-like query parameters.
+// Streamed response bodies are logged once fully read or closed.
+func NewWireLogTransport(next http.RoundTripper, opts WireLogOptions) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.MaxBody <= 0 {
+		opts.MaxBody = DefaultWireLogMaxBody
+	}
+	return &wireLogTransport{next: next, opts: opts}
+}
+
+type wireLogTransport struct {
+	next http.RoundTripper
+	opts WireLogOptions
+}
+
+func (t *wireLogTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	var reqBody []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		b, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		r = r.Clone(ctx)
+		r.Body = io.NopCloser(bytes.NewReader(b))
+	}
+	t.log(ctx, "upstream request",
+		slog.String("method", r.Method),
+		slog.String("url", redactURL(r.URL)),
+		slog.Any("headers", redactHeaders(r.Header)),
+		slog.String("body", t.body(reqBody)),
+	)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		t.log(ctx, "upstream response", slog.String("url", redactURL(r.URL)), slog.String("error", err.Error()))
+		return nil, err
+	}
+	resp.Body = &wireLogBody{
+		ReadCloser: resp.Body,
+		max:        t.opts.MaxBody,
+		done: func(body []byte, truncated bool) {
+			t.log(ctx, "upstream response",
+				slog.String("url", redactURL(r.URL)),
+				slog.Int("status", resp.StatusCode),
+				slog.Duration("elapsed", time.Since(start)),
+				slog.Any("headers", redactHeaders(resp.Header)),
+				slog.String("body", t.body(body)),
+				slog.Bool("truncated", truncated),
+			)
+		},
+	}
+	return resp, nil
+}
+
+func (t *wireLogTransport) log(ctx context.Context, msg string, attrs ...slog.Attr) {
+	t.opts.Logger.LogAttrs(ctx, slog.LevelDebug, msg, append([]slog.Attr{slog.String("provider", t.opts.Provider)}, attrs...)...)
+}
+
+// body renders b for the log, truncated and with content redacted if configured.
+func (t *wireLogTransport) body(b []byte) string {
+	if len(b) > t.opts.MaxBody {
+		b = b[:t.opts.MaxBody]
+	}
+	if t.opts.RedactContent {
+		return redactBodyContent(b)
+	}
+	return string(b)
+}
+
+// wireLogBody captures up to max bytes of a response body as it is read and
+// reports them once, at EOF or Close.
+type wireLogBody struct {
+	io.ReadCloser
+	max       int
+	buf       bytes.Buffer
+	truncated bool
+	done      func(body []byte, truncated bool)
+}
+
+func (b *wireLogBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	room := b.max - b.buf.Len()
+	b.buf.Write(p[:min(n, room)])
+	b.truncated = b.truncated || n > room
+	if err == io.EOF {
+		b.report()
+	}
+	return n, err
+}
+
+func (b *wireLogBody) Close() error {
+	b.report()
+	return b.ReadCloser.Close()
+}
+
+func (b *wireLogBody) report() {
+	if b.done != nil {
+		b.done(b.buf.Bytes(), b.truncated)
+		b.done = nil
+	}
+}
+
+// sensitiveName reports whether a header or query parameter name likely
+// carries a credential.
+func sensitiveName(name string) bool {
+	n := strings.ToLower(name)
+	for _, s := range []string{"auth", "key", "token", "secret", "cookie", "signature", "password"} {
+		if strings.Contains(n, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if sensitiveName(k) {
+			out[k] = wireLogRedacted
+			continue
+		}
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
+
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	changed := false
+	for k := range q {
+		if sensitiveName(k) {
+			q.Set(k, wireLogRedacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+// contentFields are JSON keys holding prompt or output text.
+var contentFields = map[string]bool{
+	"content": true, "prompt": true, "input": true, "text": true,
+	"arguments": true, "reasoning": true, "reasoning_content": true, "refusal": true,
+	"transcript": true,
+}
+
+// redactBodyContent replaces the values of content fields in a JSON body or in
+// each "data:" line of an SSE stream. Other bodies are replaced entirely, as
+// their content cannot be told apart.
+func redactBodyContent(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if out, ok := redactJSONContent(b); ok {
+		return out
+	}
+	lines := strings.Split(string(b), "\n")
+	sse := false
+	for i, line := range lines {
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		sse = true
+		if out, ok := redactJSONContent([]byte(strings.TrimSpace(data))); ok {
+			lines[i] = "data: " + out
+		} else if strings.TrimSpace(data) != "[DONE]" {
+			lines[i] = "data: " + wireLogRedacted
+		}
+	}
+	if !sse {
+		return wireLogRedacted
+	}
+	return strings.Join(lines, "\n")
+}
+
+func redactJSONContent(b []byte) (string, bool) {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", false
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if contentFields[k] {
+				if _, ok := x.(map[string]any); !ok {
+					v[k] = wireLogRedacted
+					continue
+				}
+			}
+			v[k] = redactValue(x)
+		}
+	case []any:
+		for i, x := range v {
+			v[i] = redactValue(x)
+		}
+	}
+	return v
+}
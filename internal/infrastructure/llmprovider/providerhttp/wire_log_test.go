@@ -0,0 +1,69 @@
+package providerhttp
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWireLogTransport_RedactsCredentials(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-secret" {
+			t.Errorf("upstream should still see the real credential, got %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "tell me a secret") {
+			t.Errorf("upstream should receive the original body, got %s", body)
+		}
+		_, _ = io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"the answer","audio":{"id":"audio_1","transcript":"spoken answer"}}}]}`)
+	}))
+	defer srv.Close()
+
+	for _, redactContent := range []bool{false, true} {
+		var logged bytes.Buffer
+		client := &http.Client{Transport: NewWireLogTransport(nil, WireLogOptions{
+			Provider:      "openrouter",
+			Logger:        slog.New(slog.NewJSONHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug})),
+			RedactContent: redactContent,
+		})}
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/chat/completions?api-key=sk-query",
+			strings.NewReader(`{"model":"m","messages":[{"role":"user","content":"tell me a secret"}]}`))
+		req.Header.Set("Authorization", "Bearer sk-secret")
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		_, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		out := logged.String()
+		if strings.Contains(out, "sk-secret") || strings.Contains(out, "sk-query") {
+			t.Fatalf("credentials leaked into the wire log:\n%s", out)
+		}
+		if !strings.Contains(out, `"Authorization":"[REDACTED]"`) || !strings.Contains(out, `"upstream response"`) {
+			t.Fatalf("expected a redacted request and a logged response:\n%s", out)
+		}
+		for _, text := range []string{"tell me a secret", "the answer", "spoken answer"} {
+			if strings.Contains(out, text) == redactContent {
+				t.Fatalf("redactContent=%v: unexpected presence of %q in the wire log:\n%s", redactContent, text, out)
+			}
+		}
+	}
+}
+
+func TestRedactBodyContent_SSE(t *testing.T) {
+	t.Parallel()
+
+	got := redactBodyContent([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"hello\"}}]}\n\ndata: [DONE]\n"))
+	want := "data: {\"choices\":[{\"delta\":{\"content\":\"[REDACTED]\"}}]}\n\ndata: [DONE]\n"
+	if got != want {
+		t.Fatalf("redactBodyContent = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,321 @@
+package metrics
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "llmgw"
+
+// DefaultLatencyBuckets are request latency histogram buckets in seconds.
+// LLM calls are slow, so they extend well past the Prometheus defaults.
+var DefaultLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60, 120}
+
+// Recorder implements application.llmgateway.Metrics on a dedicated Prometheus registry.
+type Recorder struct {
+	reg *prometheus.Registry
+
+	modelInFlight *prometheus.GaugeVec
+	latency       *prometheus.HistogramVec
+	sloBreached   *prometheus.GaugeVec
+	emptyResp     *prometheus.CounterVec
+	slowUpstream  *prometheus.CounterVec
+	usageMissing  *prometheus.CounterVec
+	cacheLookups  *prometheus.CounterVec
+	cacheHitRatio *prometheus.GaugeVec
+	deduplicated  *prometheus.CounterVec
+	authFailures  *prometheus.CounterVec
+
+	cacheMu     sync.Mutex
+	cacheCounts map[string][2]uint64 // cache -> {hits, misses}
+
+	buckets []float64
+	slo     *sloTracker // nil unless WithLatencySLO is set
+	logger  *slog.Logger
+}
+
+// Option customizes a Recorder.
+type Option func(*Recorder)
+
+// WithLatencyBuckets overrides DefaultLatencyBuckets (seconds, ascending).
+func WithLatencyBuckets(buckets []float64) Option {
+	return func(r *Recorder) {
+		if len(buckets) > 0 {
+			r.buckets = buckets
+		}
+	}
+}
+
+// WithLatencySLO logs a warning when a model's p99 latency over the rolling
+// window exceeds threshold. A zero threshold disables the check.
+func WithLatencySLO(threshold, window time.Duration) Option {
+	return func(r *Recorder) {
+		if threshold > 0 {
+			r.slo = newSLOTracker(threshold, window)
+		}
+	}
+}
+
+func New(opts ...Option) *Recorder {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	r := &Recorder{
+		reg: reg,
+		modelInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "model_requests_in_flight",
+			Help:      "Requests currently being served, by operation and routed model.",
+		}, []string{"op", "model"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Gateway cache and in-flight coalescing lookups, by cache and result (hit or miss).",
+		}, []string{"cache", "result"}),
+		cacheHitRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_hit_ratio",
+			Help:      "Hits over all lookups since start, by cache.",
+		}, []string{"cache"}),
+		deduplicated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_deduplicated_total",
+			Help:      "Requests served by coalescing or idempotency without their own upstream call, by operation.",
+		}, []string{"op"}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_failures_total",
+			Help:      "Requests rejected by authentication, by reason.",
+		}, []string{"reason"}),
+		cacheCounts: make(map[string][2]uint64),
+		buckets:     DefaultLatencyBuckets,
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "model_request_duration_seconds",
+		Help:      "End-to-end request latency including retries, by operation and routed model.",
+		Buckets:   r.buckets,
+	}, []string{"op", "model"})
+	r.sloBreached = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "model_latency_slo_breached",
+		Help:      "1 while the rolling p99 latency of a model exceeds the configured SLO threshold.",
+	}, []string{"op", "model"})
+	r.emptyResp = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "empty_responses_total",
+		Help:      "Successful chat responses without any output, by provider and routed model.",
+	}, []string{"provider", "model"})
+	r.slowUpstream = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "slow_upstream_calls_total",
+		Help:      "Successful provider calls slower than the configured threshold, by provider and routed model.",
+	}, []string{"provider", "model"})
+	r.usageMissing = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "usage_missing_total",
+		Help:      "Successful responses with output but zero total tokens (possible usage schema drift), by provider and routed model.",
+	}, []string{"provider", "model"})
+	reg.MustRegister(r.modelInFlight, r.latency, r.sloBreached, r.emptyResp, r.slowUpstream, r.usageMissing, r.cacheLookups, r.cacheHitRatio, r.deduplicated, r.authFailures)
+	return r
+}
+
+// Handler serves the registry in the Prometheus exposition format (e.g. at /metrics).
+func (r *Recorder) Handler() http.Handler {
+	return promhttp.HandlerFor(r.reg, promhttp.HandlerOpts{})
+}
+
+// ObserveGatewayInFlight exports fn as the gateway-wide in-flight request gauge.
+func (r *Recorder) ObserveGatewayInFlight(fn func() int) {
+	r.reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "requests_in_flight",
+		Help:      "Requests currently admitted by the gateway-wide concurrency cap.",
+	}, func() float64 { return float64(fn()) }))
+}
+
+func (r *Recorder) ModelRequestStarted(op, model string) {
+	r.modelInFlight.WithLabelValues(op, model).Inc()
+}
+
+func (r *Recorder) ModelRequestFinished(op, model string, elapsed time.Duration) {
+	r.modelInFlight.WithLabelValues(op, model).Dec()
+	r.latency.WithLabelValues(op, model).Observe(elapsed.Seconds())
+	if r.slo == nil {
+		return
+	}
+	model, p99, changed, breached := r.slo.observe(op, model, r.buckets, elapsed, time.Now())
+	if !changed {
+		return
+	}
+	if breached {
+		r.sloBreached.WithLabelValues(op, model).Set(1)
+		r.logger.Warn("model p99 latency above SLO", "op", op, "model", model, "p99_seconds", p99Attr(p99), "threshold", r.slo.threshold)
+		return
+	}
+	r.sloBreached.WithLabelValues(op, model).Set(0)
+	r.logger.Info("model p99 latency back within SLO", "op", op, "model", model, "p99_seconds", p99Attr(p99), "threshold", r.slo.threshold)
+}
+
+func (r *Recorder) EmptyResponse(provider, model string) {
+	r.emptyResp.WithLabelValues(provider, model).Inc()
+	r.logger.Warn("provider returned an empty response", "provider", provider, "model", model)
+}
+
+func (r *Recorder) SlowUpstreamCall(provider, model string, elapsed time.Duration) {
+	r.slowUpstream.WithLabelValues(provider, model).Inc()
+	r.logger.Warn("slow upstream call", "provider", provider, "model", model, "duration", elapsed)
+}
+
+func (r *Recorder) UsageMissing(provider, model string) {
+	r.usageMissing.WithLabelValues(provider, model).Inc()
+	r.logger.Warn("provider response has output but no token usage; its usage schema may have changed", "provider", provider, "model", model)
+}
+
+func (r *Recorder) CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	r.cacheLookups.WithLabelValues(cache, result).Inc()
+
+	// The ratio is kept here rather than left to PromQL so dashboards and
+	// alerts can read it directly.
+	r.cacheMu.Lock()
+	c := r.cacheCounts[cache]
+	if hit {
+		c[0]++
+	} else {
+		c[1]++
+	}
+	r.cacheCounts[cache] = c
+	r.cacheHitRatio.WithLabelValues(cache).Set(float64(c[0]) / float64(c[0]+c[1]))
+	r.cacheMu.Unlock()
+}
+
+func (r *Recorder) RequestDeduplicated(op string) {
+	r.deduplicated.WithLabelValues(op).Inc()
+}
+
+// AuthFailure implements auth.FailureMetrics.
+func (r *Recorder) AuthFailure(reason auth.FailureReason) {
+	r.authFailures.WithLabelValues(string(reason)).Inc()
+}
+
+// p99Attr renders +Inf as a string so JSON log handlers can encode it.
+func p99Attr(p99 float64) any {
+	if math.IsInf(p99, 1) {
+		return "+Inf"
+	}
+	return p99
+}
+
+// sloMinSamples avoids alerting on a handful of slow requests.
+const sloMinSamples = 50
+
+// sloMaxSeries caps the tracked (op, model) pairs. The service only reports
+// catalog model IDs, so this is a backstop: past it, new models share an
+// "other" series per op.
+const sloMaxSeries = 1024
+
+// sloOtherModel is the model label for series folded past sloMaxSeries.
+const sloOtherModel = "other"
+
+// sloTracker estimates rolling p99 per (op, model) from bucket counts over the
+// current and previous window, so memory is bounded by the bucket count and
+// sloMaxSeries.
+type sloTracker struct {
+	threshold time.Duration
+	window    time.Duration
+	maxSeries int
+
+	mu     sync.Mutex
+	series map[[2]string]*sloSeries
+}
+
+type sloSeries struct {
+	start    time.Time
+	cur      []uint64 // len(buckets)+1; the last slot is +Inf
+	prev     []uint64
+	breached bool
+}
+
+func newSLOTracker(threshold, window time.Duration) *sloTracker {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	return &sloTracker{threshold: threshold, window: window, maxSeries: sloMaxSeries, series: make(map[[2]string]*sloSeries)}
+}
+
+// observe records one latency and reports the model label it was tracked
+// under, the estimated p99 and whether the breach state changed.
+func (t *sloTracker) observe(op, model string, buckets []float64, d time.Duration, now time.Time) (label string, p99 float64, changed, breached bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := [2]string{op, model}
+	s := t.series[key]
+	if s == nil && len(t.series) >= t.maxSeries {
+		key[1] = sloOtherModel
+		s = t.series[key]
+	}
+	label = key[1]
+	if s == nil {
+		s = &sloSeries{start: now, cur: make([]uint64, len(buckets)+1), prev: make([]uint64, len(buckets)+1)}
+		t.series[key] = s
+	}
+	if elapsed := now.Sub(s.start); elapsed >= t.window {
+		s.prev, s.cur = s.cur, s.prev
+		if elapsed >= 2*t.window {
+			clear(s.prev) // idle for more than a full window: the old data is stale
+		}
+		clear(s.cur)
+		s.start = now
+	}
+
+	secs := d.Seconds()
+	i := 0
+	for i < len(buckets) && secs > buckets[i] {
+		i++
+	}
+	s.cur[i]++
+
+	var total uint64
+	for j := range s.cur {
+		total += s.cur[j] + s.prev[j]
+	}
+	if total < sloMinSamples {
+		return label, 0, false, s.breached
+	}
+
+	// p99 is estimated as the upper bound of the bucket holding the 99th percentile.
+	rank := (total*99 + 99) / 100
+	var cum uint64
+	p99 = math.Inf(1)
+	for j := range buckets {
+		cum += s.cur[j] + s.prev[j]
+		if cum >= rank {
+			p99 = buckets[j]
+			break
+		}
+	}
+	breached = p99 > t.threshold.Seconds()
+	changed = breached != s.breached
+	s.breached = breached
+	return label, p99, changed, breached
+}
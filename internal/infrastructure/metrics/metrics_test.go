@@ -0,0 +1,272 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type blockingProvider struct {
+	entered chan struct{}
+	release chan struct{}
+	panics  bool
+}
+
+func (p *blockingProvider) CreateChatCompletion(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	p.block()
+	return llm.ChatCompletionResponse{ID: "x"}, nil
+}
+
+func (p *blockingProvider) CreateEmbeddings(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	p.block()
+	return llm.EmbeddingsResponse{ID: "emb", Model: req.Model}, nil
+}
+
+func (p *blockingProvider) block() {
+	p.entered <- struct{}{}
+	<-p.release
+	if p.panics {
+		panic("provider exploded")
+	}
+}
+
+// joinSignalContext closes joined the first time Done is called. A caller
+// that joins an in-flight embeddings call first waits on its context there,
+// so joined is closed once it has joined.
+type joinSignalContext struct {
+	context.Context
+	once   sync.Once
+	joined chan struct{}
+}
+
+func (c *joinSignalContext) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.joined) })
+	return c.Context.Done()
+}
+
+func scrape(t *testing.T, r *Recorder) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	b, _ := io.ReadAll(rec.Body)
+	return string(b)
+}
+
+func TestRecorder_ModelInFlightGauge(t *testing.T) {
+	t.Parallel()
+
+	rec := New()
+	p := &blockingProvider{entered: make(chan struct{}), release: make(chan struct{})}
+	svc := llmgateway.NewService(map[string]llmgateway.Provider{"fake": p}, []llmgateway.ModelSpec{{ID: "fake/m", Provider: "fake"}}, nil, llmgateway.WithMetrics(rec))
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+
+	const n = 3
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = svc.CreateChatCompletion(context.Background(), req)
+		}()
+		<-p.entered
+	}
+
+	const line = `llmgw_model_requests_in_flight{model="fake/m",op="chat.completions"} `
+	if out := scrape(t, rec); !strings.Contains(out, line+"3") {
+		t.Fatalf("expected 3 in flight, got:\n%s", out)
+	}
+	close(p.release)
+	wg.Wait()
+	if out := scrape(t, rec); !strings.Contains(out, line+"0") {
+		t.Fatalf("expected 0 in flight after completion, got:\n%s", out)
+	}
+
+	// Panics must release the slot too.
+	p2 := &blockingProvider{entered: make(chan struct{}, 1), release: make(chan struct{}), panics: true}
+	close(p2.release)
+	svc = llmgateway.NewService(map[string]llmgateway.Provider{"fake": p2}, []llmgateway.ModelSpec{{ID: "fake/m", Provider: "fake"}}, nil, llmgateway.WithMetrics(rec))
+	func() {
+		defer func() { _ = recover() }()
+		_, _ = svc.CreateChatCompletion(context.Background(), req)
+	}()
+	if out := scrape(t, rec); !strings.Contains(out, line+"0") {
+		t.Fatalf("expected 0 in flight after panic, got:\n%s", out)
+	}
+}
+
+func TestRecorder_LatencySLOWarning(t *testing.T) {
+	t.Parallel()
+
+	rec := New(WithLatencyBuckets([]float64{0.5, 1, 2, 5}), WithLatencySLO(2*time.Second, time.Minute))
+	var logs bytes.Buffer
+	rec.logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	const op, model = "chat.completions", "fake/m"
+	observe := func(n int, d time.Duration) {
+		for i := 0; i < n; i++ {
+			rec.ModelRequestStarted(op, model)
+			rec.ModelRequestFinished(op, model, d)
+		}
+	}
+
+	// 1% slow requests keep p99 within the fast buckets.
+	observe(99, 300*time.Millisecond)
+	observe(1, 4*time.Second)
+	if strings.Contains(logs.String(), "above SLO") {
+		t.Fatalf("unexpected SLO warning:\n%s", logs.String())
+	}
+
+	observe(10, 4*time.Second)
+	if !strings.Contains(logs.String(), "above SLO") {
+		t.Fatalf("expected SLO warning once p99 exceeds threshold, got:\n%s", logs.String())
+	}
+	if n := strings.Count(logs.String(), "above SLO"); n != 1 {
+		t.Fatalf("expected the warning once per breach, got %d", n)
+	}
+	if out := scrape(t, rec); !strings.Contains(out, `llmgw_model_latency_slo_breached{model="fake/m",op="chat.completions"} 1`) {
+		t.Fatalf("expected breach gauge, got:\n%s", out)
+	}
+	if out := scrape(t, rec); !strings.Contains(out, `llmgw_model_request_duration_seconds_count{model="fake/m",op="chat.completions"} 110`) {
+		t.Fatalf("expected latency histogram, got:\n%s", out)
+	}
+}
+
+func TestRecorder_LatencySLOSeriesAreBounded(t *testing.T) {
+	t.Parallel()
+
+	rec := New(WithLatencyBuckets([]float64{0.5, 1, 2, 5}), WithLatencySLO(2*time.Second, time.Minute))
+	rec.slo.maxSeries = 2
+
+	const op = "chat.completions"
+	for _, model := range []string{"a", "b", "c", "d", "e"} {
+		for i := 0; i < sloMinSamples; i++ {
+			rec.ModelRequestStarted(op, model)
+			rec.ModelRequestFinished(op, model, 4*time.Second)
+		}
+	}
+
+	if n := len(rec.slo.series); n != 3 {
+		t.Fatalf("expected 2 models plus the shared other series, got %d", n)
+	}
+	out := scrape(t, rec)
+	if !strings.Contains(out, `llmgw_model_latency_slo_breached{model="other",op="chat.completions"} 1`) {
+		t.Fatalf("expected models past the cap to share the other series, got:\n%s", out)
+	}
+	if strings.Contains(out, `llmgw_model_latency_slo_breached{model="e"`) {
+		t.Fatalf("unexpected series for a model past the cap:\n%s", out)
+	}
+}
+
+func TestRecorder_CacheAndDedupCounters(t *testing.T) {
+	t.Parallel()
+
+	rec := New()
+	p := &blockingProvider{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	svc := llmgateway.NewService(map[string]llmgateway.Provider{"fake": p}, []llmgateway.ModelSpec{{ID: "fake/m", Provider: "fake"}}, nil, llmgateway.WithMetrics(rec))
+	req := llm.EmbeddingsRequest{Model: "fake/m", Input: []string{"same"}}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = svc.CreateEmbeddings(context.Background(), req) // miss: runs the upstream call
+	}()
+	<-p.entered
+	follower := &joinSignalContext{Context: context.Background(), joined: make(chan struct{})}
+	go func() {
+		defer wg.Done()
+		_, _ = svc.CreateEmbeddings(follower, req) // hit: joins the in-flight call
+	}()
+	<-follower.joined
+	close(p.release)
+	wg.Wait()
+
+	body := scrape(t, rec)
+	for _, want := range []string{
+		`llmgw_cache_lookups_total{cache="embeddings_inflight",result="hit"} 1`,
+		`llmgw_cache_lookups_total{cache="embeddings_inflight",result="miss"} 1`,
+		`llmgw_cache_hit_ratio{cache="embeddings_inflight"} 0.5`,
+		`llmgw_requests_deduplicated_total{op="embeddings"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+type fixedChatProvider struct {
+	resp llm.ChatCompletionResponse
+}
+
+func (p *fixedChatProvider) CreateChatCompletion(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	return p.resp, nil
+}
+
+func (p *fixedChatProvider) CreateEmbeddings(context.Context, llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	return llm.EmbeddingsResponse{}, nil
+}
+
+func TestRecorder_UsageMissingWarning(t *testing.T) {
+	t.Parallel()
+
+	rec := New()
+	var logs bytes.Buffer
+	rec.logger = slog.New(slog.NewTextHandler(&logs, nil))
+	choices := []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Content: "hi"}}}
+	p := &fixedChatProvider{resp: llm.ChatCompletionResponse{ID: "ok", Choices: choices, Usage: llm.TokenUsage{TotalTokens: 3}}}
+	svc := llmgateway.NewService(map[string]llmgateway.Provider{"fake": p}, []llmgateway.ModelSpec{{ID: "fake/m", Provider: "fake"}}, nil, llmgateway.WithMetrics(rec))
+	req := llm.ChatCompletionRequest{Model: "fake/m", Messages: []llm.ChatMessage{{Role: "user", Content: "hi"}}}
+
+	if _, err := svc.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if strings.Contains(logs.String(), "no token usage") {
+		t.Fatalf("unexpected warning for a response with usage:\n%s", logs.String())
+	}
+
+	// A renamed usage field decodes as zero usage.
+	p.resp = llm.ChatCompletionResponse{ID: "drifted", Choices: choices}
+	if _, err := svc.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if !strings.Contains(logs.String(), "no token usage") {
+		t.Fatalf("expected usage warning, got:\n%s", logs.String())
+	}
+	if out := scrape(t, rec); !strings.Contains(out, `llmgw_usage_missing_total{model="fake/m",provider="fake"} 1`) {
+		t.Fatalf("expected usage_missing counter, got:\n%s", out)
+	}
+}
+
+func TestRecorder_AuthFailureCounter(t *testing.T) {
+	t.Parallel()
+
+	rec := New()
+	m := auth.NewManager([]auth.ServiceToken{{Name: "svc", Token: "tok"}}, time.Hour, 0, auth.WithFailureMetrics(rec))
+	intercept := auth.UnaryServerInterceptor(m)
+	info := &grpc.UnaryServerInfo{FullMethod: "/llmgateway.v1.LLMGatewayService/ListModels"}
+	for _, md := range []metadata.MD{metadata.Pairs("x-service-token", "wrong"), metadata.Pairs("x-service-token", "wrong"), {}} {
+		_, _ = intercept(metadata.NewIncomingContext(context.Background(), md), nil, info, func(context.Context, any) (any, error) { return nil, nil })
+	}
+
+	body := scrape(t, rec)
+	for _, want := range []string{
+		`llmgw_auth_failures_total{reason="invalid_token"} 2`,
+		`llmgw_auth_failures_total{reason="missing_token"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
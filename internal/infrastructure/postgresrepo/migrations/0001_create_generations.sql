@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS generations (
+    id                      TEXT PRIMARY KEY,
+    model                   TEXT NOT NULL,
+    provider                TEXT NOT NULL DEFAULT '',
+    upstream_model          TEXT NOT NULL DEFAULT '',
+    subject                 TEXT NOT NULL DEFAULT '',
+    conversation_id         TEXT NOT NULL DEFAULT '',
+    finish_reason           TEXT NOT NULL DEFAULT '',
+    latency_ms              BIGINT NOT NULL DEFAULT 0,
+    prompt_tokens           BIGINT NOT NULL DEFAULT 0,
+    completion_tokens       BIGINT NOT NULL DEFAULT 0,
+    total_tokens            BIGINT NOT NULL DEFAULT 0,
+    cache_read_tokens       BIGINT NOT NULL DEFAULT 0,
+    cache_write_tokens      BIGINT NOT NULL DEFAULT 0,
+    reasoning_tokens        BIGINT NOT NULL DEFAULT 0,
+    prompt_audio_tokens     BIGINT NOT NULL DEFAULT 0,
+    completion_audio_tokens BIGINT NOT NULL DEFAULT 0,
+    -- Reserved for per-request pricing; the gateway does not compute cost yet.
+    cost_usd                NUMERIC(20, 10),
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS generations_conversation_idx
+    ON generations (conversation_id, created_at)
+    WHERE conversation_id <> '';
+
+CREATE INDEX IF NOT EXISTS generations_subject_idx
+    ON generations (subject, created_at);
@@ -0,0 +1,2 @@
+-- Upstream's own request id, quoted when escalating to a provider's support.
+ALTER TABLE generations ADD COLUMN IF NOT EXISTS provider_request_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- "client_cancelled" for partial records of streams the client aborted.
+ALTER TABLE generations ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT '';
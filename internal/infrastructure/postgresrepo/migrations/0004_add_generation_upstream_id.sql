@@ -0,0 +1,2 @@
+-- The provider's response ID when the record is keyed by a gateway-issued ID.
+ALTER TABLE generations ADD COLUMN IF NOT EXISTS upstream_id TEXT NOT NULL DEFAULT '';
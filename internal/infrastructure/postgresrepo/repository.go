@@ -0,0 +1,160 @@
+// Package postgresrepo stores generation records in PostgreSQL.
+package postgresrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq" // registers the "postgres" driver
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// Options configures the connection pool. Zero values keep database/sql's defaults.
+type Options struct {
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// Repository implements application.llmgateway.GenerationRepository on a
+// generations table.
+type Repository struct {
+	db *sql.DB
+}
+
+// Open connects to PostgreSQL, applies pending migrations and returns a
+// Repository owning the pool. Call Close on shutdown.
+func Open(ctx context.Context, opts Options) (*Repository, error) {
+	db, err := sql.Open("postgres", opts.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect postgres: %w", err)
+	}
+	if err := Migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return New(db), nil
+}
+
+// New wraps an existing pool; the schema must already be migrated.
+func New(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+const generationColumns = `id, model, provider, upstream_model, subject, conversation_id, finish_reason, latency_ms,
+    prompt_tokens, completion_tokens, total_tokens, cache_read_tokens, cache_write_tokens,
+    reasoning_tokens, prompt_audio_tokens, completion_audio_tokens, created_at, provider_request_id,
+    status, upstream_id`
+
+func (r *Repository) Save(ctx context.Context, gen llm.Generation) error {
+	if gen.ID == "" {
+		return llm.InvalidArgument("generation id is required")
+	}
+	created := time.Now()
+	if gen.Created > 0 {
+		created = time.Unix(gen.Created, 0)
+	}
+	u := gen.Usage
+	// Saving an existing id overwrites it, matching the in-memory store.
+	_, err := r.db.ExecContext(ctx, `INSERT INTO generations (`+generationColumns+`)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+ON CONFLICT (id) DO UPDATE SET
+    model = EXCLUDED.model,
+    provider = EXCLUDED.provider,
+    upstream_model = EXCLUDED.upstream_model,
+    subject = EXCLUDED.subject,
+    conversation_id = EXCLUDED.conversation_id,
+    finish_reason = EXCLUDED.finish_reason,
+    latency_ms = EXCLUDED.latency_ms,
+    prompt_tokens = EXCLUDED.prompt_tokens,
+    completion_tokens = EXCLUDED.completion_tokens,
+    total_tokens = EXCLUDED.total_tokens,
+    cache_read_tokens = EXCLUDED.cache_read_tokens,
+    cache_write_tokens = EXCLUDED.cache_write_tokens,
+    reasoning_tokens = EXCLUDED.reasoning_tokens,
+    prompt_audio_tokens = EXCLUDED.prompt_audio_tokens,
+    completion_audio_tokens = EXCLUDED.completion_audio_tokens,
+    provider_request_id = EXCLUDED.provider_request_id,
+    status = EXCLUDED.status,
+    upstream_id = EXCLUDED.upstream_id`,
+		gen.ID, gen.Model, gen.Provider, gen.UpstreamModel, gen.Subject, gen.ConversationID, gen.FinishReason,
+		gen.Latency.Milliseconds(),
+		u.PromptTokens, u.CompletionTokens, u.TotalTokens, u.CacheReadTokens, u.CacheWriteTokens,
+		u.ReasoningTokens, u.PromptAudioTokens, u.CompletionAudioTokens, created, gen.ProviderRequestID,
+		gen.Status, gen.UpstreamID,
+	)
+	return err
+}
+
+func (r *Repository) Get(ctx context.Context, id string) (llm.Generation, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+generationColumns+` FROM generations WHERE id = $1`, id)
+	gen, err := scanGeneration(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return llm.Generation{}, llm.NotFound("generation " + id)
+	}
+	return gen, err
+}
+
+func (r *Repository) ListGenerationsByConversation(ctx context.Context, conversationID string) ([]llm.Generation, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+generationColumns+` FROM generations
+WHERE conversation_id = $1 AND conversation_id <> ''
+ORDER BY created_at, id`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []llm.Generation
+	for rows.Next() {
+		gen, err := scanGeneration(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, gen)
+	}
+	return out, rows.Err()
+}
+
+func scanGeneration(row interface{ Scan(...any) error }) (llm.Generation, error) {
+	var (
+		gen       llm.Generation
+		latencyMs int64
+		created   time.Time
+	)
+	u := &gen.Usage
+	err := row.Scan(
+		&gen.ID, &gen.Model, &gen.Provider, &gen.UpstreamModel, &gen.Subject, &gen.ConversationID, &gen.FinishReason,
+		&latencyMs,
+		&u.PromptTokens, &u.CompletionTokens, &u.TotalTokens, &u.CacheReadTokens, &u.CacheWriteTokens,
+		&u.ReasoningTokens, &u.PromptAudioTokens, &u.CompletionAudioTokens, &created, &gen.ProviderRequestID,
+		&gen.Status, &gen.UpstreamID,
+	)
+	if err != nil {
+		return llm.Generation{}, err
+	}
+	gen.Latency = time.Duration(latencyMs) * time.Millisecond
+	gen.Created = created.Unix()
+	return gen, nil
+}
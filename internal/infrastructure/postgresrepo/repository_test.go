@@ -0,0 +1,176 @@
+package postgresrepo
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+var testColumns = []string{
+	"id", "model", "provider", "upstream_model", "subject", "conversation_id", "finish_reason", "latency_ms",
+	"prompt_tokens", "completion_tokens", "total_tokens", "cache_read_tokens", "cache_write_tokens",
+	"reasoning_tokens", "prompt_audio_tokens", "completion_audio_tokens", "created_at", "provider_request_id",
+	"status", "upstream_id",
+}
+
+func newMockRepo(t *testing.T) (*Repository, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+		_ = db.Close()
+	})
+	return New(db), mock
+}
+
+func TestRepository_SaveAndGet(t *testing.T) {
+	t.Parallel()
+
+	repo, mock := newMockRepo(t)
+	created := time.Unix(1700000000, 0)
+	gen := llm.Generation{
+		ID:             "gen-1",
+		Model:          "alias",
+		Created:        created.Unix(),
+		Usage:          llm.TokenUsage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 7, CacheReadTokens: 2},
+		ConversationID: "conv-1",
+		Subject:        "svc:a",
+		Provider:       "openrouter",
+		UpstreamModel:  "openai/gpt-4o",
+		FinishReason:   "stop",
+		Latency:        250 * time.Millisecond,
+
+		ProviderRequestID: "req-abc",
+		Status:            llm.GenerationStatusClientCancelled,
+		UpstreamID:        "chatcmpl-upstream",
+	}
+
+	mock.ExpectExec(`INSERT INTO generations .* ON CONFLICT \(id\) DO UPDATE`).
+		WithArgs("gen-1", "alias", "openrouter", "openai/gpt-4o", "svc:a", "conv-1", "stop", int64(250),
+			uint32(3), uint32(4), uint32(7), uint32(2), uint32(0), uint32(0), uint32(0), uint32(0), created, "req-abc", "client_cancelled", "chatcmpl-upstream").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Save(context.Background(), gen); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT .* FROM generations WHERE id = \$1`).
+		WithArgs("gen-1").
+		WillReturnRows(sqlmock.NewRows(testColumns).AddRow(
+			"gen-1", "alias", "openrouter", "openai/gpt-4o", "svc:a", "conv-1", "stop", int64(250),
+			int64(3), int64(4), int64(7), int64(2), int64(0), int64(0), int64(0), int64(0), created, "req-abc", "client_cancelled", "chatcmpl-upstream"))
+	got, err := repo.Get(context.Background(), "gen-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != gen {
+		t.Fatalf("round-trip mismatch:\n got %+v\nwant %+v", got, gen)
+	}
+}
+
+func TestRepository_GetNotFound(t *testing.T) {
+	t.Parallel()
+
+	repo, mock := newMockRepo(t)
+	mock.ExpectQuery(`SELECT .* FROM generations WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(testColumns))
+
+	_, err := repo.Get(context.Background(), "missing")
+	if !errors.Is(err, llm.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestRepository_ListGenerationsByConversation(t *testing.T) {
+	t.Parallel()
+
+	repo, mock := newMockRepo(t)
+	created := time.Unix(1700000000, 0)
+	mock.ExpectQuery(`SELECT .* FROM generations\s+WHERE conversation_id = \$1 .* ORDER BY created_at, id`).
+		WithArgs("conv-1").
+		WillReturnRows(sqlmock.NewRows(testColumns).
+			AddRow("gen-1", "m", "", "", "svc:a", "conv-1", "", int64(0), int64(1), int64(0), int64(1), int64(0), int64(0), int64(0), int64(0), int64(0), created, "", "", "").
+			AddRow("gen-2", "m", "", "", "svc:a", "conv-1", "", int64(0), int64(2), int64(0), int64(2), int64(0), int64(0), int64(0), int64(0), int64(0), created.Add(time.Second), "", "", ""))
+
+	gens, err := repo.ListGenerationsByConversation(context.Background(), "conv-1")
+	if err != nil {
+		t.Fatalf("ListGenerationsByConversation: %v", err)
+	}
+	if len(gens) != 2 || gens[0].ID != "gen-1" || gens[1].ID != "gen-2" || gens[1].Usage.TotalTokens != 2 {
+		t.Fatalf("unexpected generations: %+v", gens)
+	}
+}
+
+func TestMigrate_AppliesPendingMigrations(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := loadMigrations()
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("loadMigrations: %v (%d)", err, len(migrations))
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM schema_migrations`).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	for _, m := range migrations {
+		mock.ExpectExec(regexp.QuoteMeta(m.sql)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(m.version).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestMigrate_SkipsAppliedMigrations(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	applied := sqlmock.NewRows([]string{"version"})
+	for _, m := range migrations {
+		applied.AddRow(m.version)
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version FROM schema_migrations`).WillReturnRows(applied)
+	mock.ExpectCommit()
+
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
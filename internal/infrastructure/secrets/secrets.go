@@ -0,0 +1,65 @@
+// Package secrets resolves named secrets, such as upstream API keys, from a
+// secret store instead of config files or the environment.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned for a secret the store does not hold.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider resolves named secrets. Implementations (the file store below, or
+// clients for Vault or AWS Secrets Manager) must return the current value on
+// every call, so resolving again after a rotation picks up the new secret.
+type Provider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// File reads each secret from the file of the same name in a directory, as
+// Kubernetes and Docker mount secrets. Surrounding whitespace is trimmed.
+type File struct {
+	dir string
+}
+
+// NewFile returns a provider reading secrets from dir.
+func NewFile(dir string) *File {
+	return &File{dir: dir}
+}
+
+func (f *File) Secret(_ context.Context, name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	b, err := os.ReadFile(filepath.Join(f.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read secret %s: %w", name, err)
+	}
+	v := strings.TrimSpace(string(b))
+	if v == "" {
+		return "", fmt.Errorf("secret %s is empty", name)
+	}
+	return v, nil
+}
+
+// Resolve returns the values of names in order. It fails on the first secret
+// that cannot be resolved, so callers never run with a partial key set.
+func Resolve(ctx context.Context, p Provider, names []string) ([]string, error) {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		v, err := p.Secret(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
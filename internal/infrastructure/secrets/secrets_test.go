@@ -0,0 +1,94 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type fakeProvider struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func (f *fakeProvider) Secret(_ context.Context, name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (f *fakeProvider) rotate(name, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secrets[name] = value
+}
+
+func TestResolve_PicksUpRotation(t *testing.T) {
+	t.Parallel()
+
+	p := &fakeProvider{secrets: map[string]string{"openrouter": "key-1", "backup": "key-b"}}
+
+	keys, err := Resolve(context.Background(), p, []string{"openrouter", "backup"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "key-1" || keys[1] != "key-b" {
+		t.Fatalf("keys = %v", keys)
+	}
+
+	p.rotate("openrouter", "key-2")
+	keys, err = Resolve(context.Background(), p, []string{"openrouter"})
+	if err != nil {
+		t.Fatalf("Resolve after rotation: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "key-2" {
+		t.Fatalf("keys after rotation = %v, want [key-2]", keys)
+	}
+}
+
+func TestResolve_FailsOnMissingSecret(t *testing.T) {
+	t.Parallel()
+
+	p := &fakeProvider{secrets: map[string]string{"a": "x"}}
+	if _, err := Resolve(context.Background(), p, []string{"a", "missing"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFile_ReadsCurrentValue(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dashscope")
+	if err := os.WriteFile(path, []byte("sk-old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f := NewFile(dir)
+
+	v, err := f.Secret(context.Background(), "dashscope")
+	if err != nil || v != "sk-old" {
+		t.Fatalf("Secret = %q, %v; want sk-old", v, err)
+	}
+
+	if err := os.WriteFile(path, []byte("sk-new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v, err = f.Secret(context.Background(), "dashscope")
+	if err != nil || v != "sk-new" {
+		t.Fatalf("Secret after rotation = %q, %v; want sk-new", v, err)
+	}
+
+	if _, err := f.Secret(context.Background(), "absent"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("absent err = %v, want ErrNotFound", err)
+	}
+	if _, err := f.Secret(context.Background(), "../dashscope"); err == nil {
+		t.Fatal("expected error for path traversal")
+	}
+}
@@ -0,0 +1,23 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/admission"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+)
+
+// AdmissionPriority ranks queued requests by their service token's configured
+// priority. Among requests of equal token priority, service_tier "flex" goes
+// last; streaming RPCs are ranked by token alone, as their request message is
+// not read until admitted.
+func AdmissionPriority(authMgr *auth.Manager) admission.PriorityFunc {
+	return func(ctx context.Context, req any) int {
+		prio := 2 * authMgr.Priority(ctx)
+		if r, ok := req.(interface{ GetServiceTier() string }); ok && r.GetServiceTier() == llmgateway.ServiceTierFlex {
+			return prio
+		}
+		return prio + 1
+	}
+}
@@ -0,0 +1,42 @@
+package grpcserver
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// WithGzip compresses responses with gzip for clients that advertise it in
+// grpc-accept-encoding. Disabled, responses are always sent uncompressed;
+// gzip-compressed requests are accepted either way.
+func WithGzip(enabled bool) Option {
+	return func(o *options) { o.gzip = enabled }
+}
+
+// sendCompressor picks the response compressor for the RPC in ctx.
+func sendCompressor(ctx context.Context, gzipEnabled bool) {
+	name := encoding.Identity
+	if gzipEnabled {
+		if advertised, err := grpc.ClientSupportedCompressors(ctx); err == nil && slices.Contains(advertised, gzip.Name) {
+			name = gzip.Name
+		}
+	}
+	_ = grpc.SetSendCompressor(ctx, name) // Best effort: fails only outside a server RPC.
+}
+
+func compressionUnaryInterceptor(gzipEnabled bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		sendCompressor(ctx, gzipEnabled)
+		return handler(ctx, req)
+	}
+}
+
+func compressionStreamInterceptor(gzipEnabled bool) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		sendCompressor(ss.Context(), gzipEnabled)
+		return handler(srv, ss)
+	}
+}
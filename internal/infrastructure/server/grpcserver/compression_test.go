@@ -0,0 +1,95 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/stats"
+)
+
+// embeddingProvider returns one large, highly compressible vector per input.
+type embeddingProvider struct{}
+
+func (embeddingProvider) CreateChatCompletion(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	return llm.ChatCompletionResponse{}, nil
+}
+
+func (embeddingProvider) CreateEmbeddings(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	data := make([]llm.Embedding, 0, len(req.Input))
+	for i := range req.Input {
+		data = append(data, llm.Embedding{Index: uint32(i), Vector: make([]float32, 4096)})
+	}
+	return llm.EmbeddingsResponse{ID: "emb-1", Model: req.Model, Data: data}, nil
+}
+
+// payloadSizes records the uncompressed and compressed sizes of received messages.
+type payloadSizes struct {
+	mu               sync.Mutex
+	length, wireSize int
+}
+
+func (p *payloadSizes) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context   { return ctx }
+func (p *payloadSizes) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+func (p *payloadSizes) HandleConn(context.Context, stats.ConnStats)                       {}
+func (p *payloadSizes) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InPayload); ok {
+		p.mu.Lock()
+		p.length, p.wireSize = in.Length, in.CompressedLength
+		p.mu.Unlock()
+	}
+}
+
+func TestGzip_CompressesResponsesForAdvertisingClients(t *testing.T) {
+	t.Parallel()
+
+	for _, enabled := range []bool{true, false} {
+		app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": embeddingProvider{}}, nil, nil)
+		srv, err := New("127.0.0.1:0", app, nil, WithGzip(enabled))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		go func() { _ = srv.s.Serve(lis) }()
+
+		sizes := &payloadSizes{}
+		conn, err := grpc.NewClient(lis.Addr().String(),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithStatsHandler(sizes))
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		client := llmgatewayv1.NewLLMGatewayServiceClient(conn)
+
+		// UseCompressor makes the client advertise (and send) gzip.
+		res, err := client.CreateEmbeddings(context.Background(), &llmgatewayv1.CreateEmbeddingsRequest{
+			Model: "fake/emb",
+			Input: []string{"a", "b", "c", "d"},
+		}, grpc.UseCompressor(gzip.Name))
+		_ = conn.Close()
+		srv.s.Stop()
+		if err != nil {
+			t.Fatalf("gzip=%v: CreateEmbeddings: %v", enabled, err)
+		}
+
+		if len(res.GetData()) != 4 || len(res.GetData()[3].GetEmbedding()) != 4096 {
+			t.Fatalf("gzip=%v: response not decoded intact: %d embeddings", enabled, len(res.GetData()))
+		}
+		sizes.mu.Lock()
+		length, wire := sizes.length, sizes.wireSize
+		sizes.mu.Unlock()
+		if compressed := wire < length; compressed != enabled {
+			t.Fatalf("gzip=%v: response payload %d bytes on the wire for %d bytes decoded", enabled, wire, length)
+		}
+	}
+}
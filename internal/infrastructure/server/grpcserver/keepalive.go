@@ -0,0 +1,56 @@
+package grpcserver
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Keepalive configures connection keepalive. Zero durations keep gRPC's
+// defaults (no idle or age limit, 2h ping interval, 20s ping timeout, 5m
+// minimum client ping interval).
+type Keepalive struct {
+	// MaxConnectionIdle closes connections without RPCs for this long.
+	MaxConnectionIdle time.Duration
+	// MaxConnectionAge closes connections after this long so clients
+	// rebalance; MaxConnectionAgeGrace then lets in-flight streams finish.
+	MaxConnectionAge      time.Duration
+	MaxConnectionAgeGrace time.Duration
+	// Time is the server ping interval on idle connections; Timeout is how
+	// long to wait for the ping ack before closing.
+	Time    time.Duration
+	Timeout time.Duration
+	// MinPingInterval is the most often clients may ping; faster pingers are
+	// disconnected.
+	MinPingInterval time.Duration
+	// PermitWithoutStream allows client pings on connections with no active streams.
+	PermitWithoutStream bool
+}
+
+// WithKeepalive applies k to the server's connections.
+func WithKeepalive(k Keepalive) Option {
+	return func(o *options) { o.keepalive = k }
+}
+
+func (k Keepalive) params() keepalive.ServerParameters {
+	return keepalive.ServerParameters{
+		MaxConnectionIdle:     k.MaxConnectionIdle,
+		MaxConnectionAge:      k.MaxConnectionAge,
+		MaxConnectionAgeGrace: k.MaxConnectionAgeGrace,
+		Time:                  k.Time,
+		Timeout:               k.Timeout,
+	}
+}
+
+func (k Keepalive) policy() keepalive.EnforcementPolicy {
+	return keepalive.EnforcementPolicy{MinTime: k.MinPingInterval, PermitWithoutStream: k.PermitWithoutStream}
+}
+
+// serverOptions returns the grpc.ServerOptions for k; an unset Keepalive adds none.
+func (k Keepalive) serverOptions() []grpc.ServerOption {
+	if k == (Keepalive{}) {
+		return nil
+	}
+	return []grpc.ServerOption{grpc.KeepaliveParams(k.params()), grpc.KeepaliveEnforcementPolicy(k.policy())}
+}
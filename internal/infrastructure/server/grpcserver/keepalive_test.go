@@ -0,0 +1,53 @@
+package grpcserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"google.golang.org/grpc/keepalive"
+)
+
+func TestNew_AppliesKeepalive(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(nil, nil, nil)
+	srv, err := New(":0", app, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if opts := srv.keepalive.serverOptions(); opts != nil {
+		t.Fatalf("unset keepalive should keep gRPC defaults, got %d options", len(opts))
+	}
+
+	k := Keepalive{
+		MaxConnectionIdle:     5 * time.Minute,
+		MaxConnectionAge:      30 * time.Minute,
+		MaxConnectionAgeGrace: 10 * time.Minute,
+		Time:                  time.Minute,
+		Timeout:               10 * time.Second,
+		MinPingInterval:       20 * time.Second,
+		PermitWithoutStream:   true,
+	}
+	srv, err = New(":0", app, nil, WithKeepalive(k))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	wantParams := keepalive.ServerParameters{
+		MaxConnectionIdle:     5 * time.Minute,
+		MaxConnectionAge:      30 * time.Minute,
+		MaxConnectionAgeGrace: 10 * time.Minute,
+		Time:                  time.Minute,
+		Timeout:               10 * time.Second,
+	}
+	if got := srv.keepalive.params(); got != wantParams {
+		t.Fatalf("params = %+v, want %+v", got, wantParams)
+	}
+	wantPolicy := keepalive.EnforcementPolicy{MinTime: 20 * time.Second, PermitWithoutStream: true}
+	if got := srv.keepalive.policy(); got != wantPolicy {
+		t.Fatalf("policy = %+v, want %+v", got, wantPolicy)
+	}
+	if opts := srv.keepalive.serverOptions(); len(opts) != 2 {
+		t.Fatalf("expected keepalive params and enforcement policy options, got %d", len(opts))
+	}
+}
@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync/atomic"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MethodSwitch rejects calls to disabled RPC methods with Unimplemented. The
+// disabled set can be replaced while the server runs (e.g. on config reload).
+type MethodSwitch struct {
+	disabled atomic.Pointer[map[string]bool] // by short method name
+}
+
+// NewMethodSwitch returns a switch with the given methods disabled.
+func NewMethodSwitch(methods []string) (*MethodSwitch, error) {
+	sw := &MethodSwitch{}
+	if err := sw.SetDisabled(methods); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// SetDisabled replaces the disabled set. Methods are LLMGatewayService method
+// names such as "CreateEmbeddings"; on an unknown name the set is unchanged.
+func (sw *MethodSwitch) SetDisabled(methods []string) error {
+	known := make(map[string]bool)
+	for _, m := range llmgatewayv1.LLMGatewayService_ServiceDesc.Methods {
+		known[m.MethodName] = true
+	}
+	for _, m := range llmgatewayv1.LLMGatewayService_ServiceDesc.Streams {
+		known[m.StreamName] = true
+	}
+	disabled := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		if !known[m] {
+			return fmt.Errorf("unknown grpc method %q", m)
+		}
+		disabled[m] = true
+	}
+	sw.disabled.Store(&disabled)
+	return nil
+}
+
+// Enabled reports whether the short method name (e.g. "CreateEmbeddings") is
+// enabled. A nil switch enables everything.
+func (sw *MethodSwitch) Enabled(method string) bool {
+	return sw == nil || !(*sw.disabled.Load())[method]
+}
+
+// check returns an Unimplemented error if fullMethod is disabled.
+func (sw *MethodSwitch) check(fullMethod string) error {
+	if sw == nil {
+		return nil
+	}
+	if name := path.Base(fullMethod); (*sw.disabled.Load())[name] {
+		return status.Errorf(codes.Unimplemented, "%s is disabled on this gateway", name)
+	}
+	return nil
+}
+
+// WithMethodSwitch rejects calls to methods disabled in sw.
+func WithMethodSwitch(sw *MethodSwitch) Option {
+	return func(o *options) { o.methods = sw }
+}
+
+func methodSwitchUnaryInterceptor(sw *MethodSwitch) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := sw.check(info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func methodSwitchStreamInterceptor(sw *MethodSwitch) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := sw.check(info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
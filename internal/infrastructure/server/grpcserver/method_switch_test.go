@@ -0,0 +1,77 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodSwitch_RejectsDisabledMethods(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewMethodSwitch([]string{"CreateEmbedding"}); err == nil {
+		t.Fatal("expected an unknown method to be rejected")
+	}
+
+	sw, err := NewMethodSwitch([]string{"CreateEmbeddings"})
+	if err != nil {
+		t.Fatalf("NewMethodSwitch: %v", err)
+	}
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": embeddingProvider{}}, nil, nil)
+	srv, err := New("127.0.0.1:0", app, nil, WithMethodSwitch(sw))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.s.Serve(lis) }()
+	t.Cleanup(srv.s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := llmgatewayv1.NewLLMGatewayServiceClient(conn)
+	embed := func() error {
+		_, err := client.CreateEmbeddings(context.Background(), &llmgatewayv1.CreateEmbeddingsRequest{Model: "fake/emb", Input: []string{"a"}})
+		return err
+	}
+	embedStream := func() error {
+		stream, err := client.CreateEmbeddingsStream(context.Background(), &llmgatewayv1.CreateEmbeddingsStreamRequest{
+			Request: &llmgatewayv1.CreateEmbeddingsRequest{Model: "fake/emb", Input: []string{"a"}},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+
+	if err := embed(); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("disabled CreateEmbeddings: expected Unimplemented, got %v", err)
+	}
+	if err := embedStream(); err != nil {
+		t.Fatalf("CreateEmbeddingsStream should still work: %v", err)
+	}
+
+	// Reloading the set takes effect for the next call.
+	if err := sw.SetDisabled([]string{"CreateEmbeddingsStream"}); err != nil {
+		t.Fatalf("SetDisabled: %v", err)
+	}
+	if err := embed(); err != nil {
+		t.Fatalf("re-enabled CreateEmbeddings: %v", err)
+	}
+	if err := embedStream(); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("disabled CreateEmbeddingsStream: expected Unimplemented, got %v", err)
+	}
+}
@@ -10,42 +10,142 @@ import (
 	"github.com/poly-workshop/go-webmods/grpcutils"
 	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
 	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/admission"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/debuglog"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/transport/grpcadapter"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/usagecallback"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+// DefaultShutdownTimeout bounds graceful stop when WithShutdownTimeout is not set.
+const DefaultShutdownTimeout = 5 * time.Second
+
 type Server struct {
-	listenAddr string
-	s          *grpc.Server
-	lis        net.Listener
+	listenAddr      string
+	s               *grpc.Server
+	lis             net.Listener
+	shutdownTimeout time.Duration
+	keepalive       Keepalive
+	cbBatcher       *usagecallback.Batcher
+}
+
+type options struct {
+	limiter           *admission.Limiter
+	sampler           *debuglog.Sampler
+	shutdownTimeout   time.Duration
+	gzip              bool
+	methods           *MethodSwitch
+	keepalive         Keepalive
+	overrideHosts     []string
+	cbBatchSize       int
+	cbBatchInterval   time.Duration
+	streamsPerSubject int
+	tenantPools       []TenantPool
+	usageTrailers     bool
+
+	maxUpstreamTimeout time.Duration
+}
+
+type Option func(*options)
+
+// WithAdmission bounds total concurrent requests; excess requests fail with Unavailable.
+func WithAdmission(l *admission.Limiter) Option {
+	return func(o *options) { o.limiter = l }
 }
 
-func New(listenAddr string, appSvc *llmgateway.Service, authMgr *auth.Manager) (*Server, error) {
+// WithShutdownTimeout bounds how long Stop waits for in-flight RPCs (including
+// long-running streams) to drain before closing them; d <= 0 keeps DefaultShutdownTimeout.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(o *options) { o.shutdownTimeout = d }
+}
+
+// WithDebugSampling logs extra per-request detail at debug level for sampled requests.
+func WithDebugSampling(s *debuglog.Sampler) Option {
+	return func(o *options) { o.sampler = s }
+}
+
+// WithUsageCallbackBatching delivers usage callbacks as JSON arrays of up to
+// size events, at least every interval, instead of one POST per event. Buffered
+// events are flushed on Stop. size <= 0 keeps per-event delivery.
+func WithUsageCallbackBatching(size int, interval time.Duration) Option {
+	return func(o *options) {
+		o.cbBatchSize = size
+		o.cbBatchInterval = interval
+	}
+}
+
+// WithUsageTrailers sends completion token usage as x-usage-* trailers.
+func WithUsageTrailers(enabled bool) Option {
+	return func(o *options) { o.usageTrailers = enabled }
+}
+
+func New(listenAddr string, appSvc *llmgateway.Service, authMgr *auth.Manager, opts ...Option) (*Server, error) {
 	if listenAddr == "" {
 		return nil, fmt.Errorf("grpc listen address is empty")
 	}
 	if appSvc == nil {
 		return nil, fmt.Errorf("app service is nil")
 	}
+	if err := appSvc.ValidateModelWiring(); err != nil {
+		return nil, err
+	}
+
+	o := options{shutdownTimeout: DefaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.shutdownTimeout <= 0 {
+		o.shutdownTimeout = DefaultShutdownTimeout
+	}
+	for _, p := range o.tenantPools {
+		if !appSvc.HasProviderPool(p.Name) {
+			return nil, fmt.Errorf("tenant pool %q has no provider pool", p.Name)
+		}
+	}
 
+	// Disabled methods and admission run first so rejected requests cost as
+	// little as possible.
 	unaryInts := grpc.ChainUnaryInterceptor(
+		methodSwitchUnaryInterceptor(o.methods),
+		admission.UnaryServerInterceptor(o.limiter),
+		debuglog.UnaryServerInterceptor(o.sampler, slog.Default()),
 		grpcutils.BuildRequestIDInterceptor(),
 		grpcutils.BuildLogInterceptor(slog.Default()),
 		auth.UnaryServerInterceptor(authMgr),
+		upstreamOverrideUnaryInterceptor(o.overrideHosts),
+		tenantPoolUnaryInterceptor(o.tenantPools),
+		upstreamTimeoutUnaryInterceptor(o.maxUpstreamTimeout),
+		compressionUnaryInterceptor(o.gzip),
 	)
 	streamInts := grpc.ChainStreamInterceptor(
+		methodSwitchStreamInterceptor(o.methods),
+		admission.StreamServerInterceptor(o.limiter),
 		auth.StreamServerInterceptor(authMgr),
+		streamLimitStreamInterceptor(newStreamLimiter(o.streamsPerSubject)),
+		upstreamOverrideStreamInterceptor(o.overrideHosts),
+		tenantPoolStreamInterceptor(o.tenantPools),
+		upstreamTimeoutStreamInterceptor(o.maxUpstreamTimeout),
+		compressionStreamInterceptor(o.gzip),
 	)
 
-	s := grpc.NewServer(unaryInts, streamInts)
+	s := grpc.NewServer(append([]grpc.ServerOption{unaryInts, streamInts}, o.keepalive.serverOptions()...)...)
 
-	llmgatewayv1.RegisterLLMGatewayServiceServer(s, grpcadapter.NewLLMGatewayService(appSvc, authMgr))
+	adapterOpts := []grpcadapter.Option{
+		grpcadapter.WithUsageTrailers(o.usageTrailers),
+		grpcadapter.WithMethodFilter(o.methods.Enabled),
+	}
+	var cbBatcher *usagecallback.Batcher
+	if o.cbBatchSize > 0 {
+		cbBatcher = usagecallback.NewBatcher(usagecallback.New(nil, 3*time.Second), o.cbBatchSize, o.cbBatchInterval)
+		adapterOpts = append(adapterOpts, grpcadapter.WithUsageCallbackBatcher(cbBatcher))
+	}
+	llmgatewayv1.RegisterLLMGatewayServiceServer(s, grpcadapter.NewLLMGatewayService(appSvc, authMgr, adapterOpts...))
 
 	reflection.Register(s)
 
-	return &Server{listenAddr: listenAddr, s: s}, nil
+	return &Server{listenAddr: listenAddr, s: s, shutdownTimeout: o.shutdownTimeout, keepalive: o.keepalive, cbBatcher: cbBatcher}, nil
 }
 
 func (srv *Server) Start() error {
@@ -62,7 +162,17 @@ func (srv *Server) Stop(ctx context.Context) error {
 	if srv.s == nil {
 		return nil
 	}
+	err := srv.stopGRPC(ctx)
+	// Flush batched usage callbacks once no RPC can add to them.
+	if srv.cbBatcher != nil {
+		if cerr := srv.cbBatcher.Close(ctx); cerr != nil && err == nil {
+			err = fmt.Errorf("flush usage callbacks: %w", cerr)
+		}
+	}
+	return err
+}
 
+func (srv *Server) stopGRPC(ctx context.Context) error {
 	done := make(chan struct{})
 	go func() {
 		srv.s.GracefulStop()
@@ -75,7 +185,7 @@ func (srv *Server) Stop(ctx context.Context) error {
 		return ctx.Err()
 	case <-done:
 		return nil
-	case <-time.After(5 * time.Second):
+	case <-time.After(srv.shutdownTimeout):
 		srv.s.Stop()
 		return fmt.Errorf("grpc graceful stop timed out")
 	}
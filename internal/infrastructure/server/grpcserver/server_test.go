@@ -0,0 +1,58 @@
+package grpcserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+)
+
+func TestNew_ShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(nil, nil, nil)
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		want time.Duration
+	}{
+		{name: "default", want: DefaultShutdownTimeout},
+		{name: "configured", opts: []Option{WithShutdownTimeout(90 * time.Second)}, want: 90 * time.Second},
+		{name: "non-positive keeps default", opts: []Option{WithShutdownTimeout(0)}, want: DefaultShutdownTimeout},
+	} {
+		srv, err := New(":0", app, nil, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: New: %v", tt.name, err)
+		}
+		if srv.shutdownTimeout != tt.want {
+			t.Fatalf("%s: shutdownTimeout = %v, want %v", tt.name, srv.shutdownTimeout, tt.want)
+		}
+	}
+}
+
+func TestNew_RejectsModelsWithUnknownProvider(t *testing.T) {
+	t.Parallel()
+
+	providers := map[string]llmgateway.Provider{"openrouter": embeddingProvider{}}
+	ok := llmgateway.NewService(providers, []llmgateway.ModelSpec{
+		{ID: "openrouter/openai/gpt-4o", Provider: "openrouter", UpstreamModel: "openai/gpt-4o"},
+	}, nil)
+	if _, err := New(":0", ok, nil); err != nil {
+		t.Fatalf("New with valid wiring: %v", err)
+	}
+
+	typo := llmgateway.NewService(providers, []llmgateway.ModelSpec{
+		{ID: "openrouter/openai/gpt-4o", Provider: "openrouer", UpstreamModel: "openai/gpt-4o"},
+		{ID: "gpt", Provider: "openrouter"},
+	}, nil)
+	_, err := New(":0", typo, nil)
+	if err == nil {
+		t.Fatal("expected startup validation to fail")
+	}
+	for _, want := range []string{`"openrouter/openai/gpt-4o" references unknown provider "openrouer"`, `model "gpt" has no upstream_model`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q should mention %s", err, want)
+		}
+	}
+}
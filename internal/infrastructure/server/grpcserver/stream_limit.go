@@ -0,0 +1,71 @@
+package grpcserver
+
+import (
+	"sync"
+
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithStreamsPerSubject caps the streams one authenticated subject may have
+// open at once; further streams fail with ResourceExhausted until one closes.
+// n <= 0 disables the cap. Unauthenticated streams (auth disabled) are not capped.
+func WithStreamsPerSubject(n int) Option {
+	return func(o *options) { o.streamsPerSubject = n }
+}
+
+// streamLimiter counts open streams per subject. Entries are removed when
+// their count drops to zero, so the map only holds subjects with live streams.
+type streamLimiter struct {
+	max int
+
+	mu   sync.Mutex
+	open map[string]int
+}
+
+func newStreamLimiter(n int) *streamLimiter {
+	if n <= 0 {
+		return nil
+	}
+	return &streamLimiter{max: n, open: make(map[string]int)}
+}
+
+func (l *streamLimiter) acquire(subject string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.open[subject] >= l.max {
+		return false
+	}
+	l.open[subject]++
+	return true
+}
+
+func (l *streamLimiter) release(subject string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.open[subject] <= 1 {
+		delete(l.open, subject)
+		return
+	}
+	l.open[subject]--
+}
+
+// streamLimitStreamInterceptor must run after authentication.
+func streamLimitStreamInterceptor(l *streamLimiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if l == nil {
+			return handler(srv, ss)
+		}
+		p, ok := auth.PrincipalFromContext(ss.Context())
+		if !ok {
+			return handler(srv, ss)
+		}
+		if !l.acquire(p.Subject) {
+			return status.Errorf(codes.ResourceExhausted, "too many open streams for %q (max %d)", p.Subject, l.max)
+		}
+		defer l.release(p.Subject)
+		return handler(srv, ss)
+	}
+}
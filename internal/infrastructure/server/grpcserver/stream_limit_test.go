@@ -0,0 +1,118 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// holdingStreamProvider sends one chunk, then holds the stream open until its
+// context ends.
+type holdingStreamProvider struct{ embeddingProvider }
+
+func (holdingStreamProvider) CreateChatCompletionStream(ctx context.Context, _ llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error {
+	if err := emit(llm.ChatCompletionChunk{ID: "c-1", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{Content: "hi"}}}}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestStreamsPerSubject(t *testing.T) {
+	t.Parallel()
+
+	const limit = 2
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": holdingStreamProvider{}}, nil, nil)
+	authMgr := auth.NewManager([]auth.ServiceToken{
+		{Name: "app", Token: "app-token"},
+		{Name: "other", Token: "other-token"},
+	}, time.Hour, 0)
+	srv, err := New("127.0.0.1:0", app, authMgr, WithStreamsPerSubject(limit))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.s.Serve(lis) }()
+	t.Cleanup(srv.s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := llmgatewayv1.NewLLMGatewayServiceClient(conn)
+
+	// open starts a stream and waits for its first chunk, so the server has
+	// counted it; the returned cancel closes it.
+	open := func(token string) (context.CancelFunc, error) {
+		ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-service-token", token)))
+		stream, err := client.CreateChatCompletionStream(ctx, &llmgatewayv1.CreateChatCompletionStreamRequest{
+			Request: &llmgatewayv1.CreateChatCompletionRequest{
+				Model:    "fake/chat",
+				Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: structpb.NewStringValue("hi")}},
+			},
+		})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return cancel, nil
+	}
+
+	var cancels []context.CancelFunc
+	t.Cleanup(func() {
+		for _, c := range cancels {
+			c()
+		}
+	})
+	for i := range limit {
+		cancel, err := open("app-token")
+		if err != nil {
+			t.Fatalf("stream %d: %v", i+1, err)
+		}
+		cancels = append(cancels, cancel)
+	}
+	if _, err := open("app-token"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("stream %d: expected ResourceExhausted, got %v", limit+1, err)
+	}
+
+	// Other subjects have their own cap.
+	cancel, err := open("other-token")
+	if err != nil {
+		t.Fatalf("other subject: %v", err)
+	}
+	cancels = append(cancels, cancel)
+
+	// Closing a stream frees its slot once the server sees the cancellation.
+	cancels[0]()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cancel, err := open("app-token")
+		if err == nil {
+			cancels = append(cancels, cancel)
+			break
+		}
+		if status.Code(err) != codes.ResourceExhausted || time.Now().After(deadline) {
+			t.Fatalf("stream after close: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,82 @@
+package grpcserver
+
+import (
+	"context"
+	"slices"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mdTenant names the provider pool a request asks for.
+const mdTenant = "x-tenant"
+
+// TenantPool maps subjects to a provider pool registered with
+// llmgateway.WithProviderPools.
+type TenantPool struct {
+	Name     string
+	Subjects []string
+}
+
+// WithTenantPools routes requests to provider pools. A subject listed in a
+// pool uses it (the first one, if listed in several); the x-tenant header
+// picks another pool the subject is listed in, and naming any other pool is
+// PermissionDenied. Unlisted subjects use the default pool. With auth
+// disabled, x-tenant may name any pool.
+func WithTenantPools(pools []TenantPool) Option {
+	return func(o *options) { o.tenantPools = pools }
+}
+
+// tenantPool returns ctx routed to the caller's provider pool. It must run
+// after authentication.
+func tenantPool(ctx context.Context, pools []TenantPool) (context.Context, error) {
+	if len(pools) == 0 {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	want := ""
+	if v := md.Get(mdTenant); len(v) > 0 {
+		want = v[0]
+	}
+	p, authenticated := auth.PrincipalFromContext(ctx)
+	for _, pool := range pools {
+		listed := authenticated && slices.Contains(pool.Subjects, p.Subject)
+		switch {
+		case want == "" && listed:
+			return llm.WithProviderPool(ctx, pool.Name), nil
+		case want != "" && pool.Name == want:
+			if authenticated && !listed {
+				return nil, status.Errorf(codes.PermissionDenied, "tenant %q is not allowed for this caller", want)
+			}
+			return llm.WithProviderPool(ctx, pool.Name), nil
+		}
+	}
+	if want != "" {
+		return nil, status.Errorf(codes.PermissionDenied, "tenant %q is not allowed for this caller", want)
+	}
+	return ctx, nil
+}
+
+func tenantPoolUnaryInterceptor(pools []TenantPool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := tenantPool(ctx, pools)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func tenantPoolStreamInterceptor(pools []TenantPool) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := tenantPool(ss.Context(), pools)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
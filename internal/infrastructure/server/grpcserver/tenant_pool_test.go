@@ -0,0 +1,101 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// namedProvider answers embeddings with its own name as the response ID.
+type namedProvider struct {
+	embeddingProvider
+	name string
+}
+
+func (p namedProvider) CreateEmbeddings(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	return llm.EmbeddingsResponse{ID: p.name, Model: req.Model}, nil
+}
+
+func TestTenantPools_RouteToTheirOwnProviders(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": namedProvider{name: "default"}}, nil, nil,
+		llmgateway.WithProviderPools(map[string]map[string]llmgateway.Provider{
+			"acme":   {"fake": namedProvider{name: "acme"}},
+			"globex": {"fake": namedProvider{name: "globex"}},
+		}))
+	authMgr := auth.NewManager([]auth.ServiceToken{
+		{Name: "acme-app", Token: "acme-token"},
+		{Name: "shared-app", Token: "shared-token"},
+		{Name: "other-app", Token: "other-token"},
+	}, time.Hour, 0)
+	srv, err := New("127.0.0.1:0", app, authMgr, WithTenantPools([]TenantPool{
+		{Name: "acme", Subjects: []string{"acme-app", "shared-app"}},
+		{Name: "globex", Subjects: []string{"shared-app"}},
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.s.Serve(lis) }()
+	t.Cleanup(srv.s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := llmgatewayv1.NewLLMGatewayServiceClient(conn)
+	embed := func(token, tenant string) (string, error) {
+		md := metadata.Pairs("x-service-token", token)
+		if tenant != "" {
+			md.Set(mdTenant, tenant)
+		}
+		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		resp, err := client.CreateEmbeddings(ctx, &llmgatewayv1.CreateEmbeddingsRequest{Model: "fake/emb", Input: []string{"a"}})
+		return resp.GetId(), err
+	}
+
+	for _, tt := range []struct {
+		name, token, tenant, want string
+	}{
+		{name: "listed subject", token: "acme-token", want: "acme"},
+		{name: "first pool without header", token: "shared-token", want: "acme"},
+		{name: "header picks a listed pool", token: "shared-token", tenant: "globex", want: "globex"},
+		{name: "unlisted subject", token: "other-token", want: "default"},
+	} {
+		got, err := embed(tt.token, tt.tenant)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: routed to %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		name, token, tenant string
+		want                codes.Code
+	}{
+		{name: "pool of another subject", token: "acme-token", tenant: "globex", want: codes.PermissionDenied},
+		{name: "unknown pool", token: "other-token", tenant: "initech", want: codes.PermissionDenied},
+	} {
+		if _, err := embed(tt.token, tt.tenant); status.Code(err) != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
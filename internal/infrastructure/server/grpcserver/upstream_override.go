@@ -0,0 +1,72 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// WithUpstreamOverrideHosts lets admin-scoped callers send a single request to
+// another provider base URL (e.g. a staging upstream) with the
+// auth.MDUpstreamBaseURL header. The URL's host must match hosts, where
+// "*.example.com" matches subdomains; with no hosts every override is rejected.
+func WithUpstreamOverrideHosts(hosts []string) Option {
+	return func(o *options) { o.overrideHosts = hosts }
+}
+
+// upstreamOverride validates the request's base URL override, if any, and
+// returns ctx carrying it. It must run after authentication.
+func upstreamOverride(ctx context.Context, hosts []string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(auth.MDUpstreamBaseURL)
+	if len(v) == 0 || v[0] == "" {
+		return ctx, nil
+	}
+	p, _ := auth.PrincipalFromContext(ctx)
+	if !p.HasScope(auth.ScopeAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "upstream base url override requires admin scope")
+	}
+	u, err := url.Parse(v[0])
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.User != nil {
+		return nil, status.Error(codes.InvalidArgument, "upstream base url override must be an absolute http(s) url")
+	}
+	if !llm.HostAllowed(u.Hostname(), hosts) {
+		return nil, status.Errorf(codes.PermissionDenied, "upstream host %q is not allowed", u.Hostname())
+	}
+	slog.InfoContext(ctx, "upstream base url override", "subject", p.Subject, "base_url", u.String())
+	return llm.WithUpstreamBaseURL(ctx, u.String()), nil
+}
+
+func upstreamOverrideUnaryInterceptor(hosts []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := upstreamOverride(ctx, hosts)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func upstreamOverrideStreamInterceptor(hosts []string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := upstreamOverride(ss.Context(), hosts)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context { return s.ctx }
@@ -0,0 +1,98 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// baseURLProvider reports the upstream base URL override each call saw.
+type baseURLProvider struct {
+	embeddingProvider
+	seen chan string
+}
+
+func (p baseURLProvider) CreateEmbeddings(ctx context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	p.seen <- llm.UpstreamBaseURL(ctx)
+	return llm.EmbeddingsResponse{ID: "emb-1", Model: req.Model}, nil
+}
+
+func TestUpstreamOverride_AdminOnlyAndAllowlisted(t *testing.T) {
+	t.Parallel()
+
+	p := baseURLProvider{seen: make(chan string, 1)}
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": p}, nil, nil)
+	authMgr := auth.NewManager([]auth.ServiceToken{
+		{Name: "ops", Token: "admin-token", Scopes: []string{auth.ScopeAdmin}},
+		{Name: "app", Token: "app-token"},
+	}, time.Hour, 0)
+	srv, err := New("127.0.0.1:0", app, authMgr, WithUpstreamOverrideHosts([]string{"*.staging.example.com"}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.s.Serve(lis) }()
+	t.Cleanup(srv.s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := llmgatewayv1.NewLLMGatewayServiceClient(conn)
+	embed := func(token, override string) error {
+		md := metadata.Pairs("x-service-token", token)
+		if override != "" {
+			md.Set(auth.MDUpstreamBaseURL, override)
+		}
+		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		_, err := client.CreateEmbeddings(ctx, &llmgatewayv1.CreateEmbeddingsRequest{Model: "fake/emb", Input: []string{"a"}})
+		return err
+	}
+
+	if err := embed("admin-token", "https://llm.staging.example.com/v1"); err != nil {
+		t.Fatalf("admin override to an allowed host: %v", err)
+	}
+	if got := <-p.seen; got != "https://llm.staging.example.com/v1" {
+		t.Fatalf("provider saw base url %q", got)
+	}
+	if err := embed("app-token", ""); err != nil {
+		t.Fatalf("request without override: %v", err)
+	}
+	if got := <-p.seen; got != "" {
+		t.Fatalf("expected no override, provider saw %q", got)
+	}
+
+	for _, tt := range []struct {
+		name, token, override string
+		want                  codes.Code
+	}{
+		{name: "non-admin", token: "app-token", override: "https://llm.staging.example.com/v1", want: codes.PermissionDenied},
+		{name: "disallowed host", token: "admin-token", override: "https://evil.example.net/v1", want: codes.PermissionDenied},
+		{name: "bare allowlist suffix", token: "admin-token", override: "https://staging.example.com.evil.net/v1", want: codes.PermissionDenied},
+		{name: "not a url", token: "admin-token", override: "llm.staging.example.com", want: codes.InvalidArgument},
+	} {
+		if err := embed(tt.token, tt.override); status.Code(err) != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+	select {
+	case got := <-p.seen:
+		t.Fatalf("rejected overrides must not reach the provider, saw %q", got)
+	default:
+	}
+}
@@ -0,0 +1,66 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mdUpstreamTimeout asks for a longer upstream timeout (a Go duration such as
+// "10m") than the configured one, e.g. for batch jobs.
+const mdUpstreamTimeout = "x-upstream-timeout"
+
+// WithMaxUpstreamTimeout lets authenticated callers extend the request,
+// upstream attempt and provider timeouts of a request with the
+// x-upstream-timeout header, up to max; longer values are clamped. max <= 0
+// rejects the header.
+func WithMaxUpstreamTimeout(max time.Duration) Option {
+	return func(o *options) { o.maxUpstreamTimeout = max }
+}
+
+// upstreamTimeout returns ctx carrying the caller's extended upstream timeout,
+// if any. It must run after authentication.
+func upstreamTimeout(ctx context.Context, max time.Duration) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(mdUpstreamTimeout)
+	if len(v) == 0 || v[0] == "" {
+		return ctx, nil
+	}
+	if _, ok := auth.PrincipalFromContext(ctx); !ok {
+		return nil, status.Error(codes.PermissionDenied, "x-upstream-timeout requires an authenticated caller")
+	}
+	if max <= 0 {
+		return nil, status.Error(codes.PermissionDenied, "extended upstream timeouts are disabled on this gateway")
+	}
+	d, err := time.ParseDuration(v[0])
+	if err != nil || d <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid x-upstream-timeout %q: must be a positive duration such as \"10m\"", v[0])
+	}
+	return llm.WithUpstreamTimeout(ctx, min(d, max)), nil
+}
+
+func upstreamTimeoutUnaryInterceptor(max time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := upstreamTimeout(ctx, max)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func upstreamTimeoutStreamInterceptor(max time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := upstreamTimeout(ss.Context(), max)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
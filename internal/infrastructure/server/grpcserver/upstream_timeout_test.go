@@ -0,0 +1,64 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpstreamTimeout_HonoredUpToMaxAndClamped(t *testing.T) {
+	t.Parallel()
+
+	batch := auth.RequestPrincipal{Subject: "svc:batch", Method: auth.MethodServiceToken}
+	incoming := func(p *auth.RequestPrincipal, timeout string) context.Context {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(mdUpstreamTimeout, timeout))
+		if p != nil {
+			ctx = auth.WithPrincipal(ctx, *p)
+		}
+		return ctx
+	}
+
+	for _, tt := range []struct {
+		name    string
+		timeout string
+		want    time.Duration
+	}{
+		{name: "below max", timeout: "10m", want: 10 * time.Minute},
+		{name: "at max", timeout: "30m", want: 30 * time.Minute},
+		{name: "beyond max", timeout: "4h", want: 30 * time.Minute},
+	} {
+		ctx, err := upstreamTimeout(incoming(&batch, tt.timeout), 30*time.Minute)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := llm.UpstreamTimeout(ctx); got != tt.want {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	ctx, err := upstreamTimeout(context.Background(), 30*time.Minute)
+	if err != nil || llm.UpstreamTimeout(ctx) != 0 {
+		t.Fatalf("expected no extension without the header, got %v, %v", llm.UpstreamTimeout(ctx), err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		max  time.Duration
+		want codes.Code
+	}{
+		{name: "unauthenticated", ctx: incoming(nil, "10m"), max: 30 * time.Minute, want: codes.PermissionDenied},
+		{name: "disabled", ctx: incoming(&batch, "10m"), want: codes.PermissionDenied},
+		{name: "not a duration", ctx: incoming(&batch, "ten minutes"), max: 30 * time.Minute, want: codes.InvalidArgument},
+	} {
+		if _, err := upstreamTimeout(tt.ctx, tt.max); status.Code(err) != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package httpgateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const embeddingsPath = "/v1/embeddings"
+
+// maxNormalizeBody bounds how much of a request body is buffered for rewriting.
+// Larger bodies are passed through untouched.
+const maxNormalizeBody = 10 << 20 // 10MiB
+
+// withEmbeddingsStringInput accepts OpenAI's single-string form of embeddings
+// input ("input": "hello") by rewriting it to the one-element array the proto
+// expects. It must run after the body hash for signature auth is computed, so
+// signatures still cover the bytes the client actually sent.
+func withEmbeddingsStringInput(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != embeddingsPath || r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		b, err := io.ReadAll(io.LimitReader(r.Body, maxNormalizeBody+1))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		if len(b) > maxNormalizeBody {
+			r.Body = readCloser{io.MultiReader(bytes.NewReader(b), r.Body), r.Body}
+			next.ServeHTTP(w, r)
+			return
+		}
+		_ = r.Body.Close()
+
+		if rewritten, ok := normalizeEmbeddingsInput(b); ok {
+			b = rewritten
+			r.ContentLength = int64(len(b))
+			r.Header.Del("Content-Length")
+		}
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// normalizeEmbeddingsInput wraps a string "input" in an array. It reports false
+// (leaving the body to grpc-gateway's own error handling) if nothing needs rewriting.
+func normalizeEmbeddingsInput(body []byte) ([]byte, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, false
+	}
+	raw, ok := fields["input"]
+	if !ok {
+		return nil, false
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, false
+	}
+	arr, err := json.Marshal([]string{s})
+	if err != nil {
+		return nil, false
+	}
+	fields["input"] = arr
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,45 @@
+package httpgateway
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestWithEmbeddingsStringInput(t *testing.T) {
+	t.Parallel()
+
+	var got *llmgatewayv1.CreateEmbeddingsRequest
+	h := withEmbeddingsStringInput(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = &llmgatewayv1.CreateEmbeddingsRequest{}
+		if err := protojson.Unmarshal(b, got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "string", body: `{"model":"dashscope/text-embedding-v3","input":"hello"}`, want: []string{"hello"}},
+		{name: "array", body: `{"model":"dashscope/text-embedding-v3","input":["a","b"]}`, want: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/embeddings", strings.NewReader(tt.body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: unexpected status %d: %s", tt.name, rec.Code, rec.Body.String())
+		}
+		if got.GetModel() != "dashscope/text-embedding-v3" || !slices.Equal(got.GetInput(), tt.want) {
+			t.Fatalf("%s: unexpected request: %v", tt.name, got)
+		}
+	}
+}
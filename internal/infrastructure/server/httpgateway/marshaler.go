@@ -0,0 +1,50 @@
+package httpgateway
+
+import (
+	"fmt"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// JSON field name styles for gateway responses. Requests accept both styles.
+const (
+	// JSONFieldNamesSnakeCase emits proto field names ("max_tokens"), as OpenAI does.
+	JSONFieldNamesSnakeCase = "snake_case"
+	// JSONFieldNamesCamelCase emits lowerCamelCase names ("maxTokens"), grpc-gateway's default.
+	JSONFieldNamesCamelCase = "camel_case"
+)
+
+// WithJSONFieldNames sets the response field name style; "" keeps
+// JSONFieldNamesSnakeCase.
+func WithJSONFieldNames(style string) Option {
+	return func(s *Server) {
+		if style != "" {
+			s.jsonFieldNames = style
+		}
+	}
+}
+
+// jsonMarshaler returns the marshaler for style. Unmarshaling accepts both the
+// proto and the camelCase name of every field and ignores unknown fields.
+func jsonMarshaler(style string) (runtime.Marshaler, error) {
+	var useProtoNames bool
+	switch style {
+	case JSONFieldNamesSnakeCase:
+		useProtoNames = true
+	case JSONFieldNamesCamelCase:
+	default:
+		return nil, fmt.Errorf("unknown json field name style %q", style)
+	}
+	return &runtime.HTTPBodyMarshaler{
+		Marshaler: &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   useProtoNames,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		},
+	}, nil
+}
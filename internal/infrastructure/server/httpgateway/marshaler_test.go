@@ -0,0 +1,71 @@
+package httpgateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+)
+
+// echoChatServer answers with max_tokens echoed as completion_tokens.
+type echoChatServer struct {
+	llmgatewayv1.UnimplementedLLMGatewayServiceServer
+}
+
+func (echoChatServer) CreateChatCompletion(_ context.Context, req *llmgatewayv1.CreateChatCompletionRequest) (*llmgatewayv1.CreateChatCompletionResponse, error) {
+	return &llmgatewayv1.CreateChatCompletionResponse{
+		Model: req.GetModel(),
+		Choices: []*llmgatewayv1.ChatCompletionChoice{
+			{Message: &llmgatewayv1.ChatMessage{Role: "assistant"}, FinishReason: "stop"},
+		},
+		Usage: &llmgatewayv1.TokenUsage{CompletionTokens: req.GetMaxTokens()},
+	}, nil
+}
+
+func TestGatewayMux_AcceptsCamelCaseAndEmitsSnakeCase(t *testing.T) {
+	t.Parallel()
+
+	gw, err := newGatewayMux(JSONFieldNamesSnakeCase, nil)
+	if err != nil {
+		t.Fatalf("newGatewayMux: %v", err)
+	}
+	if err := llmgatewayv1.RegisterLLMGatewayServiceHandlerServer(context.Background(), gw, echoChatServer{}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	for _, body := range []string{
+		`{"model":"m","messages":[{"role":"user","content":"hi"}],"maxTokens":7}`,
+		`{"model":"m","messages":[{"role":"user","content":"hi"}],"max_tokens":7}`,
+	} {
+		rec := httptest.NewRecorder()
+		gw.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: unexpected status %d: %s", body, rec.Code, rec.Body.String())
+		}
+		var resp struct {
+			Choices []map[string]any `json:"choices"`
+			Usage   map[string]any   `json:"usage"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if resp.Usage["completion_tokens"] != float64(7) {
+			t.Fatalf("%s: expected max_tokens to reach the server and snake_case usage, got %s", body, rec.Body.String())
+		}
+		if _, ok := resp.Choices[0]["finish_reason"]; !ok || strings.Contains(rec.Body.String(), "finishReason") {
+			t.Fatalf("expected snake_case response fields, got %s", rec.Body.String())
+		}
+	}
+}
+
+func TestNew_RejectsUnknownJSONFieldNames(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(":0", "127.0.0.1:1", true, WithJSONFieldNames("kebab-case")); err == nil {
+		t.Fatal("expected an unknown field name style to be rejected")
+	}
+}
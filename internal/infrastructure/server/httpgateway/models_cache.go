@@ -0,0 +1,108 @@
+package httpgateway
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxModelsCacheEntries bounds the cache; expired entries are swept when it fills.
+const maxModelsCacheEntries = 1024
+
+// WithModelsCacheTTL caches GET /v1/models responses for ttl, saving a gRPC
+// round trip per call; ttl <= 0 disables the cache.
+func WithModelsCacheTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.modelsCacheTTL = ttl
+	}
+}
+
+// modelsCache serves repeated GET /v1/models requests from memory. Entries are
+// keyed by the caller's service token and query, so a response (which the gRPC
+// side may filter per subject) is only reused for the credential that fetched
+// it. Signature-authenticated and anonymous requests are never cached: their
+// credentials change per request, and replaying a response without the gRPC
+// side checking them would skip authentication.
+type modelsCache struct {
+	next http.Handler
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]modelsCacheEntry
+}
+
+type modelsCacheEntry struct {
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func withModelsCache(next http.Handler, ttl time.Duration) http.Handler {
+	if ttl <= 0 {
+		return next
+	}
+	return &modelsCache{next: next, ttl: ttl, now: time.Now, entries: make(map[[sha256.Size]byte]modelsCacheEntry)}
+}
+
+func (c *modelsCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := modelsCacheKey(r)
+	if !ok {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	e, hit := c.entries[key]
+	c.mu.Unlock()
+	if hit && now.Before(e.expires) {
+		for k, v := range e.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(e.body)
+		return
+	}
+
+	buf := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+	c.next.ServeHTTP(buf, r)
+	for k, v := range buf.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(buf.status)
+	_, _ = w.Write(buf.body.Bytes())
+	if buf.status != http.StatusOK {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxModelsCacheEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxModelsCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = modelsCacheEntry{header: buf.header.Clone(), body: buf.body.Bytes(), expires: now.Add(c.ttl)}
+}
+
+// modelsCacheKey identifies a cacheable models request, reporting false for
+// requests that must go to the gRPC side.
+func modelsCacheKey(r *http.Request) ([sha256.Size]byte, bool) {
+	token := r.Header.Get("X-Service-Token")
+	if r.Method != http.MethodGet || r.URL.Path != modelsPath || token == "" {
+		return [sha256.Size]byte{}, false
+	}
+	for _, h := range []string{"X-Access-Key-Id", "X-Signature", "X-Timestamp", "X-Nonce"} {
+		if r.Header.Get(h) != "" {
+			return [sha256.Size]byte{}, false
+		}
+	}
+	return sha256.Sum256([]byte(token + "\x00" + r.URL.RawQuery)), true
+}
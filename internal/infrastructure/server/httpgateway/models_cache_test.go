@@ -0,0 +1,52 @@
+package httpgateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithModelsCache(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	h := withModelsCache(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}), time.Minute).(*modelsCache)
+	now := time.Unix(1700000000, 0)
+	h.now = func() time.Time { return now }
+
+	get := func(header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Body.String() != `{"data":[]}` {
+			t.Fatalf("unexpected response: %d %q", rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+	tokenA := map[string]string{"X-Service-Token": "a"}
+
+	get(tokenA)
+	if rec := get(tokenA); calls != 1 || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("second call within the TTL should be served from cache, upstream calls = %d", calls)
+	}
+	if get(map[string]string{"X-Service-Token": "b"}); calls != 2 {
+		t.Fatalf("a different token must not share the cached list, upstream calls = %d", calls)
+	}
+	signed := map[string]string{"X-Service-Token": "a", "X-Access-Key-Id": "ak", "X-Signature": "sig"}
+	if get(signed); calls != 3 {
+		t.Fatalf("signed requests must bypass the cache, upstream calls = %d", calls)
+	}
+
+	now = now.Add(time.Minute)
+	if get(tokenA); calls != 4 {
+		t.Fatalf("expired entry should be refetched, upstream calls = %d", calls)
+	}
+}
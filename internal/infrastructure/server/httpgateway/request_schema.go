@@ -0,0 +1,152 @@
+package httpgateway
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+// schemaBaseURL names the embedded schemas so they can $ref one another.
+const schemaBaseURL = "https://llm-gateway.invalid/schemas/"
+
+// requestSchemas maps request paths to the embedded schema of their body.
+var requestSchemas = map[string]string{
+	"/v1/chat/completions":        "chat_completions.json",
+	"/v1/chat/completions:stream": "chat_completions_stream.json",
+	embeddingsPath:                "embeddings.json",
+}
+
+// WithRequestValidation checks chat and embeddings request bodies against the
+// embedded JSON schemas before forwarding them, answering violations with an
+// OpenAI-shaped 400. Only snake_case field names are checked; anything the
+// schemas do not describe is left to the gRPC side.
+func WithRequestValidation(enabled bool) Option {
+	return func(s *Server) { s.validateRequests = enabled }
+}
+
+func compileRequestSchemas() (map[string]*jsonschema.Schema, error) {
+	entries, err := schemaFS.ReadDir("schemas")
+	if err != nil {
+		return nil, err
+	}
+	c := jsonschema.NewCompiler()
+	for _, e := range entries {
+		b, err := schemaFS.ReadFile(path.Join("schemas", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", e.Name(), err)
+		}
+		if err := c.AddResource(schemaBaseURL+e.Name(), doc); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", e.Name(), err)
+		}
+	}
+	out := make(map[string]*jsonschema.Schema, len(requestSchemas))
+	for p, name := range requestSchemas {
+		sch, err := c.Compile(schemaBaseURL + name)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		out[p] = sch
+	}
+	return out, nil
+}
+
+// withRequestValidation rejects POST bodies that violate their path's schema.
+// Bodies over maxNormalizeBody are passed through unchecked.
+func withRequestValidation(next http.Handler, schemas map[string]*jsonschema.Schema) http.Handler {
+	if schemas == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sch, ok := schemas[r.URL.Path]
+		if r.Method != http.MethodPost || !ok || r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		b, err := io.ReadAll(io.LimitReader(r.Body, maxNormalizeBody+1))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		if len(b) > maxNormalizeBody {
+			r.Body = readCloser{io.MultiReader(bytes.NewReader(b), r.Body), r.Body}
+			next.ServeHTTP(w, r)
+			return
+		}
+		_ = r.Body.Close()
+
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+		if err != nil {
+			writeInvalidRequest(w, "request body is not valid JSON", "")
+			return
+		}
+		if err := sch.Validate(doc); err != nil {
+			msg, param := describeSchemaError(err)
+			writeInvalidRequest(w, msg, param)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		next.ServeHTTP(w, r)
+	})
+}
+
+var schemaErrorPrinter = message.NewPrinter(language.English)
+
+// describeSchemaError reports the first leaf violation and the dotted path of
+// the offending field ("messages.0.role"), which is empty for the body itself.
+func describeSchemaError(err error) (msg, param string) {
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err.Error(), ""
+	}
+	for len(ve.Causes) > 0 {
+		ve = ve.Causes[0]
+	}
+	param = strings.Join(ve.InstanceLocation, ".")
+	msg = ve.ErrorKind.LocalizedString(schemaErrorPrinter)
+	if param != "" {
+		msg = fmt.Sprintf("invalid request body at %q: %s", param, msg)
+	} else {
+		msg = "invalid request body: " + msg
+	}
+	return msg, param
+}
+
+// writeInvalidRequest writes an OpenAI-style error response.
+func writeInvalidRequest(w http.ResponseWriter, msg, param string) {
+	var p *string
+	if param != "" {
+		p = &param
+	}
+	body := struct {
+		Error struct {
+			Message string  `json:"message"`
+			Type    string  `json:"type"`
+			Param   *string `json:"param"`
+			Code    string  `json:"code"`
+		} `json:"error"`
+	}{}
+	body.Error.Message = msg
+	body.Error.Type = "invalid_request_error"
+	body.Error.Param = p
+	body.Error.Code = "invalid_request_body"
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(body)
+}
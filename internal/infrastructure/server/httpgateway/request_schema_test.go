@@ -0,0 +1,71 @@
+package httpgateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithRequestValidation(t *testing.T) {
+	t.Parallel()
+
+	schemas, err := compileRequestSchemas()
+	if err != nil {
+		t.Fatalf("compileRequestSchemas: %v", err)
+	}
+	var forwarded string
+	h := withRequestValidation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		forwarded = string(b)
+	}), schemas)
+
+	for _, tt := range []struct {
+		name, path, body string
+		wantParam        string // "" means the body must be forwarded
+	}{
+		{name: "valid chat", path: "/v1/chat/completions", body: `{"model":"m","messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}],"max_tokens":16}`},
+		{name: "valid stream", path: "/v1/chat/completions:stream", body: `{"request":{"model":"m","messages":[{"role":"user","content":"hi"}]}}`},
+		{name: "valid embeddings string", path: "/v1/embeddings", body: `{"model":"m","input":"hello"}`},
+		{name: "unchecked path", path: "/v1/completions", body: `{"prompt":1}`},
+		{name: "missing role", path: "/v1/chat/completions", body: `{"model":"m","messages":[{"content":"hi"}]}`, wantParam: "messages.0"},
+		{name: "temperature above 2", path: "/v1/chat/completions", body: `{"model":"m","messages":[{"role":"user","content":"hi"}],"temperature":3}`}, // the gateway's limit is configurable
+		{name: "temperature out of range", path: "/v1/chat/completions", body: `{"model":"m","messages":[{"role":"user","content":"hi"}],"temperature":-1}`, wantParam: "temperature"},
+		{name: "stream inner request", path: "/v1/chat/completions:stream", body: `{"request":{"model":"m","messages":[]}}`, wantParam: "request.messages"},
+		{name: "embeddings input type", path: "/v1/embeddings", body: `{"model":"m","input":[1,2]}`, wantParam: "input"},
+	} {
+		forwarded = ""
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		if tt.wantParam == "" {
+			if rec.Code != http.StatusOK || forwarded != tt.body {
+				t.Fatalf("%s: expected the body forwarded unchanged, got %d %q (%s)", tt.name, rec.Code, forwarded, rec.Body.String())
+			}
+			continue
+		}
+		if rec.Code != http.StatusBadRequest || forwarded != "" {
+			t.Fatalf("%s: expected 400 without forwarding, got %d (forwarded %q)", tt.name, rec.Code, forwarded)
+		}
+		var got struct {
+			Error struct {
+				Message string  `json:"message"`
+				Type    string  `json:"type"`
+				Param   *string `json:"param"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("%s: decode error body %q: %v", tt.name, rec.Body.String(), err)
+		}
+		if got.Error.Type != "invalid_request_error" || got.Error.Param == nil || *got.Error.Param != tt.wantParam || got.Error.Message == "" {
+			t.Fatalf("%s: unexpected error body %s", tt.name, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/embeddings", strings.NewReader(`{"model":`)))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "not valid JSON") {
+		t.Fatalf("expected malformed JSON rejected, got %d %s", rec.Code, rec.Body.String())
+	}
+}
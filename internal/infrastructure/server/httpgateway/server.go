@@ -15,32 +15,71 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/health"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// DefaultShutdownTimeout bounds graceful shutdown when WithShutdownTimeout is not set.
+const DefaultShutdownTimeout = 5 * time.Second
+
 type Server struct {
-	httpListen   string
-	grpcTarget   string
-	grpcInsecure bool
+	httpListen       string
+	grpcTarget       string
+	grpcInsecure     bool
+	shutdownTimeout  time.Duration
+	jsonFieldNames   string
+	modelsCacheTTL   time.Duration
+	validateRequests bool
+	schemas          map[string]*jsonschema.Schema
+	trailerHeaders   []string
 }
 
-func New(httpListen, grpcTarget string, grpcInsecure bool) (*Server, error) {
+type Option func(*Server)
+
+// WithShutdownTimeout bounds how long in-flight requests (including streamed
+// responses) may drain on shutdown; d <= 0 keeps DefaultShutdownTimeout.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		if d > 0 {
+			s.shutdownTimeout = d
+		}
+	}
+}
+
+func New(httpListen, grpcTarget string, grpcInsecure bool, opts ...Option) (*Server, error) {
 	if httpListen == "" {
 		return nil, fmt.Errorf("http listen address is empty")
 	}
 	if grpcTarget == "" {
 		return nil, fmt.Errorf("grpc target is empty")
 	}
-	return &Server{httpListen: httpListen, grpcTarget: grpcTarget, grpcInsecure: grpcInsecure}, nil
+	s := &Server{httpListen: httpListen, grpcTarget: grpcTarget, grpcInsecure: grpcInsecure, shutdownTimeout: DefaultShutdownTimeout, jsonFieldNames: JSONFieldNamesSnakeCase}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if _, err := jsonMarshaler(s.jsonFieldNames); err != nil {
+		return nil, err
+	}
+	if s.validateRequests {
+		schemas, err := compileRequestSchemas()
+		if err != nil {
+			return nil, err
+		}
+		s.schemas = schemas
+	}
+	return s, nil
 }
 
-func (s *Server) Start(ctx context.Context) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/livez", health.Livez)
-	mux.HandleFunc("/readyz", health.Readyz(health.GRPCDialReadyChecker(s.grpcTarget)))
-
-	gw := runtime.NewServeMux(
+// newGatewayMux builds the grpc-gateway mux with the header matchers and the
+// JSON marshaler for fieldNames.
+func newGatewayMux(fieldNames string, trailerHeaders []string) (*runtime.ServeMux, error) {
+	marshaler, err := jsonMarshaler(fieldNames)
+	if err != nil {
+		return nil, err
+	}
+	return runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, marshaler),
 		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
 			k := strings.ToLower(key)
 			switch k {
@@ -50,16 +89,39 @@ func (s *Server) Start(ctx context.Context) error {
 				"x-timestamp",
 				"x-nonce",
 				"x-usage-callback",
+				"x-conversation-id",
+				"x-request-timeout",
 				"x-llmgw-http-method",
 				"x-llmgw-http-path",
 				"x-llmgw-http-query",
-				"x-llmgw-body-sha256":
+				"x-llmgw-body-sha256",
+				"x-llmgw-upstream-base-url",
+				"x-tenant",
+				"x-upstream-timeout":
 				return k, true
 			default:
 				return runtime.DefaultHeaderMatcher(key)
 			}
 		}),
-	)
+		runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+			if key == "x-provider-request-id" {
+				return key, true
+			}
+			return runtime.MetadataHeaderPrefix + key, true
+		}),
+		runtime.WithForwardResponseOption(forwardTrailerHeaders(trailerHeaders)),
+	), nil
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", health.Livez)
+	mux.HandleFunc("/readyz", health.Readyz(health.GRPCDialReadyChecker(s.grpcTarget)))
+
+	gw, err := newGatewayMux(s.jsonFieldNames, s.trailerHeaders)
+	if err != nil {
+		return err
+	}
 	dialOpts := []grpc.DialOption{}
 	if s.grpcInsecure {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -72,6 +134,7 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 
 	// Inject HTTP signing context for gRPC-side signature verification.
+	api := withModelsETag(withModelsCache(withRequestValidation(withEmbeddingsStringInput(gw), s.schemas), s.modelsCacheTTL))
 	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Only for grpc-gateway forwarded requests.
 		r.Header.Set("X-LLMGW-HTTP-Method", r.Method)
@@ -101,7 +164,7 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 		r.Header.Set("X-LLMGW-Body-SHA256", hex.EncodeToString(sum[:]))
 
-		gw.ServeHTTP(w, r)
+		api.ServeHTTP(w, r)
 	}))
 
 	srv := &http.Server{
@@ -118,7 +181,7 @@ func (s *Server) Start(ctx context.Context) error {
 
 	select {
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 		defer cancel()
 		_ = srv.Shutdown(shutdownCtx)
 		return ctx.Err()
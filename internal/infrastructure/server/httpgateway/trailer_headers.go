@@ -0,0 +1,40 @@
+package httpgateway
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+)
+
+// WithTrailerHeaders copies the named gRPC trailers (such as the
+// x-usage-total-tokens usage trailer) into unary HTTP response headers, which
+// HTTP/1.1 clients and proxies read more easily than trailers. Streamed
+// responses are unaffected: their headers are sent before the trailers exist.
+func WithTrailerHeaders(keys []string) Option {
+	return func(s *Server) {
+		s.trailerHeaders = keys
+	}
+}
+
+// forwardTrailerHeaders returns a forward response option setting the keys
+// found in the call's trailer metadata as response headers.
+func forwardTrailerHeaders(keys []string) func(context.Context, http.ResponseWriter, proto.Message) error {
+	return func(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+		if len(keys) == 0 {
+			return nil
+		}
+		md, ok := runtime.ServerMetadataFromContext(ctx)
+		if !ok {
+			return nil
+		}
+		for _, k := range keys {
+			for _, v := range md.TrailerMD.Get(strings.ToLower(k)) {
+				w.Header().Add(k, v)
+			}
+		}
+		return nil
+	}
+}
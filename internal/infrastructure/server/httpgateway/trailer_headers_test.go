@@ -0,0 +1,49 @@
+package httpgateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// usageTrailerServer answers chat with usage trailers, as the gRPC server
+// does with usage trailers enabled.
+type usageTrailerServer struct {
+	echoChatServer
+}
+
+func (s usageTrailerServer) CreateChatCompletion(ctx context.Context, req *llmgatewayv1.CreateChatCompletionRequest) (*llmgatewayv1.CreateChatCompletionResponse, error) {
+	_ = grpc.SetTrailer(ctx, metadata.Pairs("x-usage-total-tokens", "42", "x-usage-prompt-tokens", "40"))
+	return s.echoChatServer.CreateChatCompletion(ctx, req)
+}
+
+func TestGatewayMux_CopiesTrailersToHeaders(t *testing.T) {
+	t.Parallel()
+
+	gw, err := newGatewayMux(JSONFieldNamesSnakeCase, []string{"x-usage-total-tokens"})
+	if err != nil {
+		t.Fatalf("newGatewayMux: %v", err)
+	}
+	if err := llmgatewayv1.RegisterLLMGatewayServiceHandlerServer(context.Background(), gw, usageTrailerServer{}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	body := `{"model":"m","messages":[{"role":"user","content":"hi"}]}`
+	gw.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("x-usage-total-tokens"); got != "42" {
+		t.Fatalf("expected the usage trailer as a header, got %q (headers %v)", got, rec.Header())
+	}
+	if got := rec.Header().Get("x-usage-prompt-tokens"); got != "" {
+		t.Fatalf("expected only configured trailers to be copied, got %q", got)
+	}
+}
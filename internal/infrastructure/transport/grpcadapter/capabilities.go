@@ -0,0 +1,50 @@
+package grpcadapter
+
+import (
+	"context"
+	"sort"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/buildinfo"
+)
+
+// WithMethodFilter reports only methods for which enabled returns true as
+// operations in GetCapabilities, e.g. to hide methods disabled by config.
+func WithMethodFilter(enabled func(method string) bool) Option {
+	return func(s *LLMGatewayService) { s.methodEnabled = enabled }
+}
+
+func (s *LLMGatewayService) GetCapabilities(ctx context.Context, _ *llmgatewayv1.GetCapabilitiesRequest) (*llmgatewayv1.GetCapabilitiesResponse, error) {
+	caps := s.app.Capabilities(ctx)
+
+	var ops []string
+	for _, m := range llmgatewayv1.LLMGatewayService_ServiceDesc.Methods {
+		ops = append(ops, m.MethodName)
+	}
+	for _, m := range llmgatewayv1.LLMGatewayService_ServiceDesc.Streams {
+		ops = append(ops, m.StreamName)
+	}
+	enabled := make(map[string]bool, len(ops))
+	out := &llmgatewayv1.GetCapabilitiesResponse{Version: buildinfo.Version}
+	for _, op := range ops {
+		if s.methodEnabled == nil || s.methodEnabled(op) {
+			enabled[op] = true
+			out.Operations = append(out.Operations, op)
+		}
+	}
+	sort.Strings(out.Operations)
+
+	out.Streaming = caps.Streaming && enabled["CreateChatCompletionStream"]
+	out.ToolCalling = caps.ToolCalling
+	out.Vision = caps.Vision
+	for _, m := range caps.Models {
+		out.Models = append(out.Models, &llmgatewayv1.ModelCapabilities{
+			Id:              m.ID,
+			Capabilities:    m.Capabilities,
+			Streaming:       m.Streaming,
+			TextCompletions: m.TextCompletions,
+			TokenCounting:   m.TokenCounting,
+		})
+	}
+	return out, nil
+}
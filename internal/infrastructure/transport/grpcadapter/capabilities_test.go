@@ -0,0 +1,50 @@
+package grpcadapter
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+)
+
+func TestGetCapabilities_MatchesConfig(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{
+		"stream": toolCallStreamProvider{},
+		"plain":  echoEmbeddingsProvider{},
+	}, []llmgateway.ModelSpec{
+		{ID: "stream/agent", Provider: "stream", Capabilities: []string{"chat", "tools"}},
+		{ID: "plain/emb", Provider: "plain", Capabilities: []string{"embeddings"}},
+	}, nil)
+
+	svc := NewLLMGatewayService(app, nil)
+	res, err := svc.GetCapabilities(context.Background(), &llmgatewayv1.GetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetCapabilities: %v", err)
+	}
+	if !res.GetStreaming() || !res.GetToolCalling() || res.GetVision() {
+		t.Fatalf("unexpected feature flags: %+v", res)
+	}
+	if !slices.Contains(res.GetOperations(), "CreateChatCompletionStream") || !slices.IsSorted(res.GetOperations()) {
+		t.Fatalf("expected every method as a sorted operation, got %v", res.GetOperations())
+	}
+	models := res.GetModels()
+	if len(models) != 2 || models[0].GetId() != "plain/emb" || models[1].GetId() != "stream/agent" {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+	if models[0].GetStreaming() || !models[1].GetStreaming() || !slices.Equal(models[1].GetCapabilities(), []string{"chat", "tools"}) {
+		t.Fatalf("unexpected per-model capabilities: %+v", models)
+	}
+
+	disabled := NewLLMGatewayService(app, nil, WithMethodFilter(func(m string) bool { return m != "CreateChatCompletionStream" }))
+	res, err = disabled.GetCapabilities(context.Background(), &llmgatewayv1.GetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetCapabilities: %v", err)
+	}
+	if res.GetStreaming() || slices.Contains(res.GetOperations(), "CreateChatCompletionStream") {
+		t.Fatalf("expected a disabled stream method to turn streaming off, got %+v", res)
+	}
+}
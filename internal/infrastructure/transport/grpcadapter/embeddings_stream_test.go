@@ -0,0 +1,77 @@
+package grpcadapter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"google.golang.org/grpc"
+)
+
+type echoEmbeddingsProvider struct{}
+
+func (echoEmbeddingsProvider) CreateChatCompletion(context.Context, llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	return llm.ChatCompletionResponse{}, nil
+}
+
+func (echoEmbeddingsProvider) CreateEmbeddings(_ context.Context, req llm.EmbeddingsRequest) (llm.EmbeddingsResponse, error) {
+	data := make([]llm.Embedding, 0, len(req.Input))
+	for i := range req.Input {
+		data = append(data, llm.Embedding{Index: uint32(i), Vector: []float32{float32(len(req.Input[i]))}})
+	}
+	return llm.EmbeddingsResponse{ID: "emb-" + req.Input[0], Model: req.Model, Data: data, Usage: llm.EmbeddingsUsage{PromptTokens: uint32(len(req.Input))}}, nil
+}
+
+type fakeEmbeddingsStream struct {
+	grpc.ServerStream
+	sent []*llmgatewayv1.CreateEmbeddingsStreamResponse
+}
+
+func (f *fakeEmbeddingsStream) Context() context.Context { return context.Background() }
+
+func (f *fakeEmbeddingsStream) Send(m *llmgatewayv1.CreateEmbeddingsStreamResponse) error {
+	f.sent = append(f.sent, m)
+	return nil
+}
+
+func TestCreateEmbeddingsStream_EmitsPerBatch(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": echoEmbeddingsProvider{}}, nil, nil)
+	svc := NewLLMGatewayService(app, nil)
+
+	input := make([]string, 10)
+	for i := range input {
+		input[i] = fmt.Sprintf("in-%d", i)
+	}
+	stream := &fakeEmbeddingsStream{}
+	err := svc.CreateEmbeddingsStream(&llmgatewayv1.CreateEmbeddingsStreamRequest{
+		Request:   &llmgatewayv1.CreateEmbeddingsRequest{Model: "fake/emb", Input: input},
+		BatchSize: 4,
+	}, stream)
+	if err != nil {
+		t.Fatalf("CreateEmbeddingsStream error: %v", err)
+	}
+
+	if len(stream.sent) != 3 {
+		t.Fatalf("expected 3 stream messages, got %d", len(stream.sent))
+	}
+	seen := make(map[uint32]bool)
+	for _, m := range stream.sent {
+		for _, e := range m.GetData() {
+			seen[e.GetIndex()] = true
+		}
+		if m.GetTotal() != 10 {
+			t.Fatalf("unexpected total: %d", m.GetTotal())
+		}
+	}
+	if len(seen) != len(input) {
+		t.Fatalf("expected every input index covered once, got %v", seen)
+	}
+	if last := stream.sent[2]; last.GetCompleted() != 10 || last.GetId() != "emb-in-8" || last.GetData()[0].GetIndex() != 8 {
+		t.Fatalf("unexpected last batch: %v", last)
+	}
+}
@@ -3,6 +3,7 @@ package grpcadapter
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -11,27 +12,46 @@ import (
 	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
 	"github.com/poly-workshop/llm-gateway/internal/infrastructure/usagecallback"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type LLMGatewayService struct {
 	llmgatewayv1.UnimplementedLLMGatewayServiceServer
 
-	app      *llmgateway.Service
-	authMgr  *auth.Manager
-	cbSender *usagecallback.Sender
+	app       *llmgateway.Service
+	authMgr   *auth.Manager
+	cbSender  *usagecallback.Sender
+	cbBatcher *usagecallback.Batcher // nil = one POST per event
+
+	usageTrailers bool
+	methodEnabled func(method string) bool // nil = all methods enabled
+}
+
+// Option customizes an LLMGatewayService.
+type Option func(*LLMGatewayService)
+
+// WithUsageCallbackBatcher delivers usage callbacks through b in batches
+// instead of one POST per event.
+func WithUsageCallbackBatcher(b *usagecallback.Batcher) Option {
+	return func(s *LLMGatewayService) { s.cbBatcher = b }
 }
 
-func NewLLMGatewayService(app *llmgateway.Service, authMgr *auth.Manager) *LLMGatewayService {
-	return &LLMGatewayService{
+func NewLLMGatewayService(app *llmgateway.Service, authMgr *auth.Manager, opts ...Option) *LLMGatewayService {
+	s := &LLMGatewayService{
 		app:      app,
 		authMgr:  authMgr,
 		cbSender: usagecallback.New(nil, 3*time.Second),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *LLMGatewayService) IssueTemporaryCredentials(ctx context.Context, _ *llmgatewayv1.IssueTemporaryCredentialsRequest) (*llmgatewayv1.IssueTemporaryCredentialsResponse, error) {
@@ -53,6 +73,9 @@ func (s *LLMGatewayService) IssueTemporaryCredentials(ctx context.Context, _ *ll
 		if errors.Is(err, auth.ErrForbidden) {
 			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
+		if errors.Is(err, auth.ErrRateLimited) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -70,10 +93,11 @@ func (s *LLMGatewayService) SetUsageCallback(ctx context.Context, req *llmgatewa
 		return nil, status.Error(codes.FailedPrecondition, "auth not configured")
 	}
 	// Requirement: ServiceToken only (not signature).
-	if auth.MethodFromContext(ctx) != auth.MethodServiceToken {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if principal.Method != auth.MethodServiceToken {
 		return nil, status.Error(codes.PermissionDenied, "service token required")
 	}
-	subject := auth.SubjectFromContext(ctx)
+	subject := principal.Subject
 	if subject == "" {
 		return nil, status.Error(codes.PermissionDenied, "missing subject")
 	}
@@ -91,18 +115,40 @@ func (s *LLMGatewayService) GetUsageCallback(ctx context.Context, _ *llmgatewayv
 		return nil, status.Error(codes.FailedPrecondition, "auth not configured")
 	}
 	// Requirement: ServiceToken only (not signature).
-	if auth.MethodFromContext(ctx) != auth.MethodServiceToken {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if principal.Method != auth.MethodServiceToken {
 		return nil, status.Error(codes.PermissionDenied, "service token required")
 	}
-	subject := auth.SubjectFromContext(ctx)
+	subject := principal.Subject
 	if subject == "" {
 		return nil, status.Error(codes.PermissionDenied, "missing subject")
 	}
 	return &llmgatewayv1.GetUsageCallbackResponse{Urls: s.authMgr.UsageCallbackAllowlist(subject)}, nil
 }
 
-func (s *LLMGatewayService) ListModels(ctx context.Context, _ *llmgatewayv1.ListModelsRequest) (*llmgatewayv1.ListModelsResponse, error) {
-	models, err := s.app.ListModels(ctx)
+// SetMaintenanceMode toggles maintenance mode; only admin callers may use it.
+func (s *LLMGatewayService) SetMaintenanceMode(ctx context.Context, req *llmgatewayv1.SetMaintenanceModeRequest) (*llmgatewayv1.SetMaintenanceModeResponse, error) {
+	if p, _ := auth.PrincipalFromContext(ctx); !p.HasScope(auth.ScopeAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "admin scope required")
+	}
+	s.app.SetMaintenance(req.GetEnabled(), time.Duration(req.GetRetryAfterSeconds())*time.Second)
+	return &llmgatewayv1.SetMaintenanceModeResponse{Enabled: s.app.InMaintenance()}, nil
+}
+
+// PurgeCache evicts response cache entries; only admin callers may use it.
+func (s *LLMGatewayService) PurgeCache(ctx context.Context, req *llmgatewayv1.PurgeCacheRequest) (*llmgatewayv1.PurgeCacheResponse, error) {
+	if p, _ := auth.PrincipalFromContext(ctx); !p.HasScope(auth.ScopeAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "admin scope required")
+	}
+	n, err := s.app.PurgeCache(llmgateway.CachePurge{All: req.GetAll(), Model: req.GetModel(), KeyPrefix: req.GetKeyPrefix()})
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	return &llmgatewayv1.PurgeCacheResponse{Purged: uint32(n)}, nil
+}
+
+func (s *LLMGatewayService) ListModels(ctx context.Context, req *llmgatewayv1.ListModelsRequest) (*llmgatewayv1.ListModelsResponse, error) {
+	models, nextPageToken, err := s.app.ListModels(ctx, int(req.GetPageSize()), req.GetPageToken())
 	if err != nil {
 		return nil, toStatusErr(err)
 	}
@@ -117,7 +163,7 @@ func (s *LLMGatewayService) ListModels(ctx context.Context, _ *llmgatewayv1.List
 			Capabilities: m.Capabilities,
 		})
 	}
-	return &llmgatewayv1.ListModelsResponse{Data: out}, nil
+	return &llmgatewayv1.ListModelsResponse{Data: out, NextPageToken: nextPageToken}, nil
 }
 
 func (s *LLMGatewayService) GetModel(ctx context.Context, req *llmgatewayv1.GetModelRequest) (*llmgatewayv1.GetModelResponse, error) {
@@ -125,158 +171,643 @@ func (s *LLMGatewayService) GetModel(ctx context.Context, req *llmgatewayv1.GetM
 	if err != nil {
 		return nil, toStatusErr(err)
 	}
-	return &llmgatewayv1.GetModelResponse{
-		Model: &llmgatewayv1.Model{
-			Id:           m.ID,
-			Name:         m.Name,
-			Provider:     m.Provider,
-			Capabilities: m.Capabilities,
-		},
-	}, nil
+	out := &llmgatewayv1.Model{
+		Id:              m.ID,
+		Name:            m.Name,
+		Provider:        m.Provider,
+		Capabilities:    m.Capabilities,
+		ContextWindow:   m.ContextWindow,
+		MaxOutputTokens: m.MaxOutputTokens,
+	}
+	if m.Pricing != nil {
+		out.Pricing = &llmgatewayv1.ModelPricing{
+			InputPerMillionTokens:  m.Pricing.InputPerMillion,
+			OutputPerMillionTokens: m.Pricing.OutputPerMillion,
+		}
+	}
+	return &llmgatewayv1.GetModelResponse{Model: out}, nil
 }
 
 func (s *LLMGatewayService) CreateChatCompletion(ctx context.Context, req *llmgatewayv1.CreateChatCompletionRequest) (*llmgatewayv1.CreateChatCompletionResponse, error) {
+	ctx, cancel, err := requestTimeoutFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	chatReq, err := chatRequestFromProto(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.app.CreateChatCompletion(ctx, chatReq)
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	setProviderRequestID(ctx, res.ProviderRequestID)
+	s.setUsageTrailer(ctx, res.Usage)
+
+	s.maybeSendUsageCallback(ctx, "chat.completions", llm.Generation{
+		ID:             res.ID,
+		Model:          res.Model,
+		Created:        res.Created,
+		Usage:          res.Usage,
+		ConversationID: chatReq.ConversationID,
+	})
+
+	return chatResponseToProto(res), nil
+}
+
+// chatResponseToProto converts a unary chat response.
+func chatResponseToProto(res llm.ChatCompletionResponse) *llmgatewayv1.CreateChatCompletionResponse {
+	choices := make([]*llmgatewayv1.ChatCompletionChoice, 0, len(res.Choices))
+	for _, c := range res.Choices {
+		c := c
+		choices = append(choices, &llmgatewayv1.ChatCompletionChoice{
+			Index: c.Index,
+			Message: &llmgatewayv1.ChatMessage{
+				Role:    c.Message.Role,
+				Content: messageContentValue(c.Message),
+				Name:    c.Message.Name,
+				Audio:   audioContentToProto(c.Message.Audio),
+			},
+			FinishReason: c.FinishReason,
+			RawContent:   c.RawContent,
+		})
+	}
+
+	out := &llmgatewayv1.CreateChatCompletionResponse{
+		Id:      res.ID,
+		Created: res.Created,
+		Model:   res.Model,
+		Choices: choices,
+		Usage:   tokenUsageToProto(res.Usage),
+	}
+	if res.Prompt != nil {
+		msgs := make([]*llmgatewayv1.ChatMessage, 0, len(res.Prompt.Messages))
+		for _, m := range res.Prompt.Messages {
+			msgs = append(msgs, chatMessageToProto(m))
+		}
+		out.Prompt = &llmgatewayv1.PromptEcho{Model: res.Prompt.Model, Messages: msgs}
+	}
+	return out
+}
+
+func chatMessageToProto(m llm.ChatMessage) *llmgatewayv1.ChatMessage {
+	out := &llmgatewayv1.ChatMessage{
+		Role:    m.Role,
+		Content: messageContentValue(m),
+		Name:    m.Name,
+	}
+	if m.CacheControl != nil {
+		out.CacheControl = &llmgatewayv1.CacheControl{Type: m.CacheControl.Type}
+	}
+	return out
+}
+
+// chatRequestFromProto converts a chat request to the domain shape, attaching the caller's subject.
+func chatRequestFromProto(ctx context.Context, req *llmgatewayv1.CreateChatCompletionRequest) (llm.ChatCompletionRequest, error) {
 	msgs := make([]llm.ChatMessage, 0, len(req.GetMessages()))
-	for _, m := range req.GetMessages() {
+	for i, m := range req.GetMessages() {
 		msg := llm.ChatMessage{
 			Role: m.GetRole(),
 			Name: m.GetName(),
 		}
+		if cc := m.GetCacheControl(); cc != nil {
+			msg.CacheControl = &llm.CacheControl{Type: cc.GetType()}
+		}
 		// Parse content field: can be string or array of content parts.
 		if err := parseMessageContent(m.GetContent(), &msg); err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid message content: %v", err)
+			return llm.ChatCompletionRequest{}, status.Errorf(codes.InvalidArgument, "invalid message content: messages[%d].%v", i, err)
 		}
 		msgs = append(msgs, msg)
 	}
 
-	res, err := s.app.CreateChatCompletion(ctx, llm.ChatCompletionRequest{
+	chatReq := llm.ChatCompletionRequest{
 		Model:       req.GetModel(),
 		Messages:    msgs,
-		Temperature: req.GetTemperature(),
-		MaxTokens:   req.GetMaxTokens(),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
 		User:        req.GetUser(),
+		Stop:        req.GetStop(),
+		N:           req.N,
+		Modalities:  req.GetModalities(),
+		ServiceTier: req.GetServiceTier(),
+		Subject:     subjectFromContext(ctx),
+
+		ConversationID: conversationIDFromContext(ctx, req.GetConversationId()),
+		Metadata:       req.GetMetadata(),
+
+		IncludeReasoning: req.GetIncludeReasoning(),
+	}
+	for _, inc := range req.GetInclude() {
+		switch inc {
+		case "prompt":
+			// Prompts can hold other users' data; only operators may see them echoed.
+			p, _ := auth.PrincipalFromContext(ctx)
+			chatReq.EchoPrompt = p.HasScope(auth.ScopeAdmin)
+		default:
+			return llm.ChatCompletionRequest{}, status.Errorf(codes.InvalidArgument, "unsupported include value: %q", inc)
+		}
+	}
+	if a := req.GetAudio(); a != nil {
+		chatReq.Audio = &llm.AudioOutput{Voice: a.GetVoice(), Format: a.GetFormat()}
+	}
+	if pp := req.GetProvider(); pp != nil {
+		chatReq.ProviderPreferences = &llm.ProviderPreferences{
+			Order:             pp.GetOrder(),
+			AllowFallbacks:    pp.AllowFallbacks,
+			RequireParameters: pp.GetRequireParameters(),
+			DataCollection:    pp.GetDataCollection(),
+		}
+	}
+	return chatReq, nil
+}
+
+func (s *LLMGatewayService) CompareCompletions(ctx context.Context, req *llmgatewayv1.CompareCompletionsRequest) (*llmgatewayv1.CompareCompletionsResponse, error) {
+	ctx, cancel, err := requestTimeoutFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	chatReq, err := chatRequestFromProto(ctx, &llmgatewayv1.CreateChatCompletionRequest{
+		Messages:       req.GetMessages(),
+		Temperature:    req.Temperature,
+		MaxTokens:      req.MaxTokens,
+		User:           req.GetUser(),
+		ConversationId: req.GetConversationId(),
 	})
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.app.CompareCompletions(ctx, chatReq, req.GetModels())
 	if err != nil {
 		return nil, toStatusErr(err)
 	}
 
-	s.maybeSendUsageCallback(ctx, "chat.completions", llm.Generation{
-		ID:      res.ID,
-		Model:   res.Model,
-		Created: res.Created,
-		Usage:   res.Usage,
+	out := make([]*llmgatewayv1.CompareCompletionsResult, 0, len(results))
+	for _, r := range results {
+		s.maybeSendUsageCallback(ctx, "chat.completions", llm.Generation{
+			ID:             r.Response.ID,
+			Model:          r.Response.Model,
+			Created:        r.Response.Created,
+			Usage:          r.Response.Usage,
+			ConversationID: chatReq.ConversationID,
+		})
+		out = append(out, &llmgatewayv1.CompareCompletionsResult{Model: r.Model, Response: chatResponseToProto(r.Response)})
+	}
+	return &llmgatewayv1.CompareCompletionsResponse{Results: out}, nil
+}
+
+func (s *LLMGatewayService) CreateChatCompletionStream(req *llmgatewayv1.CreateChatCompletionStreamRequest, stream grpc.ServerStreamingServer[llmgatewayv1.CreateChatCompletionStreamResponse]) error {
+	ctx := stream.Context()
+	chatReq, err := chatRequestFromProto(ctx, req.GetRequest())
+	if err != nil {
+		return err
+	}
+
+	gen := llm.Generation{ConversationID: chatReq.ConversationID}
+	// Tool call fragments per choice index, when assembly was requested.
+	var assemblers map[uint32]*toolCallAssembler
+	if req.GetAssembleToolCalls() {
+		assemblers = make(map[uint32]*toolCallAssembler)
+	}
+	err = s.app.CreateChatCompletionStream(ctx, chatReq, func(c llm.ChatCompletionChunk) error {
+		if gen.ID == "" {
+			gen.ID, gen.Model, gen.Created = c.ID, c.Model, c.Created
+		}
+		if c.Usage != nil {
+			gen.Usage = *c.Usage
+		}
+		choices := make([]*llmgatewayv1.CreateChatCompletionStreamChoice, 0, len(c.Choices))
+		for _, ch := range c.Choices {
+			choice := &llmgatewayv1.CreateChatCompletionStreamChoice{
+				Index: ch.Index,
+				Delta: &llmgatewayv1.ChatCompletionDelta{
+					Role:      ch.Delta.Role,
+					Content:   ch.Delta.Content,
+					ToolCalls: toolCallsToProto(ch.Delta.ToolCalls),
+					Reasoning: ch.Delta.Reasoning,
+				},
+				FinishReason: ch.FinishReason,
+			}
+			if assemblers != nil {
+				a := assemblers[ch.Index]
+				if a == nil {
+					a = &toolCallAssembler{}
+					assemblers[ch.Index] = a
+				}
+				a.add(ch.Delta.ToolCalls)
+				if ch.FinishReason != "" {
+					choice.ToolCalls = toolCallsToProto(a.toolCalls())
+				}
+			}
+			choices = append(choices, choice)
+		}
+		if len(choices) == 0 {
+			// Usage-only trailer chunk; nothing to forward in the minimal stream shape.
+			return nil
+		}
+		return stream.Send(&llmgatewayv1.CreateChatCompletionStreamResponse{
+			Id:      c.ID,
+			Created: c.Created,
+			Model:   c.Model,
+			Choices: choices,
+		})
 	})
+	if err != nil {
+		// A client that hung up mid-stream still pays for the tokens the
+		// provider already reported.
+		if errors.Is(ctx.Err(), context.Canceled) && gen.ID != "" && gen.Usage != (llm.TokenUsage{}) {
+			gen.Status = llm.GenerationStatusClientCancelled
+			s.maybeSendUsageCallback(ctx, "chat.completions", gen)
+		}
+		return toStatusErr(err)
+	}
 
-	choices := make([]*llmgatewayv1.ChatCompletionChoice, 0, len(res.Choices))
+	if s.usageTrailers {
+		stream.SetTrailer(usageTrailer(gen.Usage))
+	}
+	s.maybeSendUsageCallback(ctx, "chat.completions", gen)
+	return nil
+}
+
+func (s *LLMGatewayService) CreateCompletion(ctx context.Context, req *llmgatewayv1.CreateCompletionRequest) (*llmgatewayv1.CreateCompletionResponse, error) {
+	ctx, cancel, err := requestTimeoutFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	complReq := llm.CompletionRequest{
+		Model:          req.GetModel(),
+		Prompt:         req.GetPrompt(),
+		MaxTokens:      req.MaxTokens,
+		Stop:           req.GetStop(),
+		N:              req.N,
+		User:           req.GetUser(),
+		Subject:        subjectFromContext(ctx),
+		ConversationID: conversationIDFromContext(ctx, req.GetConversationId()),
+	}
+	if req.Temperature != nil {
+		t := req.GetTemperature()
+		complReq.Temperature = &t
+	}
+	res, err := s.app.CreateCompletion(ctx, complReq)
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	setProviderRequestID(ctx, res.ProviderRequestID)
+	s.setUsageTrailer(ctx, res.Usage)
+
+	s.maybeSendUsageCallback(ctx, "completions", llm.Generation{
+		ID:             res.ID,
+		Model:          res.Model,
+		Created:        res.Created,
+		Usage:          res.Usage,
+		ConversationID: complReq.ConversationID,
+	})
+
+	choices := make([]*llmgatewayv1.CompletionChoice, 0, len(res.Choices))
 	for _, c := range res.Choices {
-		c := c
-		choices = append(choices, &llmgatewayv1.ChatCompletionChoice{
-			Index: c.Index,
-			Message: &llmgatewayv1.ChatMessage{
-				Role:    c.Message.Role,
-				Content: structpb.NewStringValue(c.Message.Content),
-				Name:    c.Message.Name,
-			},
+		choices = append(choices, &llmgatewayv1.CompletionChoice{
+			Index:        c.Index,
+			Text:         c.Text,
 			FinishReason: c.FinishReason,
 		})
 	}
-
-	return &llmgatewayv1.CreateChatCompletionResponse{
+	return &llmgatewayv1.CreateCompletionResponse{
 		Id:      res.ID,
 		Created: res.Created,
 		Model:   res.Model,
 		Choices: choices,
-		Usage: &llmgatewayv1.TokenUsage{
-			PromptTokens:     res.Usage.PromptTokens,
-			CompletionTokens: res.Usage.CompletionTokens,
-			TotalTokens:      res.Usage.TotalTokens,
-		},
+		Usage:   tokenUsageToProto(res.Usage),
 	}, nil
 }
 
-func (s *LLMGatewayService) CreateChatCompletionStream(*llmgatewayv1.CreateChatCompletionStreamRequest, grpc.ServerStreamingServer[llmgatewayv1.CreateChatCompletionStreamResponse]) error {
-	return status.Error(codes.Unimplemented, "not implemented yet")
-}
-
 func (s *LLMGatewayService) CreateEmbeddings(ctx context.Context, req *llmgatewayv1.CreateEmbeddingsRequest) (*llmgatewayv1.CreateEmbeddingsResponse, error) {
+	ctx, cancel, err := requestTimeoutFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	conversationID := conversationIDFromContext(ctx, "")
 	res, err := s.app.CreateEmbeddings(ctx, llm.EmbeddingsRequest{
-		Model: req.GetModel(),
-		Input: req.GetInput(),
-		User:  req.GetUser(),
+		Model:          req.GetModel(),
+		Input:          req.GetInput(),
+		User:           req.GetUser(),
+		AutoChunk:      req.GetAutoChunk(),
+		Subject:        subjectFromContext(ctx),
+		ConversationID: conversationID,
 	})
 	if err != nil {
 		return nil, toStatusErr(err)
 	}
+	setProviderRequestID(ctx, res.ProviderRequestID)
 
 	s.maybeSendUsageCallback(ctx, "embeddings", llm.Generation{
 		ID:      res.ID,
 		Model:   res.Model,
-		Created: 0,
+		Created: res.Created,
 		Usage: llm.TokenUsage{
 			PromptTokens:     res.Usage.PromptTokens,
 			CompletionTokens: 0,
 			TotalTokens:      res.Usage.TotalTokens,
 		},
+		ConversationID: conversationID,
 	})
 
+	return embeddingsResponseToProto(res), nil
+}
+
+func (s *LLMGatewayService) CreateEmbeddingsGroup(ctx context.Context, req *llmgatewayv1.CreateEmbeddingsGroupRequest) (*llmgatewayv1.CreateEmbeddingsGroupResponse, error) {
+	ctx, cancel, err := requestTimeoutFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	conversationID := conversationIDFromContext(ctx, "")
+	results, err := s.app.CreateEmbeddingsGroup(ctx, llm.EmbeddingsRequest{
+		Input:          req.GetInput(),
+		User:           req.GetUser(),
+		Subject:        subjectFromContext(ctx),
+		ConversationID: conversationID,
+	}, req.GetModels())
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+
+	out := make(map[string]*llmgatewayv1.CreateEmbeddingsResponse, len(results))
+	for model, res := range results {
+		s.maybeSendUsageCallback(ctx, "embeddings", llm.Generation{
+			ID:    res.ID,
+			Model: res.Model,
+			Usage: llm.TokenUsage{
+				PromptTokens: res.Usage.PromptTokens,
+				TotalTokens:  res.Usage.TotalTokens,
+			},
+			ConversationID: conversationID,
+		})
+		out[model] = embeddingsResponseToProto(res)
+	}
+	return &llmgatewayv1.CreateEmbeddingsGroupResponse{Results: out}, nil
+}
+
+func embeddingsResponseToProto(res llm.EmbeddingsResponse) *llmgatewayv1.CreateEmbeddingsResponse {
 	data := make([]*llmgatewayv1.Embedding, 0, len(res.Data))
 	for _, e := range res.Data {
-		e := e
 		data = append(data, &llmgatewayv1.Embedding{
-			Index:     e.Index,
-			Embedding: e.Vector,
+			Index:      e.Index,
+			Embedding:  e.Vector,
+			ChunkCount: e.ChunkCount,
 		})
 	}
-
 	return &llmgatewayv1.CreateEmbeddingsResponse{
-		Id:    res.ID,
-		Model: res.Model,
-		Data:  data,
+		Id:      res.ID,
+		Model:   res.Model,
+		Created: res.Created,
+		Data:    data,
 		Usage: &llmgatewayv1.EmbeddingsUsage{
 			PromptTokens: res.Usage.PromptTokens,
 			TotalTokens:  res.Usage.TotalTokens,
 		},
-	}, nil
+	}
+}
+
+func (s *LLMGatewayService) CreateEmbeddingsStream(req *llmgatewayv1.CreateEmbeddingsStreamRequest, stream grpc.ServerStreamingServer[llmgatewayv1.CreateEmbeddingsStreamResponse]) error {
+	ctx := stream.Context()
+	in := req.GetRequest()
+	total := uint32(len(in.GetInput()))
+	var completed uint32
+	conversationID := conversationIDFromContext(ctx, "")
+	err := s.app.CreateEmbeddingsStream(ctx, llm.EmbeddingsRequest{
+		Model:          in.GetModel(),
+		Input:          in.GetInput(),
+		User:           in.GetUser(),
+		Subject:        subjectFromContext(ctx),
+		ConversationID: conversationID,
+	}, int(req.GetBatchSize()), func(res llm.EmbeddingsResponse) error {
+		s.maybeSendUsageCallback(ctx, "embeddings", llm.Generation{
+			ID:    res.ID,
+			Model: res.Model,
+			Usage: llm.TokenUsage{
+				PromptTokens: res.Usage.PromptTokens,
+				TotalTokens:  res.Usage.TotalTokens,
+			},
+			ConversationID: conversationID,
+		})
+
+		data := make([]*llmgatewayv1.Embedding, 0, len(res.Data))
+		for _, e := range res.Data {
+			data = append(data, &llmgatewayv1.Embedding{Index: e.Index, Embedding: e.Vector})
+		}
+		completed += uint32(len(res.Data))
+		return stream.Send(&llmgatewayv1.CreateEmbeddingsStreamResponse{
+			Id:    res.ID,
+			Model: res.Model,
+			Data:  data,
+			Usage: &llmgatewayv1.EmbeddingsUsage{
+				PromptTokens: res.Usage.PromptTokens,
+				TotalTokens:  res.Usage.TotalTokens,
+			},
+			Completed: completed,
+			Total:     total,
+			Created:   res.Created,
+		})
+	})
+	return toStatusErr(err)
 }
 
 func (s *LLMGatewayService) GetGeneration(ctx context.Context, req *llmgatewayv1.GetGenerationRequest) (*llmgatewayv1.GetGenerationResponse, error) {
-	gen, err := s.app.GetGeneration(ctx, req.GetId())
+	gen, err := s.app.GetGeneration(ctx, subjectFromContext(ctx), req.GetId())
 	if err != nil {
 		return nil, toStatusErr(err)
 	}
 
 	return &llmgatewayv1.GetGenerationResponse{
-		Generation: &llmgatewayv1.Generation{
-			Id:      gen.ID,
-			Model:   gen.Model,
-			Created: gen.Created,
-			Usage: &llmgatewayv1.TokenUsage{
-				PromptTokens:     gen.Usage.PromptTokens,
-				CompletionTokens: gen.Usage.CompletionTokens,
-				TotalTokens:      gen.Usage.TotalTokens,
-			},
-		},
+		Generation: generationToProto(gen, req.GetIncludeMetadata()),
 	}, nil
 }
 
+func (s *LLMGatewayService) ListGenerationsByConversation(ctx context.Context, req *llmgatewayv1.ListGenerationsByConversationRequest) (*llmgatewayv1.ListGenerationsByConversationResponse, error) {
+	gens, err := s.app.ListGenerationsByConversation(ctx, subjectFromContext(ctx), req.GetConversationId())
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+
+	out := make([]*llmgatewayv1.Generation, 0, len(gens))
+	for _, gen := range gens {
+		out = append(out, generationToProto(gen, false))
+	}
+	return &llmgatewayv1.ListGenerationsByConversationResponse{Generations: out}, nil
+}
+
+// generationToProto converts a stored generation; request metadata is only
+// included when asked for.
+func generationToProto(gen llm.Generation, includeMetadata bool) *llmgatewayv1.Generation {
+	out := &llmgatewayv1.Generation{
+		Id:             gen.ID,
+		Model:          gen.Model,
+		Created:        gen.Created,
+		Usage:          tokenUsageToProto(gen.Usage),
+		ConversationId: gen.ConversationID,
+		Status:         gen.Status,
+	}
+	if includeMetadata {
+		out.Provider = gen.Provider
+		out.UpstreamModel = gen.UpstreamModel
+		out.Subject = gen.Subject
+		out.FinishReason = gen.FinishReason
+		out.LatencyMs = gen.Latency.Milliseconds()
+		out.ProviderRequestId = gen.ProviderRequestID
+		out.UpstreamId = gen.UpstreamID
+	}
+	return out
+}
+
+func tokenUsageToProto(u llm.TokenUsage) *llmgatewayv1.TokenUsage {
+	return &llmgatewayv1.TokenUsage{
+		PromptTokens:          u.PromptTokens,
+		CompletionTokens:      u.CompletionTokens,
+		TotalTokens:           u.TotalTokens,
+		CacheReadTokens:       u.CacheReadTokens,
+		CacheWriteTokens:      u.CacheWriteTokens,
+		ReasoningTokens:       u.ReasoningTokens,
+		PromptAudioTokens:     u.PromptAudioTokens,
+		CompletionAudioTokens: u.CompletionAudioTokens,
+	}
+}
+
+// subjectFromContext is the authenticated subject passed to the application
+// layer, or "" when auth is disabled.
+func subjectFromContext(ctx context.Context) string {
+	p, _ := auth.PrincipalFromContext(ctx)
+	return p.Subject
+}
+
+// conversationIDFromContext returns the request's conversation ID, falling back
+// to the x-conversation-id metadata header (the only way to set it on embeddings).
+func conversationIDFromContext(ctx context.Context, fromRequest string) string {
+	if fromRequest != "" {
+		return fromRequest
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("x-conversation-id"); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// providerRequestIDHeader carries the upstream provider's request id on unary
+// responses, for support escalations.
+const providerRequestIDHeader = "x-provider-request-id"
+
+// setProviderRequestID sends id as the providerRequestIDHeader response header
+// when the provider returned one.
+func setProviderRequestID(ctx context.Context, id string) {
+	if id == "" {
+		return
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(providerRequestIDHeader, id)) // Best effort.
+}
+
+// requestTimeoutFromContext applies the optional x-request-timeout header (a Go
+// duration such as "30s") as a deadline. It can only shorten the server's
+// configured request timeout, which the application layer applies on top.
+func requestTimeoutFromContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get("x-request-timeout")
+	if len(v) == 0 {
+		return ctx, func() {}, nil
+	}
+	d, err := time.ParseDuration(v[0])
+	if err != nil || d <= 0 {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid x-request-timeout %q: must be a positive duration such as \"30s\"", v[0])
+	}
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return ctx, cancel, nil
+}
+
 func toStatusErr(err error) error {
 	if err == nil {
 		return nil
 	}
+	if _, ok := status.FromError(err); ok {
+		return err // already a gRPC status, e.g. from stream.Send
+	}
+	var verr *llm.ValidationError
+	if errors.As(err, &verr) {
+		return validationStatusErr(verr)
+	}
 	if errors.Is(err, llm.ErrInvalidArgument) {
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
+	if errors.Is(err, llm.ErrPermissionDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, llm.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, llm.ErrEmptyResponse) || errors.Is(err, llm.ErrStreamIdle) || errors.Is(err, llm.ErrResponseTooLarge) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	var merr *llm.MaintenanceError
+	if errors.As(err, &merr) {
+		return maintenanceStatusErr(merr)
+	}
+	var rerr *llm.RateLimitError
+	if errors.As(err, &rerr) {
+		return retryableStatusErr(codes.ResourceExhausted, rerr.Error(), rerr.RetryAfter)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
 	return status.Error(codes.Internal, err.Error())
 }
 
+// maintenanceStatusErr maps a MaintenanceError to Unavailable with a
+// google.rpc.RetryInfo detail when a retry delay is known.
+func maintenanceStatusErr(merr *llm.MaintenanceError) error {
+	return retryableStatusErr(codes.Unavailable, merr.Error(), merr.RetryAfter)
+}
+
+// retryableStatusErr builds a status with a google.rpc.RetryInfo detail when
+// retryAfter is known.
+func retryableStatusErr(code codes.Code, msg string, retryAfter time.Duration) error {
+	st := status.New(code, msg)
+	if retryAfter <= 0 {
+		return st.Err()
+	}
+	if withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// validationStatusErr maps a ValidationError to InvalidArgument with a
+// google.rpc.BadRequest detail listing every field violation.
+func validationStatusErr(verr *llm.ValidationError) error {
+	st := status.New(codes.InvalidArgument, verr.Error())
+	br := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	withDetails, err := st.WithDetails(br)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
 func (s *LLMGatewayService) maybeSendUsageCallback(ctx context.Context, op string, gen llm.Generation) {
 	if s == nil || s.authMgr == nil || s.cbSender == nil {
 		return
 	}
-	subject := auth.SubjectFromContext(ctx)
-	if subject == "" {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
 		return
 	}
+	subject := principal.Subject
 
 	// Caller provides callback URL per request. Only allow if it is in the trusted allowlist.
 	md, _ := metadata.FromIncomingContext(ctx)
@@ -298,19 +829,31 @@ func (s *LLMGatewayService) maybeSendUsageCallback(ctx context.Context, op strin
 	}
 
 	payload := usagecallback.Payload{
-		Event:            "llm.usage",
-		Subject:          subject,
-		RequestID:        requestID,
-		Operation:        op,
-		GenerationID:     gen.ID,
-		Model:            gen.Model,
-		CreatedUnix:      gen.Created,
-		PromptTokens:     gen.Usage.PromptTokens,
-		CompletionTokens: gen.Usage.CompletionTokens,
-		TotalTokens:      gen.Usage.TotalTokens,
-		OccurredAtUnix:   time.Now().Unix(),
+		Event:                 "llm.usage",
+		Subject:               subject,
+		RequestID:             requestID,
+		Operation:             op,
+		GenerationID:          gen.ID,
+		Model:                 gen.Model,
+		CreatedUnix:           gen.Created,
+		PromptTokens:          gen.Usage.PromptTokens,
+		CompletionTokens:      gen.Usage.CompletionTokens,
+		TotalTokens:           gen.Usage.TotalTokens,
+		CacheReadTokens:       gen.Usage.CacheReadTokens,
+		CacheWriteTokens:      gen.Usage.CacheWriteTokens,
+		ReasoningTokens:       gen.Usage.ReasoningTokens,
+		PromptAudioTokens:     gen.Usage.PromptAudioTokens,
+		CompletionAudioTokens: gen.Usage.CompletionAudioTokens,
+		ConversationID:        gen.ConversationID,
+		OccurredAtUnix:        time.Now().Unix(),
+		Partial:               gen.Status != "",
+		Status:                gen.Status,
+	}
+
+	if s.cbBatcher != nil {
+		s.cbBatcher.Add(cbURL, payload)
+		return
 	}
-
 	go func() {
 		// Avoid tying callback to request cancellation.
 		if err := s.cbSender.Send(context.Background(), cbURL, payload); err != nil {
@@ -319,7 +862,46 @@ func (s *LLMGatewayService) maybeSendUsageCallback(ctx context.Context, op strin
 	}()
 }
 
+// audioContentToProto converts audio output; nil stays nil.
+func audioContentToProto(a *llm.AudioContent) *llmgatewayv1.AudioContent {
+	if a == nil {
+		return nil
+	}
+	return &llmgatewayv1.AudioContent{
+		Id:         a.ID,
+		Data:       a.Data,
+		Format:     a.Format,
+		Transcript: a.Transcript,
+		ExpiresAt:  a.ExpiresAt,
+	}
+}
+
+// messageContentValue renders message content as a string, or as an array of
+// content parts for multimodal messages.
+func messageContentValue(msg llm.ChatMessage) *structpb.Value {
+	if len(msg.ContentParts) == 0 {
+		return structpb.NewStringValue(msg.Content)
+	}
+	parts := make([]*structpb.Value, 0, len(msg.ContentParts))
+	for _, cp := range msg.ContentParts {
+		fields := map[string]*structpb.Value{"type": structpb.NewStringValue(cp.Type)}
+		switch {
+		case cp.ImageURL != nil:
+			fields["image_url"] = structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+				"url":    structpb.NewStringValue(cp.ImageURL.URL),
+				"detail": structpb.NewStringValue(cp.ImageURL.Detail),
+			}})
+		default:
+			fields["text"] = structpb.NewStringValue(cp.Text)
+		}
+		parts = append(parts, structpb.NewStructValue(&structpb.Struct{Fields: fields}))
+	}
+	return structpb.NewListValue(&structpb.ListValue{Values: parts})
+}
+
 // parseMessageContent parses the content field which can be a string or an array of content parts.
+// Errors start with the path of the offending field relative to the message,
+// e.g. "content[0].image_url.url must be a string".
 func parseMessageContent(content *structpb.Value, msg *llm.ChatMessage) error {
 	if content == nil {
 		return nil
@@ -335,8 +917,8 @@ func parseMessageContent(content *structpb.Value, msg *llm.ChatMessage) error {
 			return nil
 		}
 		msg.ContentParts = make([]llm.ContentPart, 0, len(v.ListValue.Values))
-		for _, item := range v.ListValue.Values {
-			part, err := parseContentPart(item)
+		for i, item := range v.ListValue.Values {
+			part, err := parseContentPart(item, fmt.Sprintf("content[%d]", i))
 			if err != nil {
 				return err
 			}
@@ -350,48 +932,83 @@ func parseMessageContent(content *structpb.Value, msg *llm.ChatMessage) error {
 	return nil
 }
 
-// parseContentPart parses a single content part from a structpb.Value.
-func parseContentPart(v *structpb.Value) (llm.ContentPart, error) {
-	obj, ok := v.Kind.(*structpb.Value_StructValue)
+// parseContentPart parses a single content part from a structpb.Value; path
+// names the part in errors.
+func parseContentPart(v *structpb.Value, path string) (llm.ContentPart, error) {
+	obj, ok := v.GetKind().(*structpb.Value_StructValue)
 	if !ok || obj.StructValue == nil {
-		return llm.ContentPart{}, errors.New("content part must be an object")
+		return llm.ContentPart{}, fmt.Errorf("%s must be an object", path)
 	}
 
 	fields := obj.StructValue.Fields
 	part := llm.ContentPart{}
+	var err error
 
-	// Parse type field.
-	if typeVal, ok := fields["type"]; ok {
-		if s, ok := typeVal.Kind.(*structpb.Value_StringValue); ok {
-			part.Type = s.StringValue
-		}
+	if part.Type, err = stringField(fields, "type", path); err != nil {
+		return llm.ContentPart{}, err
 	}
-
 	// Parse text field (for type="text").
-	if textVal, ok := fields["text"]; ok {
-		if s, ok := textVal.Kind.(*structpb.Value_StringValue); ok {
-			part.Text = s.StringValue
-		}
+	if part.Text, err = stringField(fields, "text", path); err != nil {
+		return llm.ContentPart{}, err
 	}
 
 	// Parse image_url field (for type="image_url").
-	if imgVal, ok := fields["image_url"]; ok {
-		if imgObj, ok := imgVal.Kind.(*structpb.Value_StructValue); ok && imgObj.StructValue != nil {
-			imgFields := imgObj.StructValue.Fields
-			imgURL := &llm.ImageURL{}
-			if urlVal, ok := imgFields["url"]; ok {
-				if s, ok := urlVal.Kind.(*structpb.Value_StringValue); ok {
-					imgURL.URL = s.StringValue
-				}
-			}
-			if detailVal, ok := imgFields["detail"]; ok {
-				if s, ok := detailVal.Kind.(*structpb.Value_StringValue); ok {
-					imgURL.Detail = s.StringValue
-				}
-			}
-			part.ImageURL = imgURL
+	imgFields, err := objectField(fields, "image_url", path)
+	if err != nil {
+		return llm.ContentPart{}, err
+	}
+	if imgFields != nil {
+		imgURL := &llm.ImageURL{}
+		if imgURL.URL, err = stringField(imgFields, "url", path+".image_url"); err != nil {
+			return llm.ContentPart{}, err
+		}
+		if imgURL.Detail, err = stringField(imgFields, "detail", path+".image_url"); err != nil {
+			return llm.ContentPart{}, err
 		}
+		part.ImageURL = imgURL
+	}
+
+	// Parse cache_control field (prompt caching breakpoint).
+	ccFields, err := objectField(fields, "cache_control", path)
+	if err != nil {
+		return llm.ContentPart{}, err
+	}
+	if ccFields != nil {
+		cc := &llm.CacheControl{}
+		if cc.Type, err = stringField(ccFields, "type", path+".cache_control"); err != nil {
+			return llm.ContentPart{}, err
+		}
+		part.CacheControl = cc
 	}
 
 	return part, nil
 }
+
+// stringField returns fields[key] as a string; a missing or null field is "".
+// parent is the path of the object holding fields, for errors.
+func stringField(fields map[string]*structpb.Value, key, parent string) (string, error) {
+	switch v := fields[key].GetKind().(type) {
+	case nil, *structpb.Value_NullValue:
+		return "", nil
+	case *structpb.Value_StringValue:
+		return v.StringValue, nil
+	default:
+		return "", fmt.Errorf("%s.%s must be a string", parent, key)
+	}
+}
+
+// objectField returns the fields of the object fields[key]; a missing or
+// null field is nil. parent is the path of the object holding fields, for errors.
+func objectField(fields map[string]*structpb.Value, key, parent string) (map[string]*structpb.Value, error) {
+	switch v := fields[key].GetKind().(type) {
+	case nil, *structpb.Value_NullValue:
+		return nil, nil
+	case *structpb.Value_StructValue:
+		if v.StructValue == nil {
+			return nil, nil
+		}
+		return v.StructValue.Fields, nil
+	default:
+		return nil, fmt.Errorf("%s.%s must be an object", parent, key)
+	}
+}
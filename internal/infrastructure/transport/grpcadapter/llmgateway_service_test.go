@@ -0,0 +1,256 @@
+package grpcadapter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestToStatusErr_ValidationErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	var v llm.Violations
+	v.Add("model", "is required")
+	v.Add("messages", "is required")
+
+	st, ok := status.FromError(toStatusErr(v.Err()))
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("unexpected status: %v", st)
+	}
+	var br *errdetails.BadRequest
+	for _, d := range st.Details() {
+		if b, ok := d.(*errdetails.BadRequest); ok {
+			br = b
+		}
+	}
+	if br == nil || len(br.GetFieldViolations()) != 2 {
+		t.Fatalf("expected 2 field violations, got %+v", st.Details())
+	}
+	if br.GetFieldViolations()[0].GetField() != "model" || br.GetFieldViolations()[1].GetField() != "messages" {
+		t.Fatalf("unexpected violations: %+v", br.GetFieldViolations())
+	}
+}
+
+func TestGenerationToProto_MetadataOnlyWhenRequested(t *testing.T) {
+	t.Parallel()
+
+	gen := llm.Generation{
+		ID:            "gen-1",
+		Model:         "alias",
+		Subject:       "svc:a",
+		Provider:      "openrouter",
+		UpstreamModel: "openai/gpt-4o",
+		FinishReason:  "stop",
+		Latency:       1500 * time.Millisecond,
+
+		ProviderRequestID: "req-1",
+	}
+
+	plain := generationToProto(gen, false)
+	if plain.GetId() != "gen-1" || plain.GetProvider() != "" || plain.GetSubject() != "" || plain.GetLatencyMs() != 0 || plain.GetProviderRequestId() != "" {
+		t.Fatalf("metadata leaked without include_metadata: %+v", plain)
+	}
+	full := generationToProto(gen, true)
+	if full.GetProvider() != "openrouter" || full.GetUpstreamModel() != "openai/gpt-4o" || full.GetSubject() != "svc:a" ||
+		full.GetFinishReason() != "stop" || full.GetLatencyMs() != 1500 || full.GetProviderRequestId() != "req-1" {
+		t.Fatalf("unexpected metadata: %+v", full)
+	}
+}
+
+func TestCreateChatCompletion_IncludePromptRequiresAdmin(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": echoEmbeddingsProvider{}}, nil, nil)
+	svc := NewLLMGatewayService(app, nil)
+	req := &llmgatewayv1.CreateChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: structpb.NewStringValue("secret prompt")}},
+		Include:  []string{"prompt"},
+	}
+
+	admin := auth.WithPrincipal(context.Background(), auth.RequestPrincipal{Subject: "ops", Scopes: []string{auth.ScopeAdmin}})
+	res, err := svc.CreateChatCompletion(admin, req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	p := res.GetPrompt()
+	if p.GetModel() != "m" || len(p.GetMessages()) != 1 || p.GetMessages()[0].GetContent().GetStringValue() != "secret prompt" {
+		t.Fatalf("unexpected prompt echo: %+v", p)
+	}
+
+	for name, ctx := range map[string]context.Context{
+		"no scope":        auth.WithPrincipal(context.Background(), auth.RequestPrincipal{Subject: "billing"}),
+		"unauthenticated": context.Background(),
+	} {
+		res, err := svc.CreateChatCompletion(ctx, req)
+		if err != nil {
+			t.Fatalf("%s: CreateChatCompletion: %v", name, err)
+		}
+		if res.GetPrompt() != nil {
+			t.Fatalf("%s: prompt echoed without admin scope: %+v", name, res.GetPrompt())
+		}
+	}
+
+	req.Include = []string{"everything"}
+	if _, err := svc.CreateChatCompletion(admin, req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for unknown include, got %v", err)
+	}
+}
+
+func TestRequestTimeoutFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel, err := requestTimeoutFromContext(context.Background())
+	if err != nil {
+		t.Fatalf("no header: %v", err)
+	}
+	cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("expected no deadline without the header")
+	}
+
+	in := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-timeout", "2s"))
+	ctx, cancel, err = requestTimeoutFromContext(in)
+	if err != nil {
+		t.Fatalf("valid header: %v", err)
+	}
+	defer cancel()
+	if dl, ok := ctx.Deadline(); !ok || time.Until(dl) > 2*time.Second {
+		t.Fatalf("expected a deadline within 2s, got %v (ok=%v)", dl, ok)
+	}
+
+	in = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-timeout", "soon"))
+	if _, _, err := requestTimeoutFromContext(in); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a malformed header, got %v", err)
+	}
+
+	if code := status.Code(toStatusErr(context.DeadlineExceeded)); code != codes.DeadlineExceeded {
+		t.Fatalf("deadline exceeded mapped to %v", code)
+	}
+}
+
+func TestToStatusErr_UpstreamFailuresAreUnavailable(t *testing.T) {
+	t.Parallel()
+
+	for _, err := range []error{
+		llm.ErrStreamIdle,
+		fmt.Errorf("read response: %w", fmt.Errorf("%w: exceeds 10 bytes", llm.ErrResponseTooLarge)),
+	} {
+		if code := status.Code(toStatusErr(err)); code != codes.Unavailable {
+			t.Fatalf("%v mapped to %v, want Unavailable", err, code)
+		}
+	}
+}
+
+func TestMaintenanceMode_RejectsCompletionsWithRetryInfo(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": echoEmbeddingsProvider{}}, nil, nil)
+	svc := NewLLMGatewayService(app, nil)
+	admin := auth.WithPrincipal(context.Background(), auth.RequestPrincipal{Subject: "ops", Scopes: []string{auth.ScopeAdmin}})
+	req := &llmgatewayv1.CreateChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: structpb.NewStringValue("hi")}},
+	}
+
+	if _, err := svc.SetMaintenanceMode(context.Background(), &llmgatewayv1.SetMaintenanceModeRequest{Enabled: true}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-admin, got %v", err)
+	}
+	res, err := svc.SetMaintenanceMode(admin, &llmgatewayv1.SetMaintenanceModeRequest{Enabled: true, RetryAfterSeconds: 60})
+	if err != nil || !res.GetEnabled() {
+		t.Fatalf("enable maintenance: %v, %v", res, err)
+	}
+
+	_, err = svc.CreateChatCompletion(context.Background(), req)
+	st, _ := status.FromError(err)
+	if st.Code() != codes.Unavailable {
+		t.Fatalf("expected Unavailable during maintenance, got %v", err)
+	}
+	var delay time.Duration
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			delay = ri.GetRetryDelay().AsDuration()
+		}
+	}
+	if delay != time.Minute {
+		t.Fatalf("expected a 60s RetryInfo hint, got %v", delay)
+	}
+
+	if _, err := svc.SetMaintenanceMode(admin, &llmgatewayv1.SetMaintenanceModeRequest{Enabled: false}); err != nil {
+		t.Fatalf("disable maintenance: %v", err)
+	}
+	if _, err := svc.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("expected completions restored, got %v", err)
+	}
+}
+
+func TestPurgeCache_RequiresAdmin(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": echoEmbeddingsProvider{}}, nil, nil, llmgateway.WithEmbeddingsCache(8, time.Minute))
+	svc := NewLLMGatewayService(app, nil)
+	admin := auth.WithPrincipal(context.Background(), auth.RequestPrincipal{Subject: "ops", Scopes: []string{auth.ScopeAdmin}})
+	if _, err := svc.CreateEmbeddings(context.Background(), &llmgatewayv1.CreateEmbeddingsRequest{Model: "fake/e", Input: []string{"hi"}}); err != nil {
+		t.Fatalf("CreateEmbeddings: %v", err)
+	}
+
+	if _, err := svc.PurgeCache(context.Background(), &llmgatewayv1.PurgeCacheRequest{All: true}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-admin, got %v", err)
+	}
+	if _, err := svc.PurgeCache(admin, &llmgatewayv1.PurgeCacheRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without a selection, got %v", err)
+	}
+	res, err := svc.PurgeCache(admin, &llmgatewayv1.PurgeCacheRequest{All: true})
+	if err != nil || res.GetPurged() != 1 {
+		t.Fatalf("purge all: %v, %v", res, err)
+	}
+}
+
+func TestGetModel_ReturnsLimitsAndPricing(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": echoEmbeddingsProvider{}}, []llmgateway.ModelSpec{
+		{
+			ID:              "fake/big",
+			Name:            "Big",
+			Provider:        "fake",
+			Capabilities:    []string{"chat", "tools"},
+			ContextWindow:   128000,
+			MaxOutputTokens: 16384,
+			Pricing:         &llm.ModelPricing{InputPerMillion: 2.5, OutputPerMillion: 10},
+		},
+		{ID: "fake/small", Provider: "fake"},
+	}, nil)
+	svc := NewLLMGatewayService(app, nil)
+
+	res, err := svc.GetModel(context.Background(), &llmgatewayv1.GetModelRequest{Id: "fake/big"})
+	if err != nil {
+		t.Fatalf("GetModel: %v", err)
+	}
+	m := res.GetModel()
+	if m.GetContextWindow() != 128000 || m.GetMaxOutputTokens() != 16384 || len(m.GetCapabilities()) != 2 {
+		t.Fatalf("unexpected limits: %+v", m)
+	}
+	if p := m.GetPricing(); p.GetInputPerMillionTokens() != 2.5 || p.GetOutputPerMillionTokens() != 10 {
+		t.Fatalf("unexpected pricing: %+v", p)
+	}
+
+	res, err = svc.GetModel(context.Background(), &llmgatewayv1.GetModelRequest{Id: "fake/small"})
+	if err != nil {
+		t.Fatalf("GetModel: %v", err)
+	}
+	if m := res.GetModel(); m.GetPricing() != nil || m.GetContextWindow() != 0 {
+		t.Fatalf("expected unknown limits and pricing to be left unset, got %+v", m)
+	}
+}
@@ -0,0 +1,225 @@
+package grpcadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"github.com/poly-workshop/llm-gateway/internal/infrastructure/llmprovider/dashscope"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestChatRequestFromProto_ContentErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	ok := map[string]any{"type": "text", "text": "hi"}
+	tests := []struct {
+		name    string
+		content any
+		want    string
+	}{
+		{"content not string or array", 42.0, "messages[2].content must be a string or array"},
+		{"part not object", []any{ok, "oops"}, "messages[2].content[1] must be an object"},
+		{"type not string", []any{map[string]any{"type": 1.0}}, "messages[2].content[0].type must be a string"},
+		{"text not string", []any{ok, ok, map[string]any{"type": "text", "text": []any{"a"}}}, "messages[2].content[2].text must be a string"},
+		{"image_url not object", []any{map[string]any{"type": "image_url", "image_url": "https://x/a.png"}}, "messages[2].content[0].image_url must be an object"},
+		{"image url not string", []any{ok, map[string]any{"type": "image_url", "image_url": map[string]any{"url": true}}}, "messages[2].content[1].image_url.url must be a string"},
+		{"detail not string", []any{map[string]any{"type": "image_url", "image_url": map[string]any{"url": "u", "detail": 2.0}}}, "messages[2].content[0].image_url.detail must be a string"},
+		{"cache_control type not string", []any{map[string]any{"type": "text", "text": "hi", "cache_control": map[string]any{"type": 1.0}}}, "messages[2].content[0].cache_control.type must be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := structpb.NewValue(tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &llmgatewayv1.CreateChatCompletionRequest{
+				Model: "m",
+				Messages: []*llmgatewayv1.ChatMessage{
+					{Role: "system", Content: structpb.NewStringValue("be brief")},
+					{Role: "user", Content: structpb.NewStringValue("hello")},
+					{Role: "user", Content: content},
+				},
+			}
+			_, err = chatRequestFromProto(context.Background(), req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got %v", err)
+			}
+			if msg := status.Convert(err).Message(); !strings.HasSuffix(msg, ": "+tt.want) {
+				t.Fatalf("message = %q, want suffix %q", msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatRequestFromProto_NullFieldsAreIgnored(t *testing.T) {
+	t.Parallel()
+
+	content, err := structpb.NewValue([]any{map[string]any{"type": "text", "text": "hi", "image_url": nil, "cache_control": nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &llmgatewayv1.CreateChatCompletionRequest{
+		Model:    "m",
+		Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: content}},
+	}
+	got, err := chatRequestFromProto(context.Background(), req)
+	if err != nil {
+		t.Fatalf("chatRequestFromProto: %v", err)
+	}
+	part := got.Messages[0].ContentParts[0]
+	if part.Text != "hi" || part.ImageURL != nil || part.CacheControl != nil {
+		t.Fatalf("part = %+v", part)
+	}
+}
+
+// Vision models read text and images in the order given, so interleaved parts
+// must reach the upstream exactly as the caller sent them.
+func TestChatResponseToProto_AudioIsASeparateField(t *testing.T) {
+	t.Parallel()
+
+	res := chatResponseToProto(llm.ChatCompletionResponse{Choices: []llm.ChatCompletionChoice{{
+		Message: llm.ChatMessage{
+			Role:    "assistant",
+			Content: "hello",
+			Audio:   &llm.AudioContent{ID: "audio_1", Data: "UklGRg==", Format: "wav", Transcript: "hello", ExpiresAt: 1700000000},
+		},
+	}}})
+	msg := res.Choices[0].Message
+	if msg.GetContent().GetStringValue() != "hello" {
+		t.Fatalf("content should stay a string, got %v", msg.GetContent())
+	}
+	a := msg.GetAudio()
+	if a.GetId() != "audio_1" || a.GetData() != "UklGRg==" || a.GetFormat() != "wav" || a.GetTranscript() != "hello" || a.GetExpiresAt() != 1700000000 {
+		t.Fatalf("unexpected audio: %v", a)
+	}
+}
+
+func TestCreateChatCompletion_PreservesContentPartOrderToDashScope(t *testing.T) {
+	t.Parallel()
+
+	type part struct {
+		Type     string `json:"type"`
+		Text     string `json:"text,omitempty"`
+		ImageURL *struct {
+			URL string `json:"url"`
+		} `json:"image_url,omitempty"`
+	}
+	gotParts := make(chan []part, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content []part `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode upstream request: %v", err)
+		}
+		var parts []part
+		if len(body.Messages) > 0 {
+			parts = body.Messages[len(body.Messages)-1].Content
+		}
+		select {
+		case gotParts <- parts:
+		default:
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c","model":"qwen-vl","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{
+		"dashscope": dashscope.NewProvider(srv.URL, []string{"k"}, 2*time.Second),
+	}, nil, nil)
+	svc := NewLLMGatewayService(app, nil)
+
+	content, err := structpb.NewValue([]any{
+		map[string]any{"type": "text", "text": "compare"},
+		map[string]any{"type": "image_url", "image_url": map[string]any{"url": "https://example.com/a.png"}},
+		map[string]any{"type": "text", "text": "with"},
+		map[string]any{"type": "image_url", "image_url": map[string]any{"url": "https://example.com/b.png"}},
+		map[string]any{"type": "text", "text": "which is brighter?"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.CreateChatCompletion(context.Background(), &llmgatewayv1.CreateChatCompletionRequest{
+		Model:    "dashscope/qwen-vl",
+		Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: content}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	var got []string
+	for _, p := range <-gotParts {
+		if p.ImageURL != nil {
+			got = append(got, p.Type+":"+p.ImageURL.URL)
+		} else {
+			got = append(got, p.Type+":"+p.Text)
+		}
+	}
+	want := []string{
+		"text:compare",
+		"image_url:https://example.com/a.png",
+		"text:with",
+		"image_url:https://example.com/b.png",
+		"text:which is brighter?",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("upstream parts = %v, want %v", got, want)
+	}
+}
+
+func TestCreateChatCompletion_ForwardsNAndDedupsChoices(t *testing.T) {
+	t.Parallel()
+
+	gotN := make(chan uint32, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			N uint32 `json:"n"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode upstream request: %v", err)
+		}
+		gotN <- body.N
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c","model":"qwen-turbo","choices":[
+  {"index":0,"message":{"role":"assistant","content":"Paris"},"finish_reason":"stop"},
+  {"index":1,"message":{"role":"assistant","content":"Paris"},"finish_reason":"stop"},
+  {"index":2,"message":{"role":"assistant","content":"Paris, France"},"finish_reason":"stop"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{
+		"dashscope": dashscope.NewProvider(srv.URL, []string{"k"}, 2*time.Second),
+	}, nil, nil, llmgateway.WithChoiceDedup(true))
+	svc := NewLLMGatewayService(app, nil)
+
+	n := uint32(3)
+	res, err := svc.CreateChatCompletion(context.Background(), &llmgatewayv1.CreateChatCompletionRequest{
+		Model:    "dashscope/qwen-turbo",
+		Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: structpb.NewStringValue("capital of France?")}},
+		N:        &n,
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := <-gotN; got != 3 {
+		t.Fatalf("upstream n = %d, want 3", got)
+	}
+	choices := res.GetChoices()
+	if len(choices) != 2 || choices[0].GetMessage().GetContent().GetStringValue() != "Paris" || choices[1].GetIndex() != 1 {
+		t.Fatalf("expected the duplicate choice collapsed, got %v", choices)
+	}
+}
@@ -0,0 +1,186 @@
+package grpcadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// CreateResponse serves the OpenAI Responses API shape through the chat
+// completion flow.
+func (s *LLMGatewayService) CreateResponse(ctx context.Context, req *llmgatewayv1.CreateResponseRequest) (*llmgatewayv1.CreateResponseResponse, error) {
+	ctx, cancel, err := requestTimeoutFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	chatReq, err := chatRequestFromResponses(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.app.CreateChatCompletion(ctx, chatReq)
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+	setProviderRequestID(ctx, res.ProviderRequestID)
+	s.setUsageTrailer(ctx, res.Usage)
+
+	s.maybeSendUsageCallback(ctx, "responses", llm.Generation{
+		ID:             res.ID,
+		Model:          res.Model,
+		Created:        res.Created,
+		Usage:          res.Usage,
+		ConversationID: chatReq.ConversationID,
+	})
+	return responsesFromChat(res), nil
+}
+
+// chatRequestFromResponses converts a Responses API request to a chat request:
+// instructions become a leading system message and input the conversation.
+func chatRequestFromResponses(ctx context.Context, req *llmgatewayv1.CreateResponseRequest) (llm.ChatCompletionRequest, error) {
+	if req.GetStream() {
+		return llm.ChatCompletionRequest{}, status.Error(codes.InvalidArgument, "streaming responses are not supported; use /v1/chat/completions:stream")
+	}
+	var msgs []llm.ChatMessage
+	if req.GetInstructions() != "" {
+		msgs = append(msgs, llm.ChatMessage{Role: "system", Content: req.GetInstructions()})
+	}
+	input, err := parseResponsesInput(req.GetInput())
+	if err != nil {
+		return llm.ChatCompletionRequest{}, status.Errorf(codes.InvalidArgument, "invalid input: %v", err)
+	}
+	return llm.ChatCompletionRequest{
+		Model:       req.GetModel(),
+		Messages:    append(msgs, input...),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxOutputTokens,
+		User:        req.GetUser(),
+		Subject:     subjectFromContext(ctx),
+
+		ConversationID: conversationIDFromContext(ctx, ""),
+		Metadata:       req.GetMetadata(),
+	}, nil
+}
+
+// parseResponsesInput parses input, a string or an array of message items.
+func parseResponsesInput(v *structpb.Value) ([]llm.ChatMessage, error) {
+	switch k := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		return []llm.ChatMessage{{Role: "user", Content: k.StringValue}}, nil
+	case *structpb.Value_ListValue:
+		msgs := make([]llm.ChatMessage, 0, len(k.ListValue.GetValues()))
+		for i, item := range k.ListValue.GetValues() {
+			msg, err := parseResponsesItem(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			msgs = append(msgs, msg)
+		}
+		return msgs, nil
+	default:
+		return nil, errors.New("input must be a string or array")
+	}
+}
+
+func parseResponsesItem(v *structpb.Value) (llm.ChatMessage, error) {
+	fields := v.GetStructValue().GetFields()
+	if fields == nil {
+		return llm.ChatMessage{}, errors.New("item must be an object")
+	}
+	if t := fields["type"].GetStringValue(); t != "" && t != "message" {
+		return llm.ChatMessage{}, fmt.Errorf("unsupported item type %q", t)
+	}
+	msg := llm.ChatMessage{Role: fields["role"].GetStringValue()}
+	switch msg.Role {
+	case "developer":
+		msg.Role = "system"
+	case "user", "assistant", "system":
+	default:
+		return llm.ChatMessage{}, fmt.Errorf("unsupported role %q", msg.Role)
+	}
+	switch c := fields["content"].GetKind().(type) {
+	case *structpb.Value_StringValue:
+		msg.Content = c.StringValue
+	case *structpb.Value_ListValue:
+		for _, p := range c.ListValue.GetValues() {
+			part, err := parseResponsesContentPart(p)
+			if err != nil {
+				return llm.ChatMessage{}, err
+			}
+			msg.ContentParts = append(msg.ContentParts, part)
+		}
+	default:
+		return llm.ChatMessage{}, errors.New("content must be a string or array")
+	}
+	return msg, nil
+}
+
+func parseResponsesContentPart(v *structpb.Value) (llm.ContentPart, error) {
+	fields := v.GetStructValue().GetFields()
+	switch t := fields["type"].GetStringValue(); t {
+	case "input_text", "output_text":
+		return llm.ContentPart{Type: "text", Text: fields["text"].GetStringValue()}, nil
+	case "input_image":
+		return llm.ContentPart{Type: "image_url", ImageURL: &llm.ImageURL{
+			URL:    fields["image_url"].GetStringValue(),
+			Detail: fields["detail"].GetStringValue(),
+		}}, nil
+	default:
+		return llm.ContentPart{}, fmt.Errorf("unsupported content part type %q", t)
+	}
+}
+
+// responsesFromChat converts a chat response to the Responses API shape, one
+// output message per choice.
+func responsesFromChat(res llm.ChatCompletionResponse) *llmgatewayv1.CreateResponseResponse {
+	out := &llmgatewayv1.CreateResponseResponse{
+		Id:        res.ID,
+		Object:    "response",
+		CreatedAt: res.Created,
+		Model:     res.Model,
+		Status:    "completed",
+		Usage: &llmgatewayv1.ResponseUsage{
+			InputTokens:  res.Usage.PromptTokens,
+			OutputTokens: res.Usage.CompletionTokens,
+			TotalTokens:  res.Usage.TotalTokens,
+		},
+	}
+	for _, c := range res.Choices {
+		item := &llmgatewayv1.ResponseOutputItem{
+			Type:   "message",
+			Id:     fmt.Sprintf("msg_%s_%d", res.ID, c.Index),
+			Role:   "assistant",
+			Status: "completed",
+		}
+		text := c.Message.Content
+		for _, p := range c.Message.ContentParts {
+			text += p.Text
+		}
+		item.Content = []*llmgatewayv1.ResponseOutputContent{{Type: "output_text", Text: text}}
+		if reason := incompleteReason(c.FinishReason); reason != "" {
+			item.Status = "incomplete"
+			out.Status = "incomplete"
+			out.IncompleteDetails = &llmgatewayv1.ResponseIncompleteDetails{Reason: reason}
+		}
+		out.Output = append(out.Output, item)
+	}
+	return out
+}
+
+// incompleteReason maps a chat finish reason to the Responses API reason for
+// stopping early; "" means the output is complete.
+func incompleteReason(finish string) string {
+	switch finish {
+	case "length":
+		return "max_output_tokens"
+	case "content_filter":
+		return "content_filter"
+	}
+	return ""
+}
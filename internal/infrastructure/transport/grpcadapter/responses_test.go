@@ -0,0 +1,157 @@
+package grpcadapter
+
+import (
+	"context"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func mustValue(t *testing.T, v any) *structpb.Value {
+	t.Helper()
+	out, err := structpb.NewValue(v)
+	if err != nil {
+		t.Fatalf("structpb.NewValue: %v", err)
+	}
+	return out
+}
+
+func TestChatRequestFromResponses(t *testing.T) {
+	t.Parallel()
+
+	temp := 0.2
+	maxTokens := uint32(64)
+	req := &llmgatewayv1.CreateResponseRequest{
+		Model:        "fake/m",
+		Instructions: "Be terse.",
+		Input: mustValue(t, []any{
+			map[string]any{"role": "developer", "content": "Answer in English."},
+			map[string]any{"type": "message", "role": "user", "content": []any{
+				map[string]any{"type": "input_text", "text": "What is in this picture?"},
+				map[string]any{"type": "input_image", "image_url": "https://example.com/cat.png", "detail": "low"},
+			}},
+			map[string]any{"role": "assistant", "content": []any{map[string]any{"type": "output_text", "text": "A cat."}}},
+			map[string]any{"role": "user", "content": "What color?"},
+		}),
+		Temperature:     &temp,
+		MaxOutputTokens: &maxTokens,
+		User:            "u-1",
+	}
+	got, err := chatRequestFromResponses(context.Background(), req)
+	if err != nil {
+		t.Fatalf("chatRequestFromResponses: %v", err)
+	}
+	if got.Model != "fake/m" || got.Temperature == nil || *got.Temperature != 0.2 || got.MaxTokens == nil || *got.MaxTokens != 64 || got.User != "u-1" {
+		t.Fatalf("unexpected request fields: %+v", got)
+	}
+	if len(got.Messages) != 5 {
+		t.Fatalf("expected instructions plus 4 input messages, got %+v", got.Messages)
+	}
+	if m := got.Messages[0]; m.Role != "system" || m.Content != "Be terse." {
+		t.Fatalf("expected instructions as the leading system message, got %+v", m)
+	}
+	if m := got.Messages[1]; m.Role != "system" || m.Content != "Answer in English." {
+		t.Fatalf("expected the developer message as system, got %+v", m)
+	}
+	parts := got.Messages[2].ContentParts
+	if len(parts) != 2 || parts[0].Type != "text" || parts[0].Text != "What is in this picture?" ||
+		parts[1].Type != "image_url" || parts[1].ImageURL.URL != "https://example.com/cat.png" || parts[1].ImageURL.Detail != "low" {
+		t.Fatalf("unexpected user content parts: %+v", parts)
+	}
+	if m := got.Messages[3]; m.Role != "assistant" || len(m.ContentParts) != 1 || m.ContentParts[0].Text != "A cat." {
+		t.Fatalf("unexpected assistant message: %+v", m)
+	}
+
+	// A bare string is one user message.
+	got, err = chatRequestFromResponses(context.Background(), &llmgatewayv1.CreateResponseRequest{Model: "fake/m", Input: structpb.NewStringValue("hi")})
+	if err != nil || len(got.Messages) != 1 || got.Messages[0].Role != "user" || got.Messages[0].Content != "hi" {
+		t.Fatalf("unexpected string input translation: %+v, %v", got.Messages, err)
+	}
+
+	for name, bad := range map[string]*llmgatewayv1.CreateResponseRequest{
+		"stream":        {Model: "fake/m", Input: structpb.NewStringValue("hi"), Stream: true},
+		"tool item":     {Model: "fake/m", Input: mustValue(t, []any{map[string]any{"type": "function_call_output", "output": "42"}})},
+		"unknown role":  {Model: "fake/m", Input: mustValue(t, []any{map[string]any{"role": "tool", "content": "x"}})},
+		"unknown part":  {Model: "fake/m", Input: mustValue(t, []any{map[string]any{"role": "user", "content": []any{map[string]any{"type": "input_file"}}}})},
+		"numeric input": {Model: "fake/m", Input: structpb.NewNumberValue(1)},
+	} {
+		if _, err := chatRequestFromResponses(context.Background(), bad); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
+
+func TestResponsesFromChat(t *testing.T) {
+	t.Parallel()
+
+	res := responsesFromChat(llm.ChatCompletionResponse{
+		ID:      "gen-1",
+		Created: 1700000000,
+		Model:   "fake/m",
+		Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Content: "Orange."}, FinishReason: "stop"}},
+		Usage:   llm.TokenUsage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15},
+	})
+	if res.GetId() != "gen-1" || res.GetObject() != "response" || res.GetCreatedAt() != 1700000000 || res.GetModel() != "fake/m" || res.GetStatus() != "completed" {
+		t.Fatalf("unexpected response envelope: %+v", res)
+	}
+	if len(res.GetOutput()) != 1 {
+		t.Fatalf("expected one output item, got %+v", res.GetOutput())
+	}
+	item := res.GetOutput()[0]
+	if item.GetType() != "message" || item.GetRole() != "assistant" || item.GetStatus() != "completed" ||
+		len(item.GetContent()) != 1 || item.GetContent()[0].GetType() != "output_text" || item.GetContent()[0].GetText() != "Orange." {
+		t.Fatalf("unexpected output item: %+v", item)
+	}
+	if u := res.GetUsage(); u.GetInputTokens() != 12 || u.GetOutputTokens() != 3 || u.GetTotalTokens() != 15 {
+		t.Fatalf("unexpected usage: %+v", u)
+	}
+	if res.GetIncompleteDetails() != nil {
+		t.Fatalf("unexpected incomplete details: %+v", res.GetIncompleteDetails())
+	}
+
+	truncated := responsesFromChat(llm.ChatCompletionResponse{ID: "gen-2", Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Content: "Oran"}, FinishReason: "length"}}})
+	if truncated.GetStatus() != "incomplete" || truncated.GetIncompleteDetails().GetReason() != "max_output_tokens" || truncated.GetOutput()[0].GetStatus() != "incomplete" {
+		t.Fatalf("expected a truncated response marked incomplete, got %+v", truncated)
+	}
+}
+
+type recordingChatProvider struct {
+	echoEmbeddingsProvider
+	got llm.ChatCompletionRequest
+}
+
+func (p *recordingChatProvider) CreateChatCompletion(_ context.Context, req llm.ChatCompletionRequest) (llm.ChatCompletionResponse, error) {
+	p.got = req
+	return llm.ChatCompletionResponse{
+		ID:      "chat-1",
+		Model:   req.Model,
+		Choices: []llm.ChatCompletionChoice{{Message: llm.ChatMessage{Role: "assistant", Content: "hello"}, FinishReason: "stop"}},
+		Usage:   llm.TokenUsage{PromptTokens: 2, CompletionTokens: 1, TotalTokens: 3},
+	}, nil
+}
+
+func TestCreateResponse_UsesChatFlow(t *testing.T) {
+	t.Parallel()
+
+	p := &recordingChatProvider{}
+	svc := NewLLMGatewayService(llmgateway.NewService(map[string]llmgateway.Provider{"fake": p}, nil, nil), nil)
+	res, err := svc.CreateResponse(context.Background(), &llmgatewayv1.CreateResponseRequest{
+		Model:        "fake/m",
+		Instructions: "Be nice.",
+		Input:        structpb.NewStringValue("hi"),
+	})
+	if err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+	if len(p.got.Messages) != 2 || p.got.Messages[0].Role != "system" || p.got.Messages[1].Content != "hi" || p.got.Model != "m" {
+		t.Fatalf("unexpected upstream chat request: %+v", p.got)
+	}
+	if res.GetOutput()[0].GetContent()[0].GetText() != "hello" || res.GetUsage().GetTotalTokens() != 3 {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+}
@@ -0,0 +1,66 @@
+package grpcadapter
+
+import (
+	"sort"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+)
+
+// toolCallAssembler rebuilds complete tool calls from streamed fragments.
+// Parallel tool calls are told apart by their index.
+type toolCallAssembler struct {
+	calls map[uint32]*llm.ToolCall
+}
+
+func (a *toolCallAssembler) add(fragments []llm.ToolCall) {
+	for _, f := range fragments {
+		if a.calls == nil {
+			a.calls = make(map[uint32]*llm.ToolCall)
+		}
+		c, ok := a.calls[f.Index]
+		if !ok {
+			c = &llm.ToolCall{Index: f.Index}
+			a.calls[f.Index] = c
+		}
+		if f.ID != "" {
+			c.ID = f.ID
+		}
+		if f.Type != "" {
+			c.Type = f.Type
+		}
+		if f.Function.Name != "" {
+			c.Function.Name = f.Function.Name
+		}
+		c.Function.Arguments += f.Function.Arguments
+	}
+}
+
+// toolCalls returns the assembled calls ordered by index.
+func (a *toolCallAssembler) toolCalls() []llm.ToolCall {
+	out := make([]llm.ToolCall, 0, len(a.calls))
+	for _, c := range a.calls {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Index < out[j].Index })
+	return out
+}
+
+func toolCallsToProto(calls []llm.ToolCall) []*llmgatewayv1.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]*llmgatewayv1.ToolCall, 0, len(calls))
+	for _, c := range calls {
+		out = append(out, &llmgatewayv1.ToolCall{
+			Index: c.Index,
+			Id:    c.ID,
+			Type:  c.Type,
+			Function: &llmgatewayv1.ToolCallFunction{
+				Name:      c.Function.Name,
+				Arguments: c.Function.Arguments,
+			},
+		})
+	}
+	return out
+}
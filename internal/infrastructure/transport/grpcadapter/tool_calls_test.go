@@ -0,0 +1,95 @@
+package grpcadapter
+
+import (
+	"context"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// toolCallStreamProvider streams two parallel tool calls whose fragments interleave.
+type toolCallStreamProvider struct{ echoEmbeddingsProvider }
+
+func (toolCallStreamProvider) CreateChatCompletionStream(_ context.Context, _ llm.ChatCompletionRequest, emit func(llm.ChatCompletionChunk) error) error {
+	frag := func(tcs ...llm.ToolCall) llm.ChatCompletionChunk {
+		return llm.ChatCompletionChunk{ID: "c1", Choices: []llm.ChatCompletionChunkChoice{{Delta: llm.ChatMessage{ToolCalls: tcs}}}}
+	}
+	chunks := []llm.ChatCompletionChunk{
+		frag(llm.ToolCall{Index: 0, ID: "call_a", Type: "function", Function: llm.ToolCallFunction{Name: "get_weather"}}),
+		frag(llm.ToolCall{Index: 0, Function: llm.ToolCallFunction{Arguments: `{"city":`}}),
+		frag(llm.ToolCall{Index: 1, ID: "call_b", Type: "function", Function: llm.ToolCallFunction{Name: "get_time", Arguments: `{"tz":`}}),
+		frag(llm.ToolCall{Index: 0, Function: llm.ToolCallFunction{Arguments: `"Paris"}`}}),
+		frag(llm.ToolCall{Index: 1, Function: llm.ToolCallFunction{Arguments: `"CET"}`}}),
+		{ID: "c1", Choices: []llm.ChatCompletionChunkChoice{{FinishReason: "tool_calls"}}},
+	}
+	for _, c := range chunks {
+		if err := emit(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeChatStream struct {
+	grpc.ServerStream
+	sent []*llmgatewayv1.CreateChatCompletionStreamResponse
+}
+
+func (f *fakeChatStream) Context() context.Context { return context.Background() }
+
+func (f *fakeChatStream) Send(m *llmgatewayv1.CreateChatCompletionStreamResponse) error {
+	f.sent = append(f.sent, m)
+	return nil
+}
+
+func TestCreateChatCompletionStream_AssemblesToolCalls(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": toolCallStreamProvider{}}, nil, nil)
+	svc := NewLLMGatewayService(app, nil)
+
+	stream := &fakeChatStream{}
+	err := svc.CreateChatCompletionStream(&llmgatewayv1.CreateChatCompletionStreamRequest{
+		Request: &llmgatewayv1.CreateChatCompletionRequest{
+			Model:    "fake/m",
+			Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: structpb.NewStringValue("weather and time?")}},
+		},
+		AssembleToolCalls: true,
+	}, stream)
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream error: %v", err)
+	}
+	if len(stream.sent) != 6 {
+		t.Fatalf("expected 6 chunks, got %d", len(stream.sent))
+	}
+
+	// Fragments are passed through unchanged.
+	if frag := stream.sent[3].GetChoices()[0].GetDelta().GetToolCalls(); len(frag) != 1 || frag[0].GetFunction().GetArguments() != `"Paris"}` {
+		t.Fatalf("unexpected passthrough fragment: %v", frag)
+	}
+	for _, m := range stream.sent[:5] {
+		if len(m.GetChoices()[0].GetToolCalls()) != 0 {
+			t.Fatalf("assembled tool calls must only be on the final chunk: %v", m)
+		}
+	}
+
+	got := stream.sent[5].GetChoices()[0].GetToolCalls()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 assembled tool calls, got %v", got)
+	}
+	want := []struct{ id, name, args string }{
+		{"call_a", "get_weather", `{"city":"Paris"}`},
+		{"call_b", "get_time", `{"tz":"CET"}`},
+	}
+	for i, w := range want {
+		tc := got[i]
+		if tc.GetIndex() != uint32(i) || tc.GetId() != w.id || tc.GetType() != "function" ||
+			tc.GetFunction().GetName() != w.name || tc.GetFunction().GetArguments() != w.args {
+			t.Fatalf("tool call %d = %v, want %+v", i, tc, w)
+		}
+	}
+}
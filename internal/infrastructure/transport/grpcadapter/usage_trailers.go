@@ -0,0 +1,41 @@
+package grpcadapter
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/poly-workshop/llm-gateway/internal/domain/llm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Usage trailer keys, set on completions when WithUsageTrailers is on so
+// proxies can account tokens without parsing the body.
+const (
+	UsagePromptTokensTrailer     = "x-usage-prompt-tokens"
+	UsageCompletionTokensTrailer = "x-usage-completion-tokens"
+	UsageTotalTokensTrailer      = "x-usage-total-tokens"
+)
+
+// WithUsageTrailers sets the token usage of chat (unary and streamed), legacy
+// and Responses API completions as response trailer metadata.
+func WithUsageTrailers(enabled bool) Option {
+	return func(s *LLMGatewayService) { s.usageTrailers = enabled }
+}
+
+// usageTrailer returns u as trailer metadata.
+func usageTrailer(u llm.TokenUsage) metadata.MD {
+	return metadata.Pairs(
+		UsagePromptTokensTrailer, strconv.FormatUint(uint64(u.PromptTokens), 10),
+		UsageCompletionTokensTrailer, strconv.FormatUint(uint64(u.CompletionTokens), 10),
+		UsageTotalTokensTrailer, strconv.FormatUint(uint64(u.TotalTokens), 10),
+	)
+}
+
+// setUsageTrailer sets u as the unary response trailer when enabled.
+func (s *LLMGatewayService) setUsageTrailer(ctx context.Context, u llm.TokenUsage) {
+	if !s.usageTrailers {
+		return
+	}
+	_ = grpc.SetTrailer(ctx, usageTrailer(u)) // Best effort.
+}
@@ -0,0 +1,56 @@
+package grpcadapter
+
+import (
+	"context"
+	"testing"
+
+	llmgatewayv1 "github.com/poly-workshop/llm-gateway/gen/go/llmgateway/v1"
+	"github.com/poly-workshop/llm-gateway/internal/application/llmgateway"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// trailerTransportStream records the metadata a unary handler sets.
+type trailerTransportStream struct {
+	trailer metadata.MD
+}
+
+func (*trailerTransportStream) Method() string               { return "/test" }
+func (*trailerTransportStream) SetHeader(metadata.MD) error  { return nil }
+func (*trailerTransportStream) SendHeader(metadata.MD) error { return nil }
+func (s *trailerTransportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestCreateChatCompletion_UsageTrailers(t *testing.T) {
+	t.Parallel()
+
+	app := llmgateway.NewService(map[string]llmgateway.Provider{"fake": &recordingChatProvider{}}, nil, nil)
+	req := &llmgatewayv1.CreateChatCompletionRequest{
+		Model:    "fake/m",
+		Messages: []*llmgatewayv1.ChatMessage{{Role: "user", Content: structpb.NewStringValue("hi")}},
+	}
+	for _, enabled := range []bool{true, false} {
+		ts := &trailerTransportStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), ts)
+		svc := NewLLMGatewayService(app, nil, WithUsageTrailers(enabled))
+		if _, err := svc.CreateChatCompletion(ctx, req); err != nil {
+			t.Fatalf("CreateChatCompletion: %v", err)
+		}
+		got := ts.trailer.Get(UsageTotalTokensTrailer)
+		if !enabled {
+			if len(got) != 0 {
+				t.Fatalf("expected no usage trailer when disabled, got %v", ts.trailer)
+			}
+			continue
+		}
+		if len(got) != 1 || got[0] != "3" {
+			t.Fatalf("expected total tokens trailer 3, got %v", ts.trailer)
+		}
+		if p := ts.trailer.Get(UsagePromptTokensTrailer); len(p) != 1 || p[0] != "2" {
+			t.Fatalf("expected prompt tokens trailer 2, got %v", ts.trailer)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package usagecallback
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Batcher buffers payloads per callback URL and delivers each buffer as one
+// JSON array once it holds size payloads or interval has passed, whichever
+// comes first. Callers must check the URL against the subject's allowlist
+// before Add, as for Send.
+type Batcher struct {
+	sender   *Sender
+	size     int
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[string][]Payload // url -> buffered payloads
+	closed  bool
+
+	sends sync.WaitGroup // in-flight deliveries
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewBatcher starts a batcher delivering through sender. size < 1 is treated
+// as 1; interval <= 0 flushes by count only.
+func NewBatcher(sender *Sender, size int, interval time.Duration) *Batcher {
+	b := &Batcher{
+		sender:   sender,
+		size:     max(size, 1),
+		interval: interval,
+		pending:  make(map[string][]Payload),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *Batcher) run() {
+	defer close(b.done)
+	if b.interval <= 0 {
+		<-b.stop
+		return
+	}
+	t := time.NewTicker(b.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			b.flushAll()
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// Add buffers p for url. After Close, payloads are delivered right away.
+func (b *Batcher) Add(url string, p Payload) {
+	b.mu.Lock()
+	buf := append(b.pending[url], p)
+	if len(buf) < b.size && !b.closed {
+		b.pending[url] = buf
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, url)
+	b.deliver(url, buf)
+	b.mu.Unlock()
+}
+
+func (b *Batcher) flushAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for url, buf := range b.pending {
+		b.deliver(url, buf)
+	}
+	clear(b.pending)
+}
+
+// deliver sends buf in the background; b.mu must be held so Close can't
+// miss it.
+func (b *Batcher) deliver(url string, buf []Payload) {
+	b.sends.Add(1)
+	go func() {
+		defer b.sends.Done()
+		if err := b.sender.SendBatch(context.Background(), url, buf); err != nil {
+			slog.Warn("usage callback batch failed", "url", url, "events", len(buf), "error", err)
+		}
+	}()
+}
+
+// Close flushes every buffer and waits for deliveries to finish or ctx to end.
+func (b *Batcher) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.stop)
+	}
+	b.mu.Unlock()
+	<-b.done
+	b.flushAll()
+
+	finished := make(chan struct{})
+	go func() {
+		b.sends.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,92 @@
+package usagecallback
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// batchServer records the size of each posted batch.
+func batchServer(t *testing.T) (*httptest.Server, chan int) {
+	t.Helper()
+	batches := make(chan int, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payloads []Payload
+		if err := json.NewDecoder(r.Body).Decode(&payloads); err != nil {
+			t.Errorf("decode batch: %v", err)
+		}
+		batches <- len(payloads)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, batches
+}
+
+func waitBatch(t *testing.T, batches chan int) int {
+	t.Helper()
+	select {
+	case n := <-batches:
+		return n
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for a batch")
+		return 0
+	}
+}
+
+func TestBatcher_FlushesByCount(t *testing.T) {
+	t.Parallel()
+
+	srv, batches := batchServer(t)
+	b := NewBatcher(New(nil, time.Second), 3, time.Hour)
+	defer b.Close(context.Background())
+
+	for i := 0; i < 5; i++ {
+		b.Add(srv.URL, Payload{Event: "llm.usage", GenerationID: "gen"})
+	}
+	if n := waitBatch(t, batches); n != 3 {
+		t.Fatalf("expected a batch of 3, got %d", n)
+	}
+	select {
+	case n := <-batches:
+		t.Fatalf("unexpected early batch of %d", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBatcher_FlushesByInterval(t *testing.T) {
+	t.Parallel()
+
+	srv, batches := batchServer(t)
+	b := NewBatcher(New(nil, time.Second), 100, 20*time.Millisecond)
+	defer b.Close(context.Background())
+
+	b.Add(srv.URL, Payload{Event: "llm.usage"})
+	b.Add(srv.URL, Payload{Event: "llm.usage"})
+	if n := waitBatch(t, batches); n != 2 {
+		t.Fatalf("expected the interval to flush 2 events, got %d", n)
+	}
+}
+
+func TestBatcher_CloseFlushesPending(t *testing.T) {
+	t.Parallel()
+
+	srv, batches := batchServer(t)
+	other, otherBatches := batchServer(t)
+	b := NewBatcher(New(nil, time.Second), 100, time.Hour)
+
+	b.Add(srv.URL, Payload{Event: "llm.usage"})
+	b.Add(srv.URL, Payload{Event: "llm.usage"})
+	b.Add(other.URL, Payload{Event: "llm.usage"})
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Close waits for delivery, so both batches have already arrived.
+	if len(batches) != 1 || <-batches != 2 {
+		t.Fatalf("expected one final batch of 2 for the first url")
+	}
+	if len(otherBatches) != 1 || <-otherBatches != 1 {
+		t.Fatalf("expected one final batch of 1 for the second url")
+	}
+}
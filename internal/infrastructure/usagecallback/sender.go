@@ -36,9 +36,32 @@ type Payload struct {
 	CompletionTokens uint32 `json:"completion_tokens"`
 	TotalTokens      uint32 `json:"total_tokens"`
 	OccurredAtUnix   int64  `json:"occurred_at_unix"`
+
+	// Optional token breakdowns, omitted when the provider doesn't report them.
+	CacheReadTokens       uint32 `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens      uint32 `json:"cache_write_tokens,omitempty"`
+	ReasoningTokens       uint32 `json:"reasoning_tokens,omitempty"`
+	PromptAudioTokens     uint32 `json:"prompt_audio_tokens,omitempty"`
+	CompletionAudioTokens uint32 `json:"completion_audio_tokens,omitempty"`
+
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// Partial is set when the client cancelled a stream; the token counts then
+	// cover only what was generated before, and Status says why.
+	Partial bool   `json:"partial,omitempty"`
+	Status  string `json:"status,omitempty"`
 }
 
 func (s *Sender) Send(ctx context.Context, url string, payload Payload) error {
+	return s.post(ctx, url, payload)
+}
+
+// SendBatch delivers payloads to url as one JSON array.
+func (s *Sender) SendBatch(ctx context.Context, url string, payloads []Payload) error {
+	return s.post(ctx, url, payloads)
+}
+
+func (s *Sender) post(ctx context.Context, url string, body any) error {
 	if s == nil || s.client == nil {
 		return fmt.Errorf("usage callback sender not configured")
 	}
@@ -48,7 +71,7 @@ func (s *Sender) Send(ctx context.Context, url string, payload Payload) error {
 	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	b, err := json.Marshal(payload)
+	b, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}